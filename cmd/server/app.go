@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -12,10 +13,17 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/mohamadchoker/order-delivery-service/internal/config"
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/breaker"
 	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres"
 	"github.com/mohamadchoker/order-delivery-service/internal/service"
 	grpchandler "github.com/mohamadchoker/order-delivery-service/internal/transport/grpc"
+	httphandler "github.com/mohamadchoker/order-delivery-service/internal/transport/http"
+	"github.com/mohamadchoker/order-delivery-service/pkg/circuitbreaker"
 	"github.com/mohamadchoker/order-delivery-service/pkg/logger"
+	"github.com/mohamadchoker/order-delivery-service/pkg/metrics"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 	dbpkg "github.com/mohamadchoker/order-delivery-service/pkg/postgres"
 )
 
@@ -26,11 +34,24 @@ type App struct {
 	db     *gorm.DB
 
 	grpcServer    *GRPCServer
+	httpServer    *HTTPServer
 	metricsServer *MetricsServer
+	metrics       *metrics.Metrics
+
+	reassignWorker       *ReassignWorker            // nil when disabled
+	metricsSummaryWorker *MetricsSummaryWorker      // nil when disabled
+	escalationWorker     *EscalationWorker          // nil when disabled
+	throttledNotifier    *service.ThrottledNotifier // nil when notifications are not throttled
+	auditFile            *os.File                   // nil when there is no audit log
+	stopWorkers          context.CancelFunc
+
+	lifecycle *Lifecycle
 }
 
 // NewApp creates a new application instance with all dependencies initialized
 func NewApp(version, buildDate, gitCommit string) (*App, error) {
+	startedAt := time.Now()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -42,6 +63,7 @@ func NewApp(version, buildDate, gitCommit string) (*App, error) {
 		Level:            cfg.Logger.Level,
 		Development:      cfg.Logger.Development,
 		EnableStacktrace: cfg.Logger.EnableStacktrace,
+		Encoding:         cfg.Logger.Encoding,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
@@ -54,6 +76,16 @@ func NewApp(version, buildDate, gitCommit string) (*App, error) {
 		zap.Int("grpc_port", cfg.Server.Port),
 	)
 
+	// Metrics are registered with a registry of their own, served by the metrics server
+	registry := metrics.NewRegistry()
+	appMetrics, err := metrics.New(registry, cfg.Server.MetricsNamespace, cfg.Server.MetricsSubsystem)
+	if err != nil {
+		return nil, err
+	}
+	if err := appMetrics.TrackCountries(cfg.Server.MetricsCountries); err != nil {
+		return nil, err
+	}
+
 	// Connect to database
 	db, err := dbpkg.Connect(cfg.Database)
 	if err != nil {
@@ -61,34 +93,324 @@ func NewApp(version, buildDate, gitCommit string) (*App, error) {
 	}
 	log.Info("Database connection established")
 
+	if cfg.Database.AutoMigrate {
+		if err := dbpkg.Migrate(db); err != nil {
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		log.Info("Database schema migrated")
+	}
+
 	// Initialize business layer (dependency injection)
-	repo := postgres.NewRepository(db)
-	useCase := service.NewDeliveryUseCase(repo, log)
-	handler := grpchandler.NewHandler(useCase, log)
+	var repo service.DeliveryRepository = postgres.NewRepositoryWithConfig(db, postgres.Config{
+		ExplainQueries:     cfg.Database.ExplainQueries,
+		TransactionTimeout: cfg.Database.TransactionTimeout,
+		CountTimeout:       cfg.Database.CountTimeout,
+		CascadeAuditLog:    cfg.Database.CascadeAuditLog,
+		Logger:             log,
+	})
+	if cfg.Database.BreakerFailureThreshold > 0 {
+		repo = breaker.NewRepository(repo, circuitbreaker.New(circuitbreaker.Settings{
+			FailureThreshold: cfg.Database.BreakerFailureThreshold,
+			Cooldown:         cfg.Database.BreakerCooldown,
+			IsFailure:        breaker.IsDatabaseFailure,
+			OnStateChange: func(from, to circuitbreaker.State) {
+				log.Warn("Database circuit breaker state changed",
+					zap.Stringer("from", from),
+					zap.Stringer("to", to),
+				)
+			},
+		}))
+	}
+
+	notifier, throttledNotifier := newNotifier(log, cfg.Delivery.NotificationThrottle)
+
+	// Audit trail of every mutation, appended to across restarts
+	var (
+		auditFile   *os.File
+		auditLogger service.AuditLogger
+	)
+	if cfg.Delivery.AuditLogPath != "" {
+		auditFile, err = os.OpenFile(cfg.Delivery.AuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		auditLogger = service.NewJSONAuditLogger(auditFile)
+	}
+	if cfg.Delivery.AuditLogDB {
+		auditLogger = postgres.NewAuditLogger(db)
+	}
+
+	useCase := service.NewDeliveryUseCaseWithConfig(repo, log, service.Config{
+		UppercaseIDs:        cfg.Delivery.UppercaseIDs,
+		MaxNotesLength:      cfg.Delivery.MaxNotesLength,
+		DefaultNotes:        cfg.Delivery.DefaultNotes,
+		PrependDefaultNotes: cfg.Delivery.PrependDefaultNotes,
+		Limits: service.Limits{
+			BatchGet:      cfg.Limits.MaxBatchGet,
+			SyncBatch:     cfg.Limits.MaxSyncBatch,
+			DriverMetrics: cfg.Limits.MaxDriverMetrics,
+			ImportRows:    cfg.Limits.MaxImportRows,
+		},
+		MaxActivePerOrder: cfg.Delivery.MaxActivePerOrder,
+		MaxAttempts:       cfg.Delivery.MaxAttempts,
+		PickupLockout: domain.PickupLockout{
+			MaxAttempts: cfg.Delivery.MaxPickupAttempts,
+			Duration:    cfg.Delivery.PickupLockout,
+		},
+		MinScheduleAdvance:  cfg.Delivery.MinScheduleAdvance,
+		MaxScheduleAdvance:  cfg.Delivery.MaxScheduleAdvance,
+		AllowPastScheduling: cfg.Delivery.AllowPastScheduling,
+		DeliveryWindowSlack: cfg.Delivery.DeliveryWindowSlack,
+		OnTimeGracePeriod:   cfg.Delivery.OnTimeGracePeriod,
+
+		MaxDeliveryDistanceKm: cfg.Delivery.MaxDeliveryDistanceKm,
+		AverageSpeedKmh:       cfg.Delivery.AverageSpeedKmh,
+
+		MaxConcurrentMetrics: cfg.Delivery.MaxConcurrentMetrics,
+		MetricsQueueTimeout:  cfg.Delivery.MetricsQueueTimeout,
+
+		AllowedVehicleTypes: cfg.Delivery.AllowedVehicleTypes,
+		FailureReasonCodes:  cfg.Delivery.FailureReasonCodes,
+		BusinessHours:       cfg.Delivery.BusinessHours,
+		MetadataValidator:   service.MetadataSchemas(cfg.Delivery.MetadataSchemas),
+		IDGenerator:         cfg.Delivery.IDGenerator,
+		Notifier:            notifier,
+		AuditLogger:         auditLogger,
+		Broadcaster:         service.NewStatusBroadcaster(constants.WatchBufferSize),
+	})
+	handler := grpchandler.NewHandlerWithBuildInfo(useCase, log, grpchandler.BuildInfo{
+		Version:   version,
+		BuildDate: buildDate,
+		GitCommit: gitCommit,
+		StartedAt: startedAt,
+	}).WithMetrics(appMetrics)
+
+	// Sensitive fields shared by payload logging and gateway responses
+	redactor := middleware.NewRedactor(cfg.Redaction.Fields)
+
+	// Caller-supplied request IDs are validated the same way by both servers
+	requestIDs := middleware.RequestIDConfig{
+		Pattern:   cfg.Server.RequestIDPattern,
+		MaxLength: cfg.Server.RequestIDMaxLength,
+	}
 
 	// Create gRPC server
 	grpcServer, err := NewGRPCServer(GRPCConfig{
 		Port:           cfg.Server.Port,
-		RequestTimeout: 30 * time.Second,
-		Logger:         log,
+		RequestTimeout: cfg.Server.RequestTimeout,
+		AdminAPIKeys:   cfg.Auth.AdminAPIKeys,
+		APIKeyActors:   middleware.APIKeyActors(append(slices.Clip(cfg.Auth.APIKeys), cfg.Auth.AdminAPIKeys...), cfg.Auth.APIKeyActors),
+		RequestID:      requestIDs,
+		Logging: middleware.LoggingConfig{
+			SkipMethods:         cfg.Logger.SkipMethods,
+			DebugMethodPrefixes: cfg.Logger.DebugMethodPrefixes,
+			LogPayloads:         cfg.Logger.LogPayloads,
+			Redactor:            redactor,
+		},
+		Metrics: appMetrics,
+		Logger:  log,
 	}, handler)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC server: %w", err)
 	}
 
+	// Admin keys are gateway keys too, so admin operations can be called over HTTP
+	gatewayKeys := cfg.Auth.APIKeys
+	if len(gatewayKeys) > 0 {
+		gatewayKeys = append(slices.Clip(gatewayKeys), cfg.Auth.AdminAPIKeys...)
+	}
+
+	// Create HTTP gateway server
+	httpServer, err := NewHTTPServer(context.Background(), HTTPConfig{
+		Port:     cfg.Server.HTTPPort,
+		GRPCPort: cfg.Server.Port,
+		CORS: middleware.CORSConfig{
+			AllowedOrigins: cfg.CORS.AllowedOrigins,
+			AllowedMethods: cfg.CORS.AllowedMethods,
+			AllowedHeaders: cfg.CORS.AllowedHeaders,
+			MaxAge:         cfg.CORS.MaxAge,
+		},
+		Auth: middleware.AuthConfig{
+			APIKeys:      gatewayKeys,
+			ExemptRoutes: cfg.Auth.ExemptRoutes,
+		},
+		RedactUnauthenticated: cfg.Redaction.GatewayResponses,
+		Redactor:              redactor,
+		MetricsExport:         httphandler.NewMetricsExportHandler(useCase, log),
+		RequestID:             requestIDs,
+		Logger:                log,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP gateway: %w", err)
+	}
+
+	// Create reassign-on-timeout worker
+	var reassignWorker *ReassignWorker
+	if cfg.Delivery.ReassignInterval > 0 {
+		reassignWorker = NewReassignWorker(ReassignConfig{
+			Interval:    cfg.Delivery.ReassignInterval,
+			GracePeriod: cfg.Delivery.ReassignGracePeriod,
+			Metrics:     appMetrics,
+			Logger:      log,
+		}, useCase)
+	}
+
+	// Create priority escalation worker
+	var escalationWorker *EscalationWorker
+	if cfg.Delivery.EscalationInterval > 0 && len(cfg.Delivery.PriorityEscalations) > 0 {
+		escalationWorker = NewEscalationWorker(EscalationConfig{
+			Interval:    cfg.Delivery.EscalationInterval,
+			Escalations: cfg.Delivery.PriorityEscalations,
+			Metrics:     appMetrics,
+			Logger:      log,
+		}, useCase)
+	}
+
+	// Create metrics summary worker
+	var metricsSummaryWorker *MetricsSummaryWorker
+	if cfg.Delivery.MetricsSummaryInterval > 0 {
+		metricsSummaryWorker = NewMetricsSummaryWorker(MetricsSummaryConfig{
+			Interval:     cfg.Delivery.MetricsSummaryInterval,
+			LookbackDays: cfg.Delivery.MetricsSummaryLookbackDays,
+			Logger:       log,
+		}, useCase)
+	}
+
 	// Create metrics server
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
 	metricsServer := NewMetricsServer(MetricsConfig{
-		Port:   9090, // TODO: Add to config
-		Logger: log,
+		Port:     9090, // TODO: Add to config
+		Gatherer: registry,
+		HealthDetail: httphandler.NewHealthDetailHandler(
+			dbpkg.NewHealth(sqlDB, constants.HealthDetailVersionTTL, constants.HealthDetailQueryTimeout), log),
+		// Database and driver versions are only for operators holding an API key
+		HealthDetailAuth: middleware.AuthConfig{APIKeys: gatewayKeys},
+		Logger:           log,
 	})
 
-	return &App{
+	app := &App{
 		config:        cfg,
 		logger:        log,
 		db:            db,
 		grpcServer:    grpcServer,
+		httpServer:    httpServer,
 		metricsServer: metricsServer,
-	}, nil
+		metrics:       appMetrics,
+
+		reassignWorker:       reassignWorker,
+		metricsSummaryWorker: metricsSummaryWorker,
+		escalationWorker:     escalationWorker,
+		throttledNotifier:    throttledNotifier,
+		auditFile:            auditFile,
+
+		lifecycle: NewLifecycle(log),
+	}
+	app.registerShutdown()
+
+	return app, nil
+}
+
+// registerShutdown registers how each component is stopped. Register new components here in the
+// phase after everything that still depends on them.
+func (a *App) registerShutdown() {
+	a.lifecycle.OnShutdown(PhaseStopIntake, "grpc health", func(context.Context) error {
+		a.grpcServer.SetNotServing()
+		return nil
+	})
+
+	// REST requests are proxied to gRPC, so the gateway drains first
+	a.lifecycle.OnShutdownWithin(PhaseDrainGateway, "http gateway", a.config.Server.HTTPShutdownTimeout, func(ctx context.Context) error {
+		return a.httpServer.Shutdown(ctx)
+	})
+
+	a.lifecycle.OnShutdownWithin(PhaseDrainGRPC, "grpc server", a.config.Server.GRPCShutdownTimeout, func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			a.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-ctx.Done():
+			a.logger.Warn("gRPC shutdown timeout exceeded, forcing stop")
+			a.grpcServer.Stop()
+		case <-stopped:
+			// Graceful stop completed
+		}
+		return nil
+	})
+
+	if a.reassignWorker != nil {
+		a.lifecycle.OnShutdown(PhaseStopWorkers, "reassign worker", func(ctx context.Context) error {
+			// Workers only run once Run has started them
+			if a.stopWorkers == nil {
+				return nil
+			}
+			a.stopWorkers()
+			select {
+			case <-a.reassignWorker.Done():
+			case <-ctx.Done():
+				a.logger.Warn("Reassign worker did not stop before shutdown timeout")
+			}
+			return nil
+		})
+	}
+
+	if a.metricsSummaryWorker != nil {
+		a.lifecycle.OnShutdown(PhaseStopWorkers, "metrics summary worker", func(ctx context.Context) error {
+			if a.stopWorkers == nil {
+				return nil
+			}
+			a.stopWorkers()
+			select {
+			case <-a.metricsSummaryWorker.Done():
+			case <-ctx.Done():
+				a.logger.Warn("Metrics summary worker did not stop before shutdown timeout")
+			}
+			return nil
+		})
+	}
+
+	if a.escalationWorker != nil {
+		a.lifecycle.OnShutdown(PhaseStopWorkers, "priority escalation worker", func(ctx context.Context) error {
+			if a.stopWorkers == nil {
+				return nil
+			}
+			a.stopWorkers()
+			select {
+			case <-a.escalationWorker.Done():
+			case <-ctx.Done():
+				a.logger.Warn("Priority escalation worker did not stop before shutdown timeout")
+			}
+			return nil
+		})
+	}
+
+	// Send status changes still held back by the throttle, including any from the workers
+	if a.throttledNotifier != nil {
+		a.lifecycle.OnShutdown(PhaseStopWorkers, "notifier", func(context.Context) error {
+			a.throttledNotifier.Close()
+			return nil
+		})
+	}
+
+	// Metrics stay scrapeable while everything else drains
+	a.lifecycle.OnShutdownWithin(PhaseCloseResources, "metrics server", a.config.Server.MetricsShutdownTimeout, func(ctx context.Context) error {
+		return a.metricsServer.Shutdown(ctx)
+	})
+
+	a.lifecycle.OnShutdown(PhaseCloseResources, "database", func(context.Context) error {
+		return dbpkg.Close(a.db)
+	})
+
+	if a.auditFile != nil {
+		a.lifecycle.OnShutdown(PhaseCloseResources, "audit log", func(context.Context) error {
+			return a.auditFile.Close()
+		})
+	}
 }
 
 // Run starts all servers and blocks until shutdown signal is received
@@ -107,6 +429,26 @@ func (a *App) Run() error {
 		}
 	}()
 
+	// Start HTTP gateway in background
+	go func() {
+		if err := a.httpServer.Start(); err != nil {
+			a.logger.Error("HTTP gateway error", zap.Error(err))
+		}
+	}()
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	a.stopWorkers = stopWorkers
+	if a.reassignWorker != nil {
+		go a.reassignWorker.Start(workerCtx)
+	}
+	if a.metricsSummaryWorker != nil {
+		go a.metricsSummaryWorker.Start(workerCtx)
+	}
+	if a.escalationWorker != nil {
+		go a.escalationWorker.Start(workerCtx)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -118,44 +460,54 @@ func (a *App) Run() error {
 	return a.Shutdown()
 }
 
-// Shutdown gracefully shuts down all servers and closes resources
+// Shutdown gracefully shuts down all servers and closes resources in dependency order
+// (see registerShutdown). ShutdownTimeout bounds the whole shutdown; within it the gateway, gRPC
+// and metrics servers are each capped by their own timeout.
 func (a *App) Shutdown() error {
+	a.logInFlightRequests()
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.Server.ShutdownTimeout)
 	defer cancel()
 
-	// Shutdown gRPC server with timeout
-	stopped := make(chan struct{})
-	go func() {
-		a.grpcServer.GracefulStop()
-		close(stopped)
-	}()
+	err := a.lifecycle.Shutdown(shutdownCtx)
 
-	select {
-	case <-shutdownCtx.Done():
-		a.logger.Warn("Shutdown timeout exceeded, forcing stop")
-		a.grpcServer.Stop()
-	case <-stopped:
-		// Graceful stop completed
-	}
+	// Sync logger; errors on stderr are common on some systems and are ignored
+	_ = a.logger.Sync()
+
+	return err
+}
 
-	// Shutdown metrics server
-	metricsCtx, metricsCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer metricsCancel()
-	if err := a.metricsServer.Shutdown(metricsCtx); err != nil {
-		a.logger.Error("Failed to shutdown metrics server", zap.Error(err))
+// logInFlightRequests logs which methods still have active requests to help diagnose slow-draining shutdowns
+func (a *App) logInFlightRequests() {
+	inFlight := a.metrics.ActiveRequestsSnapshot()
+	if len(inFlight) == 0 {
+		a.logger.Info("No in-flight requests at shutdown")
+		return
 	}
 
-	// Close database connection
-	if err := dbpkg.Close(a.db); err != nil {
-		a.logger.Error("Failed to close database connection", zap.Error(err))
-		return err
+	total := 0
+	for _, count := range inFlight {
+		total += count
 	}
 
-	// Sync logger
-	if err := a.logger.Sync(); err != nil {
-		// Ignore sync errors on stderr (common on some systems)
-		return nil
+	a.logger.Warn("Requests still in flight at shutdown",
+		zap.Int("total", total),
+		zap.Any("by_method", inFlight),
+	)
+}
+
+// newNotifier builds the status-change notifier: every change is logged, throttled per delivery
+// when throttle is set, and recipients are notified of the statuses they opted into. The recipient
+// filter sits outside the throttle, so a wanted status is never coalesced into an unwanted one.
+// The returned ThrottledNotifier, nil without throttling, must be closed on shutdown.
+func newNotifier(log *zap.Logger, throttle time.Duration) (service.Notifier, *service.ThrottledNotifier) {
+	notifier := service.NewLogNotifier(log)
+	var throttledNotifier *service.ThrottledNotifier
+	if throttle > 0 {
+		throttledNotifier = service.NewThrottledNotifier(notifier, throttle)
+		notifier = throttledNotifier
 	}
 
-	return nil
+	recipients := service.NewRecipientNotifier(service.NewRecipientLogNotifier(log))
+	return service.NewFanoutNotifier(notifier, recipients), throttledNotifier
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+func TestNewNotifier_NotifiesRecipientsOfWantedStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	notifier, throttled := newNotifier(zap.New(core), time.Hour)
+	require.NotNil(t, throttled)
+	defer throttled.Close()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Notifier = notifier
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	email := "jane@example.com"
+	existing := &domain.DeliveryAssignment{
+		ID:             uuid.New(),
+		OrderID:        "ORDER-123",
+		Status:         domain.DeliveryStatusPending,
+		RecipientEmail: &email,
+		NotifyPrefs:    domain.NotifyPrefs{domain.DeliveryStatusCancelled},
+	}
+	ctx := context.Background()
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetByIDForUpdate(ctx, existing.ID).Return(existing, nil).Times(2)
+	mockRepo.EXPECT().Update(ctx, existing).Return(nil).Times(2)
+
+	// The recipient opted out of ASSIGNED; the throttle holds it back from the log notifier, but it
+	// must not coalesce it into the CANCELLED the recipient asked for
+	driverID := "DRIVER-1"
+	existing.DriverID = &driverID
+	_, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusAssigned, "", "", "")
+	require.NoError(t, err)
+	_, err = uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusCancelled, "", "", "")
+	require.NoError(t, err)
+
+	sent := logs.FilterMessage("Notifying recipient").All()
+	require.Len(t, sent, 1)
+	assert.Equal(t, string(domain.DeliveryStatusCancelled), sent[0].ContextMap()["status"])
+	assert.Equal(t, existing.ID.String(), sent[0].ContextMap()["id"])
+	for _, entry := range logs.All() {
+		for _, value := range entry.ContextMap() {
+			assert.NotEqual(t, email, value, "the address is not logged")
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	"github.com/mohamadchoker/order-delivery-service/pkg/metrics"
+)
+
+// EscalationWorker periodically raises the priority of deliveries that have been waiting in
+// PENDING for too long, so dispatchers pick them up first
+type EscalationWorker struct {
+	useCase     service.DeliveryUseCase
+	interval    time.Duration
+	escalations []domain.PriorityEscalation
+	clock       domain.Clock
+	metrics     *metrics.Metrics
+	logger      *zap.Logger
+	done        chan struct{}
+}
+
+// EscalationConfig holds configuration for the priority escalation worker
+type EscalationConfig struct {
+	Interval    time.Duration
+	Escalations []domain.PriorityEscalation // Applied in order on every pass
+	Clock       domain.Clock                // Defaults to domain.SystemClock
+	Metrics     *metrics.Metrics            // nil records nothing
+	Logger      *zap.Logger
+}
+
+// NewEscalationWorker creates a new priority escalation worker
+func NewEscalationWorker(cfg EscalationConfig, useCase service.DeliveryUseCase) *EscalationWorker {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = domain.SystemClock
+	}
+
+	return &EscalationWorker{
+		useCase:     useCase,
+		interval:    cfg.Interval,
+		escalations: cfg.Escalations,
+		clock:       clock,
+		metrics:     cfg.Metrics,
+		logger:      cfg.Logger,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs the worker until ctx is canceled (blocking)
+func (w *EscalationWorker) Start(ctx context.Context) {
+	defer close(w.done)
+
+	w.logger.Info("Priority escalation worker started",
+		zap.Duration("interval", w.interval),
+		zap.Int("rules", len(w.escalations)),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Priority escalation worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// Done is closed once Start has returned
+func (w *EscalationWorker) Done() <-chan struct{} {
+	return w.done
+}
+
+// RunOnce applies every escalation rule to the PENDING deliveries that have waited past its
+// threshold and returns how many were escalated. Rules run in order, so a delivery that has
+// waited long enough may be escalated more than once in the same pass.
+func (w *EscalationWorker) RunOnce(ctx context.Context) int {
+	now := w.clock.Now()
+
+	total := 0
+	for _, rule := range w.escalations {
+		escalated, err := w.useCase.EscalatePendingPriority(ctx, rule.From, rule.To, now.Add(-rule.After))
+		if err != nil {
+			w.logger.Error("Failed to escalate waiting deliveries",
+				zap.Error(err),
+				zap.String("from", string(rule.From)),
+				zap.String("to", string(rule.To)),
+			)
+			continue
+		}
+
+		for _, assignment := range escalated {
+			w.metrics.RecordDeliveryOperation(constants.OpEscalate, string(assignment.Status))
+			w.logger.Info("Delivery priority escalated after waiting in PENDING",
+				zap.String("event", constants.OpEscalate),
+				zap.String("id", assignment.ID.String()),
+				zap.String("order_id", assignment.OrderID),
+				zap.String("from", string(rule.From)),
+				zap.String("to", string(assignment.Priority)),
+				zap.Time("created_at", assignment.CreatedAt),
+			)
+		}
+		total += len(escalated)
+	}
+
+	return total
+}
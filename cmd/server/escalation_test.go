@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// escalationRecorder collects the status changes sent to the notifier
+type escalationRecorder struct {
+	changes []service.StatusChange
+}
+
+func (r *escalationRecorder) NotifyStatusChange(_ context.Context, change service.StatusChange) {
+	r.changes = append(r.changes, change)
+}
+
+func TestEscalationWorker_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := fakeClock{now: now}
+	recorder := &escalationRecorder{}
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	cfg := service.DefaultConfig()
+	cfg.Clock = clock
+	cfg.Notifier = recorder
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	escalations, err := domain.ParsePriorityEscalations("LOW:NORMAL:1h,NORMAL:HIGH:2h")
+	require.NoError(t, err)
+
+	// Seeded PENDING deliveries that have waited past each threshold, as the repository
+	// returns them once escalated
+	waitingLow := &domain.DeliveryAssignment{
+		ID:        uuid.New(),
+		OrderID:   "ORDER-1",
+		Status:    domain.DeliveryStatusPending,
+		Priority:  domain.PriorityNormal,
+		CreatedAt: now.Add(-90 * time.Minute),
+		UpdatedAt: now,
+	}
+	waitingNormal := &domain.DeliveryAssignment{
+		ID:        uuid.New(),
+		OrderID:   "ORDER-2",
+		Status:    domain.DeliveryStatusPending,
+		Priority:  domain.PriorityHigh,
+		CreatedAt: now.Add(-3 * time.Hour),
+		UpdatedAt: now,
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().
+			EscalateWaitingPending(gomock.Any(), domain.PriorityLow, domain.PriorityNormal, now.Add(-time.Hour), now, constants.EscalationBatchSize).
+			Return([]*domain.DeliveryAssignment{waitingLow}, nil),
+		mockRepo.EXPECT().
+			EscalateWaitingPending(gomock.Any(), domain.PriorityNormal, domain.PriorityHigh, now.Add(-2*time.Hour), now, constants.EscalationBatchSize).
+			Return([]*domain.DeliveryAssignment{waitingNormal}, nil),
+	)
+
+	worker := NewEscalationWorker(EscalationConfig{
+		Interval:    time.Minute,
+		Escalations: escalations,
+		Clock:       clock,
+		Logger:      logger,
+	}, uc)
+
+	count := worker.RunOnce(context.Background())
+
+	assert.Equal(t, 2, count)
+
+	// Each escalation is emitted as an event, with the status unchanged
+	require.Len(t, recorder.changes, 2)
+	for i, assignment := range []*domain.DeliveryAssignment{waitingLow, waitingNormal} {
+		change := recorder.changes[i]
+		assert.Equal(t, assignment.ID, change.DeliveryID)
+		assert.Equal(t, domain.DeliveryStatusPending, change.Status)
+		assert.Equal(t, assignment.Priority, change.Priority)
+		assert.Equal(t, now, change.ChangedAt)
+		assert.True(t, change.PriorityEscalated)
+	}
+}
+
+func TestEscalationWorker_NothingWaiting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	recorder := &escalationRecorder{}
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	cfg := service.DefaultConfig()
+	cfg.Clock = fakeClock{now: now}
+	cfg.Notifier = recorder
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	// Deliveries claimed or escalated since they were created no longer match the update
+	mockRepo.EXPECT().
+		EscalateWaitingPending(gomock.Any(), domain.PriorityLow, domain.PriorityNormal, now.Add(-time.Hour), now, constants.EscalationBatchSize).
+		Return([]*domain.DeliveryAssignment{}, nil)
+
+	worker := NewEscalationWorker(EscalationConfig{
+		Interval:    time.Minute,
+		Escalations: []domain.PriorityEscalation{{From: domain.PriorityLow, To: domain.PriorityNormal, After: time.Hour}},
+		Clock:       fakeClock{now: now},
+		Logger:      logger,
+	}, uc)
+
+	require.Zero(t, worker.RunOnce(context.Background()))
+	assert.Empty(t, recorder.changes)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -11,6 +12,9 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	grpchandler "github.com/mohamadchoker/order-delivery-service/internal/transport/grpc"
 	"github.com/mohamadchoker/order-delivery-service/pkg/metrics"
 	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 	pb "github.com/mohamadchoker/order-delivery-service/proto"
@@ -28,6 +32,11 @@ type GRPCServer struct {
 type GRPCConfig struct {
 	Port           int
 	RequestTimeout time.Duration
+	AdminAPIKeys   []string                // Keys allowed to call grpchandler.AdminMethods; empty refuses them
+	APIKeyActors   map[string]domain.Actor // Caller recorded for database auditing per API key
+	RequestID      middleware.RequestIDConfig
+	Logging        middleware.LoggingConfig
+	Metrics        *metrics.Metrics // nil records nothing
 	Logger         *zap.Logger
 }
 
@@ -42,10 +51,21 @@ func NewGRPCServer(cfg GRPCConfig, handler pb.DeliveryServiceServer) (*GRPCServe
 	// Create gRPC server with middleware chain
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
-			middleware.RequestIDUnaryInterceptor(),
+			middleware.RequestIDUnaryInterceptorWithConfig(cfg.RequestID),
+			middleware.TraceIDUnaryInterceptorWithConfig(cfg.RequestID),
+			middleware.APIVersionUnaryInterceptor(strings.Split(constants.SupportedAPIVersions, ",")),
+			middleware.AdminUnaryInterceptor(cfg.AdminAPIKeys, grpchandler.AdminMethods()),
+			middleware.ActorUnaryInterceptor(cfg.APIKeyActors),
 			middleware.TimeoutUnaryInterceptor(cfg.RequestTimeout),
-			metrics.MetricsUnaryInterceptor(),
-			middleware.LoggingUnaryInterceptor(cfg.Logger),
+			cfg.Metrics.UnaryInterceptor(),
+			cfg.Metrics.SLOUnaryInterceptor(),
+			middleware.RetryAfterUnaryInterceptor(),
+			middleware.LoggingUnaryInterceptorWithConfig(cfg.Logger, cfg.Logging),
+			middleware.ValidationUnaryInterceptor(grpchandler.RequiredFields()),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.RequestIDStreamInterceptorWithConfig(cfg.RequestID),
+			middleware.TraceIDStreamInterceptorWithConfig(cfg.RequestID),
 		),
 	)
 
@@ -74,6 +94,11 @@ func (s *GRPCServer) Start() error {
 	return s.server.Serve(s.listener)
 }
 
+// SetNotServing reports NOT_SERVING on the health service so load balancers stop sending traffic
+func (s *GRPCServer) SetNotServing() {
+	s.healthServer.Shutdown()
+}
+
 // GracefulStop gracefully stops the gRPC server
 func (s *GRPCServer) GracefulStop() {
 	s.logger.Info("Stopping gRPC server gracefully...")
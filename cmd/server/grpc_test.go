@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+// deadlineRecorder records the deadline each GetDeliveryAssignment call runs under
+type deadlineRecorder struct {
+	pb.UnimplementedDeliveryServiceServer
+	deadlines chan time.Duration
+}
+
+func (d *deadlineRecorder) GetDeliveryAssignment(ctx context.Context, _ *pb.GetDeliveryAssignmentRequest) (*pb.DeliveryAssignment, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		d.deadlines <- 0
+	} else {
+		d.deadlines <- time.Until(deadline)
+	}
+	return &pb.DeliveryAssignment{}, nil
+}
+
+func TestNewGRPCServer_RequestTimeout(t *testing.T) {
+	const timeout = 2 * time.Second
+	recorder := &deadlineRecorder{deadlines: make(chan time.Duration, 1)}
+
+	server, err := NewGRPCServer(GRPCConfig{
+		Port:           0,
+		RequestTimeout: timeout,
+		Logger:         zap.NewNop(),
+	}, recorder)
+	require.NoError(t, err)
+	go func() { _ = server.Start() }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The client deadline is longer, so the one the handler sees comes from the server config
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = pb.NewDeliveryServiceClient(conn).GetDeliveryAssignment(ctx, &pb.GetDeliveryAssignmentRequest{Id: uuid.NewString()})
+	require.NoError(t, err)
+
+	remaining := <-recorder.deadlines
+	assert.LessOrEqual(t, remaining, timeout)
+	assert.Greater(t, remaining, timeout-time.Second)
+}
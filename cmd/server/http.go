@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	httphandler "github.com/mohamadchoker/order-delivery-service/internal/transport/http"
 	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 	pb "github.com/mohamadchoker/order-delivery-service/proto"
 )
@@ -24,23 +30,59 @@ type HTTPServer struct {
 type HTTPConfig struct {
 	Port     int
 	GRPCPort int
+	CORS     middleware.CORSConfig
+	Auth     middleware.AuthConfig
 	Logger   *zap.Logger
+
+	// RequestID decides which X-Request-ID headers from callers are kept
+	RequestID middleware.RequestIDConfig
+
+	// RedactUnauthenticated serves requests without a valid API key with Redactor's fields redacted
+	RedactUnauthenticated bool
+	Redactor              *middleware.Redactor
+
+	// MetricsExport serves the metrics CSV download; nil leaves it unmounted
+	MetricsExport http.Handler
 }
 
 // NewHTTPServer creates and configures a new HTTP gateway server
 func NewHTTPServer(ctx context.Context, cfg HTTPConfig) (*HTTPServer, error) {
-	// Create gRPC-Gateway mux
-	gwMux := runtime.NewServeMux()
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-
-	// Register gateway handlers
 	grpcAddress := fmt.Sprintf("localhost:%d", cfg.GRPCPort)
-	if err := pb.RegisterDeliveryServiceHandlerFromEndpoint(ctx, gwMux, grpcAddress, opts); err != nil {
-		return nil, fmt.Errorf("failed to register gateway: %w", err)
+	gwMux, err := newGatewayMux(ctx, grpcAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	// Wrap with HTTP logging middleware
-	httpHandler := middleware.HTTPLoggingMiddleware(cfg.Logger)(gwMux)
+	var httpHandler http.Handler = gwMux
+	if cfg.RedactUnauthenticated && cfg.Redactor.Enabled() {
+		// Same routes, but every marshaled response passes through the redactor
+		redactedMux, err := newGatewayMux(ctx, grpcAddress, runtime.WithMarshalerOption(runtime.MIMEWildcard,
+			&middleware.RedactingMarshaler{Marshaler: defaultGatewayMarshaler(), Redactor: cfg.Redactor}))
+		if err != nil {
+			return nil, err
+		}
+		httpHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if middleware.IsAuthenticated(r.Context()) {
+				gwMux.ServeHTTP(w, r)
+				return
+			}
+			redactedMux.ServeHTTP(w, r)
+		})
+	}
+
+	// Plain HTTP endpoints share the gateway's port and middleware
+	if cfg.MetricsExport != nil {
+		mux := http.NewServeMux()
+		mux.Handle(httphandler.MetricsExportPath, cfg.MetricsExport)
+		mux.Handle("/", httpHandler)
+		httpHandler = mux
+	}
+
+	// Wrap with auth, CORS and HTTP logging middleware (logging outermost so preflights are logged,
+	// CORS outside auth so preflights are answered without a key)
+	httpHandler = middleware.APIKeyAuthMiddleware(cfg.Auth)(httpHandler)
+	httpHandler = middleware.CORSMiddleware(cfg.CORS)(httpHandler)
+	httpHandler = middleware.HTTPLoggingMiddlewareWithConfig(cfg.Logger, cfg.RequestID)(httpHandler)
 
 	// Create HTTP server
 	httpServer := &http.Server{
@@ -54,6 +96,72 @@ func NewHTTPServer(ctx context.Context, cfg HTTPConfig) (*HTTPServer, error) {
 	}, nil
 }
 
+// newGatewayMux creates a gRPC-Gateway mux proxying to grpcAddress, forwarding the API key so
+// gRPC handlers can see the caller
+func newGatewayMux(ctx context.Context, grpcAddress string, opts ...runtime.ServeMuxOption) (*runtime.ServeMux, error) {
+	opts = append([]runtime.ServeMuxOption{
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+	}, opts...)
+	gwMux := runtime.NewServeMux(opts...)
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	// Register gateway handlers
+	if err := pb.RegisterDeliveryServiceHandlerFromEndpoint(ctx, gwMux, grpcAddress, dialOpts); err != nil {
+		return nil, fmt.Errorf("failed to register gateway: %w", err)
+	}
+	return gwMux, nil
+}
+
+// gatewayHTTPStatus overrides the gateway's default HTTP status for the gRPC codes where it does
+// not fit our API. Codes not listed keep the grpc-gateway mapping (see runtime.HTTPStatusFromCode).
+//
+//	gRPC code           default  served  returned for
+//	FailedPrecondition  400      409     invalid status transitions, unsupported API versions
+var gatewayHTTPStatus = map[codes.Code]int{
+	codes.FailedPrecondition: http.StatusConflict,
+}
+
+// gatewayErrorHandler writes errors like runtime.DefaultHTTPErrorHandler, with the HTTP status
+// taken from gatewayHTTPStatus when the gRPC code is listed there
+func gatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if httpStatus, ok := gatewayHTTPStatus[status.Code(err)]; ok {
+		err = &runtime.HTTPStatusError{HTTPStatus: httpStatus, Err: err}
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// defaultGatewayMarshaler mirrors the gateway's built-in JSON marshaler
+func defaultGatewayMarshaler() runtime.Marshaler {
+	return &runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		},
+	}
+}
+
+// incomingHeaderMatcher forwards the API key, API version and trace ID headers as gRPC metadata on
+// top of the gateway defaults. The caller's identity is derived from the API key on the gRPC side.
+func incomingHeaderMatcher(key string) (string, bool) {
+	for _, forwarded := range []string{constants.APIKeyHeader, constants.APIVersionHeader, constants.TraceIDHeader} {
+		if strings.EqualFold(key, forwarded) {
+			return strings.ToLower(key), true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher returns the retry-after metadata as the standard Retry-After header and
+// other metadata with the gateway's default Grpc-Metadata- prefix
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, constants.RetryAfterHeader) {
+		return constants.RetryAfterHeader, true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
 // Start starts the HTTP gateway server (blocking)
 func (s *HTTPServer) Start() error {
 	s.logger.Info("HTTP gateway listening", zap.String("address", s.server.Addr))
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	grpchandler "github.com/mohamadchoker/order-delivery-service/internal/transport/grpc"
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+func TestGateway_InvalidStatusTransitionIsConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.New()
+	mockUseCase := mocks.NewMockDeliveryUseCase(ctrl)
+	mockUseCase.EXPECT().UpdateDeliveryStatus(gomock.Any(), id, domain.DeliveryStatusDelivered, "", "", "").
+		Return(nil, domain.ErrInvalidStatusTransition)
+
+	server, err := NewGRPCServer(GRPCConfig{
+		Port:           0,
+		RequestTimeout: time.Minute,
+		Logger:         zap.NewNop(),
+	}, grpchandler.NewHandler(mockUseCase, zap.NewNop()))
+	require.NoError(t, err)
+	go func() { _ = server.Start() }()
+	t.Cleanup(server.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gwMux, err := newGatewayMux(ctx, server.listener.Addr().String())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/v1/deliveries/"+id.String()+"/status",
+		strings.NewReader(`{"status": "DELIVERED"}`))
+	rec := httptest.NewRecorder()
+	gwMux.ServeHTTP(rec, req)
+
+	// FailedPrecondition would be 400 by default
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid status transition")
+}
+
+func TestRateLimitedResponse_RetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// As returned by the metrics query limiter once it sheds a call
+	limited := &domain.RetryAfterError{
+		RetryAfter: 2500 * time.Millisecond,
+		Err:        fmt.Errorf("%w: too many concurrent metrics queries (limit 1)", domain.ErrResourceExhausted),
+	}
+	mockUseCase := mocks.NewMockDeliveryUseCase(ctrl)
+	mockUseCase.EXPECT().GetDeliveryMetrics(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, limited).Times(2)
+
+	server, err := NewGRPCServer(GRPCConfig{
+		Port:           0,
+		RequestTimeout: time.Minute,
+		Logger:         zap.NewNop(),
+	}, grpchandler.NewHandler(mockUseCase, zap.NewNop()))
+	require.NoError(t, err)
+	go func() { _ = server.Start() }()
+	t.Cleanup(server.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("gRPC", func(t *testing.T) {
+		conn, err := grpc.NewClient(server.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		var header metadata.MD
+		_, err = pb.NewDeliveryServiceClient(conn).GetDeliveryMetrics(ctx, &pb.GetDeliveryMetricsRequest{}, grpc.Header(&header))
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, []string{"3"}, header.Get("retry-after"), "rounded up to whole seconds")
+	})
+
+	t.Run("HTTP gateway", func(t *testing.T) {
+		gwMux, err := newGatewayMux(ctx, server.listener.Addr().String())
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		gwMux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/deliveries/metrics", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+	})
+}
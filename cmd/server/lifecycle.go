@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ShutdownPhase orders shutdown steps: every step of a phase finishes before the next phase
+// starts, so a component is stopped only after everything that depends on it
+type ShutdownPhase int
+
+const (
+	// PhaseStopIntake tells load balancers to stop routing new work here
+	PhaseStopIntake ShutdownPhase = iota
+	// PhaseDrainGateway drains REST traffic, which is proxied to gRPC
+	PhaseDrainGateway
+	// PhaseDrainGRPC drains in-flight gRPC requests
+	PhaseDrainGRPC
+	// PhaseStopWorkers stops background work that still uses the database
+	PhaseStopWorkers
+	// PhaseCloseResources releases what everything above depended on (metrics, database)
+	PhaseCloseResources
+)
+
+// shutdownStep is one registered component shutdown
+type shutdownStep struct {
+	phase   ShutdownPhase
+	name    string
+	timeout time.Duration // Cap on the step within the overall shutdown deadline; 0 is no cap
+	stop    func(ctx context.Context) error
+}
+
+// Lifecycle runs the shutdown steps components register, in phase order.
+// Steps in the same phase run in registration order.
+type Lifecycle struct {
+	logger *zap.Logger
+	steps  []shutdownStep
+}
+
+// NewLifecycle creates an empty lifecycle manager
+func NewLifecycle(logger *zap.Logger) *Lifecycle {
+	return &Lifecycle{logger: logger}
+}
+
+// OnShutdown registers stop to run during phase
+func (l *Lifecycle) OnShutdown(phase ShutdownPhase, name string, stop func(ctx context.Context) error) {
+	l.steps = append(l.steps, shutdownStep{phase: phase, name: name, stop: stop})
+}
+
+// OnShutdownWithin registers stop to run during phase, given at most timeout. The step never runs
+// past the overall shutdown deadline, so a slow step leaves less time for the steps after it.
+func (l *Lifecycle) OnShutdownWithin(phase ShutdownPhase, name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	l.steps = append(l.steps, shutdownStep{phase: phase, name: name, timeout: timeout, stop: stop})
+}
+
+// ordered returns the registered steps in the order Shutdown runs them
+func (l *Lifecycle) ordered() []shutdownStep {
+	steps := make([]shutdownStep, len(l.steps))
+	copy(steps, l.steps)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].phase < steps[j].phase })
+	return steps
+}
+
+// Shutdown runs every step in order. A failing step is logged and does not prevent the rest
+// from running; all failures are returned joined.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, step := range l.ordered() {
+		if err := step.run(ctx); err != nil {
+			l.logger.Error("Shutdown step failed", zap.String("step", step.name), zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run calls stop with the shutdown context, capped at the step's timeout if it has one
+func (s shutdownStep) run(ctx context.Context) error {
+	if s.timeout <= 0 {
+		return s.stop(ctx)
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.stop(stepCtx)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/config"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+func TestLifecycle_RunsStepsInPhaseOrder(t *testing.T) {
+	lifecycle := NewLifecycle(zap.NewNop())
+
+	var ran []string
+	step := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+
+	// Registered out of order on purpose
+	lifecycle.OnShutdown(PhaseCloseResources, "database", step("database", nil))
+	lifecycle.OnShutdown(PhaseStopWorkers, "worker", step("worker", errors.New("worker stuck")))
+	lifecycle.OnShutdown(PhaseDrainGRPC, "grpc", step("grpc", nil))
+	lifecycle.OnShutdown(PhaseStopIntake, "health", step("health", nil))
+	lifecycle.OnShutdown(PhaseDrainGateway, "gateway", step("gateway", nil))
+	lifecycle.OnShutdown(PhaseCloseResources, "metrics", step("metrics", nil))
+
+	err := lifecycle.Shutdown(context.Background())
+
+	assert.Equal(t, []string{"health", "gateway", "grpc", "worker", "database", "metrics"}, ran,
+		"phases in order, registration order within a phase, and a failure does not stop later steps")
+	assert.ErrorContains(t, err, "worker stuck")
+}
+
+func TestApp_ShutdownOrder(t *testing.T) {
+	app := &App{
+		config: &config.Config{Server: config.ServerConfig{
+			GRPCShutdownTimeout:    20 * time.Second,
+			HTTPShutdownTimeout:    10 * time.Second,
+			MetricsShutdownTimeout: 5 * time.Second,
+		}},
+		logger:            zap.NewNop(),
+		reassignWorker:    &ReassignWorker{},
+		throttledNotifier: service.NewThrottledNotifier(service.NewLogNotifier(zap.NewNop()), 0),
+		lifecycle:         NewLifecycle(zap.NewNop()),
+	}
+	app.registerShutdown()
+
+	var order []string
+	timeouts := make(map[string]time.Duration)
+	for _, step := range app.lifecycle.ordered() {
+		order = append(order, step.name)
+		timeouts[step.name] = step.timeout
+	}
+
+	// Stop accepting new work, drain the gateway, drain gRPC, stop workers, then close the database
+	assert.Equal(t, []string{
+		"grpc health",
+		"http gateway",
+		"grpc server",
+		"reassign worker",
+		"notifier",
+		"metrics server",
+		"database",
+	}, order)
+
+	// The servers are each capped by their configured timeout; all share the shutdown deadline
+	assert.Equal(t, map[string]time.Duration{
+		"grpc health":     0,
+		"http gateway":    10 * time.Second,
+		"grpc server":     20 * time.Second,
+		"reassign worker": 0,
+		"notifier":        0,
+		"metrics server":  5 * time.Second,
+		"database":        0,
+	}, timeouts)
+}
+
+func TestLifecycle_StepTimeouts(t *testing.T) {
+	lifecycle := NewLifecycle(zap.NewNop())
+
+	// Each stub stops as soon as its context ends and records how long it was given
+	waited := make(map[string]time.Duration)
+	stub := func(name string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			start := time.Now()
+			<-ctx.Done()
+			waited[name] = time.Since(start)
+			return nil
+		}
+	}
+
+	lifecycle.OnShutdownWithin(PhaseDrainGateway, "http gateway", 20*time.Millisecond, stub("http gateway"))
+	lifecycle.OnShutdownWithin(PhaseDrainGRPC, "grpc server", 60*time.Millisecond, stub("grpc server"))
+	lifecycle.OnShutdown(PhaseStopWorkers, "worker", stub("worker"))
+	lifecycle.OnShutdownWithin(PhaseCloseResources, "metrics server", 40*time.Millisecond, stub("metrics server"))
+
+	// The overall deadline runs out while gRPC drains
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.NoError(t, lifecycle.Shutdown(ctx))
+
+	// The gateway stops at its own cap, and gRPC at the overall deadline before reaching its cap
+	assert.GreaterOrEqual(t, waited["http gateway"], 20*time.Millisecond)
+	assert.Less(t, waited["http gateway"], 50*time.Millisecond)
+	assert.Less(t, waited["grpc server"], 60*time.Millisecond)
+	// Once the deadline has passed the remaining steps get nothing, whatever their own cap
+	assert.Less(t, waited["worker"], 10*time.Millisecond)
+	assert.Less(t, waited["metrics server"], 10*time.Millisecond)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "shutdown ends at the overall deadline")
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	_ "time/tzdata" // Embed the IANA database; the runtime image has no zoneinfo
+
+	dbpkg "github.com/mohamadchoker/order-delivery-service/pkg/postgres"
 )
 
 // Version information - set via ldflags during build
@@ -13,6 +17,15 @@ var (
 )
 
 func main() {
+	// "server migrate up|status" manages the schema and exits without serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(context.Background(), os.Args[2:], os.Stdout, dbpkg.Connect); err != nil {
+			fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create and initialize application
 	application, err := NewApp(version, buildDate, gitCommit)
 	if err != nil {
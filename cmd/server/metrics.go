@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+
+	httphandler "github.com/mohamadchoker/order-delivery-service/internal/transport/http"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 )
 
 // MetricsServer wraps the Prometheus metrics HTTP server
@@ -17,15 +21,27 @@ type MetricsServer struct {
 
 // MetricsConfig holds configuration for the metrics server
 type MetricsConfig struct {
-	Port   int
-	Logger *zap.Logger
+	Port     int
+	Gatherer prometheus.Gatherer // Registry the service's metrics are registered with
+	Logger   *zap.Logger
+
+	// HealthDetail serves the dependency versions and pool stats; nil leaves it unmounted
+	HealthDetail http.Handler
+	// HealthDetailAuth guards HealthDetail with the API keys the gateway accepts (none = open)
+	HealthDetailAuth middleware.AuthConfig
 }
 
 // NewMetricsServer creates and configures a new metrics server
 func NewMetricsServer(cfg MetricsConfig) *MetricsServer {
+	mux := http.NewServeMux()
+	mux.Handle("/", promhttp.HandlerFor(cfg.Gatherer, promhttp.HandlerOpts{}))
+	if cfg.HealthDetail != nil {
+		mux.Handle(httphandler.HealthDetailPath, middleware.APIKeyAuthMiddleware(cfg.HealthDetailAuth)(cfg.HealthDetail))
+	}
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: promhttp.Handler(),
+		Handler: mux,
 	}
 
 	return &MetricsServer{
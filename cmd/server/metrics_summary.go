@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// MetricsSummaryWorker periodically recomputes the daily metrics summary of recent days. Deliveries
+// keep changing status after the day they were created, so the last few days are refreshed again
+// on every pass.
+type MetricsSummaryWorker struct {
+	useCase      service.DeliveryUseCase
+	interval     time.Duration
+	lookbackDays int
+	clock        domain.Clock
+	logger       *zap.Logger
+	done         chan struct{}
+}
+
+// MetricsSummaryConfig holds configuration for the metrics summary worker
+type MetricsSummaryConfig struct {
+	Interval     time.Duration
+	LookbackDays int          // Completed days refreshed per pass, counting back from yesterday
+	Clock        domain.Clock // Defaults to domain.SystemClock
+	Logger       *zap.Logger
+}
+
+// NewMetricsSummaryWorker creates a new metrics summary worker
+func NewMetricsSummaryWorker(cfg MetricsSummaryConfig, useCase service.DeliveryUseCase) *MetricsSummaryWorker {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = domain.SystemClock
+	}
+
+	return &MetricsSummaryWorker{
+		useCase:      useCase,
+		interval:     cfg.Interval,
+		lookbackDays: cfg.LookbackDays,
+		clock:        clock,
+		logger:       cfg.Logger,
+		done:         make(chan struct{}),
+	}
+}
+
+// Start runs the worker until ctx is canceled (blocking). The first pass runs immediately so a
+// fresh deployment does not wait a whole interval for its summary.
+func (w *MetricsSummaryWorker) Start(ctx context.Context) {
+	defer close(w.done)
+
+	w.logger.Info("Metrics summary worker started",
+		zap.Duration("interval", w.interval),
+		zap.Int("lookback_days", w.lookbackDays),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.RunOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Metrics summary worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// Done is closed once Start has returned
+func (w *MetricsSummaryWorker) Done() <-chan struct{} {
+	return w.done
+}
+
+// RunOnce refreshes the summary of each of the last lookbackDays completed days and returns how
+// many were refreshed. A failed day is logged and the remaining days are still refreshed.
+func (w *MetricsSummaryWorker) RunOnce(ctx context.Context) int {
+	today := w.clock.Now().UTC().Truncate(24 * time.Hour)
+
+	refreshed := 0
+	for i := 1; i <= w.lookbackDays; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		day := today.AddDate(0, 0, -i)
+		if err := w.useCase.RefreshMetricsSummary(ctx, day); err != nil {
+			w.logger.Error("Failed to refresh metrics summary",
+				zap.Error(err),
+				zap.String("day", day.Format(time.DateOnly)),
+			)
+			continue
+		}
+		refreshed++
+	}
+
+	w.logger.Debug("Metrics summary refreshed", zap.Int("days", refreshed))
+	return refreshed
+}
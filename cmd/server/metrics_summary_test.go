@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+func TestMetricsSummaryWorker_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fakeClock{now: now}
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	// Yesterday back; today is never summarized and a failed day does not stop the pass
+	gomock.InOrder(
+		mockRepo.EXPECT().RefreshMetricsSummary(gomock.Any(), time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC), cfg.OnTimeGracePeriod).Return(nil),
+		mockRepo.EXPECT().RefreshMetricsSummary(gomock.Any(), time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC), cfg.OnTimeGracePeriod).Return(errors.New("db down")),
+		mockRepo.EXPECT().RefreshMetricsSummary(gomock.Any(), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), cfg.OnTimeGracePeriod).Return(nil),
+	)
+
+	worker := NewMetricsSummaryWorker(MetricsSummaryConfig{
+		Interval:     time.Hour,
+		LookbackDays: 3,
+		Clock:        fakeClock{now: now},
+		Logger:       zap.NewNop(),
+	}, uc)
+
+	assert.Equal(t, 2, worker.RunOnce(context.Background()))
+}
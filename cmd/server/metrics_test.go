@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	httphandler "github.com/mohamadchoker/order-delivery-service/internal/transport/http"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
+)
+
+func TestMetricsServer_HealthDetailRequiresAPIKey(t *testing.T) {
+	server := NewMetricsServer(MetricsConfig{
+		Port:     9090,
+		Gatherer: prometheus.NewRegistry(),
+		Logger:   zap.NewNop(),
+		HealthDetail: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		HealthDetailAuth: middleware.AuthConfig{APIKeys: []string{"secret"}},
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		apiKey   string
+		expected int
+	}{
+		{name: "detail without key", path: httphandler.HealthDetailPath, expected: http.StatusUnauthorized},
+		{name: "detail with wrong key", path: httphandler.HealthDetailPath, apiKey: "guess", expected: http.StatusUnauthorized},
+		{name: "detail with key", path: httphandler.HealthDetailPath, apiKey: "secret", expected: http.StatusOK},
+		{name: "metrics stay open", path: "/metrics", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set(constants.APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.Handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/config"
+	"github.com/mohamadchoker/order-delivery-service/migrations"
+	dbpkg "github.com/mohamadchoker/order-delivery-service/pkg/postgres"
+)
+
+const migrateUsage = "usage: server migrate <up|status>"
+
+// connectFunc opens the database; replaceable in tests
+type connectFunc func(cfg config.DatabaseConfig) (*gorm.DB, error)
+
+// runMigrateCommand applies or reports the embedded SQL migrations and returns without starting
+// any server, so it can run as an init container before the service is rolled out
+func runMigrateCommand(ctx context.Context, args []string, out io.Writer, connect connectFunc) error {
+	if len(args) != 1 || (args[0] != "up" && args[0] != "status") {
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() { _ = dbpkg.Close(db) }()
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	if args[0] == "up" {
+		applied, err := dbpkg.MigrateUp(ctx, sqlDB, migrations.FS)
+		for _, file := range applied {
+			fmt.Fprintf(out, "applied %s\n", file.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	status, err := dbpkg.GetMigrationStatus(ctx, sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	state := "clean"
+	if status.Dirty {
+		state = "dirty"
+	}
+	fmt.Fprintf(out, "version: %d (%s)\n", status.Version, state)
+	fmt.Fprintf(out, "pending: %d\n", len(status.Pending))
+	for _, file := range status.Pending {
+		fmt.Fprintf(out, "  %s\n", file.Name)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/config"
+)
+
+func TestRunMigrateCommand_Status(t *testing.T) {
+	conn := &versionConn{version: 5}
+	connects := 0
+	connect := func(config.DatabaseConfig) (*gorm.DB, error) {
+		connects++
+		return gorm.Open(pgdriver.New(pgdriver.Config{Conn: sql.OpenDB(versionConnector{conn})}), &gorm.Config{
+			DisableAutomaticPing: true,
+			Logger:               logger.Discard,
+		})
+	}
+
+	var out bytes.Buffer
+	err := runMigrateCommand(context.Background(), []string{"status"}, &out, connect)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, connects)
+	assert.Contains(t, out.String(), "version: 5 (clean)")
+	assert.Contains(t, out.String(), "000006_add_delivery_window.up.sql")
+	assert.NotContains(t, out.String(), "000005_add_delivery_time_zone.up.sql")
+	// status never changes the schema beyond creating the version table
+	for _, stmt := range conn.executed {
+		assert.True(t, strings.HasPrefix(stmt, "CREATE TABLE IF NOT EXISTS schema_migrations"), stmt)
+	}
+}
+
+func TestRunMigrateCommand_Usage(t *testing.T) {
+	connect := func(config.DatabaseConfig) (*gorm.DB, error) {
+		t.Fatal("must not connect for invalid arguments")
+		return nil, nil
+	}
+
+	for _, args := range [][]string{nil, {"down"}, {"up", "extra"}} {
+		err := runMigrateCommand(context.Background(), args, io.Discard, connect)
+		assert.EqualError(t, err, migrateUsage)
+	}
+}
+
+// versionConn is a database connection that only knows the schema_migrations version
+type versionConn struct {
+	version  int64
+	executed []string
+}
+
+type versionConnector struct{ conn *versionConn }
+
+func (c versionConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+
+func (c versionConnector) Driver() driver.Driver { return nil }
+
+func (c *versionConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements not supported")
+}
+
+func (c *versionConn) Close() error { return nil }
+
+func (c *versionConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c *versionConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.executed = append(c.executed, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *versionConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM schema_migrations") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	return &versionRows{version: c.version}, nil
+}
+
+type versionRows struct {
+	version int64
+	done    bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version", "dirty"} }
+
+func (r *versionRows) Close() error { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], dest[1] = r.version, false
+	r.done = true
+	return nil
+}
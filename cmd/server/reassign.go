@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	"github.com/mohamadchoker/order-delivery-service/pkg/metrics"
+)
+
+// ReassignWorker periodically unassigns drivers from deliveries that were never picked up
+// so they can be re-dispatched
+type ReassignWorker struct {
+	useCase     service.DeliveryUseCase
+	interval    time.Duration
+	gracePeriod time.Duration
+	clock       domain.Clock
+	metrics     *metrics.Metrics
+	logger      *zap.Logger
+	done        chan struct{}
+}
+
+// ReassignConfig holds configuration for the reassign worker
+type ReassignConfig struct {
+	Interval    time.Duration
+	GracePeriod time.Duration
+	Clock       domain.Clock     // Defaults to domain.SystemClock
+	Metrics     *metrics.Metrics // nil records nothing
+	Logger      *zap.Logger
+}
+
+// NewReassignWorker creates a new reassign-on-timeout worker
+func NewReassignWorker(cfg ReassignConfig, useCase service.DeliveryUseCase) *ReassignWorker {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = domain.SystemClock
+	}
+
+	return &ReassignWorker{
+		useCase:     useCase,
+		interval:    cfg.Interval,
+		gracePeriod: cfg.GracePeriod,
+		clock:       clock,
+		metrics:     cfg.Metrics,
+		logger:      cfg.Logger,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs the worker until ctx is canceled (blocking)
+func (w *ReassignWorker) Start(ctx context.Context) {
+	defer close(w.done)
+
+	w.logger.Info("Reassign worker started",
+		zap.Duration("interval", w.interval),
+		zap.Duration("grace_period", w.gracePeriod),
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Reassign worker stopped")
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// Done is closed once Start has returned
+func (w *ReassignWorker) Done() <-chan struct{} {
+	return w.done
+}
+
+// RunOnce unassigns every delivery whose pickup is overdue by more than the grace period. A
+// promoted backup driver's pickup is due from their promotion (domain.DeliveryAssignment.PickupDueAt).
+func (w *ReassignWorker) RunOnce(ctx context.Context) int {
+	cutoff := w.clock.Now().Add(-w.gracePeriod)
+
+	unassigned, err := w.useCase.UnassignOverdueDeliveries(ctx, cutoff)
+	if err != nil {
+		w.logger.Error("Failed to unassign overdue deliveries", zap.Error(err))
+		return 0
+	}
+
+	for _, assignment := range unassigned {
+		w.metrics.RecordDeliveryOperation(constants.OpAutoUnassign, string(assignment.Status))
+		w.logger.Info("Delivery auto-unassigned after pickup timeout",
+			zap.String("event", constants.OpAutoUnassign),
+			zap.String("id", assignment.ID.String()),
+			zap.String("order_id", assignment.OrderID),
+			zap.Time("scheduled_pickup_time", assignment.ScheduledPickupTime),
+			zap.String("status", string(assignment.Status)),
+			zap.Stringp("driver_id", assignment.DriverID), // The promoted backup driver, if there was one
+		)
+	}
+
+	return len(unassigned)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// fakeClock always returns the same instant
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestReassignWorker_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	grace := 15 * time.Minute
+
+	driverID := "DRIVER-123"
+	overdue := []*domain.DeliveryAssignment{
+		{
+			ID:                  uuid.New(),
+			OrderID:             "ORDER-1",
+			DriverID:            &driverID,
+			Status:              domain.DeliveryStatusAssigned,
+			ScheduledPickupTime: now.Add(-1 * time.Hour),
+		},
+		{
+			ID:                  uuid.New(),
+			OrderID:             "ORDER-2",
+			DriverID:            &driverID,
+			Status:              domain.DeliveryStatusAssigned,
+			ScheduledPickupTime: now.Add(-20 * time.Minute),
+		},
+	}
+
+	mockRepo.EXPECT().
+		ListOverdueAssigned(gomock.Any(), now.Add(-grace), constants.ReassignBatchSize).
+		Return(overdue, nil).
+		Times(1)
+
+	// Each delivery is locked and changed in its own transaction
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(len(overdue))
+	for _, assignment := range overdue {
+		mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), assignment.ID).Return(assignment, nil)
+	}
+	mockRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		Return(nil).
+		Times(len(overdue))
+
+	worker := NewReassignWorker(ReassignConfig{
+		Interval:    time.Minute,
+		GracePeriod: grace,
+		Clock:       fakeClock{now: now},
+		Logger:      logger,
+	}, uc)
+
+	count := worker.RunOnce(context.Background())
+
+	assert.Equal(t, len(overdue), count)
+	for _, assignment := range overdue {
+		assert.Equal(t, domain.DeliveryStatusPending, assignment.Status)
+		assert.Nil(t, assignment.DriverID)
+	}
+}
+
+func TestReassignWorker_SkipsAssignmentsThatMovedOn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	driverID := "DRIVER-123"
+	listed := &domain.DeliveryAssignment{
+		ID:                  uuid.New(),
+		OrderID:             "ORDER-1",
+		DriverID:            &driverID,
+		Status:              domain.DeliveryStatusAssigned,
+		ScheduledPickupTime: now.Add(-1 * time.Hour),
+	}
+	// The driver confirms pickup between the listing and the worker locking the row
+	pickedUp := *listed
+	pickedUp.Status = domain.DeliveryStatusPickedUp
+	rescheduled := &domain.DeliveryAssignment{
+		ID:                  uuid.New(),
+		OrderID:             "ORDER-2",
+		DriverID:            &driverID,
+		Status:              domain.DeliveryStatusAssigned,
+		ScheduledPickupTime: now.Add(-1 * time.Hour),
+	}
+
+	mockRepo.EXPECT().
+		ListOverdueAssigned(gomock.Any(), now, constants.ReassignBatchSize).
+		Return([]*domain.DeliveryAssignment{listed, rescheduled}, nil).
+		Times(1)
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), listed.ID).Return(&pickedUp, nil)
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), rescheduled.ID).Return(&domain.DeliveryAssignment{
+		ID:                  rescheduled.ID,
+		DriverID:            &driverID,
+		Status:              domain.DeliveryStatusAssigned,
+		ScheduledPickupTime: now.Add(time.Hour),
+	}, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	worker := NewReassignWorker(ReassignConfig{
+		Interval: time.Minute,
+		Clock:    fakeClock{now: now},
+		Logger:   logger,
+	}, uc)
+
+	count := worker.RunOnce(context.Background())
+
+	require.Zero(t, count, "neither the picked-up nor the rescheduled delivery is written back")
+	assert.Equal(t, domain.DeliveryStatusPickedUp, pickedUp.Status)
+	assert.NotNil(t, pickedUp.DriverID)
+}
+
+func TestReassignWorker_PromotedBackupGetsGracePeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger := zap.NewNop()
+	clock := &fakeClock{now: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)}
+	cfg := service.DefaultConfig()
+	cfg.Clock = clock
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	grace := 15 * time.Minute
+	primary, backup := "DRIVER-1", "DRIVER-2"
+	delivery := &domain.DeliveryAssignment{
+		ID:                  uuid.New(),
+		OrderID:             "ORDER-1",
+		DriverID:            &primary,
+		BackupDriverID:      &backup,
+		Status:              domain.DeliveryStatusAssigned,
+		ScheduledPickupTime: clock.now.Add(-1 * time.Hour),
+	}
+
+	// The listing may still return the delivery after its backup took over; the locked row decides
+	mockRepo.EXPECT().ListOverdueAssigned(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*domain.DeliveryAssignment{delivery}, nil).AnyTimes()
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).AnyTimes()
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), delivery.ID).Return(delivery, nil).AnyTimes()
+	mockRepo.EXPECT().Update(gomock.Any(), delivery).Return(nil).Times(2)
+
+	worker := NewReassignWorker(ReassignConfig{
+		Interval:    time.Minute,
+		GracePeriod: grace,
+		Clock:       clock,
+		Logger:      logger,
+	}, uc)
+
+	// The primary is overdue, so the backup takes over
+	require.Equal(t, 1, worker.RunOnce(context.Background()))
+	assert.Equal(t, domain.DeliveryStatusAssigned, delivery.Status)
+	assert.Equal(t, backup, *delivery.DriverID)
+
+	// The next ticks leave the backup alone until they too are overdue by the grace period
+	clock.now = clock.now.Add(time.Minute)
+	assert.Zero(t, worker.RunOnce(context.Background()))
+	clock.now = clock.now.Add(grace)
+	assert.Equal(t, backup, *delivery.DriverID)
+
+	require.Equal(t, 1, worker.RunOnce(context.Background()))
+	assert.Equal(t, domain.DeliveryStatusPending, delivery.Status)
+	assert.Nil(t, delivery.DriverID)
+}
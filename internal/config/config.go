@@ -1,45 +1,159 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/pkg/jsonschema"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logger    LoggerConfig
+	Delivery  DeliveryConfig
+	CORS      CORSConfig
+	Auth      AuthConfig
+	Redaction RedactionConfig
+	Limits    LimitsConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Port            int
+	HTTPPort        int
 	MetricsPort     int
-	ShutdownTimeout time.Duration
+	ShutdownTimeout time.Duration // Deadline for the whole shutdown, every step included
+	RequestTimeout  time.Duration // Deadline applied to every gRPC request
+
+	MetricsNamespace string   // Prefix of every Prometheus metric, to tell instances apart
+	MetricsSubsystem string   // Second part of the prefix, after the namespace
+	MetricsCountries []string // Delivery countries with a metrics label of their own; others are counted as OTHER
+
+	RequestIDPattern   *regexp.Regexp // Incoming request IDs kept as-is; others are replaced
+	RequestIDMaxLength int            // Longest incoming request ID kept
+
+	GRPCShutdownTimeout    time.Duration // Graceful stop of the gRPC server before it is forced (within ShutdownTimeout)
+	HTTPShutdownTimeout    time.Duration // Draining the HTTP gateway (within ShutdownTimeout)
+	MetricsShutdownTimeout time.Duration // Closing the metrics server (within ShutdownTimeout)
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
-	LogSQL          bool // Enable SQL query logging
+	Host               string
+	Port               int
+	User               string
+	Password           string
+	DBName             string
+	SSLMode            string
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration // Close pooled connections idle longer than this (0 = never)
+	LogSQL             bool          // Enable SQL query logging
+	ExplainQueries     bool          // Log EXPLAIN plans for List queries at debug level
+	AutoMigrate        bool          // Create/update the schema from the GORM models at startup
+	TransactionTimeout time.Duration // Roll back transactions held open longer than this (0 = no limit)
+	CountTimeout       time.Duration // Estimate List totals whose count runs longer than this (0 = always exact)
+	CascadeAuditLog    bool          // Soft delete a delivery's audit log rows with it instead of retaining them
+
+	BreakerFailureThreshold int           // Consecutive database failures that open the circuit breaker (0 = disabled)
+	BreakerCooldown         time.Duration // How long the open breaker fails fast before probing the database
 }
 
 // LoggerConfig holds logger configuration
 type LoggerConfig struct {
 	Level            string //nolint:goimports,gofmt
 	Development      bool
-	EnableStacktrace bool // Enable stack traces in logs (useful for debugging)
+	EnableStacktrace bool   // Enable stack traces in logs (useful for debugging)
+	Encoding         string // "json" or "console"; empty follows Development
+
+	SkipMethods         []string // gRPC methods whose successful calls are not logged
+	DebugMethodPrefixes []string // gRPC method name prefixes whose successful calls are logged at debug
+	LogPayloads         bool     // Log gRPC request and response payloads at debug level (redacted)
+}
+
+// DeliveryConfig holds delivery business rule configuration
+type DeliveryConfig struct {
+	UppercaseIDs        bool          // Normalize order and driver IDs to upper case
+	MaxNotesLength      int           // Maximum length of delivery notes (0 = unlimited)
+	DefaultNotes        string        // Notes for deliveries created without any (empty = none)
+	PrependDefaultNotes bool          // Also prepend DefaultNotes to notes the caller sent
+	ReassignInterval    time.Duration // How often to unassign overdue deliveries (0 = disabled)
+	ReassignGracePeriod time.Duration // How long past scheduled pickup an assignment may stay unpicked
+	EscalationInterval  time.Duration // How often waiting PENDING deliveries are escalated (0 = disabled)
+
+	PriorityEscalations []domain.PriorityEscalation // Priority raises for deliveries waiting in PENDING
+
+	MetricsSummaryInterval     time.Duration        // How often the daily metrics summary is refreshed (0 = disabled)
+	MetricsSummaryLookbackDays int                  // Completed days refreshed per pass
+	MaxActivePerOrder          int                  // Maximum non-terminal deliveries per order (0 = unlimited)
+	MaxAttempts                int                  // Delivery attempts before a reattempt dead-letters instead (0 = unlimited)
+	MaxPickupAttempts          int                  // Wrong pickup codes in a row before pickup is locked (0 = never locked)
+	PickupLockout              time.Duration        // How long pickup stays locked after too many wrong codes
+	MinScheduleAdvance         time.Duration        // Minimum lead time for a scheduled pickup
+	MaxScheduleAdvance         time.Duration        // Scheduling horizon for pickups (0 = unlimited)
+	AllowPastScheduling        bool                 // Let imports backfill deliveries with past pickups on request
+	DeliveryWindowSlack        time.Duration        // Slack around the estimate for derived delivery windows (0 = no window)
+	OnTimeGracePeriod          time.Duration        // Lateness still counted as on time in metrics
+	MaxDeliveryDistanceKm      float64              // Longest pickup-to-delivery distance a feasibility check accepts (0 = unlimited)
+	AverageSpeedKmh            float64              // Travel speed feasibility checks assume
+	MaxConcurrentMetrics       int                  // Metrics aggregations run at once (0 = unlimited)
+	MetricsQueueTimeout        time.Duration        // How long a metrics call waits for a slot before ResourceExhausted
+	AllowedVehicleTypes        []string             // Vehicle types a delivery may require
+	FailureReasonCodes         []string             // Reason codes a failed delivery may be given
+	BusinessHours              domain.BusinessHours // Operating hours for pickups and deliveries (empty = any time)
+	IDGenerator                domain.IDGenerator   // UUID version of new delivery IDs
+	NotificationThrottle       time.Duration        // Minimum interval between status notifications per delivery (0 = no throttling)
+	AuditLogPath               string               // File audit entries are appended to as JSON lines (empty = no audit log)
+	AuditLogDB                 bool                 // Store audit entries in the delivery_audit_log table instead
+
+	MetadataSchemas map[string]*jsonschema.Schema // JSON schema each tenant's delivery metadata must match, by tenant ID (none = any metadata)
+}
+
+// CORSConfig holds CORS configuration for the HTTP gateway
+type CORSConfig struct {
+	AllowedOrigins []string // Empty means no cross-origin access ("*" in development)
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+}
+
+// AuthConfig holds API key authentication for the HTTP gateway and the admin operations
+type AuthConfig struct {
+	APIKeys      []string // Empty disables authentication
+	ExemptRoutes []string // Gateway paths served without a key; must be listed explicitly
+	AdminAPIKeys []string // Keys allowed to call admin operations; empty disables them
+
+	// APIKeyActors names the caller recorded for database auditing per key; other keys are
+	// recorded by fingerprint
+	APIKeyActors map[string]domain.Actor
+}
+
+// RedactionConfig lists sensitive fields replaced with "[REDACTED]" in logged payloads and,
+// optionally, in gateway responses to requests without a valid API key
+type RedactionConfig struct {
+	Fields           []string // Proto field paths such as "pickup_address.street"
+	GatewayResponses bool     // Also redact gateway responses for unauthenticated requests
+}
+
+// LimitsConfig holds the maximum number of items each batch operation accepts in one request
+type LimitsConfig struct {
+	MaxBatchGet      int // IDs per BatchGetDeliveries request
+	MaxSyncBatch     int // Deliveries per ListModifiedDeliveries request
+	MaxDriverMetrics int // Drivers per GetMetricsForDrivers request
+	MaxImportRows    int // Rows per ImportDeliveries stream
 }
 
 // Load loads configuration from environment variables with sensible defaults
@@ -47,26 +161,148 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
 			Port:            getEnvAsInt("PORT", 50051),
+			HTTPPort:        getEnvAsInt("HTTP_PORT", 8080),
 			MetricsPort:     getEnvAsInt("METRICS_PORT", 9090),
 			ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+			RequestTimeout:  getEnvAsDuration("REQUEST_TIMEOUT", constants.DefaultContextTimeout),
+
+			MetricsNamespace: getEnv("METRICS_NAMESPACE", constants.DefaultMetricsNamespace),
+			MetricsSubsystem: getEnv("METRICS_SUBSYSTEM", constants.DefaultMetricsSubsystem),
+			MetricsCountries: getEnvAsSlice("METRICS_COUNTRIES", strings.Split(constants.DefaultMetricsCountries, ",")),
+
+			RequestIDMaxLength: getEnvAsInt("REQUEST_ID_MAX_LENGTH", constants.DefaultRequestIDMaxLength),
+
+			GRPCShutdownTimeout:    getEnvAsDuration("GRPC_SHUTDOWN_TIMEOUT", constants.DefaultGRPCShutdownTimeout),
+			HTTPShutdownTimeout:    getEnvAsDuration("HTTP_SHUTDOWN_TIMEOUT", constants.DefaultHTTPShutdownTimeout),
+			MetricsShutdownTimeout: getEnvAsDuration("METRICS_SHUTDOWN_TIMEOUT", constants.DefaultMetricsShutdownTimeout),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnvAsInt("DB_PORT", 5432),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", "postgres"),
-			DBName:          getEnv("DB_NAME", "order_delivery_db"),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
-			LogSQL:          getEnvAsBool("DB_LOG_SQL", false),
+			Host:               getEnv("DB_HOST", "localhost"),
+			Port:               getEnvAsInt("DB_PORT", 5432),
+			User:               getEnv("DB_USER", "postgres"),
+			Password:           getEnv("DB_PASSWORD", "postgres"),
+			DBName:             getEnv("DB_NAME", "order_delivery_db"),
+			SSLMode:            getEnv("DB_SSLMODE", "disable"),
+			MaxOpenConns:       getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:       getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime:    getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			ConnMaxIdleTime:    getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", constants.DefaultConnMaxIdleTime),
+			LogSQL:             getEnvAsBool("DB_LOG_SQL", false),
+			ExplainQueries:     getEnvAsBool("DB_EXPLAIN_QUERIES", false),
+			AutoMigrate:        getEnvAsBool("DB_AUTO_MIGRATE", false),
+			TransactionTimeout: getEnvAsDuration("DB_TRANSACTION_TIMEOUT", constants.DefaultTransactionTimeout),
+			CountTimeout:       getEnvAsDuration("DB_COUNT_TIMEOUT", constants.DefaultCountTimeout),
+			CascadeAuditLog:    getEnvAsBool("DB_CASCADE_AUDIT_LOG", false),
+
+			BreakerFailureThreshold: getEnvAsInt("DB_BREAKER_FAILURE_THRESHOLD", constants.DefaultBreakerFailureThreshold),
+			BreakerCooldown:         getEnvAsDuration("DB_BREAKER_COOLDOWN", constants.DefaultBreakerCooldown),
 		},
 		Logger: LoggerConfig{
 			Level:            getEnv("LOG_LEVEL", "info"), //nolint:goimports,gofmt
 			Development:      getEnvAsBool("LOG_DEV", false),
 			EnableStacktrace: getEnvAsBool("LOG_STACKTRACE", false),
+			Encoding:         getEnv("LOG_ENCODING", ""),
+
+			SkipMethods:         getEnvAsSlice("LOG_SKIP_METHODS", []string{constants.HealthCheckMethod}),
+			DebugMethodPrefixes: getEnvAsSlice("LOG_DEBUG_METHOD_PREFIXES", nil),
+			LogPayloads:         getEnvAsBool("LOG_PAYLOADS", false),
+		},
+		Delivery: DeliveryConfig{
+			UppercaseIDs:        getEnvAsBool("DELIVERY_UPPERCASE_IDS", false),
+			MaxNotesLength:      getEnvAsInt("DELIVERY_MAX_NOTES_LENGTH", constants.DefaultMaxNotesLength),
+			DefaultNotes:        getEnv("DELIVERY_DEFAULT_NOTES", ""),
+			PrependDefaultNotes: getEnvAsBool("DELIVERY_PREPEND_DEFAULT_NOTES", false),
+			ReassignInterval:    getEnvAsDuration("DELIVERY_REASSIGN_INTERVAL", constants.DefaultReassignInterval),
+			ReassignGracePeriod: getEnvAsDuration("DELIVERY_REASSIGN_GRACE_PERIOD", constants.DefaultReassignGracePeriod),
+			EscalationInterval:  getEnvAsDuration("DELIVERY_ESCALATION_INTERVAL", constants.DefaultEscalationInterval),
+
+			MetricsSummaryInterval:     getEnvAsDuration("DELIVERY_METRICS_SUMMARY_INTERVAL", constants.DefaultMetricsSummaryInterval),
+			MetricsSummaryLookbackDays: getEnvAsInt("DELIVERY_METRICS_SUMMARY_LOOKBACK_DAYS", constants.DefaultMetricsSummaryLookbackDays),
+			MaxActivePerOrder:          getEnvAsInt("DELIVERY_MAX_ACTIVE_PER_ORDER", constants.DefaultMaxActivePerOrder),
+			MaxAttempts:                getEnvAsInt("DELIVERY_MAX_ATTEMPTS", constants.DefaultMaxAttempts),
+			MaxPickupAttempts:          getEnvAsInt("DELIVERY_MAX_PICKUP_ATTEMPTS", constants.DefaultMaxPickupAttempts),
+			PickupLockout:              getEnvAsDuration("DELIVERY_PICKUP_LOCKOUT", constants.DefaultPickupLockout),
+			MinScheduleAdvance:         getEnvAsDuration("DELIVERY_MIN_SCHEDULE_ADVANCE", constants.MinScheduleAdvance),
+			MaxScheduleAdvance:         getEnvAsDuration("DELIVERY_MAX_SCHEDULE_ADVANCE", constants.MaxScheduleAdvance),
+			AllowPastScheduling:        getEnvAsBool("DELIVERY_ALLOW_PAST_SCHEDULING", false),
+			DeliveryWindowSlack:        getEnvAsDuration("DELIVERY_WINDOW_SLACK", constants.DefaultDeliveryWindowSlack),
+			OnTimeGracePeriod:          getEnvAsDuration("DELIVERY_ON_TIME_GRACE_PERIOD", constants.DefaultOnTimeGracePeriod),
+			MaxDeliveryDistanceKm:      getEnvAsFloat("DELIVERY_MAX_DISTANCE_KM", constants.DefaultMaxDeliveryDistanceKm),
+			AverageSpeedKmh:            getEnvAsFloat("DELIVERY_AVERAGE_SPEED_KMH", constants.DefaultAverageSpeedKmh),
+			MaxConcurrentMetrics:       getEnvAsInt("DELIVERY_METRICS_MAX_CONCURRENCY", constants.DefaultMaxConcurrentMetrics),
+			MetricsQueueTimeout:        getEnvAsDuration("DELIVERY_METRICS_QUEUE_TIMEOUT", constants.DefaultMetricsQueueTimeout),
+			AllowedVehicleTypes:        getEnvAsSlice("DELIVERY_VEHICLE_TYPES", strings.Split(constants.DefaultVehicleTypes, ",")),
+			FailureReasonCodes:         getEnvAsSlice("DELIVERY_FAILURE_REASON_CODES", strings.Split(constants.DefaultFailureReasonCodes, ",")),
+			NotificationThrottle:       getEnvAsDuration("DELIVERY_NOTIFICATION_THROTTLE", constants.DefaultNotificationThrottle),
+			AuditLogPath:               getEnv("DELIVERY_AUDIT_LOG_PATH", ""),
+			AuditLogDB:                 getEnvAsBool("DELIVERY_AUDIT_LOG_DB", false),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", constants.RequestIDHeader, constants.APIKeyHeader, constants.APIVersionHeader, constants.TraceIDHeader}),
+			MaxAge:         getEnvAsDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		Auth: AuthConfig{
+			APIKeys:      getEnvAsSlice("AUTH_API_KEYS", nil),
+			ExemptRoutes: getEnvAsSlice("AUTH_EXEMPT_ROUTES", nil),
+			AdminAPIKeys: getEnvAsSlice("AUTH_ADMIN_API_KEYS", nil),
 		},
+		Redaction: RedactionConfig{
+			Fields: getEnvAsSlice("REDACT_FIELDS", []string{
+				"pickup_address.street", "delivery_address.street", "notes", "timeline_notes.note", "feedback", "pickup_code", "recipient_email", "metadata",
+			}),
+			GatewayResponses: getEnvAsBool("REDACT_GATEWAY_RESPONSES", false),
+		},
+		Limits: LimitsConfig{
+			// DELIVERY_MAX_BATCH_SIZE is the previous name of LIMITS_MAX_BATCH_GET
+			MaxBatchGet:      getEnvAsInt("LIMITS_MAX_BATCH_GET", getEnvAsInt("DELIVERY_MAX_BATCH_SIZE", constants.DefaultMaxBatchSize)),
+			MaxSyncBatch:     getEnvAsInt("LIMITS_MAX_SYNC_BATCH", constants.MaxSyncBatchSize),
+			MaxDriverMetrics: getEnvAsInt("LIMITS_MAX_DRIVER_METRICS", constants.DefaultMaxDriverMetricsBatch),
+			MaxImportRows:    getEnvAsInt("LIMITS_MAX_IMPORT_ROWS", constants.DefaultMaxImportRows),
+		},
+	}
+
+	// Parsed separately because a malformed spec must fail loading rather than fall back
+	businessHours, err := domain.ParseBusinessHours(os.Getenv("DELIVERY_BUSINESS_HOURS"))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	cfg.Delivery.BusinessHours = businessHours
+
+	escalations, err := domain.ParsePriorityEscalations(getEnv("DELIVERY_PRIORITY_ESCALATIONS", constants.DefaultPriorityEscalations))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	cfg.Delivery.PriorityEscalations = escalations
+
+	metadataSchemas, err := loadMetadataSchemas(os.Getenv("DELIVERY_METADATA_SCHEMAS_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	cfg.Delivery.MetadataSchemas = metadataSchemas
+
+	idGenerator, err := domain.IDGeneratorForVersion(getEnv("DELIVERY_ID_VERSION", constants.DefaultIDVersion))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	cfg.Delivery.IDGenerator = idGenerator
+
+	apiKeyActors, err := middleware.ParseAPIKeyActors(os.Getenv("AUTH_API_KEY_ACTORS"))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	cfg.Auth.APIKeyActors = apiKeyActors
+
+	requestIDPattern, err := middleware.ParseRequestIDPattern(getEnv("REQUEST_ID_PATTERN", constants.DefaultRequestIDPattern))
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: invalid request ID pattern: %w", err)
+	}
+	cfg.Server.RequestIDPattern = requestIDPattern
+
+	// Allow any origin only in development when none are configured
+	if len(cfg.CORS.AllowedOrigins) == 0 && cfg.Logger.Development {
+		cfg.CORS.AllowedOrigins = []string{"*"}
 	}
 
 	// Validate required fields
@@ -82,15 +318,134 @@ func (c *Config) validate() error {
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
+	if c.Server.HTTPPort < 1 || c.Server.HTTPPort > 65535 {
+		return fmt.Errorf("invalid HTTP port: %d", c.Server.HTTPPort)
+	}
+	if c.Server.RequestTimeout <= 0 {
+		return fmt.Errorf("invalid request timeout: %v", c.Server.RequestTimeout)
+	}
+	if c.Server.RequestIDMaxLength < 1 {
+		return fmt.Errorf("invalid request ID max length: %d", c.Server.RequestIDMaxLength)
+	}
+	if c.Server.ShutdownTimeout <= 0 || c.Server.GRPCShutdownTimeout <= 0 ||
+		c.Server.HTTPShutdownTimeout <= 0 || c.Server.MetricsShutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown timeouts must be positive")
+	}
+	if max(c.Server.GRPCShutdownTimeout, c.Server.HTTPShutdownTimeout, c.Server.MetricsShutdownTimeout) > c.Server.ShutdownTimeout {
+		return fmt.Errorf("gRPC, HTTP and metrics shutdown timeouts must not exceed the shutdown timeout %v", c.Server.ShutdownTimeout)
+	}
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
 	if c.Database.User == "" {
 		return fmt.Errorf("database user is required")
 	}
+	if c.Delivery.MaxNotesLength < 0 {
+		return fmt.Errorf("invalid max notes length: %d", c.Delivery.MaxNotesLength)
+	}
+	if notesLength := utf8.RuneCountInString(c.Delivery.DefaultNotes); c.Delivery.MaxNotesLength > 0 && notesLength > c.Delivery.MaxNotesLength {
+		return fmt.Errorf("default notes exceed max notes length: %d > %d", notesLength, c.Delivery.MaxNotesLength)
+	}
+	if c.Delivery.ReassignInterval < 0 || c.Delivery.ReassignGracePeriod < 0 {
+		return fmt.Errorf("reassign interval and grace period cannot be negative")
+	}
+	if c.Delivery.EscalationInterval < 0 {
+		return fmt.Errorf("invalid priority escalation interval: %v", c.Delivery.EscalationInterval)
+	}
+	if c.Delivery.MetricsSummaryInterval < 0 {
+		return fmt.Errorf("invalid metrics summary interval: %v", c.Delivery.MetricsSummaryInterval)
+	}
+	if c.Delivery.MetricsSummaryInterval > 0 && c.Delivery.MetricsSummaryLookbackDays < 1 {
+		return fmt.Errorf("metrics summary lookback must be at least one day: %d", c.Delivery.MetricsSummaryLookbackDays)
+	}
+	if c.Limits.MaxBatchGet < 1 {
+		return fmt.Errorf("invalid max batch get size: %d", c.Limits.MaxBatchGet)
+	}
+	if c.Limits.MaxSyncBatch < 1 {
+		return fmt.Errorf("invalid max sync batch size: %d", c.Limits.MaxSyncBatch)
+	}
+	if c.Limits.MaxDriverMetrics < 1 {
+		return fmt.Errorf("invalid max driver metrics batch size: %d", c.Limits.MaxDriverMetrics)
+	}
+	if c.Limits.MaxImportRows < 1 {
+		return fmt.Errorf("invalid max import rows: %d", c.Limits.MaxImportRows)
+	}
+	for key, actor := range c.Auth.APIKeyActors {
+		if !slices.Contains(c.Auth.APIKeys, key) && !slices.Contains(c.Auth.AdminAPIKeys, key) {
+			return fmt.Errorf("API key actor %q names a key that is not in AUTH_API_KEYS or AUTH_ADMIN_API_KEYS", actor.UserID)
+		}
+	}
+	if c.Delivery.MaxActivePerOrder < 0 {
+		return fmt.Errorf("invalid max active deliveries per order: %d", c.Delivery.MaxActivePerOrder)
+	}
+	if c.Delivery.MaxAttempts < 0 {
+		return fmt.Errorf("invalid max delivery attempts: %d", c.Delivery.MaxAttempts)
+	}
+	if c.Delivery.MaxPickupAttempts < 0 {
+		return fmt.Errorf("invalid max pickup attempts: %d", c.Delivery.MaxPickupAttempts)
+	}
+	if c.Delivery.MaxPickupAttempts > 0 && c.Delivery.PickupLockout <= 0 {
+		return fmt.Errorf("pickup lockout must be positive when pickup attempts are limited: %v", c.Delivery.PickupLockout)
+	}
+	if c.Delivery.MinScheduleAdvance < 0 || c.Delivery.MaxScheduleAdvance < 0 {
+		return fmt.Errorf("schedule advance limits cannot be negative")
+	}
+	if c.Delivery.MaxScheduleAdvance > 0 && c.Delivery.MinScheduleAdvance > c.Delivery.MaxScheduleAdvance {
+		return fmt.Errorf("min schedule advance cannot exceed max schedule advance")
+	}
+	if c.Delivery.DeliveryWindowSlack < 0 {
+		return fmt.Errorf("invalid delivery window slack: %v", c.Delivery.DeliveryWindowSlack)
+	}
+	if c.Delivery.OnTimeGracePeriod < 0 {
+		return fmt.Errorf("invalid on-time grace period: %v", c.Delivery.OnTimeGracePeriod)
+	}
+	if !(c.Delivery.MaxDeliveryDistanceKm >= 0) {
+		return fmt.Errorf("invalid max delivery distance: %v km", c.Delivery.MaxDeliveryDistanceKm)
+	}
+	if !(c.Delivery.AverageSpeedKmh > 0) {
+		return fmt.Errorf("invalid average speed: %v km/h", c.Delivery.AverageSpeedKmh)
+	}
+	if c.Delivery.MaxConcurrentMetrics < 0 {
+		return fmt.Errorf("invalid max concurrent metrics queries: %d", c.Delivery.MaxConcurrentMetrics)
+	}
+	if c.Delivery.MetricsQueueTimeout < 0 {
+		return fmt.Errorf("invalid metrics queue timeout: %v", c.Delivery.MetricsQueueTimeout)
+	}
+	if len(c.Delivery.AllowedVehicleTypes) == 0 {
+		return fmt.Errorf("at least one delivery vehicle type is required")
+	}
+	if len(c.Delivery.FailureReasonCodes) == 0 {
+		return fmt.Errorf("at least one delivery failure reason code is required")
+	}
+	if c.Delivery.NotificationThrottle < 0 {
+		return fmt.Errorf("invalid notification throttle: %v", c.Delivery.NotificationThrottle)
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("invalid connection max idle time: %v", c.Database.ConnMaxIdleTime)
+	}
 	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
 		return fmt.Errorf("max_idle_conns cannot exceed max_open_conns")
 	}
+	switch c.Logger.Encoding {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("invalid log encoding %q: must be json or console", c.Logger.Encoding)
+	}
+	if c.Database.TransactionTimeout < 0 {
+		return fmt.Errorf("invalid database transaction timeout: %v", c.Database.TransactionTimeout)
+	}
+	if c.Database.CountTimeout < 0 {
+		return fmt.Errorf("invalid database count timeout: %v", c.Database.CountTimeout)
+	}
+	if c.Delivery.AuditLogDB && c.Delivery.AuditLogPath != "" {
+		return fmt.Errorf("audit log must go to either a file or the database, not both")
+	}
+	if c.Database.BreakerFailureThreshold < 0 {
+		return fmt.Errorf("invalid database breaker failure threshold: %d", c.Database.BreakerFailureThreshold)
+	}
+	if c.Database.BreakerFailureThreshold > 0 && c.Database.BreakerCooldown <= 0 {
+		return fmt.Errorf("invalid database breaker cooldown: %v", c.Database.BreakerCooldown)
+	}
 	return nil
 }
 
@@ -104,6 +459,35 @@ func (c *DatabaseConfig) GetDSN() string {
 
 // Helper functions to read environment variables
 
+// loadMetadataSchemas reads a JSON object mapping tenant IDs to the JSON schema of their delivery
+// metadata from path; an empty path configures no schemas
+func loadMetadataSchemas(path string) (map[string]*jsonschema.Schema, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read metadata schemas: %w", err)
+	}
+
+	var tenants map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("parse metadata schemas %s: %w", path, err)
+	}
+	schemas := make(map[string]*jsonschema.Schema, len(tenants))
+	for tenantID, raw := range tenants {
+		if tenantID == "" {
+			return nil, fmt.Errorf("metadata schemas %s: tenant ID is required", path)
+		}
+		schema, err := jsonschema.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("metadata schema for tenant %q: %w", tenantID, err)
+		}
+		schemas[tenantID] = schema
+	}
+	return schemas, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -123,6 +507,18 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -135,6 +531,20 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return value
 }
 
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
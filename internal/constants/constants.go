@@ -17,15 +17,114 @@ const (
 	DriverIDMinLength = 1
 	DriverIDMaxLength = 100
 
+	// Notes constraints
+	DefaultMaxNotesLength = 4000
+
+	// Order constraints
+	DefaultMaxActivePerOrder = 10 // Safety net against retry loops creating duplicate deliveries
+	DefaultMaxAttempts       = 3  // Delivery attempts before a failed delivery is dead-lettered instead of reattempted
+
+	// Delivery IDs
+	DefaultIDVersion = "v4" // UUID version of new delivery IDs; v7 is time-ordered for better index locality
+
+	// Metrics CSV export
+	MaxMetricsExportDays = 366 // One row per day; bounds the number of metrics queries per download
+
+	// Leaderboard constraints
+	DefaultLeaderboardSize = 10
+	MaxLeaderboardSize     = 100
+
+	// Sync constraints
+	DefaultSyncBatchSize = 100 // Deliveries returned per ListModifiedSince call when no limit is given
+	MaxSyncBatchSize     = 500
+
+	// Batch constraints
+	DefaultMaxBatchSize = 100 // Max IDs accepted by a single batch get
+
+	DefaultMaxDriverMetricsBatch = 50 // Max drivers per GetMetricsForDrivers request
+
+	DefaultMaxImportRows = 10000 // Max rows per ImportDeliveries stream
+
+	// Package constraints
+	MaxPackageDescriptionLength = 200
+
+	// Tag constraints
+	MaxTagLength       = 50
+	MaxTagsPerDelivery = 20
+
+	// Pickup confirmation code
+	PickupCodeLength         = 6                // Decimal digits
+	DefaultMaxPickupAttempts = 5                // Wrong codes in a row before pickup is locked
+	DefaultPickupLockout     = 15 * time.Minute // How long pickup stays locked
+
+	// Rating constraints
+	MinRating = 1
+	MaxRating = 5
+
+	// Reassign-on-timeout worker
+	DefaultReassignInterval    = 1 * time.Minute  // How often overdue assignments are checked
+	DefaultReassignGracePeriod = 15 * time.Minute // How long past scheduled pickup before unassigning
+	ReassignBatchSize          = 100              // Max assignments unassigned per pass
+
+	// Priority escalation worker
+	DefaultEscalationInterval  = 5 * time.Minute                // How often waiting PENDING deliveries are checked (0 disables)
+	DefaultPriorityEscalations = "LOW:NORMAL:1h,NORMAL:HIGH:2h" // FROM:TO:AFTER rules, AFTER measured from creation
+	EscalationBatchSize        = 100                            // Max deliveries escalated per rule and pass
+
+	// Driver location pings
+	MaxLocationClockSkew = 1 * time.Minute // How far in the future a ping's device timestamp may be
+
+	// Metrics summary worker
+	DefaultMetricsSummaryInterval     = 1 * time.Hour // How often recent days are re-summarized
+	DefaultMetricsSummaryLookbackDays = 7             // Completed days refreshed per pass
+
 	// Time constraints
 	MinScheduleAdvance  = 30 * time.Minute    // Minimum time before scheduled pickup
 	MaxScheduleAdvance  = 30 * 24 * time.Hour // Maximum time for scheduling (30 days)
 	MinDeliveryDuration = 15 * time.Minute    // Minimum time between pickup and delivery
 
+	DefaultDeliveryWindowSlack = 30 * time.Minute // Derived window is the estimate plus/minus this
+
+	// Route feasibility checks
+	DefaultMaxDeliveryDistanceKm = 50.0 // Longest straight-line pickup-to-delivery distance (0 disables the check)
+	DefaultAverageSpeedKmh       = 25.0 // Average travel speed used to estimate the time a delivery needs
+
+	// Lateness still counted as on time in delivery metrics
+	DefaultOnTimeGracePeriod = 5 * time.Minute
+
+	// Metrics query concurrency
+	DefaultMaxConcurrentMetrics = 4               // Metrics aggregations run at once (0 disables the limit)
+	DefaultMetricsQueueTimeout  = 2 * time.Second // How long a metrics call waits for a slot before being rejected
+
+	// Vehicle types a delivery may require, comma-separated
+	DefaultVehicleTypes = "BIKE,CAR,VAN,REFRIGERATED"
+
+	// Reason codes a failed delivery may be given, comma-separated
+	DefaultFailureReasonCodes = "CUSTOMER_UNAVAILABLE,WRONG_ADDRESS,DAMAGED,REFUSED,OTHER"
+
+	// Status-change notifications
+	DefaultNotificationThrottle = 5 * time.Second // At most one notification per delivery per interval
+	WatchBufferSize             = 64              // Status changes buffered per watcher before it is dropped
+
 	// Database
-	DefaultMaxOpenConns    = 25
-	DefaultMaxIdleConns    = 5
-	DefaultConnMaxLifetime = 5 * time.Minute
+	DefaultMaxOpenConns       = 25
+	DefaultMaxIdleConns       = 5
+	DefaultConnMaxLifetime    = 5 * time.Minute
+	DefaultConnMaxIdleTime    = 1 * time.Minute  // Below typical pooler/server idle timeouts
+	ExportBatchSize           = 500              // Rows fetched per batch when streaming exports
+	ImportBatchSize           = 100              // Rows inserted per transaction when importing
+	BulkDeleteBatchSize       = 500              // Rows soft-deleted per statement by a bulk delete
+	DefaultTransactionTimeout = 30 * time.Second // Longest a transaction may stay open (0 disables the limit)
+	DefaultCountTimeout       = 3 * time.Second  // Longest a List count may run before the total is estimated
+
+	// Database circuit breaker
+	DefaultBreakerFailureThreshold = 5 // Consecutive failures that open the breaker (0 disables it)
+	DefaultBreakerCooldown         = 30 * time.Second
+
+	// Shutdown timeouts
+	DefaultGRPCShutdownTimeout    = 20 * time.Second // Graceful gRPC stop before in-flight calls are cut
+	DefaultHTTPShutdownTimeout    = 10 * time.Second // Gateway drain
+	DefaultMetricsShutdownTimeout = 5 * time.Second  // Metrics server close
 
 	// Context timeouts
 	DefaultContextTimeout   = 30 * time.Second
@@ -36,9 +135,42 @@ const (
 	RequestIDHeader = "X-Request-ID"
 	RequestIDKey    = "request_id"
 
-	// Metrics
-	MetricsNamespace = "order_delivery"
-	MetricsSubsystem = "service"
+	// Incoming request IDs are kept only if they match the pattern in full and fit the length;
+	// others are replaced so header values cannot inject into logs
+	DefaultRequestIDPattern   = `[A-Za-z0-9._:-]+`
+	DefaultRequestIDMaxLength = 128
+
+	// Trace ID returned on every response (gRPC trailer, HTTP header) for clients to quote to support
+	TraceIDHeader = "X-Trace-ID"
+
+	// API key header checked by the HTTP gateway and forwarded to gRPC as metadata
+	APIKeyHeader = "X-API-Key"
+
+	// API version negotiation: clients may send the version they were built against.
+	// Versions are listed oldest to newest; the newest is used when the header is absent.
+	APIVersionHeader     = "X-API-Version"
+	SupportedAPIVersions = "v1"
+
+	// Suggested retry delay on ResourceExhausted and Unavailable responses (gRPC header and HTTP
+	// header, in whole seconds; also google.rpc.RetryInfo in the status details)
+	RetryAfterHeader = "Retry-After"
+	MinRetryAfter    = 1 * time.Second // Shortest delay suggested, so clients never retry in a tight loop
+
+	// Health check method (skipped by request logging by default)
+	HealthCheckMethod = "/grpc.health.v1.Health/Check"
+
+	// How long /health/detail reuses the PostgreSQL server version before querying it again
+	HealthDetailVersionTTL = 5 * time.Minute
+	// How long /health/detail waits for the server version before reporting the database unavailable
+	HealthDetailQueryTimeout = 2 * time.Second
+
+	// Prometheus metric prefix, e.g. order_delivery_service_grpc_requests_total
+	DefaultMetricsNamespace = "order_delivery"
+	DefaultMetricsSubsystem = "service"
+
+	// Delivery countries counted under their own metrics label, comma-separated (at most
+	// metrics.MaxTrackedCountries); the rest are counted as OTHER
+	DefaultMetricsCountries = "US,CA,GB,DE,FR"
 )
 
 // Resource names for logging and errors
@@ -50,12 +182,27 @@ const (
 
 // Operation names for domain errors
 const (
-	OpCreate       = "create"
-	OpGet          = "get"
-	OpUpdate       = "update"
-	OpDelete       = "delete"
-	OpList         = "list"
-	OpAssignDriver = "assign_driver"
-	OpUpdateStatus = "update_status"
-	OpGetMetrics   = "get_metrics"
+	OpCreate             = "create"
+	OpGet                = "get"
+	OpUpdate             = "update"
+	OpDelete             = "delete"
+	OpList               = "list"
+	OpAssignDriver       = "assign_driver"
+	OpAssignBackupDriver = "assign_backup_driver"
+	OpUpdateStatus       = "update_status"
+	OpGetMetrics         = "get_metrics"
+	OpFeedback           = "submit_feedback"
+	OpUnassignDriver     = "unassign_driver"
+	OpAutoUnassign       = "auto_unassign"
+	OpDeadLetter         = "dead_letter"
+	OpReattempt          = "reattempt"
+	OpUpdateDriverETA    = "update_driver_eta"
+	OpBulkDelete         = "bulk_delete"
+	OpEscalate           = "escalate_priority"
+	OpImport             = "import"
+	OpAppendNote         = "append_note"
+	OpSplit              = "split"
+	OpAddTags            = "add_tags"
+	OpRemoveTags         = "remove_tags"
+	OpDeleteByOrder      = "delete_by_order"
 )
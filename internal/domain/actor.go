@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// Actor identifies who a request is made by, for auditing
+type Actor struct {
+	UserID   string
+	TenantID string
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying actor
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, if any
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
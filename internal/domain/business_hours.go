@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OpeningHours is one day's operating window as offsets from local midnight. A window whose
+// Close is not after Open runs overnight and closes on the following day.
+type OpeningHours struct {
+	Open  time.Duration
+	Close time.Duration
+}
+
+// overnight reports whether the window closes on the following day
+func (o OpeningHours) overnight() bool {
+	return o.Close <= o.Open
+}
+
+// BusinessHours holds the operating window of each weekday; days without one are closed.
+// An empty BusinessHours means always open.
+type BusinessHours map[time.Weekday]OpeningHours
+
+// weekdayNames maps the day names accepted by ParseBusinessHours to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+// ParseBusinessHours parses a comma-separated list of DAY=HH:MM-HH:MM windows, e.g.
+// "MON=08:00-20:00,FRI=18:00-02:00". Day names are three letters, case-insensitive; 24:00 closes
+// at midnight. An empty spec returns nil (always open).
+func ParseBusinessHours(spec string) (BusinessHours, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	hours := make(BusinessHours)
+	for _, entry := range strings.Split(spec, ",") {
+		day, window, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("business hours entry %q: expected DAY=HH:MM-HH:MM", entry)
+		}
+		weekday, ok := weekdayNames[strings.ToUpper(day)]
+		if !ok {
+			return nil, fmt.Errorf("business hours entry %q: unknown day %q", entry, day)
+		}
+		if _, dup := hours[weekday]; dup {
+			return nil, fmt.Errorf("business hours entry %q: %s is listed twice", entry, weekday)
+		}
+
+		open, closing, ok := strings.Cut(window, "-")
+		if !ok {
+			return nil, fmt.Errorf("business hours entry %q: expected DAY=HH:MM-HH:MM", entry)
+		}
+		openAt, err := parseClock(open)
+		if err != nil {
+			return nil, fmt.Errorf("business hours entry %q: %w", entry, err)
+		}
+		closeAt, err := parseClock(closing)
+		if err != nil {
+			return nil, fmt.Errorf("business hours entry %q: %w", entry, err)
+		}
+		if openAt == closeAt || openAt == 24*time.Hour {
+			return nil, fmt.Errorf("business hours entry %q: window is empty", entry)
+		}
+		if closeAt == 24*time.Hour {
+			closeAt = 0 // Overnight to midnight
+		}
+
+		hours[weekday] = OpeningHours{Open: openAt, Close: closeAt}
+	}
+
+	return hours, nil
+}
+
+// parseClock parses HH:MM (00:00 to 24:00) into an offset from midnight
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err == nil {
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+	if strings.TrimSpace(s) == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+}
+
+// Contains reports whether t falls within the operating hours, reading t as wall-clock time in loc.
+// Opening times are inclusive, closing times exclusive.
+func (h BusinessHours) Contains(t time.Time, loc *time.Location) bool {
+	if len(h) == 0 {
+		return true
+	}
+
+	local := t.In(loc)
+	offset := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second +
+		time.Duration(local.Nanosecond())
+
+	// Today's window, which may run on past midnight
+	if today, ok := h[local.Weekday()]; ok {
+		if offset >= today.Open && (today.overnight() || offset < today.Close) {
+			return true
+		}
+	}
+
+	// Yesterday's overnight window
+	if yesterday, ok := h[(local.Weekday()+6)%7]; ok && yesterday.overnight() {
+		return offset < yesterday.Close
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBusinessHours(t *testing.T) {
+	hours, err := ParseBusinessHours("mon=08:00-20:00, FRI=18:00-02:00,SAT=10:00-24:00")
+	require.NoError(t, err)
+	assert.Equal(t, BusinessHours{
+		time.Monday:   {Open: 8 * time.Hour, Close: 20 * time.Hour},
+		time.Friday:   {Open: 18 * time.Hour, Close: 2 * time.Hour},
+		time.Saturday: {Open: 10 * time.Hour, Close: 0},
+	}, hours)
+
+	empty, err := ParseBusinessHours("")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	for _, spec := range []string{
+		"MON",
+		"XYZ=08:00-20:00",
+		"MON=08:00",
+		"MON=8am-20:00",
+		"MON=08:00-08:00",
+		"MON=08:00-20:00,MON=09:00-17:00",
+	} {
+		_, err := ParseBusinessHours(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBusinessHours_Contains(t *testing.T) {
+	hours, err := ParseBusinessHours("MON=08:00-20:00,FRI=18:00-02:00")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2026-03-02 is a Monday
+	tests := []struct {
+		name string
+		at   time.Time
+		loc  *time.Location
+		want bool
+	}{
+		{"at opening", time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), time.UTC, true},
+		{"before opening", time.Date(2026, 3, 2, 7, 59, 0, 0, time.UTC), time.UTC, false},
+		{"at closing", time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC), time.UTC, false},
+		{"closed day", time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), time.UTC, false},
+		{"overnight before midnight", time.Date(2026, 3, 6, 23, 0, 0, 0, time.UTC), time.UTC, true},
+		{"overnight after midnight", time.Date(2026, 3, 7, 1, 30, 0, 0, time.UTC), time.UTC, true},
+		{"after overnight close", time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC), time.UTC, false},
+		{"read in time zone", time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC), newYork, true},       // 08:00 EST
+		{"off hours in time zone", time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), newYork, false}, // 07:00 EST
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hours.Contains(tt.at, tt.loc))
+		})
+	}
+
+	assert.True(t, BusinessHours(nil).Contains(time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC), time.UTC))
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Clock supplies the current time so time-dependent behavior can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is a Clock backed by time.Now
+type systemClock struct{}
+
+// Now returns the current wall-clock time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the default Clock used when none is injected
+var SystemClock Clock = systemClock{}
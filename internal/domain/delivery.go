@@ -1,9 +1,18 @@
 package domain
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 )
 
 // DeliveryStatus represents the current status of a delivery
@@ -17,8 +26,182 @@ const (
 	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
 	DeliveryStatusFailed    DeliveryStatus = "FAILED"
 	DeliveryStatusCancelled DeliveryStatus = "CANCELED"
+
+	// DeliveryStatusDeadLetter marks a failed delivery that will never be retried
+	DeliveryStatusDeadLetter DeliveryStatus = "DEAD_LETTER"
+)
+
+// legacyStatusSpellings maps spellings found in older rows and clients to the canonical status.
+// The proto enum spells CANCELLED with two Ls while the domain uses CANCELED.
+var legacyStatusSpellings = map[DeliveryStatus]DeliveryStatus{
+	"CANCELLED": DeliveryStatusCancelled,
+}
+
+// Canonical returns the canonical spelling of s; unknown statuses are returned unchanged
+func (s DeliveryStatus) Canonical() DeliveryStatus {
+	if canonical, ok := legacyStatusSpellings[s]; ok {
+		return canonical
+	}
+	return s
+}
+
+// Spellings returns every spelling s may be stored under, canonical first, so queries also match legacy rows
+func (s DeliveryStatus) Spellings() []DeliveryStatus {
+	canonical := s.Canonical()
+	spellings := []DeliveryStatus{canonical}
+	for legacy, target := range legacyStatusSpellings {
+		if target == canonical {
+			spellings = append(spellings, legacy)
+		}
+	}
+	return spellings
+}
+
+// statusTransitions is the delivery state machine: the statuses each status may move to
+var statusTransitions = map[DeliveryStatus][]DeliveryStatus{
+	DeliveryStatusPending:    {DeliveryStatusAssigned, DeliveryStatusCancelled},
+	DeliveryStatusAssigned:   {DeliveryStatusPickedUp, DeliveryStatusCancelled},
+	DeliveryStatusPickedUp:   {DeliveryStatusInTransit, DeliveryStatusFailed},
+	DeliveryStatusInTransit:  {DeliveryStatusDelivered, DeliveryStatusFailed},
+	DeliveryStatusDelivered:  {},
+	DeliveryStatusFailed:     {DeliveryStatusDeadLetter},
+	DeliveryStatusCancelled:  {},
+	DeliveryStatusDeadLetter: {},
+}
+
+// allStatuses lists every delivery status, active ones first in lifecycle order
+var allStatuses = []DeliveryStatus{
+	DeliveryStatusPending,
+	DeliveryStatusAssigned,
+	DeliveryStatusPickedUp,
+	DeliveryStatusInTransit,
+	DeliveryStatusDelivered,
+	DeliveryStatusFailed,
+	DeliveryStatusCancelled,
+	DeliveryStatusDeadLetter,
+}
+
+// terminalStatuses are the statuses in which a delivery is finished. This is the one list of
+// them; FAILED counts even though it may still be dead-lettered, as nothing more happens to the package.
+var terminalStatuses = []DeliveryStatus{
+	DeliveryStatusDelivered,
+	DeliveryStatusFailed,
+	DeliveryStatusCancelled,
+	DeliveryStatusDeadLetter,
+}
+
+// TerminalStatuses returns the statuses in which a delivery is finished
+func TerminalStatuses() []DeliveryStatus {
+	return slices.Clone(terminalStatuses)
+}
+
+// IsTerminal reports whether s is a status in which the delivery is finished. Legacy spellings
+// count as their canonical status; unknown statuses are not terminal.
+func (s DeliveryStatus) IsTerminal() bool {
+	return slices.Contains(terminalStatuses, s.Canonical())
+}
+
+// AllStatuses returns every delivery status, active ones first in lifecycle order
+func AllStatuses() []DeliveryStatus {
+	return slices.Clone(allStatuses)
+}
+
+// IsActive reports whether the delivery is still in progress: any known status that is not
+// terminal. PENDING is active, as the delivery is waiting for a driver rather than finished.
+// Unknown statuses are neither active nor terminal.
+func (s DeliveryStatus) IsActive() bool {
+	return slices.Contains(allStatuses, s.Canonical()) && !s.IsTerminal()
+}
+
+// ActiveStatuses returns the statuses in which a delivery is in progress, in lifecycle order
+func ActiveStatuses() []DeliveryStatus {
+	var active []DeliveryStatus
+	for _, s := range allStatuses {
+		if s.IsActive() {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// NotifyPrefs lists the statuses a delivery's recipient is notified of; empty means every status
+type NotifyPrefs []DeliveryStatus
+
+// Includes reports whether the recipient wants to be notified when the delivery reaches status
+func (p NotifyPrefs) Includes(status DeliveryStatus) bool {
+	return len(p) == 0 || slices.Contains(p, status)
+}
+
+// AllowedTransitions returns the statuses a delivery in s may move to; none for unknown statuses
+func (s DeliveryStatus) AllowedTransitions() []DeliveryStatus {
+	return append([]DeliveryStatus{}, statusTransitions[s]...)
+}
+
+// StatusTransitions lists the statuses a delivery may move to from one status
+type StatusTransitions struct {
+	From DeliveryStatus
+	To   []DeliveryStatus
+}
+
+// StatusTransitionGraph returns the allowed transitions of every status, in AllStatuses order
+func StatusTransitionGraph() []StatusTransitions {
+	statuses := AllStatuses()
+	graph := make([]StatusTransitions, len(statuses))
+	for i, s := range statuses {
+		graph[i] = StatusTransitions{From: s, To: s.AllowedTransitions()}
+	}
+	return graph
+}
+
+// IsPickedUp reports whether the package has left the pickup location (PICKED_UP or any later status)
+func (s DeliveryStatus) IsPickedUp() bool {
+	switch s {
+	case DeliveryStatusPickedUp, DeliveryStatusInTransit, DeliveryStatusDelivered,
+		DeliveryStatusFailed, DeliveryStatusDeadLetter:
+		return true
+	default:
+		return false
+	}
+}
+
+// PickedUpStatuses returns the statuses in which the package has left the pickup location, in
+// lifecycle order
+func PickedUpStatuses() []DeliveryStatus {
+	var pickedUp []DeliveryStatus
+	for _, s := range allStatuses {
+		if s.IsPickedUp() {
+			pickedUp = append(pickedUp, s)
+		}
+	}
+	return pickedUp
+}
+
+// ActivityFilter groups statuses into in-progress and finished deliveries
+type ActivityFilter string
+
+const (
+	ActivityAny      ActivityFilter = "ANY"
+	ActivityActive   ActivityFilter = "ACTIVE"
+	ActivityTerminal ActivityFilter = "TERMINAL"
+
+	// ActivityDeadLetter selects only permanently-failed deliveries
+	ActivityDeadLetter ActivityFilter = "DEAD_LETTER"
 )
 
+// Statuses returns the statuses covered by the filter, or nil for ActivityAny
+func (f ActivityFilter) Statuses() []DeliveryStatus {
+	switch f {
+	case ActivityActive:
+		return ActiveStatuses()
+	case ActivityTerminal:
+		return TerminalStatuses()
+	case ActivityDeadLetter:
+		return []DeliveryStatus{DeliveryStatusDeadLetter}
+	default:
+		return nil
+	}
+}
+
 // Address represents a physical address with coordinates
 type Address struct {
 	Street     string  `json:"street"`
@@ -30,21 +213,61 @@ type Address struct {
 	Longitude  float64 `json:"longitude"`
 }
 
+// TimedNote is a timestamped note left on a delivery's timeline
+type TimedNote struct {
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Package is one item carried by a delivery
+type Package struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+}
+
 // DeliveryAssignment represents a delivery assignment in the domain
 type DeliveryAssignment struct {
-	ID                    uuid.UUID      `json:"id"`
-	OrderID               string         `json:"order_id"`
-	DriverID              *string        `json:"driver_id,omitempty"`
-	Status                DeliveryStatus `json:"status"`
-	PickupAddress         Address        `json:"pickup_address"`
-	DeliveryAddress       Address        `json:"delivery_address"`
-	ScheduledPickupTime   time.Time      `json:"scheduled_pickup_time"`
-	EstimatedDeliveryTime time.Time      `json:"estimated_delivery_time"`
-	ActualPickupTime      *time.Time     `json:"actual_pickup_time,omitempty"`
-	ActualDeliveryTime    *time.Time     `json:"actual_delivery_time,omitempty"`
-	Notes                 string         `json:"notes"`
-	CreatedAt             time.Time      `json:"created_at"`
-	UpdatedAt             time.Time      `json:"updated_at"`
+	ID                           uuid.UUID        `json:"id"`
+	OrderID                      string           `json:"order_id"`
+	DriverID                     *string          `json:"driver_id,omitempty"`
+	BackupDriverID               *string          `json:"backup_driver_id,omitempty"`   // Takes over if the primary driver is unassigned
+	DriverAssignedAt             *time.Time       `json:"driver_assigned_at,omitempty"` // When DriverID was assigned or promoted from backup
+	Status                       DeliveryStatus   `json:"status"`
+	Priority                     DeliveryPriority `json:"priority"` // Raised automatically while the delivery waits in PENDING
+	PickupAddress                Address          `json:"pickup_address"`
+	DeliveryAddress              Address          `json:"delivery_address"`
+	ScheduledPickupTime          time.Time        `json:"scheduled_pickup_time"`
+	EstimatedDeliveryTime        time.Time        `json:"estimated_delivery_time"`
+	EstimatedDeliveryWindowStart *time.Time       `json:"estimated_delivery_window_start,omitempty"` // Customer-facing range around the estimate
+	EstimatedDeliveryWindowEnd   *time.Time       `json:"estimated_delivery_window_end,omitempty"`
+	DriverETA                    *time.Time       `json:"driver_eta,omitempty"` // Driver's live arrival estimate; EstimatedDeliveryTime stays the commitment
+	ActualPickupTime             *time.Time       `json:"actual_pickup_time,omitempty"`
+	ActualDeliveryTime           *time.Time       `json:"actual_delivery_time,omitempty"`
+	Notes                        string           `json:"notes"`
+	Rating                       *int             `json:"rating,omitempty"`
+	Feedback                     *string          `json:"feedback,omitempty"`
+	TimelineNotes                []TimedNote      `json:"timeline_notes,omitempty"`
+	DeadLetterReason             *string          `json:"dead_letter_reason,omitempty"`
+	FailureReasonCode            *string          `json:"failure_reason_code,omitempty"`    // Why the delivery failed, e.g. WRONG_ADDRESS; details go in the notes
+	TimeZone                     string           `json:"time_zone,omitempty"`              // Merchant's IANA zone for display; times are stored in UTC
+	AllowConcurrent              bool             `json:"allow_concurrent,omitempty"`       // May be active alongside another delivery for the same order
+	RequiredVehicleType          *string          `json:"required_vehicle_type,omitempty"`  // e.g. REFRIGERATED; nil means any vehicle
+	PickupCode                   string           `json:"-"`                                // Given to the sender at creation; required to confirm pickup (empty for legacy rows)
+	FailedPickupAttempts         int              `json:"failed_pickup_attempts,omitempty"` // Wrong pickup codes since the last pickup or lockout
+	PickupLockedUntil            *time.Time       `json:"pickup_locked_until,omitempty"`    // Pickup is refused until then after too many wrong codes
+	Tags                         []string         `json:"tags,omitempty"`                   // Lower-case labels for ops, e.g. "vip"; in the order they were added
+	Packages                     []Package        `json:"packages,omitempty"`               // Packages carried, when known (set on split deliveries)
+	RecipientEmail               *string          `json:"recipient_email,omitempty"`        // Where the recipient is emailed on status changes; nil means not emailed
+	NotifyPrefs                  NotifyPrefs      `json:"notify_prefs,omitempty"`           // Statuses the recipient is notified of; empty means all
+	Metadata                     map[string]any   `json:"metadata,omitempty"`               // Tenant-defined details such as a gate code, as a JSON object
+	ParentDeliveryID             *uuid.UUID       `json:"parent_delivery_id,omitempty"`     // Delivery this one was split or reattempted from
+	AttemptNumber                int              `json:"attempt_number"`                   // 1 for the first attempt, incremented by each reattempt
+	CreatedAt                    time.Time        `json:"created_at"`
+	UpdatedAt                    time.Time        `json:"updated_at"`
+	DeletedAt                    *time.Time       `json:"deleted_at,omitempty"` // Only set when soft-deleted rows are requested (sync)
+
+	clock Clock       // Source of timestamps; nil means SystemClock
+	ids   IDGenerator // Source of IDs for deliveries created from this one; nil means UUIDv4
 }
 
 // NewDeliveryAssignment creates a new delivery assignment with default values
@@ -55,20 +278,153 @@ func NewDeliveryAssignment(
 	scheduledPickupTime time.Time,
 	estimatedDeliveryTime time.Time,
 	notes string,
-) *DeliveryAssignment {
-	now := time.Now()
+) (*DeliveryAssignment, error) {
+	return NewDeliveryAssignmentWithClock(
+		SystemClock,
+		orderID,
+		pickupAddress,
+		deliveryAddress,
+		scheduledPickupTime,
+		estimatedDeliveryTime,
+		notes,
+	)
+}
+
+// NewDeliveryAssignmentWithClock creates a new delivery assignment whose timestamps come from clock
+func NewDeliveryAssignmentWithClock(
+	clock Clock,
+	orderID string,
+	pickupAddress Address,
+	deliveryAddress Address,
+	scheduledPickupTime time.Time,
+	estimatedDeliveryTime time.Time,
+	notes string,
+) (*DeliveryAssignment, error) {
+	return NewDeliveryAssignmentWithIDs(
+		clock,
+		UUIDv4,
+		orderID,
+		pickupAddress,
+		deliveryAddress,
+		scheduledPickupTime,
+		estimatedDeliveryTime,
+		notes,
+	)
+}
+
+// NewDeliveryAssignmentWithIDs creates a new delivery assignment whose timestamps come from clock
+// and whose ID, like those of deliveries later split or reattempted from it, comes from ids
+func NewDeliveryAssignmentWithIDs(
+	clock Clock,
+	ids IDGenerator,
+	orderID string,
+	pickupAddress Address,
+	deliveryAddress Address,
+	scheduledPickupTime time.Time,
+	estimatedDeliveryTime time.Time,
+	notes string,
+) (*DeliveryAssignment, error) {
+	pickupCode, err := generatePickupCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
 	return &DeliveryAssignment{
-		ID:                    uuid.New(),
+		ID:                    ids.NewID(),
 		OrderID:               orderID,
 		Status:                DeliveryStatusPending,
+		Priority:              PriorityNormal,
 		PickupAddress:         pickupAddress,
 		DeliveryAddress:       deliveryAddress,
 		ScheduledPickupTime:   scheduledPickupTime,
 		EstimatedDeliveryTime: estimatedDeliveryTime,
 		Notes:                 notes,
+		PickupCode:            pickupCode,
+		AttemptNumber:         1,
 		CreatedAt:             now,
 		UpdatedAt:             now,
+		clock:                 clock,
+		ids:                   ids,
+	}, nil
+}
+
+// SetClock sets the clock used for timestamps on subsequent state changes
+func (d *DeliveryAssignment) SetClock(clock Clock) {
+	d.clock = clock
+}
+
+// SetIDGenerator sets the generator used for the IDs of deliveries split or reattempted from this one
+func (d *DeliveryAssignment) SetIDGenerator(ids IDGenerator) {
+	d.ids = ids
+}
+
+// idGenerator returns the entity's ID generator, or UUIDv4 when none is set
+func (d *DeliveryAssignment) idGenerator() IDGenerator {
+	if d.ids == nil {
+		return UUIDv4
+	}
+	return d.ids
+}
+
+// now returns the current time from the entity's clock
+func (d *DeliveryAssignment) now() time.Time {
+	return d.clockOrDefault().Now()
+}
+
+// clockOrDefault returns the entity's clock, or SystemClock when none is set
+func (d *DeliveryAssignment) clockOrDefault() Clock {
+	if d.clock == nil {
+		return SystemClock
+	}
+	return d.clock
+}
+
+// Location returns the merchant's time zone, falling back to UTC when unset or unknown
+func (d *DeliveryAssignment) Location() *time.Location {
+	if d.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		return time.UTC
 	}
+	return loc
+}
+
+// LocalScheduledPickupTime returns the scheduled pickup time in the merchant's time zone
+func (d *DeliveryAssignment) LocalScheduledPickupTime() time.Time {
+	return d.ScheduledPickupTime.In(d.Location())
+}
+
+// LocalEstimatedDeliveryTime returns the estimated delivery time in the merchant's time zone
+func (d *DeliveryAssignment) LocalEstimatedDeliveryTime() time.Time {
+	return d.EstimatedDeliveryTime.In(d.Location())
+}
+
+// DeriveDeliveryWindow sets the delivery window to the estimated delivery time plus/minus slack.
+// A non-positive slack clears the window.
+func (d *DeliveryAssignment) DeriveDeliveryWindow(slack time.Duration) {
+	if slack <= 0 {
+		d.EstimatedDeliveryWindowStart = nil
+		d.EstimatedDeliveryWindowEnd = nil
+		return
+	}
+	start := d.EstimatedDeliveryTime.Add(-slack)
+	end := d.EstimatedDeliveryTime.Add(slack)
+	d.EstimatedDeliveryWindowStart = &start
+	d.EstimatedDeliveryWindowEnd = &end
+}
+
+// SLADeadline returns the time by which the delivery is committed to arrive. It is currently the
+// estimated delivery time; callers should use it rather than reading that field directly.
+func (d *DeliveryAssignment) SLADeadline() time.Time {
+	return d.EstimatedDeliveryTime
+}
+
+// HasDeliveryWindow reports whether the delivery has an estimated delivery window
+func (d *DeliveryAssignment) HasDeliveryWindow() bool {
+	return d.EstimatedDeliveryWindowStart != nil && d.EstimatedDeliveryWindowEnd != nil
 }
 
 // AssignDriver assigns a driver to the delivery
@@ -76,23 +432,220 @@ func (d *DeliveryAssignment) AssignDriver(driverID string) error {
 	if d.Status != DeliveryStatusPending {
 		return ErrInvalidStatusTransition
 	}
+	now := d.now()
 	d.DriverID = &driverID
+	d.DriverAssignedAt = &now
 	d.Status = DeliveryStatusAssigned
-	d.UpdatedAt = time.Now()
+	d.UpdatedAt = now
+	return nil
+}
+
+// PickupDueAt is when the current driver was due to pick up the package: the scheduled pickup
+// time, or when they were assigned if that was later, so a driver assigned late or promoted from
+// backup is not held to a pickup time that had already passed
+func (d *DeliveryAssignment) PickupDueAt() time.Time {
+	if d.DriverAssignedAt != nil && d.DriverAssignedAt.After(d.ScheduledPickupTime) {
+		return *d.DriverAssignedAt
+	}
+	return d.ScheduledPickupTime
+}
+
+// AssignBackupDriver sets the driver who takes over if the primary driver is unassigned. A
+// primary driver must be assigned and the delivery not yet picked up; the backup must differ
+// from the primary. Assigning another backup replaces the previous one.
+func (d *DeliveryAssignment) AssignBackupDriver(driverID string) error {
+	if d.Status != DeliveryStatusAssigned || d.DriverID == nil {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpAssignBackupDriver,
+			Message:      "a backup driver can only be assigned once a primary driver is assigned",
+		}
+	}
+	if driverID == *d.DriverID {
+		return &ValidationError{Field: "driver_id", Message: "must differ from the primary driver"}
+	}
+	d.BackupDriverID = &driverID
+	d.UpdatedAt = d.now()
 	return nil
 }
 
-// UpdateStatus updates the delivery status with validation
+// UnassignDriver removes the driver so the delivery can be re-dispatched. If a backup driver is
+// set, they are promoted to primary and the delivery stays ASSIGNED instead.
+// Only deliveries that have not been picked up yet can be unassigned.
+func (d *DeliveryAssignment) UnassignDriver() error {
+	if d.Status != DeliveryStatusAssigned {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpUnassignDriver,
+			Message:      "only assigned deliveries can be unassigned",
+		}
+	}
+	now := d.now()
+	if d.BackupDriverID != nil {
+		d.DriverID = d.BackupDriverID
+		d.DriverAssignedAt = &now
+		d.BackupDriverID = nil
+	} else {
+		d.DriverID = nil
+		d.DriverAssignedAt = nil
+		d.Status = DeliveryStatusPending
+	}
+	d.UpdatedAt = now
+	return nil
+}
+
+// AppendNote adds a timestamped note to the end of the timeline without changing status.
+// Timeline notes are append-only; earlier notes are never modified.
+func (d *DeliveryAssignment) AppendNote(note string) TimedNote {
+	now := d.now()
+	timed := TimedNote{Note: note, CreatedAt: now}
+	d.TimelineNotes = append(d.TimelineNotes, timed)
+	d.UpdatedAt = now
+	return timed
+}
+
+// UpdateDriverETA records the driver's latest arrival estimate without changing status. It is only
+// accepted while the package is on its way (PICKED_UP or IN_TRANSIT) and must be in the future.
+// EstimatedDeliveryTime, and with it the SLA deadline, is left unchanged.
+func (d *DeliveryAssignment) UpdateDriverETA(eta time.Time) error {
+	if d.Status != DeliveryStatusPickedUp && d.Status != DeliveryStatusInTransit {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpUpdateDriverETA,
+			Message:      "driver ETA can only be updated once the package is picked up",
+		}
+	}
+
+	now := d.now()
+	if !eta.After(now) {
+		return &ValidationError{Field: "driver_eta", Message: "must be in the future"}
+	}
+
+	d.DriverETA = &eta
+	d.UpdatedAt = now
+	return nil
+}
+
+// EscalatePriority raises the priority of a delivery still waiting for a driver. Only PENDING
+// deliveries are escalated and the priority is never lowered.
+func (d *DeliveryAssignment) EscalatePriority(priority DeliveryPriority) error {
+	if d.Status != DeliveryStatusPending {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpEscalate,
+			Message:      "only pending deliveries are escalated",
+		}
+	}
+	if !priority.Outranks(d.Priority) {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Priority),
+			RequestedOp:  constants.OpEscalate,
+			Message:      fmt.Sprintf("priority %s does not outrank %s", priority, d.Priority),
+		}
+	}
+
+	d.Priority = priority
+	d.UpdatedAt = d.now()
+	return nil
+}
+
+// UpdateStatus updates the delivery status with validation.
+// Re-sending the current status is an idempotent no-op and leaves timestamps untouched.
+// Moving to PICKED_UP requires the pickup code when the delivery has one; use ConfirmPickup.
 func (d *DeliveryAssignment) UpdateStatus(status DeliveryStatus) error {
+	if status == d.Status {
+		return nil
+	}
+
+	if status == DeliveryStatusPickedUp && d.PickupCode != "" {
+		return &ValidationError{Field: "pickup_code", Message: "is required to confirm pickup"}
+	}
+	if status == DeliveryStatusFailed {
+		return &ValidationError{Field: "failure_reason_code", Message: "is required to fail a delivery"}
+	}
+
+	return d.transitionTo(status)
+}
+
+// Fail moves the delivery to FAILED and records why. The caller checks reasonCode against the
+// configured codes; failing an already failed delivery keeps its original reason.
+func (d *DeliveryAssignment) Fail(reasonCode string) error {
+	if reasonCode == "" {
+		return &ValidationError{Field: "failure_reason_code", Message: "is required to fail a delivery"}
+	}
+
+	if d.Status == DeliveryStatusFailed {
+		return nil
+	}
+	if err := d.transitionTo(DeliveryStatusFailed); err != nil {
+		return err
+	}
+	d.FailureReasonCode = &reasonCode
+	return nil
+}
+
+// PickupLockout stops pickup codes from being guessed: after MaxAttempts wrong codes in a row,
+// every code, the right one included, is refused for Duration. Zero MaxAttempts never locks.
+type PickupLockout struct {
+	MaxAttempts int
+	Duration    time.Duration
+}
+
+// ConfirmPickup moves the delivery to PICKED_UP after checking code against the pickup code
+// generated at creation. Deliveries created before pickup codes existed accept any code.
+// A wrong code is counted in FailedPickupAttempts and may lock pickup under lockout; the caller
+// must save the delivery even though an error is returned. While locked, a RetryAfterError
+// wrapping ErrResourceExhausted is returned.
+func (d *DeliveryAssignment) ConfirmPickup(code string, lockout PickupLockout) error {
+	now := d.now()
+	if d.PickupLockedUntil != nil && now.Before(*d.PickupLockedUntil) {
+		return &RetryAfterError{
+			RetryAfter: d.PickupLockedUntil.Sub(now),
+			Err:        fmt.Errorf("%w: too many wrong pickup codes", ErrResourceExhausted),
+		}
+	}
+
+	if d.PickupCode != "" && subtle.ConstantTimeCompare([]byte(code), []byte(d.PickupCode)) != 1 {
+		if code == "" {
+			return &ValidationError{Field: "pickup_code", Message: "is required to confirm pickup"}
+		}
+		d.FailedPickupAttempts++
+		if lockout.MaxAttempts > 0 && d.FailedPickupAttempts >= lockout.MaxAttempts {
+			lockedUntil := now.Add(lockout.Duration)
+			d.PickupLockedUntil = &lockedUntil
+			d.FailedPickupAttempts = 0
+		}
+		d.UpdatedAt = now
+		return &ValidationError{Field: "pickup_code", Message: "does not match"}
+	}
+
+	if d.Status == DeliveryStatusPickedUp {
+		return nil
+	}
+	if err := d.transitionTo(DeliveryStatusPickedUp); err != nil {
+		return err
+	}
+	d.FailedPickupAttempts = 0
+	d.PickupLockedUntil = nil
+	return nil
+}
+
+// transitionTo changes the status and records status timestamps
+func (d *DeliveryAssignment) transitionTo(status DeliveryStatus) error {
 	if !d.isValidStatusTransition(status) {
 		return ErrInvalidStatusTransition
 	}
 
+	now := d.now()
 	d.Status = status
-	d.UpdatedAt = time.Now()
+	d.UpdatedAt = now
 
 	// Set timestamps based on status
-	now := time.Now()
 	switch status {
 	case DeliveryStatusPickedUp:
 		d.ActualPickupTime = &now
@@ -103,24 +656,228 @@ func (d *DeliveryAssignment) UpdateStatus(status DeliveryStatus) error {
 	return nil
 }
 
-// isValidStatusTransition checks if a status transition is valid
-func (d *DeliveryAssignment) isValidStatusTransition(newStatus DeliveryStatus) bool {
-	validTransitions := map[DeliveryStatus][]DeliveryStatus{
-		DeliveryStatusPending:   {DeliveryStatusAssigned, DeliveryStatusCancelled},
-		DeliveryStatusAssigned:  {DeliveryStatusPickedUp, DeliveryStatusCancelled},
-		DeliveryStatusPickedUp:  {DeliveryStatusInTransit, DeliveryStatusFailed},
-		DeliveryStatusInTransit: {DeliveryStatusDelivered, DeliveryStatusFailed},
-		DeliveryStatusDelivered: {},
-		DeliveryStatusFailed:    {},
-		DeliveryStatusCancelled: {},
-	}
-
-	allowed, exists := validTransitions[d.Status]
-	if !exists {
+// Split records a partial delivery: this delivery becomes DELIVERED and the returned PENDING
+// child, linked through ParentDeliveryID, carries the remaining packages to be delivered at the
+// given times. The child keeps the order, addresses, notes and delivery requirements. When this
+// delivery's packages are known, the remaining ones are moved off it so each package is counted
+// once; the remaining packages must then be among those it carries, and at least one package
+// must stay with this delivery, since nothing would have been delivered otherwise.
+func (d *DeliveryAssignment) Split(remaining []Package, scheduledPickupTime, estimatedDeliveryTime time.Time) (*DeliveryAssignment, error) {
+	if len(remaining) == 0 {
+		return nil, &ValidationError{Field: "remaining_packages", Message: "at least one package is required"}
+	}
+	delivered, err := d.packagesWithout(remaining)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.Packages) > 0 && len(delivered) == 0 {
+		return nil, &ValidationError{Field: "remaining_packages", Message: "must leave at least one package delivered"}
+	}
+	child, err := d.newChild(scheduledPickupTime, estimatedDeliveryTime)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.transitionTo(DeliveryStatusDelivered); err != nil {
+		return nil, err
+	}
+
+	d.Packages = delivered
+	child.Packages = slices.Clone(remaining)
+
+	return child, nil
+}
+
+// packagesWithout returns this delivery's packages less the given ones, matched by description.
+// A delivery whose packages are not known has nothing to remove.
+func (d *DeliveryAssignment) packagesWithout(removed []Package) ([]Package, error) {
+	if len(d.Packages) == 0 {
+		return nil, nil
+	}
+
+	kept := slices.Clone(d.Packages)
+	for i, p := range removed {
+		quantity := p.Quantity
+		for j := range kept {
+			if quantity == 0 {
+				break
+			}
+			if kept[j].Description == p.Description {
+				taken := min(kept[j].Quantity, quantity)
+				kept[j].Quantity -= taken
+				quantity -= taken
+			}
+		}
+		if quantity > 0 {
+			return nil, &ValidationError{
+				Field:   fmt.Sprintf("remaining_packages[%d]", i),
+				Message: "exceeds the packages carried by the delivery",
+			}
+		}
+	}
+
+	return slices.DeleteFunc(kept, func(p Package) bool { return p.Quantity == 0 }), nil
+}
+
+// Reattempt returns a new PENDING delivery that tries a failed one again at the given times.
+// It is linked through ParentDeliveryID, has the next AttemptNumber, and keeps the order,
+// addresses, notes, packages and delivery requirements. This delivery is left FAILED for audit.
+func (d *DeliveryAssignment) Reattempt(scheduledPickupTime, estimatedDeliveryTime time.Time) (*DeliveryAssignment, error) {
+	if d.Status != DeliveryStatusFailed {
+		return nil, &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpReattempt,
+			Message:      "only failed deliveries can be reattempted",
+		}
+	}
+
+	child, err := d.newChild(scheduledPickupTime, estimatedDeliveryTime)
+	if err != nil {
+		return nil, err
+	}
+	child.Packages = slices.Clone(d.Packages)
+	child.AttemptNumber = max(d.AttemptNumber, 1) + 1
+
+	return child, nil
+}
+
+// newChild returns a PENDING delivery linked to this one through ParentDeliveryID, with the
+// same order, addresses, notes, priority and delivery requirements
+func (d *DeliveryAssignment) newChild(scheduledPickupTime, estimatedDeliveryTime time.Time) (*DeliveryAssignment, error) {
+	child, err := NewDeliveryAssignmentWithIDs(
+		d.clockOrDefault(),
+		d.idGenerator(),
+		d.OrderID,
+		d.PickupAddress,
+		d.DeliveryAddress,
+		scheduledPickupTime,
+		estimatedDeliveryTime,
+		d.Notes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	child.TimeZone = d.TimeZone
+	child.AllowConcurrent = d.AllowConcurrent
+	child.Priority = d.Priority
+	child.RequiredVehicleType = d.RequiredVehicleType
+	child.Tags = slices.Clone(d.Tags)
+	child.RecipientEmail = d.RecipientEmail
+	child.NotifyPrefs = slices.Clone(d.NotifyPrefs)
+	child.Metadata = maps.Clone(d.Metadata)
+	parentID := d.ID
+	child.ParentDeliveryID = &parentID
+	return child, nil
+}
+
+// SubmitFeedback records the recipient's rating and optional feedback.
+// Feedback can only be captured once the delivery has been delivered.
+func (d *DeliveryAssignment) SubmitFeedback(rating int, feedback string) error {
+	if d.Status != DeliveryStatusDelivered {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpFeedback,
+			Message:      "feedback can only be submitted for delivered deliveries",
+		}
+	}
+
+	d.Rating = &rating
+	if feedback != "" {
+		d.Feedback = &feedback
+	}
+	d.UpdatedAt = d.now()
+	return nil
+}
+
+// MarkDeadLetter moves a failed delivery to DEAD_LETTER so it is never retried.
+// Only FAILED deliveries can be dead-lettered.
+func (d *DeliveryAssignment) MarkDeadLetter(reason string) error {
+	if d.Status != DeliveryStatusFailed {
+		return &ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(d.Status),
+			RequestedOp:  constants.OpDeadLetter,
+			Message:      "only failed deliveries can be dead-lettered",
+		}
+	}
+
+	d.Status = DeliveryStatusDeadLetter
+	d.DeadLetterReason = &reason
+	d.UpdatedAt = d.now()
+	return nil
+}
+
+// AcceptsVehicle reports whether a driver with the given vehicle type can take the delivery
+func (d *DeliveryAssignment) AcceptsVehicle(vehicleType string) bool {
+	return d.RequiredVehicleType == nil || strings.EqualFold(*d.RequiredVehicleType, vehicleType)
+}
+
+// HasTag reports whether the delivery is tagged with tag
+func (d *DeliveryAssignment) HasTag(tag string) bool {
+	return slices.Contains(d.Tags, tag)
+}
+
+// AddTags adds the tags the delivery does not have yet and reports whether any were added.
+// Tags are compared as given, so callers normalize them first.
+func (d *DeliveryAssignment) AddTags(tags ...string) (bool, error) {
+	merged := slices.Clone(d.Tags)
+	for _, tag := range tags {
+		if !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+
+	if len(merged) == len(d.Tags) {
+		return false, nil
+	}
+	if len(merged) > constants.MaxTagsPerDelivery {
+		return false, &ValidationError{
+			Field:   "tags",
+			Message: fmt.Sprintf("a delivery can have at most %d tags", constants.MaxTagsPerDelivery),
+		}
+	}
+
+	d.Tags = merged
+	d.UpdatedAt = d.now()
+	return true, nil
+}
+
+// RemoveTags removes the given tags and reports whether the delivery had any of them
+func (d *DeliveryAssignment) RemoveTags(tags ...string) bool {
+	kept := slices.DeleteFunc(slices.Clone(d.Tags), func(tag string) bool {
+		return slices.Contains(tags, tag)
+	})
+
+	if len(kept) == len(d.Tags) {
 		return false
 	}
 
-	for _, status := range allowed {
+	d.Tags = kept
+	d.UpdatedAt = d.now()
+	return true
+}
+
+// LifecycleDuration returns the time from creation to delivery.
+// It reports false until the delivery has been delivered.
+func (d *DeliveryAssignment) LifecycleDuration() (time.Duration, bool) {
+	if d.Status != DeliveryStatusDelivered || d.ActualDeliveryTime == nil {
+		return 0, false
+	}
+	return d.ActualDeliveryTime.Sub(d.CreatedAt), true
+}
+
+// ModifiedAt returns when the delivery last changed, counting a soft delete as a change
+func (d *DeliveryAssignment) ModifiedAt() time.Time {
+	if d.DeletedAt != nil && d.DeletedAt.After(d.UpdatedAt) {
+		return *d.DeletedAt
+	}
+	return d.UpdatedAt
+}
+
+// isValidStatusTransition checks if a status transition is valid
+func (d *DeliveryAssignment) isValidStatusTransition(newStatus DeliveryStatus) bool {
+	for _, status := range d.Status.AllowedTransitions() {
 		if status == newStatus {
 			return true
 		}
@@ -129,12 +886,61 @@ func (d *DeliveryAssignment) isValidStatusTransition(newStatus DeliveryStatus) b
 	return false
 }
 
+// DeliveryStatusInfo is a lightweight view of a delivery's current status for polling clients
+type DeliveryStatusInfo struct {
+	ID        uuid.UUID      `json:"id"`
+	Status    DeliveryStatus `json:"status"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// DeliverySLA is a delivery's SLA deadline and the time left until it at the moment it was read
+type DeliverySLA struct {
+	ID        uuid.UUID     `json:"id"`
+	Deadline  time.Time     `json:"deadline"`
+	Remaining time.Duration `json:"remaining"` // Zero once the deadline has passed
+}
+
+// DriverStats is a driver's position on the leaderboard for a time range
+type DriverStats struct {
+	Rank                int32   `json:"rank"`
+	DriverID            string  `json:"driver_id"`
+	CompletedDeliveries int32   `json:"completed_deliveries"`
+	OnTimeDeliveryRate  float64 `json:"on_time_delivery_rate"`
+}
+
+// DriverLocation is a location ping reported by a driver's device
+type DriverLocation struct {
+	DriverID   string    `json:"driver_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	RecordedAt time.Time `json:"recorded_at"` // When the device took the fix
+}
+
 // DeliveryMetrics contains aggregated delivery statistics
 type DeliveryMetrics struct {
-	TotalDeliveries            int32   `json:"total_deliveries"`
-	CompletedDeliveries        int32   `json:"completed_deliveries"`
-	FailedDeliveries           int32   `json:"failed_deliveries"`
-	CancelledDeliveries        int32   `json:"canceled_deliveries"`
-	AverageDeliveryTimeMinutes float64 `json:"average_delivery_time_minutes"`
-	OnTimeDeliveryRate         float64 `json:"on_time_delivery_rate"`
+	TotalDeliveries                 int32            `json:"total_deliveries"`
+	CompletedDeliveries             int32            `json:"completed_deliveries"`
+	FailedDeliveries                int32            `json:"failed_deliveries"`
+	CancelledDeliveries             int32            `json:"canceled_deliveries"`
+	DeadLetterDeliveries            int32            `json:"dead_letter_deliveries"`
+	AverageDeliveryTimeMinutes      float64          `json:"average_delivery_time_minutes"`
+	AverageLifecycleDurationMinutes float64          `json:"average_lifecycle_duration_minutes"` // Created to delivered
+	OnTimeDeliveryRate              float64          `json:"on_time_delivery_rate"`
+	AverageRating                   float64          `json:"average_rating"`
+	FailuresByReason                map[string]int32 `json:"failures_by_reason,omitempty"` // FAILED and DEAD_LETTER deliveries per failure reason code
+	Errors                          []string         `json:"errors,omitempty"`             // Sub-metrics that failed and were left at zero
+}
+
+// generatePickupCode returns a random numeric code of constants.PickupCodeLength digits
+func generatePickupCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < constants.PickupCodeLength; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pickup code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", constants.PickupCodeLength, n), nil
 }
@@ -1,11 +1,17 @@
 package domain
 
 import (
+	"bytes"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 )
 
 func TestNewDeliveryAssignment(t *testing.T) {
@@ -16,7 +22,7 @@ func TestNewDeliveryAssignment(t *testing.T) {
 	estimatedTime := time.Now().Add(3 * time.Hour)
 	notes := "Handle with care"
 
-	assignment := NewDeliveryAssignment(
+	assignment, err := NewDeliveryAssignment(
 		orderID,
 		pickupAddr,
 		deliveryAddr,
@@ -25,6 +31,7 @@ func TestNewDeliveryAssignment(t *testing.T) {
 		notes,
 	)
 
+	require.NoError(t, err)
 	assert.NotNil(t, assignment)
 	assert.NotEqual(t, assignment.ID.String(), "")
 	assert.Equal(t, orderID, assignment.OrderID)
@@ -77,6 +84,8 @@ func TestAssignDriver(t *testing.T) {
 				require.NoError(t, err)
 				assert.Equal(t, tt.driverID, *assignment.DriverID)
 				assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+				require.NotNil(t, assignment.DriverAssignedAt)
+				assert.Equal(t, assignment.UpdatedAt, *assignment.DriverAssignedAt)
 			}
 		})
 	}
@@ -159,6 +168,34 @@ func TestUpdateStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateStatus_SameStatusIsNoOp(t *testing.T) {
+	updatedAt := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	pickedUpAt := updatedAt.Add(-time.Hour)
+
+	for _, status := range []DeliveryStatus{DeliveryStatusPending, DeliveryStatusPickedUp, DeliveryStatusDelivered} {
+		t.Run(string(status), func(t *testing.T) {
+			assignment := &DeliveryAssignment{
+				Status:           status,
+				UpdatedAt:        updatedAt,
+				ActualPickupTime: &pickedUpAt,
+				clock:            &fakeClock{now: updatedAt.Add(time.Hour)},
+			}
+
+			err := assignment.UpdateStatus(status)
+
+			require.NoError(t, err)
+			assert.Equal(t, status, assignment.Status)
+			assert.Equal(t, updatedAt, assignment.UpdatedAt)
+			assert.Equal(t, &pickedUpAt, assignment.ActualPickupTime)
+			assert.Nil(t, assignment.ActualDeliveryTime)
+		})
+	}
+
+	// Illegal transitions are still rejected
+	assignment := &DeliveryAssignment{Status: DeliveryStatusPending}
+	assert.ErrorIs(t, assignment.UpdateStatus(DeliveryStatusDelivered), ErrInvalidStatusTransition)
+}
+
 func TestIsValidStatusTransition(t *testing.T) {
 	assignment := &DeliveryAssignment{
 		Status: DeliveryStatusPending,
@@ -172,8 +209,636 @@ func TestIsValidStatusTransition(t *testing.T) {
 	assert.False(t, assignment.isValidStatusTransition(DeliveryStatusPickedUp))
 	assert.False(t, assignment.isValidStatusTransition(DeliveryStatusDelivered))
 
+	// FAILED can only be dead-lettered
+	assignment.Status = DeliveryStatusFailed
+	assert.True(t, assignment.isValidStatusTransition(DeliveryStatusDeadLetter))
+	assert.False(t, assignment.isValidStatusTransition(DeliveryStatusPending))
+
 	// Test no transitions from final states
 	assignment.Status = DeliveryStatusDelivered
 	assert.False(t, assignment.isValidStatusTransition(DeliveryStatusPending))
 	assert.False(t, assignment.isValidStatusTransition(DeliveryStatusAssigned))
 }
+
+func TestSubmitFeedback(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      DeliveryStatus
+		expectError bool
+	}{
+		{name: "delivered accepts feedback", status: DeliveryStatusDelivered, expectError: false},
+		{name: "in transit rejects feedback", status: DeliveryStatusInTransit, expectError: true},
+		{name: "pending rejects feedback", status: DeliveryStatusPending, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &DeliveryAssignment{
+				Status: tt.status,
+			}
+
+			err := assignment.SubmitFeedback(5, "Great service")
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Nil(t, assignment.Rating)
+				assert.Nil(t, assignment.Feedback)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, assignment.Rating)
+				assert.Equal(t, 5, *assignment.Rating)
+				require.NotNil(t, assignment.Feedback)
+				assert.Equal(t, "Great service", *assignment.Feedback)
+			}
+		})
+	}
+}
+
+func TestUpdateDriverETA(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	estimate := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name      string
+		status    DeliveryStatus
+		eta       time.Time
+		expectErr error
+	}{
+		{name: "picked up", status: DeliveryStatusPickedUp, eta: now.Add(30 * time.Minute)},
+		{name: "in transit", status: DeliveryStatusInTransit, eta: now.Add(3 * time.Hour)},
+		{name: "assigned is not on its way yet", status: DeliveryStatusAssigned, eta: now.Add(30 * time.Minute), expectErr: ErrConflict},
+		{name: "delivered is finished", status: DeliveryStatusDelivered, eta: now.Add(30 * time.Minute), expectErr: ErrConflict},
+		{name: "eta in the past", status: DeliveryStatusInTransit, eta: now.Add(-time.Minute), expectErr: ErrInvalidInput},
+		{name: "eta now", status: DeliveryStatusInTransit, eta: now, expectErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &DeliveryAssignment{
+				Status:                tt.status,
+				EstimatedDeliveryTime: estimate,
+				clock:                 &fakeClock{now: now},
+			}
+
+			err := assignment.UpdateDriverETA(tt.eta)
+
+			// The committed estimate never moves
+			assert.Equal(t, estimate, assignment.EstimatedDeliveryTime)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, assignment.DriverETA)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, assignment.DriverETA)
+			assert.Equal(t, tt.eta, *assignment.DriverETA)
+			assert.Equal(t, tt.status, assignment.Status)
+			assert.Equal(t, now, assignment.UpdatedAt)
+		})
+	}
+}
+
+func TestUnassignDriver(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      DeliveryStatus
+		expectError bool
+	}{
+		{name: "assigned can be unassigned", status: DeliveryStatusAssigned, expectError: false},
+		{name: "pending cannot be unassigned", status: DeliveryStatusPending, expectError: true},
+		{name: "picked up cannot be unassigned", status: DeliveryStatusPickedUp, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driverID := "DRIVER-123"
+			assignment := &DeliveryAssignment{
+				Status:   tt.status,
+				DriverID: &driverID,
+			}
+
+			err := assignment.UnassignDriver()
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+				assert.Equal(t, tt.status, assignment.Status)
+				assert.NotNil(t, assignment.DriverID)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, DeliveryStatusPending, assignment.Status)
+				assert.Nil(t, assignment.DriverID)
+			}
+		})
+	}
+}
+
+func TestAssignBackupDriver(t *testing.T) {
+	primary := "DRIVER-1"
+	assignment := &DeliveryAssignment{Status: DeliveryStatusAssigned, DriverID: &primary}
+
+	require.NoError(t, assignment.AssignBackupDriver("DRIVER-2"))
+	require.NotNil(t, assignment.BackupDriverID)
+	assert.Equal(t, "DRIVER-2", *assignment.BackupDriverID)
+	assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+	assert.False(t, assignment.UpdatedAt.IsZero())
+
+	// The primary cannot be their own backup
+	var validationErr *ValidationError
+	require.ErrorAs(t, assignment.AssignBackupDriver(primary), &validationErr)
+	assert.Equal(t, "driver_id", validationErr.Field)
+	assert.Equal(t, "DRIVER-2", *assignment.BackupDriverID)
+
+	// A backup needs a primary
+	pending := &DeliveryAssignment{Status: DeliveryStatusPending}
+	assert.ErrorIs(t, pending.AssignBackupDriver("DRIVER-2"), ErrInvalidStatusTransition)
+	assert.Nil(t, pending.BackupDriverID)
+}
+
+func TestUnassignDriver_PromotesBackup(t *testing.T) {
+	primary, backup := "DRIVER-1", "DRIVER-2"
+	scheduled := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: scheduled.Add(30 * time.Minute)}
+	assignment := &DeliveryAssignment{
+		Status:              DeliveryStatusAssigned,
+		DriverID:            &primary,
+		BackupDriverID:      &backup,
+		ScheduledPickupTime: scheduled,
+		clock:               clock,
+	}
+
+	require.NoError(t, assignment.UnassignDriver())
+	assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+	require.NotNil(t, assignment.DriverID)
+	assert.Equal(t, backup, *assignment.DriverID)
+	assert.Nil(t, assignment.BackupDriverID)
+	// The backup's pickup is due from their promotion, not the schedule that had already passed
+	assert.Equal(t, clock.now, assignment.PickupDueAt())
+
+	// With the backup used up, the next unassignment puts the delivery back to PENDING
+	require.NoError(t, assignment.UnassignDriver())
+	assert.Equal(t, DeliveryStatusPending, assignment.Status)
+	assert.Nil(t, assignment.DriverID)
+	assert.Nil(t, assignment.DriverAssignedAt)
+	assert.Equal(t, scheduled, assignment.PickupDueAt())
+}
+
+func TestPickupDueAt(t *testing.T) {
+	scheduled := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	early, late := scheduled.Add(-time.Hour), scheduled.Add(time.Hour)
+
+	assert.Equal(t, scheduled, (&DeliveryAssignment{ScheduledPickupTime: scheduled}).PickupDueAt())
+	assert.Equal(t, scheduled, (&DeliveryAssignment{ScheduledPickupTime: scheduled, DriverAssignedAt: &early}).PickupDueAt())
+	assert.Equal(t, late, (&DeliveryAssignment{ScheduledPickupTime: scheduled, DriverAssignedAt: &late}).PickupDueAt())
+}
+
+func TestMarkDeadLetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      DeliveryStatus
+		expectError bool
+	}{
+		{name: "failed can be dead-lettered", status: DeliveryStatusFailed, expectError: false},
+		{name: "in transit cannot be dead-lettered", status: DeliveryStatusInTransit, expectError: true},
+		{name: "cancelled cannot be dead-lettered", status: DeliveryStatusCancelled, expectError: true},
+		{name: "dead letter cannot be dead-lettered again", status: DeliveryStatusDeadLetter, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &DeliveryAssignment{
+				Status: tt.status,
+			}
+
+			err := assignment.MarkDeadLetter("address does not exist")
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrConflict)
+				assert.Equal(t, tt.status, assignment.Status)
+				assert.Nil(t, assignment.DeadLetterReason)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, DeliveryStatusDeadLetter, assignment.Status)
+				require.NotNil(t, assignment.DeadLetterReason)
+				assert.Equal(t, "address does not exist", *assignment.DeadLetterReason)
+			}
+		})
+	}
+}
+
+func TestDeadLetterIsTerminal(t *testing.T) {
+	assignment := &DeliveryAssignment{
+		Status: DeliveryStatusDeadLetter,
+	}
+
+	for _, status := range []DeliveryStatus{
+		DeliveryStatusPending,
+		DeliveryStatusAssigned,
+		DeliveryStatusPickedUp,
+		DeliveryStatusInTransit,
+		DeliveryStatusDelivered,
+		DeliveryStatusFailed,
+		DeliveryStatusCancelled,
+	} {
+		assert.False(t, assignment.isValidStatusTransition(status), "DEAD_LETTER -> %s", status)
+	}
+
+	assert.False(t, DeliveryStatusDeadLetter.IsActive())
+	assert.Contains(t, ActivityTerminal.Statuses(), DeliveryStatusDeadLetter)
+	assert.Equal(t, []DeliveryStatus{DeliveryStatusDeadLetter}, ActivityDeadLetter.Statuses())
+}
+
+func TestLocalTimes_AcrossDST(t *testing.T) {
+	// US daylight saving time starts 2026-03-08 at 02:00 local time
+	assignment := &DeliveryAssignment{
+		TimeZone:              "America/New_York",
+		ScheduledPickupTime:   time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC),
+		EstimatedDeliveryTime: time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC),
+	}
+
+	pickup := assignment.LocalScheduledPickupTime()
+	delivery := assignment.LocalEstimatedDeliveryTime()
+
+	assert.Equal(t, "2026-03-08T01:30:00-05:00", pickup.Format(time.RFC3339))
+	assert.Equal(t, "2026-03-08T03:30:00-04:00", delivery.Format(time.RFC3339))
+	// Same instants, only the presentation changes
+	assert.True(t, pickup.Equal(assignment.ScheduledPickupTime))
+	assert.Equal(t, time.Hour, delivery.Sub(pickup))
+}
+
+func TestLocation_DefaultsToUTC(t *testing.T) {
+	assert.Equal(t, time.UTC, (&DeliveryAssignment{}).Location())
+	assert.Equal(t, time.UTC, (&DeliveryAssignment{TimeZone: "Mars/Olympus_Mons"}).Location())
+}
+
+func TestDeriveDeliveryWindow(t *testing.T) {
+	estimate := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	assignment := &DeliveryAssignment{EstimatedDeliveryTime: estimate}
+
+	assignment.DeriveDeliveryWindow(30 * time.Minute)
+	require.True(t, assignment.HasDeliveryWindow())
+	assert.Equal(t, estimate.Add(-30*time.Minute), *assignment.EstimatedDeliveryWindowStart)
+	assert.Equal(t, estimate.Add(30*time.Minute), *assignment.EstimatedDeliveryWindowEnd)
+
+	assignment.DeriveDeliveryWindow(0)
+	assert.False(t, assignment.HasDeliveryWindow())
+	assert.Nil(t, assignment.EstimatedDeliveryWindowStart)
+	assert.Nil(t, assignment.EstimatedDeliveryWindowEnd)
+}
+
+// fakeClock returns a fixed instant that tests can advance
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestClockDrivenTimestamps(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	created := clock.now
+
+	assignment, err := NewDeliveryAssignmentWithClock(
+		clock,
+		"ORDER-123",
+		Address{City: "New York"},
+		Address{City: "Boston"},
+		created.Add(1*time.Hour),
+		created.Add(3*time.Hour),
+		"",
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, created, assignment.CreatedAt)
+	assert.Equal(t, created, assignment.UpdatedAt)
+
+	clock.now = created.Add(10 * time.Minute)
+	require.NoError(t, assignment.AssignDriver("DRIVER-123"))
+	assert.Equal(t, clock.now, assignment.UpdatedAt)
+
+	clock.now = created.Add(70 * time.Minute)
+	require.NoError(t, assignment.ConfirmPickup(assignment.PickupCode, PickupLockout{}))
+	require.NotNil(t, assignment.ActualPickupTime)
+	assert.Equal(t, clock.now, *assignment.ActualPickupTime)
+	assert.Equal(t, clock.now, assignment.UpdatedAt)
+
+	require.NoError(t, assignment.UpdateStatus(DeliveryStatusInTransit))
+
+	clock.now = created.Add(150 * time.Minute)
+	require.NoError(t, assignment.UpdateStatus(DeliveryStatusDelivered))
+	require.NotNil(t, assignment.ActualDeliveryTime)
+	assert.Equal(t, clock.now, *assignment.ActualDeliveryTime)
+	assert.Equal(t, created, assignment.CreatedAt)
+}
+
+func TestConfirmPickup(t *testing.T) {
+	tests := []struct {
+		name       string
+		pickupCode string
+		code       string
+		expectErr  bool
+	}{
+		{name: "matching code", pickupCode: "042917", code: "042917", expectErr: false},
+		{name: "wrong code", pickupCode: "042917", code: "042918", expectErr: true},
+		{name: "missing code", pickupCode: "042917", code: "", expectErr: true},
+		{name: "legacy delivery without code", pickupCode: "", code: "", expectErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driverID := "DRIVER-123"
+			assignment := &DeliveryAssignment{
+				Status:     DeliveryStatusAssigned,
+				DriverID:   &driverID,
+				PickupCode: tt.pickupCode,
+			}
+
+			err := assignment.ConfirmPickup(tt.code, PickupLockout{})
+
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidInput)
+				assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+				assert.Nil(t, assignment.ActualPickupTime)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, DeliveryStatusPickedUp, assignment.Status)
+				assert.NotNil(t, assignment.ActualPickupTime)
+			}
+		})
+	}
+}
+
+func TestConfirmPickup_LocksAfterRepeatedWrongCodes(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	lockout := PickupLockout{MaxAttempts: 3, Duration: 15 * time.Minute}
+	driverID := "DRIVER-123"
+	assignment := &DeliveryAssignment{
+		Status:     DeliveryStatusAssigned,
+		DriverID:   &driverID,
+		PickupCode: "042917",
+		clock:      clock,
+	}
+
+	// A missing code is a malformed request, not a guess
+	require.ErrorIs(t, assignment.ConfirmPickup("", lockout), ErrInvalidInput)
+	assert.Zero(t, assignment.FailedPickupAttempts)
+
+	for i := 1; i < lockout.MaxAttempts; i++ {
+		require.ErrorIs(t, assignment.ConfirmPickup("000000", lockout), ErrInvalidInput)
+		assert.Equal(t, i, assignment.FailedPickupAttempts)
+		assert.Nil(t, assignment.PickupLockedUntil)
+	}
+
+	// The last allowed guess locks pickup, even for the right code
+	require.ErrorIs(t, assignment.ConfirmPickup("000000", lockout), ErrInvalidInput)
+	require.NotNil(t, assignment.PickupLockedUntil)
+	assert.Equal(t, clock.now.Add(lockout.Duration), *assignment.PickupLockedUntil)
+
+	clock.now = clock.now.Add(5 * time.Minute)
+	err := assignment.ConfirmPickup("042917", lockout)
+	assert.ErrorIs(t, err, ErrResourceExhausted)
+	var retryErr *RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 10*time.Minute, retryErr.RetryAfter)
+	assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+
+	// Once the lockout passes the right code confirms pickup and clears the record
+	clock.now = clock.now.Add(10 * time.Minute)
+	require.NoError(t, assignment.ConfirmPickup("042917", lockout))
+	assert.Equal(t, DeliveryStatusPickedUp, assignment.Status)
+	assert.Zero(t, assignment.FailedPickupAttempts)
+	assert.Nil(t, assignment.PickupLockedUntil)
+}
+
+func TestUpdateStatus_PickupRequiresCode(t *testing.T) {
+	driverID := "DRIVER-123"
+	assignment := &DeliveryAssignment{
+		Status:     DeliveryStatusAssigned,
+		DriverID:   &driverID,
+		PickupCode: "042917",
+	}
+
+	assert.ErrorIs(t, assignment.UpdateStatus(DeliveryStatusPickedUp), ErrInvalidInput)
+	assert.Equal(t, DeliveryStatusAssigned, assignment.Status)
+}
+
+func TestNewDeliveryAssignment_GeneratesPickupCode(t *testing.T) {
+	assignment, err := NewDeliveryAssignment("ORDER-123", Address{}, Address{}, time.Now(), time.Now().Add(time.Hour), "")
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^[0-9]{6}$`, assignment.PickupCode)
+}
+
+func TestLifecycleDuration(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	delivered := created.Add(150 * time.Minute)
+
+	tests := []struct {
+		name       string
+		status     DeliveryStatus
+		deliveryAt *time.Time
+		expected   time.Duration
+		ok         bool
+	}{
+		{name: "delivered", status: DeliveryStatusDelivered, deliveryAt: &delivered, expected: 150 * time.Minute, ok: true},
+		{name: "in transit", status: DeliveryStatusInTransit, deliveryAt: nil, expected: 0, ok: false},
+		{name: "failed", status: DeliveryStatusFailed, deliveryAt: nil, expected: 0, ok: false},
+		{name: "delivered without delivery time", status: DeliveryStatusDelivered, deliveryAt: nil, expected: 0, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &DeliveryAssignment{
+				Status:             tt.status,
+				CreatedAt:          created,
+				ActualDeliveryTime: tt.deliveryAt,
+			}
+
+			duration, ok := assignment.LifecycleDuration()
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, duration)
+		})
+	}
+}
+
+func TestAcceptsVehicle(t *testing.T) {
+	refrigerated := "REFRIGERATED"
+
+	assert.True(t, (&DeliveryAssignment{}).AcceptsVehicle("BIKE"), "no requirement accepts any vehicle")
+	assert.True(t, (&DeliveryAssignment{RequiredVehicleType: &refrigerated}).AcceptsVehicle("refrigerated"))
+	assert.False(t, (&DeliveryAssignment{RequiredVehicleType: &refrigerated}).AcceptsVehicle("VAN"))
+}
+
+func TestTags_AddRemoveIdempotent(t *testing.T) {
+	d := &DeliveryAssignment{}
+
+	added, err := d.AddTags("vip", "promo", "vip")
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{"vip", "promo"}, d.Tags)
+
+	added, err = d.AddTags("promo")
+	require.NoError(t, err)
+	assert.False(t, added, "adding an existing tag is a no-op")
+	assert.Equal(t, []string{"vip", "promo"}, d.Tags)
+
+	assert.True(t, d.RemoveTags("vip", "unknown"))
+	assert.Equal(t, []string{"promo"}, d.Tags)
+	assert.False(t, d.RemoveTags("vip"), "removing a missing tag is a no-op")
+	assert.True(t, d.HasTag("promo"))
+}
+
+func TestTags_MaxPerDelivery(t *testing.T) {
+	d := &DeliveryAssignment{}
+	for i := 0; i < constants.MaxTagsPerDelivery; i++ {
+		_, err := d.AddTags(fmt.Sprintf("tag-%d", i))
+		require.NoError(t, err)
+	}
+
+	_, err := d.AddTags("one-too-many")
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.Len(t, d.Tags, constants.MaxTagsPerDelivery)
+}
+
+func TestIsTerminal(t *testing.T) {
+	tests := []struct {
+		status   DeliveryStatus
+		terminal bool
+	}{
+		{status: DeliveryStatusPending, terminal: false},
+		{status: DeliveryStatusAssigned, terminal: false},
+		{status: DeliveryStatusPickedUp, terminal: false},
+		{status: DeliveryStatusInTransit, terminal: false},
+		{status: DeliveryStatusDelivered, terminal: true},
+		{status: DeliveryStatusFailed, terminal: true},
+		{status: DeliveryStatusCancelled, terminal: true},
+		{status: DeliveryStatusDeadLetter, terminal: true},
+		{status: "CANCELLED", terminal: true}, // Legacy spelling
+		{status: "UNKNOWN", terminal: false},
+	}
+
+	require.Len(t, tests, len(AllStatuses())+2, "every status is classified")
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.terminal, tt.status.IsTerminal())
+			assert.Equal(t, tt.terminal, slices.Contains(TerminalStatuses(), tt.status.Canonical()))
+		})
+	}
+
+	// The terminal filter selects exactly the terminal statuses
+	assert.Equal(t, TerminalStatuses(), ActivityTerminal.Statuses())
+}
+
+func TestIsActive_ConsistentWithIsTerminal(t *testing.T) {
+	for _, status := range AllStatuses() {
+		t.Run(string(status), func(t *testing.T) {
+			assert.NotEqual(t, status.IsActive(), status.IsTerminal(), "exactly one of active and terminal")
+			assert.Equal(t, status.IsActive(), slices.Contains(ActivityActive.Statuses(), status))
+		})
+	}
+
+	// Waiting for a driver is in progress, not finished
+	assert.True(t, DeliveryStatusPending.IsActive())
+	assert.Equal(t, []DeliveryStatus{
+		DeliveryStatusPending,
+		DeliveryStatusAssigned,
+		DeliveryStatusPickedUp,
+		DeliveryStatusInTransit,
+	}, ActiveStatuses())
+
+	// Unknown statuses are neither
+	assert.False(t, DeliveryStatus("UNKNOWN").IsActive())
+	assert.False(t, DeliveryStatus("UNKNOWN").IsTerminal())
+	assert.False(t, DeliveryStatus("CANCELLED").IsActive())
+}
+
+func TestStatusTransitionGraph_MatchesStateMachine(t *testing.T) {
+	graph := StatusTransitionGraph()
+
+	require.Len(t, graph, len(AllStatuses()), "one entry per status")
+	for i, entry := range graph {
+		assert.Equal(t, AllStatuses()[i], entry.From)
+
+		allowed := map[DeliveryStatus]bool{}
+		for _, to := range entry.To {
+			allowed[to] = true
+		}
+
+		for _, to := range AllStatuses() {
+			d := &DeliveryAssignment{Status: entry.From}
+			assert.Equal(t, d.isValidStatusTransition(to), allowed[to], "%s -> %s", entry.From, to)
+		}
+	}
+}
+
+func TestDeliveryStatus_LegacySpellings(t *testing.T) {
+	assert.Equal(t, DeliveryStatusCancelled, DeliveryStatus("CANCELLED").Canonical())
+	assert.Equal(t, DeliveryStatusCancelled, DeliveryStatusCancelled.Canonical())
+	assert.Equal(t, DeliveryStatusPending, DeliveryStatusPending.Canonical())
+
+	assert.Equal(t, []DeliveryStatus{DeliveryStatusCancelled, "CANCELLED"}, DeliveryStatusCancelled.Spellings())
+	assert.Equal(t, []DeliveryStatus{DeliveryStatusCancelled, "CANCELLED"}, DeliveryStatus("CANCELLED").Spellings())
+	assert.Equal(t, []DeliveryStatus{DeliveryStatusDelivered}, DeliveryStatusDelivered.Spellings())
+}
+
+func TestFail(t *testing.T) {
+	assignment := &DeliveryAssignment{Status: DeliveryStatusInTransit}
+
+	// A reason is required, also through UpdateStatus
+	var validationErr *ValidationError
+	require.ErrorAs(t, assignment.UpdateStatus(DeliveryStatusFailed), &validationErr)
+	assert.Equal(t, "failure_reason_code", validationErr.Field)
+	require.ErrorAs(t, assignment.Fail(""), &validationErr)
+	assert.Equal(t, DeliveryStatusInTransit, assignment.Status)
+
+	require.NoError(t, assignment.Fail("DAMAGED"))
+	assert.Equal(t, DeliveryStatusFailed, assignment.Status)
+	require.NotNil(t, assignment.FailureReasonCode)
+	assert.Equal(t, "DAMAGED", *assignment.FailureReasonCode)
+
+	// Retrying keeps the original reason
+	require.NoError(t, assignment.Fail("REFUSED"))
+	assert.Equal(t, "DAMAGED", *assignment.FailureReasonCode)
+
+	assert.ErrorIs(t, (&DeliveryAssignment{Status: DeliveryStatusPending}).Fail("DAMAGED"), ErrInvalidStatusTransition)
+}
+
+func TestNotifyPrefs_Includes(t *testing.T) {
+	// Empty prefs include every status
+	assert.True(t, NotifyPrefs(nil).Includes(DeliveryStatusAssigned))
+
+	prefs := NotifyPrefs{DeliveryStatusDelivered, DeliveryStatusFailed}
+	assert.True(t, prefs.Includes(DeliveryStatusDelivered))
+	assert.False(t, prefs.Includes(DeliveryStatusAssigned))
+}
+
+func TestUUIDv7_TimeOrdered(t *testing.T) {
+	ids := make([]uuid.UUID, 1000)
+	for i := range ids {
+		ids[i] = UUIDv7.NewID()
+		assert.Equal(t, uuid.Version(7), ids[i].Version())
+	}
+
+	// Each ID sorts after the one generated before it
+	for i := 1; i < len(ids); i++ {
+		assert.Negative(t, bytes.Compare(ids[i-1][:], ids[i][:]), "id %d", i)
+	}
+}
+
+func TestNewDeliveryAssignmentWithIDs(t *testing.T) {
+	assignment, err := NewDeliveryAssignmentWithIDs(SystemClock, UUIDv7, "ORDER-1", Address{}, Address{}, time.Now(), time.Now(), "")
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), assignment.ID.Version())
+
+	// Deliveries created from it use the same generator
+	assignment.Status = DeliveryStatusFailed
+	reattempt, err := assignment.Reattempt(time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), reattempt.ID.Version())
+
+	v4, err := NewDeliveryAssignment("ORDER-1", Address{}, Address{}, time.Now(), time.Now(), "")
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), v4.ID.Version())
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// HasCoordinates reports whether the address has coordinates (both zero means none)
+func (a Address) HasCoordinates() bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
+
+// DistanceKm returns the straight-line (great-circle) distance to another address in kilometres,
+// using the Haversine formula. It ignores roads, so actual routes are longer.
+func (a Address) DistanceKm(to Address) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := to.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
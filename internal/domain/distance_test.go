@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddress_DistanceKm(t *testing.T) {
+	paris := Address{Latitude: 48.8566, Longitude: 2.3522}
+	london := Address{Latitude: 51.5074, Longitude: -0.1278}
+
+	assert.InDelta(t, 343.5, paris.DistanceKm(london), 1)
+	assert.InDelta(t, paris.DistanceKm(london), london.DistanceKm(paris), 1e-9)
+	assert.Zero(t, paris.DistanceKm(paris))
+
+	assert.True(t, paris.HasCoordinates())
+	assert.False(t, Address{City: "Paris"}.HasCoordinates())
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Sentinel errors for common cases
@@ -30,6 +31,12 @@ var (
 
 	// ErrTimeout is returned when operation times out
 	ErrTimeout = errors.New("operation timeout")
+
+	// ErrUnavailable is returned when a dependency is temporarily unavailable and the call can be retried later
+	ErrUnavailable = errors.New("service unavailable")
+
+	// ErrResourceExhausted is returned when a call is shed because a concurrency limit is reached
+	ErrResourceExhausted = errors.New("resource exhausted")
 )
 
 // Error DomainError represents a domain-specific error with context
@@ -144,3 +151,20 @@ func (e *ConflictError) Unwrap() error {
 func (e *ConflictError) Is(target error) bool {
 	return target == ErrConflict || target == ErrInvalidStatusTransition
 }
+
+// RetryAfterError wraps an ErrUnavailable or ErrResourceExhausted error with how long the caller
+// should wait before retrying
+type RetryAfterError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error implements the error interface
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %v)", e.Err, e.RetryAfter)
+}
+
+// Unwrap implements the unwrap interface
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator creates IDs for new entities
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// uuidV4Generator creates random version 4 UUIDs
+type uuidV4Generator struct{}
+
+// NewID returns a random UUIDv4
+func (uuidV4Generator) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// uuidV7Generator creates time-ordered version 7 UUIDs
+type uuidV7Generator struct{}
+
+// NewID returns a UUIDv7. IDs generated later sort after earlier ones, so inserts append to
+// the primary key index instead of landing at random pages.
+func (uuidV7Generator) NewID() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+var (
+	// UUIDv4 is the default IDGenerator used when none is injected
+	UUIDv4 IDGenerator = uuidV4Generator{}
+	// UUIDv7 generates time-ordered IDs
+	UUIDv7 IDGenerator = uuidV7Generator{}
+)
+
+// IDGeneratorForVersion returns the generator for a UUID version ("v4" or "v7")
+func IDGeneratorForVersion(version string) (IDGenerator, error) {
+	switch version {
+	case "v4":
+		return UUIDv4, nil
+	case "v7":
+		return UUIDv7, nil
+	default:
+		return nil, fmt.Errorf("unsupported UUID version %q (want v4 or v7)", version)
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryPriority ranks how urgently a delivery should be dispatched
+type DeliveryPriority string
+
+const (
+	PriorityLow    DeliveryPriority = "LOW"
+	PriorityNormal DeliveryPriority = "NORMAL"
+	PriorityHigh   DeliveryPriority = "HIGH"
+)
+
+// priorityRanks orders the priorities from least to most urgent
+var priorityRanks = map[DeliveryPriority]int{
+	PriorityLow:    1,
+	PriorityNormal: 2,
+	PriorityHigh:   3,
+}
+
+// IsValid reports whether p is a known priority
+func (p DeliveryPriority) IsValid() bool {
+	_, ok := priorityRanks[p]
+	return ok
+}
+
+// Rank orders p among the priorities, higher being more urgent; unknown priorities rank 0
+func (p DeliveryPriority) Rank() int {
+	return priorityRanks[p]
+}
+
+// Outranks reports whether p is more urgent than other
+func (p DeliveryPriority) Outranks(other DeliveryPriority) bool {
+	return priorityRanks[p] > priorityRanks[other]
+}
+
+// PriorityEscalation raises PENDING deliveries of priority From to To once they have been
+// waiting for After since they were created
+type PriorityEscalation struct {
+	From  DeliveryPriority
+	To    DeliveryPriority
+	After time.Duration
+}
+
+// ParsePriorityEscalations parses a comma-separated list of FROM:TO:AFTER rules, e.g.
+// "LOW:NORMAL:1h,NORMAL:HIGH:2h". Priorities are case-insensitive and AFTER is a Go duration.
+// Each priority may be escalated from only once, and only to a more urgent one. An empty spec
+// returns nil (no escalation).
+func ParsePriorityEscalations(spec string) ([]PriorityEscalation, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var escalations []PriorityEscalation
+	seen := make(map[DeliveryPriority]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("priority escalation %q: expected FROM:TO:AFTER", entry)
+		}
+
+		from := DeliveryPriority(strings.ToUpper(strings.TrimSpace(parts[0])))
+		to := DeliveryPriority(strings.ToUpper(strings.TrimSpace(parts[1])))
+		if !from.IsValid() || !to.IsValid() {
+			return nil, fmt.Errorf("priority escalation %q: priorities must be LOW, NORMAL or HIGH", entry)
+		}
+		if !to.Outranks(from) {
+			return nil, fmt.Errorf("priority escalation %q: %s does not outrank %s", entry, to, from)
+		}
+		if seen[from] {
+			return nil, fmt.Errorf("priority escalation %q: %s is escalated twice", entry, from)
+		}
+		seen[from] = true
+
+		after, err := time.ParseDuration(strings.TrimSpace(parts[2]))
+		if err != nil || after <= 0 {
+			return nil, fmt.Errorf("priority escalation %q: wait must be a positive duration", entry)
+		}
+
+		escalations = append(escalations, PriorityEscalation{From: from, To: to, After: after})
+	}
+
+	return escalations, nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriorityEscalations(t *testing.T) {
+	escalations, err := ParsePriorityEscalations("low:NORMAL:1h, NORMAL:HIGH:90m")
+	require.NoError(t, err)
+	assert.Equal(t, []PriorityEscalation{
+		{From: PriorityLow, To: PriorityNormal, After: time.Hour},
+		{From: PriorityNormal, To: PriorityHigh, After: 90 * time.Minute},
+	}, escalations)
+
+	empty, err := ParsePriorityEscalations("")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	for _, spec := range []string{
+		"LOW:NORMAL",
+		"LOW:URGENT:1h",
+		"HIGH:LOW:1h",
+		"NORMAL:NORMAL:1h",
+		"LOW:NORMAL:soon",
+		"LOW:NORMAL:0s",
+		"LOW:NORMAL:1h,LOW:HIGH:2h",
+	} {
+		_, err := ParsePriorityEscalations(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEscalatePriority(t *testing.T) {
+	assignment := &DeliveryAssignment{Status: DeliveryStatusPending, Priority: PriorityLow}
+
+	require.NoError(t, assignment.EscalatePriority(PriorityNormal))
+	assert.Equal(t, PriorityNormal, assignment.Priority)
+	assert.False(t, assignment.UpdatedAt.IsZero())
+
+	// Only ever raised
+	assert.ErrorIs(t, assignment.EscalatePriority(PriorityLow), ErrConflict)
+	assert.ErrorIs(t, assignment.EscalatePriority(PriorityNormal), ErrConflict)
+	assert.Equal(t, PriorityNormal, assignment.Priority)
+
+	// Only while waiting for a driver
+	assigned := &DeliveryAssignment{Status: DeliveryStatusAssigned, Priority: PriorityLow}
+	assert.ErrorIs(t, assigned.EscalatePriority(PriorityHigh), ErrConflict)
+	assert.Equal(t, PriorityLow, assigned.Priority)
+}
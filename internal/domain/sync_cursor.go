@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errMalformedSyncCursor = errors.New("malformed sync cursor")
+
+// SyncCursor is a position in the change feed: the change time of the last delivery returned and
+// its ID. Deliveries changed at the same instant are ordered by ID, so resuming after the cursor
+// neither skips nor repeats the ones sharing its time.
+type SyncCursor struct {
+	ModifiedAt time.Time
+	ID         uuid.UUID
+}
+
+// SyncCursorSince starts the feed after every change made at or before since
+func SyncCursorSince(since time.Time) SyncCursor {
+	return SyncCursor{ModifiedAt: since, ID: uuid.Max}
+}
+
+// String encodes the cursor as an opaque URL-safe token
+func (c SyncCursor) String() string {
+	raw := strconv.FormatInt(c.ModifiedAt.UnixNano(), 10) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseSyncCursor decodes a token returned by SyncCursor.String
+func ParseSyncCursor(token string) (SyncCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return SyncCursor{}, errMalformedSyncCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return SyncCursor{}, errMalformedSyncCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return SyncCursor{}, errMalformedSyncCursor
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return SyncCursor{}, errMalformedSyncCursor
+	}
+	return SyncCursor{ModifiedAt: time.Unix(0, unixNano).UTC(), ID: parsedID}, nil
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCursor_RoundTrip(t *testing.T) {
+	cursor := SyncCursor{ModifiedAt: time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC), ID: uuid.New()}
+
+	parsed, err := ParseSyncCursor(cursor.String())
+	require.NoError(t, err)
+	assert.True(t, cursor.ModifiedAt.Equal(parsed.ModifiedAt))
+	assert.Equal(t, cursor.ID, parsed.ID)
+
+	for _, token := range []string{"", "not base64!", "MTIz", "YWJjX2RlZg"} {
+		_, err := ParseSyncCursor(token)
+		assert.Error(t, err, token)
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+type traceIDKey struct{}
+
+// ContextWithTraceID returns a copy of ctx carrying traceID, the ID clients quote to support and
+// that every log line of the request is tagged with
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or "" if there is none
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
@@ -0,0 +1,229 @@
+// Package breaker wraps a delivery repository with a circuit breaker so that, while the
+// database is failing, requests fail fast with domain.ErrUnavailable instead of each
+// waiting for a database timeout.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	"github.com/mohamadchoker/order-delivery-service/pkg/circuitbreaker"
+)
+
+// repository runs every call of the wrapped repository through the breaker
+type repository struct {
+	next    service.DeliveryRepository
+	breaker *circuitbreaker.Breaker
+}
+
+// NewRepository wraps next with breaker. The breaker should use IsDatabaseFailure so that
+// business outcomes such as not found do not count against the database.
+func NewRepository(next service.DeliveryRepository, breaker *circuitbreaker.Breaker) service.DeliveryRepository {
+	return &repository{next: next, breaker: breaker}
+}
+
+// IsDatabaseFailure reports whether err means the database misbehaved, as opposed to a business
+// outcome (not found, conflicts, invalid input) or the caller giving up. A call cut short by its
+// context's deadline is not held against the database either: a client with a tight deadline
+// would otherwise open the breaker for every other caller.
+func IsDatabaseFailure(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrInvalidInput),
+		errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrAlreadyExists),
+		errors.Is(err, domain.ErrConflict):
+		return false
+	default:
+		return true
+	}
+}
+
+// execute runs fn through the breaker, translating a rejected call into domain.ErrUnavailable
+// carrying the time left until the breaker probes the database again
+func (r *repository) execute(fn func() error) error {
+	err := r.breaker.Execute(fn)
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		return &domain.RetryAfterError{
+			RetryAfter: r.breaker.RetryAfter(),
+			Err:        fmt.Errorf("%w: database circuit breaker is open", domain.ErrUnavailable),
+		}
+	}
+	return err
+}
+
+// query runs a call returning a value through the breaker
+func query[T any](r *repository, fn func() (T, error)) (T, error) {
+	var result T
+	err := r.execute(func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+func (r *repository) Create(ctx context.Context, assignment *domain.DeliveryAssignment) error {
+	return r.execute(func() error { return r.next.Create(ctx, assignment) })
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
+	return query(r, func() (*domain.DeliveryAssignment, error) { return r.next.GetByID(ctx, id) })
+}
+
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
+	return query(r, func() (*domain.DeliveryAssignment, error) { return r.next.GetByIDForUpdate(ctx, id) })
+}
+
+func (r *repository) GetStatusByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryStatusInfo, error) {
+	return query(r, func() (*domain.DeliveryStatusInfo, error) { return r.next.GetStatusByID(ctx, id) })
+}
+
+func (r *repository) GetByOrderID(ctx context.Context, orderID string) ([]*domain.DeliveryAssignment, error) {
+	return query(r, func() ([]*domain.DeliveryAssignment, error) { return r.next.GetByOrderID(ctx, orderID) })
+}
+
+func (r *repository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.DeliveryAssignment, error) {
+	return query(r, func() ([]*domain.DeliveryAssignment, error) { return r.next.GetByIDs(ctx, ids) })
+}
+
+func (r *repository) Update(ctx context.Context, assignment *domain.DeliveryAssignment) error {
+	return r.execute(func() error { return r.next.Update(ctx, assignment) })
+}
+
+func (r *repository) AppendNote(ctx context.Context, id uuid.UUID, note domain.TimedNote) error {
+	return r.execute(func() error { return r.next.AppendNote(ctx, id, note) })
+}
+
+func (r *repository) UpdateDriverETA(ctx context.Context, id uuid.UUID, eta, updatedAt time.Time) (*domain.DeliveryAssignment, error) {
+	return query(r, func() (*domain.DeliveryAssignment, error) {
+		return r.next.UpdateDriverETA(ctx, id, eta, updatedAt)
+	})
+}
+
+func (r *repository) List(ctx context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, service.ListTotal, error) {
+	var total service.ListTotal
+	assignments, err := query(r, func() ([]*domain.DeliveryAssignment, error) {
+		var (
+			assignments []*domain.DeliveryAssignment
+			err         error
+		)
+		assignments, total, err = r.next.List(ctx, filters)
+		return assignments, err
+	})
+	return assignments, total, err
+}
+
+func (r *repository) ListWithStatusBreakdown(ctx context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, service.ListTotal, map[domain.DeliveryStatus]int64, error) {
+	var (
+		assignments []*domain.DeliveryAssignment
+		total       service.ListTotal
+	)
+	breakdown, err := query(r, func() (map[domain.DeliveryStatus]int64, error) {
+		var (
+			breakdown map[domain.DeliveryStatus]int64
+			err       error
+		)
+		assignments, total, breakdown, err = r.next.ListWithStatusBreakdown(ctx, filters)
+		return breakdown, err
+	})
+	return assignments, total, breakdown, err
+}
+
+func (r *repository) GetMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string, onTimeGrace time.Duration) (*domain.DeliveryMetrics, error) {
+	return query(r, func() (*domain.DeliveryMetrics, error) {
+		return r.next.GetMetrics(ctx, startTime, endTime, driverID, onTimeGrace)
+	})
+}
+
+func (r *repository) RefreshMetricsSummary(ctx context.Context, day time.Time, onTimeGrace time.Duration) error {
+	return r.execute(func() error { return r.next.RefreshMetricsSummary(ctx, day, onTimeGrace) })
+}
+
+func (r *repository) GetMetricsForDrivers(ctx context.Context, driverIDs []string, startTime, endTime time.Time, onTimeGrace time.Duration) (map[string]*domain.DeliveryMetrics, error) {
+	return query(r, func() (map[string]*domain.DeliveryMetrics, error) {
+		return r.next.GetMetricsForDrivers(ctx, driverIDs, startTime, endTime, onTimeGrace)
+	})
+}
+
+func (r *repository) GetDriverLeaderboard(ctx context.Context, startTime, endTime time.Time, limit int, onTimeGrace time.Duration) ([]domain.DriverStats, error) {
+	return query(r, func() ([]domain.DriverStats, error) {
+		return r.next.GetDriverLeaderboard(ctx, startTime, endTime, limit, onTimeGrace)
+	})
+}
+
+func (r *repository) ListActiveDriverIDs(ctx context.Context) ([]string, error) {
+	return query(r, func() ([]string, error) { return r.next.ListActiveDriverIDs(ctx) })
+}
+
+func (r *repository) ListServedCities(ctx context.Context) ([]string, error) {
+	return query(r, func() ([]string, error) { return r.next.ListServedCities(ctx) })
+}
+
+func (r *repository) RecordDriverLocation(ctx context.Context, driverID string, latitude, longitude float64, recordedAt time.Time) error {
+	return r.execute(func() error { return r.next.RecordDriverLocation(ctx, driverID, latitude, longitude, recordedAt) })
+}
+
+func (r *repository) GetLatestDriverLocation(ctx context.Context, driverID string) (*domain.DriverLocation, error) {
+	return query(r, func() (*domain.DriverLocation, error) { return r.next.GetLatestDriverLocation(ctx, driverID) })
+}
+
+func (r *repository) ListModifiedSince(ctx context.Context, after domain.SyncCursor, limit int) ([]*domain.DeliveryAssignment, error) {
+	return query(r, func() ([]*domain.DeliveryAssignment, error) { return r.next.ListModifiedSince(ctx, after, limit) })
+}
+
+// StreamAll counts as one call. Errors returned by fn are the caller's, but they cannot be told
+// apart from database errors here, so fn should return errors IsDatabaseFailure ignores
+// (e.g. context.Canceled) when the consumer stops.
+func (r *repository) StreamAll(ctx context.Context, fn func(*domain.DeliveryAssignment) error) error {
+	return r.execute(func() error { return r.next.StreamAll(ctx, fn) })
+}
+
+func (r *repository) ListOverdueAssigned(ctx context.Context, cutoff time.Time, limit int) ([]*domain.DeliveryAssignment, error) {
+	return query(r, func() ([]*domain.DeliveryAssignment, error) {
+		return r.next.ListOverdueAssigned(ctx, cutoff, limit)
+	})
+}
+
+func (r *repository) EscalateWaitingPending(ctx context.Context, from, to domain.DeliveryPriority, cutoff, escalatedAt time.Time, limit int) ([]*domain.DeliveryAssignment, error) {
+	return query(r, func() ([]*domain.DeliveryAssignment, error) {
+		return r.next.EscalateWaitingPending(ctx, from, to, cutoff, escalatedAt, limit)
+	})
+}
+
+func (r *repository) ClaimNextPending(ctx context.Context, driverID string) (*domain.DeliveryAssignment, error) {
+	return query(r, func() (*domain.DeliveryAssignment, error) {
+		return r.next.ClaimNextPending(ctx, driverID)
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.execute(func() error { return r.next.Delete(ctx, id) })
+}
+
+func (r *repository) DeleteByOrderID(ctx context.Context, orderID string) ([]uuid.UUID, error) {
+	return query(r, func() ([]uuid.UUID, error) { return r.next.DeleteByOrderID(ctx, orderID) })
+}
+
+func (r *repository) CountMatching(ctx context.Context, filters service.ListFilters) (int64, error) {
+	return query(r, func() (int64, error) { return r.next.CountMatching(ctx, filters) })
+}
+
+func (r *repository) DeleteMatching(ctx context.Context, filters service.ListFilters, limit int) ([]uuid.UUID, error) {
+	return query(r, func() ([]uuid.UUID, error) { return r.next.DeleteMatching(ctx, filters, limit) })
+}
+
+// WithTransaction counts the whole transaction as one call; the repository passed to fn is the
+// transaction's own and is not guarded again
+func (r *repository) WithTransaction(ctx context.Context, fn func(repo service.DeliveryRepository) error) error {
+	return r.execute(func() error { return r.next.WithTransaction(ctx, fn) })
+}
@@ -0,0 +1,80 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/breaker"
+	"github.com/mohamadchoker/order-delivery-service/pkg/circuitbreaker"
+)
+
+func TestRepository_FailsFastWhenDatabaseIsDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	id := uuid.New()
+	dbErr := errors.New("connection refused")
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	// Only the calls before the breaker opens reach the database
+	mockRepo.EXPECT().GetByID(ctx, id).Return(nil, dbErr).Times(3)
+
+	repo := breaker.NewRepository(mockRepo, circuitbreaker.New(circuitbreaker.Settings{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+		IsFailure:        breaker.IsDatabaseFailure,
+	}))
+
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetByID(ctx, id)
+		assert.ErrorIs(t, err, dbErr)
+	}
+
+	_, err := repo.GetByID(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrUnavailable)
+}
+
+func TestRepository_BusinessErrorsDoNotOpen(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "not found", err: &domain.NotFoundError{Resource: "delivery", ID: id.String()}},
+		{name: "caller canceled", err: fmt.Errorf("query deliveries: %w", context.Canceled)},
+		{name: "caller deadline exceeded", err: fmt.Errorf("query deliveries: %w", context.DeadlineExceeded)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			mockRepo.EXPECT().GetByID(ctx, id).Return(nil, tt.err).Times(3)
+
+			repo := breaker.NewRepository(mockRepo, circuitbreaker.New(circuitbreaker.Settings{
+				FailureThreshold: 1,
+				Cooldown:         time.Minute,
+				IsFailure:        breaker.IsDatabaseFailure,
+			}))
+
+			for i := 0; i < 3; i++ {
+				_, err := repo.GetByID(ctx, id)
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres/model"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// auditLogger stores audit entries in the delivery_audit_log table
+type auditLogger struct {
+	db *gorm.DB
+}
+
+// NewAuditLogger creates an audit logger storing entries in the database next to the deliveries
+func NewAuditLogger(db *gorm.DB) service.AuditLogger {
+	return &auditLogger{db: db}
+}
+
+// Audit inserts entry as one row
+func (l *auditLogger) Audit(ctx context.Context, entry service.AuditEntry) error {
+	row, err := auditLogEntryFromEntry(entry)
+	if err != nil {
+		return err
+	}
+	if err := l.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to store audit entry: %w", err)
+	}
+	return nil
+}
+
+// auditLogEntryFromEntry converts an audit entry to its database row
+func auditLogEntryFromEntry(entry service.AuditEntry) (*model.AuditLogEntry, error) {
+	row := &model.AuditLogEntry{
+		OrderID:    entry.OrderID,
+		Action:     entry.Action,
+		UserID:     entry.UserID,
+		TenantID:   entry.TenantID,
+		TraceID:    entry.TraceID,
+		Affected:   entry.Affected,
+		RecordedAt: entry.Time,
+	}
+	if entry.DeliveryID != "" {
+		id, err := uuid.Parse(entry.DeliveryID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit entry delivery ID %q: %w", entry.DeliveryID, err)
+		}
+		row.DeliveryID = &id
+	}
+	if len(entry.Changes) > 0 {
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit entry changes: %w", err)
+		}
+		row.Changes = changes
+	}
+	if len(entry.DeliveryIDs) > 0 {
+		ids, err := json.Marshal(entry.DeliveryIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit entry delivery IDs: %w", err)
+		}
+		row.DeliveryIDs = ids
+	}
+	row.Filters = model.RawJSON("{}")
+	if len(entry.Filters) > 0 {
+		filters, err := json.Marshal(entry.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit entry filters: %w", err)
+		}
+		row.Filters = filters
+	}
+	return row, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres/model"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+func TestAuditLogger_Audit(t *testing.T) {
+	var insert string
+	var values []interface{}
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		insert = query
+		for _, arg := range args {
+			values = append(values, arg.Value)
+		}
+		return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}, nil
+	})
+	logger := NewAuditLogger(db)
+
+	deliveryID := uuid.New()
+	recordedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	err := logger.Audit(context.Background(), service.AuditEntry{
+		Time:       recordedAt,
+		Action:     constants.OpAssignDriver,
+		UserID:     "user-1",
+		TenantID:   "tenant-1",
+		TraceID:    "trace-1",
+		DeliveryID: deliveryID.String(),
+		OrderID:    "ORDER-1",
+		Changes:    []service.FieldChange{{Field: "driver_id", Before: json.RawMessage("null"), After: json.RawMessage(`"DRIVER-1"`)}},
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, insert, `INSERT INTO "delivery_audit_log"`)
+	assert.Contains(t, values, &deliveryID)
+	assert.Contains(t, values, constants.OpAssignDriver)
+	assert.Contains(t, values, "user-1")
+	assert.Contains(t, values, recordedAt)
+
+	// A bulk delete stores the deleted IDs and its filters as JSON
+	values = nil
+	err = logger.Audit(context.Background(), service.AuditEntry{
+		Time:        recordedAt,
+		Action:      constants.OpBulkDelete,
+		Affected:    1,
+		DeliveryIDs: []string{deliveryID.String()},
+		Filters:     map[string]string{"status": "CANCELED"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, values, model.RawJSON(`["`+deliveryID.String()+`"]`))
+	assert.Contains(t, values, model.RawJSON(`{"status":"CANCELED"}`))
+
+	// An entry naming no valid delivery is refused before reaching the database
+	insert = ""
+	err = logger.Audit(context.Background(), service.AuditEntry{Action: constants.OpDelete, DeliveryID: "not-a-uuid"})
+	assert.ErrorContains(t, err, "invalid audit entry delivery ID")
+	assert.Empty(t, insert)
+}
@@ -2,40 +2,132 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 	"github.com/mohamadchoker/order-delivery-service/internal/domain"
 	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres/model"
 	"github.com/mohamadchoker/order-delivery-service/internal/service"
 )
 
+// Config holds optional repository diagnostics settings
+type Config struct {
+	// ExplainQueries logs the EXPLAIN plan of List queries at debug level. Opt-in; each
+	// List then costs an extra round trip, so never enable it in production.
+	ExplainQueries bool
+	// TransactionTimeout bounds how long WithTransaction may hold a transaction open; past it
+	// the transaction is rolled back and domain.ErrTimeout returned. Zero disables the limit.
+	TransactionTimeout time.Duration
+	// CountTimeout bounds how long the count behind List's total may run. A count exceeding it
+	// is replaced by the planner's estimate of the matching rows, so loose filters on a huge table
+	// do not hold up the page. Zero always counts exactly.
+	CountTimeout time.Duration
+	// CascadeAuditLog soft deletes a delivery's delivery_audit_log rows in the same transaction as
+	// the delivery itself. By default they are retained, so the audit trail outlives the delivery.
+	// Either way the rows stay in the table, so restoring a delivery can restore them too.
+	CascadeAuditLog bool
+	// Clock supplies the time summaries are stamped as refreshed at (nil uses the system clock)
+	Clock  domain.Clock
+	Logger *zap.Logger
+}
+
 // repository implements service.DeliveryRepository using PostgreSQL
 type repository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	config Config
+
+	// inTransaction is set on repositories handed out by WithTransaction
+	inTransaction bool
+
+	// explain runs EXPLAIN for sql and returns the plan lines (replaceable in tests)
+	explain func(ctx context.Context, sql string, vars []interface{}) ([]string, error)
 }
 
 // NewRepository creates a new repository
 func NewRepository(db *gorm.DB) service.DeliveryRepository {
-	return &repository{db: db}
+	return NewRepositoryWithConfig(db, Config{})
+}
+
+// NewRepositoryWithConfig creates a new repository with diagnostics configuration
+func NewRepositoryWithConfig(db *gorm.DB, cfg Config) service.DeliveryRepository {
+	return newRepository(db, cfg)
+}
+
+func newRepository(db *gorm.DB, cfg Config) *repository {
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = domain.SystemClock
+	}
+
+	r := &repository{db: db, config: cfg}
+	r.explain = r.runExplain
+	return r
 }
 
 // Create creates a new delivery assignment
 func (r *repository) Create(ctx context.Context, assignment *domain.DeliveryAssignment) error {
 	dbModel := model.FromEntity(assignment)
 
-	if err := r.db.WithContext(ctx).Create(dbModel).Error; err != nil {
+	err := r.audited(ctx, func(db *gorm.DB) error {
+		return db.Create(dbModel).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err, activeOrderIndex) {
+			return fmt.Errorf("order %s already has an active delivery: %w", assignment.OrderID, domain.ErrAlreadyExists)
+		}
 		return err
 	}
 
-	*assignment = *dbModel.ToEntity()
+	refreshServerFields(assignment, dbModel)
 	return nil
 }
 
+// activeOrderIndex is the partial unique index allowing one active delivery per order (migration 000007)
+const activeOrderIndex = "uq_delivery_assignments_active_order"
+
+// PostgreSQL SQLSTATEs
+const (
+	uniqueViolationCode = "23505" // unique_violation
+	queryCanceledCode   = "57014" // query_canceled, raised when a statement times out or is canceled
+)
+
+// isUniqueViolation reports whether err is a unique violation of the named constraint or index
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraint
+}
+
+// isQueryCanceled reports whether err is a statement canceled by PostgreSQL, e.g. on timeout
+func isQueryCanceled(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == queryCanceledCode
+}
+
+// refreshServerFields copies the values computed by the database (primary key default and
+// timestamps) back onto the entity without touching fields owned by the caller
+func refreshServerFields(assignment *domain.DeliveryAssignment, dbModel *model.DeliveryAssignment) {
+	assignment.ID = dbModel.ID
+	assignment.CreatedAt = dbModel.CreatedAt
+	assignment.UpdatedAt = dbModel.UpdatedAt
+}
+
 // GetByID retrieves a delivery assignment by ID
 func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
 	var dbModel model.DeliveryAssignment
@@ -50,54 +142,199 @@ func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Deliver
 	return dbModel.ToEntity(), nil
 }
 
+// GetByIDForUpdate retrieves a delivery assignment by ID, locking its row FOR UPDATE until the
+// surrounding transaction ends
+func (r *repository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
+	var dbModel model.DeliveryAssignment
+
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&dbModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return dbModel.ToEntity(), nil
+}
+
+// GetStatusByID retrieves only the id, status and updated_at columns of a delivery assignment
+func (r *repository) GetStatusByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryStatusInfo, error) {
+	var dbModel model.DeliveryAssignment
+
+	if err := r.db.WithContext(ctx).
+		Select("id", "status", "updated_at").
+		First(&dbModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.DeliveryStatusInfo{
+		ID:        dbModel.ID,
+		Status:    dbModel.Status.Canonical(),
+		UpdatedAt: dbModel.UpdatedAt,
+	}, nil
+}
+
+// GetByOrderID retrieves all delivery assignments for an order, oldest first
+func (r *repository) GetByOrderID(ctx context.Context, orderID string) ([]*domain.DeliveryAssignment, error) {
+	var dbModels []model.DeliveryAssignment
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&dbModels).Error; err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*domain.DeliveryAssignment, len(dbModels))
+	for i, dbModel := range dbModels {
+		assignments[i] = dbModel.ToEntity()
+	}
+
+	return assignments, nil
+}
+
+// GetByIDs retrieves delivery assignments by ID using a single IN query
+func (r *repository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.DeliveryAssignment, error) {
+	if len(ids) == 0 {
+		return []*domain.DeliveryAssignment{}, nil
+	}
+
+	var dbModels []model.DeliveryAssignment
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&dbModels).Error; err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*domain.DeliveryAssignment, len(dbModels))
+	for i, dbModel := range dbModels {
+		assignments[i] = dbModel.ToEntity()
+	}
+
+	return assignments, nil
+}
+
 // Update updates an existing delivery assignment
 func (r *repository) Update(ctx context.Context, assignment *domain.DeliveryAssignment) error {
 	dbModel := model.FromEntity(assignment)
 
-	result := r.db.WithContext(ctx).
-		Model(&model.DeliveryAssignment{}).
-		Where("id = ?", assignment.ID).
-		Updates(dbModel)
-
-	if result.Error != nil {
+	// Select all columns so cleared fields (e.g. an unassigned driver) are persisted too.
+	// Timeline notes are append-only and only written through AppendNote.
+	// RETURNING gives back the stored timestamps so the entity is not stale.
+	var stored model.DeliveryAssignment
+	var result *gorm.DB
+	err := r.audited(ctx, func(db *gorm.DB) error {
+		result = db.
+			Model(&stored).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}, {Name: "updated_at"}}}).
+			Where("id = ?", assignment.ID).
+			Select("*").
+			Omit("id", "created_at", "deleted_at", "timeline_notes").
+			Updates(dbModel)
 		return result.Error
+	})
+
+	if err != nil {
+		return err
 	}
 
 	if result.RowsAffected == 0 {
 		return domain.ErrNotFound
 	}
 
+	refreshServerFields(assignment, &stored)
 	return nil
 }
 
-// List retrieves delivery assignments with pagination and filters
-func (r *repository) List(ctx context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, int64, error) {
-	var dbModels []model.DeliveryAssignment
-	var totalCount int64
+// AppendNote appends a note to the timeline in a single statement so concurrent appends are not lost
+func (r *repository) AppendNote(ctx context.Context, id uuid.UUID, note domain.TimedNote) error {
+	payload, err := json.Marshal([]domain.TimedNote{note})
+	if err != nil {
+		return err
+	}
 
-	query := r.db.WithContext(ctx).Model(&model.DeliveryAssignment{})
+	var result *gorm.DB
+	err = r.audited(ctx, func(db *gorm.DB) error {
+		result = db.
+			Model(&model.DeliveryAssignment{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"timeline_notes": gorm.Expr("timeline_notes || ?::jsonb", string(payload)),
+				"updated_at":     note.CreatedAt,
+			})
+		return result.Error
+	})
 
-	// Apply filters
-	if filters.Status != nil {
-		query = query.Where("status = ?", *filters.Status)
+	if err != nil {
+		return err
 	}
-	if filters.DriverID != nil {
-		query = query.Where("driver_id = ?", *filters.DriverID)
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
 	}
 
+	return nil
+}
+
+// UpdateDriverETA sets the driver ETA of a delivery that is still on its way (PICKED_UP or
+// IN_TRANSIT) in a single statement and returns the delivery as updated. No other column is
+// written, so concurrent changes to the rest of the delivery are not overwritten; ErrNotFound
+// means the delivery does not exist or is no longer on its way.
+func (r *repository) UpdateDriverETA(ctx context.Context, id uuid.UUID, eta, updatedAt time.Time) (*domain.DeliveryAssignment, error) {
+	onTheWay := withLegacySpellings([]domain.DeliveryStatus{domain.DeliveryStatusPickedUp, domain.DeliveryStatusInTransit})
+
+	var dbModels []model.DeliveryAssignment
+	err := r.audited(ctx, func(db *gorm.DB) error {
+		return db.
+			Model(&dbModels).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND status IN ?", id, onTheWay).
+			Updates(map[string]interface{}{
+				"driver_eta": eta,
+				"updated_at": updatedAt,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dbModels) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return dbModels[0].ToEntity(), nil
+}
+
+// List retrieves delivery assignments with pagination and filters
+func (r *repository) List(ctx context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, service.ListTotal, error) {
+	var dbModels []model.DeliveryAssignment
+
 	// Count total records
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
+	total, err := r.count(ctx, filters)
+	if err != nil {
+		return nil, service.ListTotal{}, err
+	}
+
+	query, err := filteredQuery(r.db.WithContext(ctx), filters)
+	if err != nil {
+		return nil, service.ListTotal{}, err
 	}
 
 	// Apply pagination
 	offset := (filters.Page - 1) * filters.PageSize
-	if err := query.
+	query = query.
 		Order("created_at DESC").
 		Limit(filters.PageSize).
-		Offset(offset).
-		Find(&dbModels).Error; err != nil {
-		return nil, 0, err
+		Offset(offset)
+
+	if r.config.ExplainQueries {
+		r.logQueryPlan(ctx, "list", query)
+	}
+
+	if err := query.Find(&dbModels).Error; err != nil {
+		return nil, service.ListTotal{}, err
 	}
 
 	// Convert to entities
@@ -106,117 +343,1132 @@ func (r *repository) List(ctx context.Context, filters service.ListFilters) ([]*
 		assignments[i] = dbModel.ToEntity()
 	}
 
-	return assignments, totalCount, nil
+	return assignments, total, nil
+}
+
+// count counts the deliveries matching filters. With a CountTimeout, outside of a transaction,
+// the count is given that long; if it runs out, the planner's estimate is returned instead. A
+// count canceled by the caller returns its error rather than an estimate.
+func (r *repository) count(ctx context.Context, filters service.ListFilters) (service.ListTotal, error) {
+	countRows := func(db *gorm.DB) (int64, error) {
+		query, err := filteredQuery(db, filters)
+		if err != nil {
+			return 0, err
+		}
+		var count int64
+		err = query.Count(&count).Error
+		return count, err
+	}
+
+	// A canceled statement would abort the caller's transaction
+	if r.config.CountTimeout <= 0 || r.inTransaction {
+		count, err := countRows(r.db.WithContext(ctx))
+		return service.ListTotal{Count: count}, err
+	}
+
+	// The driver cancels the statement on the server once the deadline passes, so the count needs
+	// neither a transaction nor a statement_timeout of its own
+	countCtx, cancel := context.WithTimeout(ctx, r.config.CountTimeout)
+	defer cancel()
+	count, err := countRows(r.db.WithContext(countCtx))
+	if err == nil || ctx.Err() != nil {
+		return service.ListTotal{Count: count}, err
+	}
+	if !errors.Is(countCtx.Err(), context.DeadlineExceeded) && !isQueryCanceled(err) {
+		return service.ListTotal{}, err
+	}
+
+	estimate, err := r.estimateCount(ctx, filters)
+	if err != nil {
+		return service.ListTotal{}, fmt.Errorf("failed to estimate count after the exact count timed out: %w", err)
+	}
+	r.config.Logger.Warn("List count timed out; returning an estimate",
+		zap.Duration("count_timeout", r.config.CountTimeout),
+		zap.Int64("estimate", estimate),
+	)
+	return service.ListTotal{Count: estimate, IsEstimate: true}, nil
+}
+
+// planRowsPattern extracts the planner's row estimate from the top line of an EXPLAIN plan, e.g.
+// "Seq Scan on delivery_assignments  (cost=0.00..2041.00 rows=48213 width=712)"
+var planRowsPattern = regexp.MustCompile(`\brows=(\d+)`)
+
+// estimateCount returns the planner's estimate of the deliveries matching filters. It only plans
+// the query, so it is fast regardless of how many rows match.
+func (r *repository) estimateCount(ctx context.Context, filters service.ListFilters) (int64, error) {
+	query, err := filteredQuery(r.db.WithContext(ctx), filters)
+	if err != nil {
+		return 0, err
+	}
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]model.DeliveryAssignment{}).Statement
+
+	plan, err := r.explain(ctx, stmt.SQL.String(), stmt.Vars)
+	if err != nil {
+		return 0, err
+	}
+	if len(plan) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+	match := planRowsPattern.FindStringSubmatch(plan[0])
+	if match == nil {
+		return 0, fmt.Errorf("no row estimate in query plan %q", plan[0])
+	}
+	return strconv.ParseInt(match[1], 10, 64)
+}
+
+// ListWithStatusBreakdown runs List and counts the matching deliveries per status in one
+// read-only REPEATABLE READ transaction, so the page, total and breakdown describe the same snapshot
+func (r *repository) ListWithStatusBreakdown(ctx context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, service.ListTotal, map[domain.DeliveryStatus]int64, error) {
+	var (
+		assignments []*domain.DeliveryAssignment
+		total       service.ListTotal
+		breakdown   map[domain.DeliveryStatus]int64
+	)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query, err := filteredQuery(tx, filters)
+		if err != nil {
+			return err
+		}
+
+		type statusCount struct {
+			Status domain.DeliveryStatus
+			Count  int64
+		}
+		var counts []statusCount
+		if err := query.Select("status, COUNT(*) AS count").Group("status").Find(&counts).Error; err != nil {
+			return err
+		}
+
+		breakdown = make(map[domain.DeliveryStatus]int64, len(counts))
+		for _, c := range counts {
+			breakdown[c.Status.Canonical()] += c.Count
+		}
+
+		// The breakdown has already counted every match, so the total is exact either way
+		txRepo := newRepository(tx, r.config)
+		txRepo.inTransaction = true
+		assignments, total, err = txRepo.List(ctx, filters)
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, service.ListTotal{}, nil, err
+	}
+
+	return assignments, total, breakdown, nil
+}
+
+// filteredQuery applies the List filters to a new delivery query on db
+func filteredQuery(db *gorm.DB, filters service.ListFilters) (*gorm.DB, error) {
+	query := db.Model(&model.DeliveryAssignment{})
+
+	if statuses, restricted := resolveStatuses(filters); restricted {
+		query = query.Where("status IN ?", withLegacySpellings(statuses))
+	}
+	if filters.DriverID != nil {
+		query = query.Where("driver_id = ?", *filters.DriverID)
+	}
+	if filters.HasDriver != nil {
+		if *filters.HasDriver {
+			query = query.Where("driver_id IS NOT NULL")
+		} else {
+			query = query.Where("driver_id IS NULL")
+		}
+	}
+	if filters.RequiredVehicleType != nil {
+		query = query.Where("required_vehicle_type = ?", *filters.RequiredVehicleType)
+	}
+	if filters.Tag != nil {
+		// Containment is served by the GIN index on tags
+		tag, err := json.Marshal([]string{*filters.Tag})
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("tags @> ?::jsonb", string(tag))
+	}
+	if filters.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filters.CreatedBefore)
+	}
+
+	return query, nil
+}
+
+// logQueryPlan captures the SQL of query with a dry run and logs its EXPLAIN plan at debug level.
+// Failures are logged and never affect the real query.
+func (r *repository) logQueryPlan(ctx context.Context, name string, query *gorm.DB) {
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]model.DeliveryAssignment{}).Statement
+	sql := stmt.SQL.String()
+
+	plan, err := r.explain(ctx, sql, stmt.Vars)
+	if err != nil {
+		r.config.Logger.Debug("Failed to explain query",
+			zap.String("query", name),
+			zap.String("sql", sql),
+			zap.Error(err),
+		)
+		return
+	}
+
+	r.config.Logger.Debug("Query plan",
+		zap.String("query", name),
+		zap.String("sql", sql),
+		zap.String("plan", strings.Join(plan, "\n")),
+	)
+}
+
+// runExplain executes EXPLAIN for sql on the underlying connection pool
+func (r *repository) runExplain(ctx context.Context, sql string, vars []interface{}) ([]string, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sqlDB.QueryContext(ctx, "EXPLAIN "+sql, vars...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		plan = append(plan, line)
+	}
+
+	return plan, rows.Err()
+}
+
+// resolveStatuses intersects the explicit status filter with the activity filter.
+// restricted is false when no status filtering applies; an empty result means nothing can match.
+func resolveStatuses(filters service.ListFilters) (statuses []domain.DeliveryStatus, restricted bool) {
+	activity := filters.Activity.Statuses()
+
+	switch {
+	case filters.Status == nil && activity == nil:
+		return nil, false
+	case filters.Status == nil:
+		return activity, true
+	case activity == nil:
+		return []domain.DeliveryStatus{*filters.Status}, true
+	}
+
+	for _, s := range activity {
+		if s == *filters.Status {
+			return []domain.DeliveryStatus{s}, true
+		}
+	}
+	return []domain.DeliveryStatus{}, true
+}
+
+// withLegacySpellings expands statuses with the legacy spellings they may be stored under
+func withLegacySpellings(statuses []domain.DeliveryStatus) []domain.DeliveryStatus {
+	expanded := make([]domain.DeliveryStatus, 0, len(statuses))
+	for _, s := range statuses {
+		expanded = append(expanded, s.Spellings()...)
+	}
+	return expanded
+}
+
+// ListOverdueAssigned retrieves ASSIGNED deliveries whose pickup was due before cutoff (see
+// domain.DeliveryAssignment.PickupDueAt), oldest scheduled pickup first
+func (r *repository) ListOverdueAssigned(ctx context.Context, cutoff time.Time, limit int) ([]*domain.DeliveryAssignment, error) {
+	var dbModels []model.DeliveryAssignment
+
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_pickup_time < ?", domain.DeliveryStatusAssigned, cutoff).
+		Where("driver_assigned_at IS NULL OR driver_assigned_at < ?", cutoff).
+		Order("scheduled_pickup_time ASC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*domain.DeliveryAssignment, len(dbModels))
+	for i, dbModel := range dbModels {
+		assignments[i] = dbModel.ToEntity()
+	}
+
+	return assignments, nil
+}
+
+// EscalateWaitingPending raises up to limit of the oldest PENDING deliveries of priority from that
+// were created before cutoff to priority to in a single statement and returns them as updated.
+// The status and priority are rechecked as each row is locked, so a delivery claimed or escalated
+// concurrently is left alone instead of having its newer state overwritten.
+func (r *repository) EscalateWaitingPending(ctx context.Context, from, to domain.DeliveryPriority, cutoff, escalatedAt time.Time, limit int) ([]*domain.DeliveryAssignment, error) {
+	var dbModels []model.DeliveryAssignment
+
+	err := r.audited(ctx, func(db *gorm.DB) error {
+		waiting := db.Session(&gorm.Session{NewDB: true}).
+			Model(&model.DeliveryAssignment{}).
+			Select("id").
+			Where("status = ? AND priority = ? AND created_at < ?", domain.DeliveryStatusPending, from, cutoff).
+			Order("created_at ASC").
+			Limit(limit)
+
+		return db.
+			Model(&dbModels).
+			Clauses(clause.Returning{}).
+			Where("id IN (?)", waiting).
+			Where("status = ? AND priority = ?", domain.DeliveryStatusPending, from).
+			Updates(map[string]interface{}{
+				"priority":   to,
+				"updated_at": escalatedAt,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*domain.DeliveryAssignment, len(dbModels))
+	for i, dbModel := range dbModels {
+		assignments[i] = dbModel.ToEntity()
+	}
+
+	return assignments, nil
+}
+
+// claimOrder dispatches the most urgent PENDING delivery first and the oldest among equals,
+// ranking priorities as domain.DeliveryPriority.Rank does
+var claimOrder = fmt.Sprintf(
+	"CASE priority WHEN '%s' THEN %d WHEN '%s' THEN %d WHEN '%s' THEN %d ELSE 0 END DESC, created_at ASC, id ASC",
+	domain.PriorityHigh, domain.PriorityHigh.Rank(),
+	domain.PriorityNormal, domain.PriorityNormal.Rank(),
+	domain.PriorityLow, domain.PriorityLow.Rank(),
+)
+
+// ClaimNextPending assigns the most urgent, then oldest, PENDING delivery to driverID in one transaction. The row is
+// selected FOR UPDATE SKIP LOCKED, so concurrent claimers each get a different delivery instead of
+// waiting on or double-claiming the same one.
+func (r *repository) ClaimNextPending(ctx context.Context, driverID string) (*domain.DeliveryAssignment, error) {
+	var claimed *domain.DeliveryAssignment
+	claim := func(repo service.DeliveryRepository) error {
+		tx := repo.(*repository)
+
+		var dbModel model.DeliveryAssignment
+		err := tx.db.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", domain.DeliveryStatusPending).
+			Order(claimOrder).
+			First(&dbModel).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+
+		assignment := dbModel.ToEntity()
+		if err := assignment.AssignDriver(driverID); err != nil {
+			return err
+		}
+		if err := tx.Update(ctx, assignment); err != nil {
+			return err
+		}
+		claimed = assignment
+		return nil
+	}
+
+	// Already in a transaction: the lock is held until the caller's transaction ends
+	var err error
+	if r.inTransaction {
+		err = claim(r)
+	} else {
+		err = r.WithTransaction(ctx, claim)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
 }
 
-// GetMetrics retrieves delivery metrics for a time range
-func (r *repository) GetMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string) (*domain.DeliveryMetrics, error) {
+// modifiedAtColumn is when a row last changed. Soft deletes only set deleted_at, so it is
+// taken into account for deleted rows.
+const modifiedAtColumn = "GREATEST(updated_at, COALESCE(deleted_at, updated_at))"
+
+// ListModifiedSince retrieves rows changed after the cursor, including soft-deleted ones, oldest
+// change first. Rows changed at the same instant are ordered by ID, so the row comparison resumes
+// exactly after the cursor even when more rows share its time than fit in one batch.
+func (r *repository) ListModifiedSince(ctx context.Context, after domain.SyncCursor, limit int) ([]*domain.DeliveryAssignment, error) {
+	var dbModels []model.DeliveryAssignment
+
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("("+modifiedAtColumn+", id) > (?, ?)", after.ModifiedAt, after.ID).
+		Order(modifiedAtColumn + " ASC, id ASC").
+		Limit(limit).
+		Find(&dbModels).Error; err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*domain.DeliveryAssignment, len(dbModels))
+	for i, dbModel := range dbModels {
+		assignments[i] = dbModel.ToEntity()
+	}
+
+	return assignments, nil
+}
+
+// metricQuery computes one part of the delivery metrics
+type metricQuery struct {
+	name     string
+	required bool // A failing required query aborts the whole call
+	run      func(metrics *domain.DeliveryMetrics) error
+}
+
+// terminalStatusCount is how the metrics count the deliveries in one terminal status
+type terminalStatusCount struct {
+	status domain.DeliveryStatus
+	column string                               // Column the aggregate queries select the count as
+	count  func(*domain.DeliveryMetrics) *int32 // Where the count goes in the metrics
+}
+
+// terminalStatusCounts lists the count of every terminal status (domain.TerminalStatuses). All
+// metrics queries group statuses through it, via statusCountFor and terminalCountsSelect.
+var terminalStatusCounts = []terminalStatusCount{
+	{domain.DeliveryStatusDelivered, "completed_deliveries", func(m *domain.DeliveryMetrics) *int32 { return &m.CompletedDeliveries }},
+	{domain.DeliveryStatusFailed, "failed_deliveries", func(m *domain.DeliveryMetrics) *int32 { return &m.FailedDeliveries }},
+	{domain.DeliveryStatusCancelled, "cancelled_deliveries", func(m *domain.DeliveryMetrics) *int32 { return &m.CancelledDeliveries }},
+	{domain.DeliveryStatusDeadLetter, "dead_letter_deliveries", func(m *domain.DeliveryMetrics) *int32 { return &m.DeadLetterDeliveries }},
+}
+
+// statusCountFor returns the count of deliveries in status, in any spelling; active statuses have none
+func statusCountFor(status domain.DeliveryStatus) (terminalStatusCount, bool) {
+	status = status.Canonical()
+	for _, c := range terminalStatusCounts {
+		if c.status == status {
+			return c, true
+		}
+	}
+	return terminalStatusCount{}, false
+}
+
+// terminalCountsSelect returns the select expressions counting the deliveries in each terminal
+// status, legacy spellings included, and their arguments
+func terminalCountsSelect() (string, []interface{}) {
+	exprs := make([]string, len(terminalStatusCounts))
+	args := make([]interface{}, len(terminalStatusCounts))
+	for i, c := range terminalStatusCounts {
+		exprs[i] = "COUNT(*) FILTER (WHERE status IN ?) AS " + c.column
+		args[i] = c.status.Spellings()
+	}
+	return strings.Join(exprs, ", "), args
+}
+
+// collectMetrics runs each query independently. Failures of optional queries are recorded
+// in metrics.Errors and leave their values at zero so the remaining metrics are still returned.
+func collectMetrics(queries []metricQuery) (*domain.DeliveryMetrics, error) {
 	var metrics domain.DeliveryMetrics
 
-	query := r.db.WithContext(ctx).Model(&model.DeliveryAssignment{}).
-		Where("created_at BETWEEN ? AND ?", startTime, endTime)
+	for _, q := range queries {
+		if err := q.run(&metrics); err != nil {
+			if q.required {
+				return nil, fmt.Errorf("%s: %w", q.name, err)
+			}
+			metrics.Errors = append(metrics.Errors, fmt.Sprintf("%s: %v", q.name, err))
+		}
+	}
+
+	return &metrics, nil
+}
+
+// GetMetrics retrieves delivery metrics for a time range. Fleet-wide ranges covering whole days
+// that are all in the daily summary are served from it, with the partial days around them
+// queried live; anything else is computed live.
+func (r *repository) GetMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string, onTimeGrace time.Duration) (*domain.DeliveryMetrics, error) {
+	if driverID == nil {
+		metrics, err := r.summarizedMetrics(ctx, startTime, endTime, onTimeGrace)
+		if err != nil {
+			// The summary is an optimization; the live queries still give the answer
+			r.config.Logger.Warn("Failed to read metrics summary, computing live", zap.Error(err))
+		} else if metrics != nil {
+			return metrics, nil
+		}
+	}
+
+	return r.liveMetrics(ctx, startTime, endTime, driverID, onTimeGrace)
+}
+
+// liveMetrics computes delivery metrics from delivery_assignments.
+// Total and per-status counts are required; the remaining metrics are best-effort.
+func (r *repository) liveMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string, onTimeGrace time.Duration) (*domain.DeliveryMetrics, error) {
+	// scoped builds a fresh query filtered by time range and optional driver
+	scoped := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&model.DeliveryAssignment{}).
+			Where("created_at BETWEEN ? AND ?", startTime, endTime)
+		if driverID != nil {
+			query = query.Where("driver_id = ?", *driverID)
+		}
+		return query
+	}
+
+	return collectMetrics([]metricQuery{
+		{
+			name:     "total_deliveries",
+			required: true,
+			run: func(metrics *domain.DeliveryMetrics) error {
+				var totalCount int64
+				if err := scoped().Count(&totalCount).Error; err != nil {
+					return err
+				}
+				metrics.TotalDeliveries = int32(totalCount)
+				return nil
+			},
+		},
+		{
+			name:     "status_counts",
+			required: true,
+			run: func(metrics *domain.DeliveryMetrics) error {
+				type StatusCount struct {
+					Status domain.DeliveryStatus
+					Count  int32
+				}
+
+				var statusCounts []StatusCount
+				if err := scoped().
+					Select("status, COUNT(*) as count").
+					Group("status").
+					Find(&statusCounts).Error; err != nil {
+					return err
+				}
+
+				for _, sc := range statusCounts {
+					if c, ok := statusCountFor(sc.Status); ok {
+						*c.count(metrics) += sc.Count
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "average_delivery_time",
+			run: func(metrics *domain.DeliveryMetrics) error {
+				type AvgTime struct {
+					AvgMinutes float64
+				}
+				var avgTime AvgTime
+
+				if err := r.db.WithContext(ctx).
+					Model(&model.DeliveryAssignment{}).
+					Where("status = ? AND actual_pickup_time IS NOT NULL AND actual_delivery_time IS NOT NULL", domain.DeliveryStatusDelivered).
+					Where("created_at BETWEEN ? AND ?", startTime, endTime).
+					Select("AVG(EXTRACT(EPOCH FROM (actual_delivery_time - actual_pickup_time))/60) as avg_minutes").
+					Scan(&avgTime).Error; err != nil {
+					return err
+				}
+				metrics.AverageDeliveryTimeMinutes = avgTime.AvgMinutes
+				return nil
+			},
+		},
+		{
+			name: "average_lifecycle_duration",
+			run: func(metrics *domain.DeliveryMetrics) error {
+				type AvgLifecycle struct {
+					AvgLifecycleMinutes float64
+				}
+				var avgLifecycle AvgLifecycle
+
+				if err := scoped().
+					Where("status = ? AND actual_delivery_time IS NOT NULL", domain.DeliveryStatusDelivered).
+					Select("COALESCE(AVG(EXTRACT(EPOCH FROM (actual_delivery_time - created_at))/60), 0) as avg_lifecycle_minutes").
+					Scan(&avgLifecycle).Error; err != nil {
+					return err
+				}
+				metrics.AverageLifecycleDurationMinutes = avgLifecycle.AvgLifecycleMinutes
+				return nil
+			},
+		},
+		{
+			name: "on_time_delivery_rate",
+			run: func(metrics *domain.DeliveryMetrics) error {
+				type OnTimeCount struct {
+					OnTime int32
+					Total  int32
+				}
+				var onTimeCount OnTimeCount
+
+				if err := r.db.WithContext(ctx).
+					Model(&model.DeliveryAssignment{}).
+					Where("status = ? AND actual_delivery_time IS NOT NULL", domain.DeliveryStatusDelivered).
+					Where("created_at BETWEEN ? AND ?", startTime, endTime).
+					Select("SUM(CASE WHEN actual_delivery_time <= estimated_delivery_time + make_interval(secs => ?) THEN 1 ELSE 0 END) as on_time, COUNT(*) as total",
+						onTimeGrace.Seconds()).
+					Scan(&onTimeCount).Error; err != nil {
+					return err
+				}
 
-	if driverID != nil {
-		query = query.Where("driver_id = ?", *driverID)
+				if onTimeCount.Total > 0 {
+					metrics.OnTimeDeliveryRate = float64(onTimeCount.OnTime) / float64(onTimeCount.Total) * 100
+				}
+				return nil
+			},
+		},
+		{
+			name: "average_rating",
+			run: func(metrics *domain.DeliveryMetrics) error {
+				type AvgRating struct {
+					AvgRating float64
+				}
+				var avgRating AvgRating
+
+				if err := scoped().
+					Where("rating IS NOT NULL").
+					Select("COALESCE(AVG(rating), 0) as avg_rating").
+					Scan(&avgRating).Error; err != nil {
+					return err
+				}
+				metrics.AverageRating = avgRating.AvgRating
+				return nil
+			},
+		},
+		{
+			name: "failures_by_reason",
+			run: func(metrics *domain.DeliveryMetrics) error {
+				counts, err := failureReasonCounts(scoped())
+				if err != nil {
+					return err
+				}
+				metrics.FailuresByReason = make(map[string]int32, len(counts))
+				for code, count := range counts {
+					metrics.FailuresByReason[code] = int32(count)
+				}
+				return nil
+			},
+		},
+	})
+}
+
+// failureReasonCounts counts the deliveries matched by query that failed, including those
+// dead-lettered since, per failure reason code. Deliveries failed before codes were recorded are
+// not counted.
+func failureReasonCounts(query *gorm.DB) (model.FailureCounts, error) {
+	type reasonCount struct {
+		FailureReasonCode string
+		Count             int64
 	}
 
-	// Total deliveries
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
+	var counts []reasonCount
+	if err := failedWithReason(query).
+		Select("failure_reason_code, COUNT(*) AS count").
+		Group("failure_reason_code").
+		Find(&counts).Error; err != nil {
 		return nil, err
 	}
-	metrics.TotalDeliveries = int32(totalCount)
-	// Count by status
-	type StatusCount struct {
-		Status domain.DeliveryStatus
-		Count  int32
+
+	failures := make(model.FailureCounts, len(counts))
+	for _, c := range counts {
+		failures[c.FailureReasonCode] = c.Count
+	}
+	return failures, nil
+}
+
+// failedWithReason narrows query to the deliveries that failed with a reason code, including those
+// dead-lettered since
+func failedWithReason(query *gorm.DB) *gorm.DB {
+	return query.Where("status IN ? AND failure_reason_code IS NOT NULL",
+		[]domain.DeliveryStatus{domain.DeliveryStatusFailed, domain.DeliveryStatusDeadLetter})
+}
+
+// summaryDay truncates t to the start of its UTC day, the granularity of the metrics summary
+func summaryDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// summarizedMetrics combines the daily summary for the whole days in [startTime, endTime] with live
+// aggregates of the partial days around them. It returns nil metrics when the range contains no
+// whole day or any of them is missing from the summary (or was computed with another grace period).
+func (r *repository) summarizedMetrics(ctx context.Context, startTime, endTime time.Time, onTimeGrace time.Duration) (*domain.DeliveryMetrics, error) {
+	// endTime is inclusive; timestamps are stored with microsecond precision
+	rangeEnd := endTime.Add(time.Microsecond)
+
+	firstDay := summaryDay(startTime)
+	if firstDay.Before(startTime) {
+		firstDay = firstDay.Add(24 * time.Hour)
+	}
+	lastDayEnd := summaryDay(rangeEnd)
+	if !lastDayEnd.After(firstDay) {
+		return nil, nil
 	}
 
-	var statusCounts []StatusCount
-	if err := query.
-		Select("status, COUNT(*) as count").
-		Group("status").
-		Find(&statusCounts).Error; err != nil {
+	// Dates are compared as text so the session time zone cannot shift them
+	var days []model.DeliveryMetricsDaily
+	if err := r.db.WithContext(ctx).
+		Where("day >= ?::date AND day < ?::date", firstDay.Format(time.DateOnly), lastDayEnd.Format(time.DateOnly)).
+		Where("on_time_grace_seconds = ?", onTimeGrace.Seconds()).
+		Find(&days).Error; err != nil {
 		return nil, err
 	}
+	if len(days) != int(lastDayEnd.Sub(firstDay)/(24*time.Hour)) {
+		return nil, nil
+	}
+
+	var total model.MetricsAggregate
+	for _, day := range days {
+		total.Add(day.MetricsAggregate)
+	}
 
-	for _, sc := range statusCounts {
-		switch sc.Status {
-		case domain.DeliveryStatusDelivered:
-			metrics.CompletedDeliveries = sc.Count
-		case domain.DeliveryStatusFailed:
-			metrics.FailedDeliveries = sc.Count
-		case domain.DeliveryStatusCancelled:
-			metrics.CancelledDeliveries = sc.Count
+	for _, partial := range [][2]time.Time{{startTime, firstDay}, {lastDayEnd, rangeEnd}} {
+		if !partial[1].After(partial[0]) {
+			continue
 		}
+		aggregate, err := r.aggregateMetrics(ctx, partial[0], partial[1], onTimeGrace)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(*aggregate)
 	}
 
-	// Average delivery time for completed deliveries
-	type AvgTime struct {
-		AvgMinutes float64
+	return total.ToEntity(), nil
+}
+
+// aggregateMetrics computes the metrics sums and counts of the deliveries created in [from, to)
+func (r *repository) aggregateMetrics(ctx context.Context, from, to time.Time, onTimeGrace time.Duration) (*model.MetricsAggregate, error) {
+	// scoped builds a fresh query of the deliveries created in the period
+	scoped := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&model.DeliveryAssignment{}).
+			Where("created_at >= ? AND created_at < ?", from, to)
+	}
+
+	delivered := domain.DeliveryStatusDelivered
+	statusCounts, statusArgs := terminalCountsSelect()
+	var aggregate model.MetricsAggregate
+	if err := scoped().
+		Select("COUNT(*) AS total_deliveries, "+statusCounts+", "+
+			"COALESCE(SUM(EXTRACT(EPOCH FROM (actual_delivery_time - actual_pickup_time))/60) "+
+			"FILTER (WHERE status = ? AND actual_pickup_time IS NOT NULL AND actual_delivery_time IS NOT NULL), 0) AS delivery_time_sum_minutes, "+
+			"COUNT(*) FILTER (WHERE status = ? AND actual_pickup_time IS NOT NULL AND actual_delivery_time IS NOT NULL) AS delivery_time_count, "+
+			"COALESCE(SUM(EXTRACT(EPOCH FROM (actual_delivery_time - created_at))/60) "+
+			"FILTER (WHERE status = ? AND actual_delivery_time IS NOT NULL), 0) AS lifecycle_sum_minutes, "+
+			"COUNT(*) FILTER (WHERE status = ? AND actual_delivery_time IS NOT NULL) AS delivered_count, "+
+			"COUNT(*) FILTER (WHERE status = ? AND actual_delivery_time IS NOT NULL "+
+			"AND actual_delivery_time <= estimated_delivery_time + make_interval(secs => ?)) AS on_time_count, "+
+			"COALESCE(SUM(rating), 0) AS rating_sum, "+
+			"COUNT(rating) AS rating_count",
+			append(statusArgs, delivered, delivered, delivered, delivered, delivered, onTimeGrace.Seconds())...).
+		Scan(&aggregate).Error; err != nil {
+		return nil, err
+	}
+
+	failures, err := failureReasonCounts(scoped())
+	if err != nil {
+		return nil, err
 	}
-	var avgTime AvgTime
+	aggregate.FailuresByReason = failures
 
+	return &aggregate, nil
+}
+
+// RefreshMetricsSummary recomputes the summary row of the UTC day containing day and upserts it
+func (r *repository) RefreshMetricsSummary(ctx context.Context, day time.Time, onTimeGrace time.Duration) error {
+	day = summaryDay(day)
+
+	aggregate, err := r.aggregateMetrics(ctx, day, day.Add(24*time.Hour), onTimeGrace)
+	if err != nil {
+		return err
+	}
+
+	summary := model.DeliveryMetricsDaily{
+		Day:                day,
+		OnTimeGraceSeconds: onTimeGrace.Seconds(),
+		MetricsAggregate:   *aggregate,
+		RefreshedAt:        r.config.Clock.Now().UTC(),
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}},
+			UpdateAll: true,
+		}).
+		Create(&summary).Error
+}
+
+// GetMetricsForDrivers computes the GetMetrics values per driver with one query grouped by
+// driver_id, and one more for the failures by reason, however many drivers are requested
+func (r *repository) GetMetricsForDrivers(ctx context.Context, driverIDs []string, startTime, endTime time.Time, onTimeGrace time.Duration) (map[string]*domain.DeliveryMetrics, error) {
+	type driverMetricsRow struct {
+		DriverID                        string
+		TotalDeliveries                 int32
+		CompletedDeliveries             int32
+		FailedDeliveries                int32
+		CancelledDeliveries             int32
+		DeadLetterDeliveries            int32
+		AverageDeliveryTimeMinutes      float64
+		AverageLifecycleDurationMinutes float64
+		OnTimeDeliveryRate              float64
+		AverageRating                   float64
+	}
+
+	delivered := domain.DeliveryStatusDelivered
+	statusCounts, statusArgs := terminalCountsSelect()
+	var rows []driverMetricsRow
 	if err := r.db.WithContext(ctx).
 		Model(&model.DeliveryAssignment{}).
-		Where("status = ? AND actual_pickup_time IS NOT NULL AND actual_delivery_time IS NOT NULL", domain.DeliveryStatusDelivered).
+		Select("driver_id, "+
+			"COUNT(*) AS total_deliveries, "+statusCounts+", "+
+			"COALESCE(AVG(EXTRACT(EPOCH FROM (actual_delivery_time - actual_pickup_time))/60) "+
+			"FILTER (WHERE status = ? AND actual_pickup_time IS NOT NULL AND actual_delivery_time IS NOT NULL), 0) AS average_delivery_time_minutes, "+
+			"COALESCE(AVG(EXTRACT(EPOCH FROM (actual_delivery_time - created_at))/60) "+
+			"FILTER (WHERE status = ? AND actual_delivery_time IS NOT NULL), 0) AS average_lifecycle_duration_minutes, "+
+			"COALESCE(AVG(CASE WHEN actual_delivery_time <= estimated_delivery_time + make_interval(secs => ?) THEN 100.0 ELSE 0 END) "+
+			"FILTER (WHERE status = ? AND actual_delivery_time IS NOT NULL), 0) AS on_time_delivery_rate, "+
+			"COALESCE(AVG(rating), 0) AS average_rating",
+			append(statusArgs, delivered, delivered, onTimeGrace.Seconds(), delivered)...).
+		Where("driver_id IN ?", driverIDs).
 		Where("created_at BETWEEN ? AND ?", startTime, endTime).
-		Select("AVG(EXTRACT(EPOCH FROM (actual_delivery_time - actual_pickup_time))/60) as avg_minutes").
-		Scan(&avgTime).Error; err != nil {
+		Group("driver_id").
+		Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	metrics.AverageDeliveryTimeMinutes = avgTime.AvgMinutes
 
-	// On-time delivery rate
-	type OnTimeCount struct {
-		OnTime int32
-		Total  int32
+	metrics := make(map[string]*domain.DeliveryMetrics, len(driverIDs))
+	for _, driverID := range driverIDs {
+		metrics[driverID] = &domain.DeliveryMetrics{}
+	}
+	for _, row := range rows {
+		metrics[row.DriverID] = &domain.DeliveryMetrics{
+			TotalDeliveries:                 row.TotalDeliveries,
+			CompletedDeliveries:             row.CompletedDeliveries,
+			FailedDeliveries:                row.FailedDeliveries,
+			CancelledDeliveries:             row.CancelledDeliveries,
+			DeadLetterDeliveries:            row.DeadLetterDeliveries,
+			AverageDeliveryTimeMinutes:      row.AverageDeliveryTimeMinutes,
+			AverageLifecycleDurationMinutes: row.AverageLifecycleDurationMinutes,
+			OnTimeDeliveryRate:              row.OnTimeDeliveryRate,
+			AverageRating:                   row.AverageRating,
+		}
+	}
+
+	type driverFailureRow struct {
+		DriverID          string
+		FailureReasonCode string
+		Count             int32
+	}
+
+	var failures []driverFailureRow
+	if err := failedWithReason(r.db.WithContext(ctx).Model(&model.DeliveryAssignment{})).
+		Where("driver_id IN ?", driverIDs).
+		Where("created_at BETWEEN ? AND ?", startTime, endTime).
+		Select("driver_id, failure_reason_code, COUNT(*) AS count").
+		Group("driver_id, failure_reason_code").
+		Scan(&failures).Error; err != nil {
+		return nil, err
+	}
+	for _, failure := range failures {
+		driverMetrics := metrics[failure.DriverID]
+		if driverMetrics.FailuresByReason == nil {
+			driverMetrics.FailuresByReason = make(map[string]int32)
+		}
+		driverMetrics.FailuresByReason[failure.FailureReasonCode] = failure.Count
+	}
+
+	return metrics, nil
+}
+
+// GetDriverLeaderboard ranks drivers by deliveries completed in the time range.
+// Ties are broken by on-time rate and then driver ID so the order is deterministic. The on-time
+// rate allows onTimeGrace like GetMetrics, so both report the same rate for a driver.
+func (r *repository) GetDriverLeaderboard(ctx context.Context, startTime, endTime time.Time, limit int, onTimeGrace time.Duration) ([]domain.DriverStats, error) {
+	type leaderboardRow struct {
+		DriverID            string
+		CompletedDeliveries int32
+		OnTimeDeliveryRate  float64
 	}
-	var onTimeCount OnTimeCount
 
+	var rows []leaderboardRow
 	if err := r.db.WithContext(ctx).
 		Model(&model.DeliveryAssignment{}).
-		Where("status = ? AND actual_delivery_time IS NOT NULL", domain.DeliveryStatusDelivered).
+		Select("driver_id, "+
+			"COUNT(*) AS completed_deliveries, "+
+			"SUM(CASE WHEN actual_delivery_time <= estimated_delivery_time + make_interval(secs => ?) THEN 1 ELSE 0 END) * 100.0 / COUNT(*) AS on_time_delivery_rate",
+			onTimeGrace.Seconds()).
+		Where("status = ? AND driver_id IS NOT NULL", domain.DeliveryStatusDelivered).
 		Where("created_at BETWEEN ? AND ?", startTime, endTime).
-		Select("SUM(CASE WHEN actual_delivery_time <= estimated_delivery_time THEN 1 ELSE 0 END) as on_time, COUNT(*) as total").
-		Scan(&onTimeCount).Error; err != nil {
+		Group("driver_id").
+		Order("completed_deliveries DESC, on_time_delivery_rate DESC, driver_id ASC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
-	if onTimeCount.Total > 0 {
-		metrics.OnTimeDeliveryRate = float64(onTimeCount.OnTime) / float64(onTimeCount.Total) * 100
+	leaderboard := make([]domain.DriverStats, len(rows))
+	for i, row := range rows {
+		leaderboard[i] = domain.DriverStats{
+			Rank:                int32(i + 1),
+			DriverID:            row.DriverID,
+			CompletedDeliveries: row.CompletedDeliveries,
+			OnTimeDeliveryRate:  row.OnTimeDeliveryRate,
+		}
 	}
 
-	return &metrics, nil
+	return leaderboard, nil
+}
+
+// ListActiveDriverIDs returns the distinct drivers assigned to at least one non-terminal delivery
+func (r *repository) ListActiveDriverIDs(ctx context.Context) ([]string, error) {
+	driverIDs := []string{}
+	if err := r.db.WithContext(ctx).
+		Model(&model.DeliveryAssignment{}).
+		Distinct("driver_id").
+		Where("status IN ? AND driver_id IS NOT NULL", domain.ActiveStatuses()).
+		Order("driver_id ASC").
+		Pluck("driver_id", &driverIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return driverIDs, nil
+}
+
+// ListServedCities retrieves the distinct pickup and delivery cities, merged and sorted ascending.
+// Blank cities are skipped; cities are compared as stored, after trimming.
+func (r *repository) ListServedCities(ctx context.Context) ([]string, error) {
+	served := make(map[string]struct{})
+	for _, column := range []string{"pickup_address", "delivery_address"} {
+		city := fmt.Sprintf("btrim(%s->>'city')", column)
+
+		var cities []string
+		if err := r.db.WithContext(ctx).
+			Model(&model.DeliveryAssignment{}).
+			Distinct(city).
+			Where(city+" <> ''").
+			Pluck(city, &cities).Error; err != nil {
+			return nil, err
+		}
+		for _, c := range cities {
+			served[c] = struct{}{}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(served)), nil
+}
+
+// RecordDriverLocation stores a location ping
+func (r *repository) RecordDriverLocation(ctx context.Context, driverID string, latitude, longitude float64, recordedAt time.Time) error {
+	return r.db.WithContext(ctx).Create(&model.DriverLocation{
+		DriverID:   driverID,
+		Latitude:   latitude,
+		Longitude:  longitude,
+		RecordedAt: recordedAt,
+	}).Error
+}
+
+// GetLatestDriverLocation retrieves the ping with the latest recorded time of a driver
+func (r *repository) GetLatestDriverLocation(ctx context.Context, driverID string) (*domain.DriverLocation, error) {
+	var location model.DriverLocation
+
+	if err := r.db.WithContext(ctx).
+		Where("driver_id = ?", driverID).
+		Order("recorded_at DESC, id DESC").
+		First(&location).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &domain.NotFoundError{Resource: "driver location", ID: driverID}
+		}
+		return nil, err
+	}
+
+	return location.ToEntity(), nil
+}
+
+// StreamAll iterates over all delivery assignments in primary key order without loading
+// the whole table into memory
+func (r *repository) StreamAll(ctx context.Context, fn func(*domain.DeliveryAssignment) error) error {
+	var batch []model.DeliveryAssignment
+
+	result := r.db.WithContext(ctx).
+		FindInBatches(&batch, constants.ExportBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if err := fn(batch[i].ToEntity()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	return result.Error
 }
 
 // Delete soft deletes a delivery assignment
 func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&model.DeliveryAssignment{}, "id = ?", id)
+	deleted, err := r.softDelete(ctx, func(db *gorm.DB) (*gorm.DB, error) {
+		return db.Where("id = ?", id), nil
+	})
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete delivery assignment: %w", result.Error)
+	if err != nil {
+		return fmt.Errorf("failed to delete delivery assignment: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
+	if len(deleted) == 0 {
 		return domain.ErrNotFound
 	}
 
 	return nil
 }
 
-// WithTransaction executes a function within a database transaction
+// DeleteByOrderID soft deletes the delivery assignments for an order that have not been picked up,
+// in a single statement. The status is checked by the UPDATE itself, so a delivery picked up
+// concurrently is never deleted. Deleting an order with no remaining deliveries is not an error,
+// so retries are safe. The IDs of the deleted deliveries are returned.
+func (r *repository) DeleteByOrderID(ctx context.Context, orderID string) ([]uuid.UUID, error) {
+	deleted, err := r.softDelete(ctx, func(db *gorm.DB) (*gorm.DB, error) {
+		return db.Where("order_id = ? AND status NOT IN ?", orderID, withLegacySpellings(domain.PickedUpStatuses())), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete delivery assignments for order: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// deletableQuery narrows filteredQuery to the deliveries a bulk delete may remove: like a delete
+// by order, it never touches one a driver has picked up
+func deletableQuery(db *gorm.DB, filters service.ListFilters) (*gorm.DB, error) {
+	query, err := filteredQuery(db, filters)
+	if err != nil {
+		return nil, err
+	}
+	return query.Where("status NOT IN ?", withLegacySpellings(domain.PickedUpStatuses())), nil
+}
+
+// CountMatching counts the delivery assignments matching filters that a bulk delete may remove
+func (r *repository) CountMatching(ctx context.Context, filters service.ListFilters) (int64, error) {
+	query, err := deletableQuery(r.db.WithContext(ctx), filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count delivery assignments: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteMatching soft deletes up to limit of the oldest delivery assignments matching filters in a
+// single statement, so a large bulk delete holds its row locks only one batch at a time. The IDs
+// of the deleted deliveries are returned.
+func (r *repository) DeleteMatching(ctx context.Context, filters service.ListFilters, limit int) ([]uuid.UUID, error) {
+	deleted, err := r.softDelete(ctx, func(db *gorm.DB) (*gorm.DB, error) {
+		batch, err := deletableQuery(db.Session(&gorm.Session{NewDB: true}), filters)
+		if err != nil {
+			return nil, err
+		}
+		batch = batch.Select("id").Order("created_at").Limit(limit)
+
+		return db.Where("id IN (?)", batch), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete delivery assignments: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// softDelete soft deletes the delivery assignments selected by scope and applies the delete
+// policy to their related rows, returning the IDs of the deliveries deleted. Timeline notes,
+// feedback and the rest live on the delivery row and are hidden with it. Audit log rows are
+// retained unless Config.CascadeAuditLog is set; then the deliveries are locked and selected
+// first, and they and their audit log rows are soft deleted in one transaction, so a delivery is
+// never deleted without its audit log or the other way round.
+func (r *repository) softDelete(ctx context.Context, scope func(db *gorm.DB) (*gorm.DB, error)) ([]uuid.UUID, error) {
+	if !r.config.CascadeAuditLog {
+		var dbModels []model.DeliveryAssignment
+		err := r.audited(ctx, func(db *gorm.DB) error {
+			scoped, err := scope(db)
+			if err != nil {
+				return err
+			}
+			return scoped.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Delete(&dbModels).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		deleted := make([]uuid.UUID, len(dbModels))
+		for i, dbModel := range dbModels {
+			deleted[i] = dbModel.ID
+		}
+		return deleted, nil
+	}
+
+	var deleted []uuid.UUID
+	cascade := func(repo service.DeliveryRepository) error {
+		tx := repo.(*repository).db.WithContext(ctx)
+
+		scoped, err := scope(tx.Model(&model.DeliveryAssignment{}))
+		if err != nil {
+			return err
+		}
+		var ids []uuid.UUID
+		if err := scoped.Clauses(clause.Locking{Strength: "UPDATE"}).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Delete(&model.DeliveryAssignment{}, "id IN ?", ids).Error; err != nil {
+			return err
+		}
+		deleted = ids // Locked above, so every one of them was deleted
+
+		return tx.Delete(&model.AuditLogEntry{}, "delivery_id IN ?", ids).Error
+	}
+
+	// Already in a transaction: the caller's transaction covers the cascade
+	var err error
+	if r.inTransaction {
+		err = cascade(r)
+	} else {
+		err = r.WithTransaction(ctx, cascade)
+	}
+	return deleted, err
+}
+
+// WithTransaction executes a function within a database transaction.
+// The request's actor, if any, is set on the transaction first (see setSessionActor).
 func (r *repository) WithTransaction(ctx context.Context, fn func(repo service.DeliveryRepository) error) error {
-	tx := r.db.WithContext(ctx).Begin()
+	// The transaction is begun with txCtx, so database/sql rolls it back as soon as the
+	// deadline passes, releasing its locks even while fn is still running
+	txCtx := ctx
+	if r.config.TransactionTimeout > 0 {
+		var cancel context.CancelFunc
+		txCtx, cancel = context.WithTimeout(ctx, r.config.TransactionTimeout)
+		defer cancel()
+	}
+	timedOut := func() bool {
+		return errors.Is(txCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	}
+
+	tx := r.db.WithContext(txCtx).Begin()
 	if tx.Error != nil {
 		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
 	}
 
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		if err := setSessionActor(tx, actor); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// Create a new repository instance with the transaction
-	txRepo := &repository{db: tx}
+	txRepo := newRepository(tx, r.config)
+	txRepo.inTransaction = true
 
 	// Execute the function
-	if err := fn(txRepo); err != nil {
+	err := fn(txRepo)
+	if timedOut() {
+		tx.Rollback() // Already rolled back when the deadline passed
+		return fmt.Errorf("%w: transaction exceeded %v", domain.ErrTimeout, r.config.TransactionTimeout)
+	}
+	if err != nil {
 		if rbErr := tx.Rollback().Error; rbErr != nil {
 			return fmt.Errorf("failed to rollback transaction after error %v: %w", err, rbErr)
 		}
@@ -230,3 +1482,32 @@ func (r *repository) WithTransaction(ctx context.Context, fn func(repo service.D
 
 	return nil
 }
+
+// audited runs a write so that database triggers can see who made it: when ctx carries an actor,
+// fn runs in a transaction that has the actor set. Without an actor, or inside WithTransaction
+// where it is already set, fn runs on the plain handle and costs no extra round trips.
+func (r *repository) audited(ctx context.Context, fn func(db *gorm.DB) error) error {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok || r.inTransaction {
+		return fn(r.db.WithContext(ctx))
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setSessionActor(tx, actor); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// setSessionActor stores actor in the transaction-local settings app.current_user and
+// app.tenant_id for audit triggers and row-level security policies. set_config(..., true) is
+// SET LOCAL with bound parameters, so the values cannot inject SQL.
+func setSessionActor(tx *gorm.DB, actor domain.Actor) error {
+	err := tx.Exec("SELECT set_config('app.current_user', ?, true), set_config('app.tenant_id', ?, true)",
+		actor.UserID, actor.TenantID).Error
+	if err != nil {
+		return fmt.Errorf("failed to set session actor: %w", err)
+	}
+	return nil
+}
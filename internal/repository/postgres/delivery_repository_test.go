@@ -0,0 +1,2095 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres/model"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	pgmigrate "github.com/mohamadchoker/order-delivery-service/pkg/postgres"
+)
+
+func TestCollectMetrics(t *testing.T) {
+	counts := metricQuery{
+		name:     "total_deliveries",
+		required: true,
+		run: func(metrics *domain.DeliveryMetrics) error {
+			metrics.TotalDeliveries = 10
+			metrics.CompletedDeliveries = 7
+			return nil
+		},
+	}
+	rating := metricQuery{
+		name: "average_rating",
+		run: func(metrics *domain.DeliveryMetrics) error {
+			metrics.AverageRating = 4.5
+			return nil
+		},
+	}
+	failing := func(name string, required bool) metricQuery {
+		return metricQuery{
+			name:     name,
+			required: required,
+			run: func(_ *domain.DeliveryMetrics) error {
+				return errors.New("query timeout")
+			},
+		}
+	}
+
+	t.Run("all queries succeed", func(t *testing.T) {
+		metrics, err := collectMetrics([]metricQuery{counts, rating})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(10), metrics.TotalDeliveries)
+		assert.Equal(t, 4.5, metrics.AverageRating)
+		assert.Empty(t, metrics.Errors)
+	})
+
+	t.Run("optional failure returns partial result", func(t *testing.T) {
+		metrics, err := collectMetrics([]metricQuery{counts, failing("average_delivery_time", false), rating})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(10), metrics.TotalDeliveries)
+		assert.Equal(t, int32(7), metrics.CompletedDeliveries)
+		assert.Equal(t, 4.5, metrics.AverageRating)
+		assert.Zero(t, metrics.AverageDeliveryTimeMinutes)
+		assert.Equal(t, []string{"average_delivery_time: query timeout"}, metrics.Errors)
+	})
+
+	t.Run("required failure aborts", func(t *testing.T) {
+		metrics, err := collectMetrics([]metricQuery{failing("status_counts", true), rating})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status_counts")
+		assert.Nil(t, metrics)
+	})
+}
+
+func TestTerminalStatusCounts(t *testing.T) {
+	// Every terminal status is counted, once, and active statuses are not
+	var counted []domain.DeliveryStatus
+	for _, c := range terminalStatusCounts {
+		counted = append(counted, c.status)
+	}
+	assert.ElementsMatch(t, domain.TerminalStatuses(), counted)
+	for _, status := range domain.ActiveStatuses() {
+		_, ok := statusCountFor(status)
+		assert.False(t, ok, status)
+	}
+
+	c, ok := statusCountFor("CANCELLED")
+	require.True(t, ok, "legacy spellings count as their canonical status")
+	var metrics domain.DeliveryMetrics
+	*c.count(&metrics) += 2
+	assert.Equal(t, int32(2), metrics.CancelledDeliveries)
+
+	selects, args := terminalCountsSelect()
+	assert.Equal(t, "COUNT(*) FILTER (WHERE status IN ?) AS completed_deliveries, "+
+		"COUNT(*) FILTER (WHERE status IN ?) AS failed_deliveries, "+
+		"COUNT(*) FILTER (WHERE status IN ?) AS cancelled_deliveries, "+
+		"COUNT(*) FILTER (WHERE status IN ?) AS dead_letter_deliveries", selects)
+	assert.Contains(t, args, domain.DeliveryStatusCancelled.Spellings())
+}
+
+func TestResolveStatuses(t *testing.T) {
+	pending := domain.DeliveryStatusPending
+	delivered := domain.DeliveryStatusDelivered
+
+	tests := []struct {
+		name       string
+		filters    service.ListFilters
+		expected   []domain.DeliveryStatus
+		restricted bool
+	}{
+		{
+			name:       "any without status",
+			filters:    service.ListFilters{Activity: domain.ActivityAny},
+			expected:   nil,
+			restricted: false,
+		},
+		{
+			name:       "empty activity treated as any",
+			filters:    service.ListFilters{},
+			expected:   nil,
+			restricted: false,
+		},
+		{
+			name:    "active",
+			filters: service.ListFilters{Activity: domain.ActivityActive},
+			expected: []domain.DeliveryStatus{
+				domain.DeliveryStatusPending,
+				domain.DeliveryStatusAssigned,
+				domain.DeliveryStatusPickedUp,
+				domain.DeliveryStatusInTransit,
+			},
+			restricted: true,
+		},
+		{
+			name:    "terminal",
+			filters: service.ListFilters{Activity: domain.ActivityTerminal},
+			expected: []domain.DeliveryStatus{
+				domain.DeliveryStatusDelivered,
+				domain.DeliveryStatusFailed,
+				domain.DeliveryStatusCancelled,
+				domain.DeliveryStatusDeadLetter,
+			},
+			restricted: true,
+		},
+		{
+			name:       "dead letter",
+			filters:    service.ListFilters{Activity: domain.ActivityDeadLetter},
+			expected:   []domain.DeliveryStatus{domain.DeliveryStatusDeadLetter},
+			restricted: true,
+		},
+		{
+			name:       "any with status",
+			filters:    service.ListFilters{Activity: domain.ActivityAny, Status: &pending},
+			expected:   []domain.DeliveryStatus{pending},
+			restricted: true,
+		},
+		{
+			name:       "active intersected with matching status",
+			filters:    service.ListFilters{Activity: domain.ActivityActive, Status: &pending},
+			expected:   []domain.DeliveryStatus{pending},
+			restricted: true,
+		},
+		{
+			name:       "terminal intersected with non-matching status",
+			filters:    service.ListFilters{Activity: domain.ActivityTerminal, Status: &pending},
+			expected:   []domain.DeliveryStatus{},
+			restricted: true,
+		},
+		{
+			name:       "active intersected with non-matching status",
+			filters:    service.ListFilters{Activity: domain.ActivityActive, Status: &delivered},
+			expected:   []domain.DeliveryStatus{},
+			restricted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses, restricted := resolveStatuses(tt.filters)
+
+			assert.Equal(t, tt.restricted, restricted)
+			assert.Equal(t, tt.expected, statuses)
+		})
+	}
+}
+
+func TestLogQueryPlan(t *testing.T) {
+	// No connection is made: the plan is captured with a dry run and EXPLAIN is stubbed
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	repo := newRepository(db, Config{ExplainQueries: true, Logger: zap.New(core)})
+
+	var explainedSQL string
+	var explainedVars []interface{}
+	repo.explain = func(_ context.Context, sql string, vars []interface{}) ([]string, error) {
+		explainedSQL = sql
+		explainedVars = vars
+		return []string{"Limit  (cost=0.15..8.17 rows=20 width=0)", "  ->  Index Scan using idx_status"}, nil
+	}
+
+	query := db.Model(&model.DeliveryAssignment{}).
+		Where("status IN ?", []domain.DeliveryStatus{domain.DeliveryStatusPending}).
+		Order("created_at DESC").
+		Limit(20)
+
+	repo.logQueryPlan(context.Background(), "list", query)
+
+	assert.Contains(t, explainedSQL, "SELECT * FROM \"delivery_assignments\"")
+	assert.Contains(t, explainedSQL, "LIMIT 20")
+	assert.Equal(t, []interface{}{domain.DeliveryStatusPending}, explainedVars)
+
+	entries := logs.FilterMessage("Query plan").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "list", fields["query"])
+	assert.Contains(t, fields["plan"], "Index Scan using idx_status")
+}
+
+func TestLogQueryPlan_ExplainFailure(t *testing.T) {
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	repo := newRepository(db, Config{ExplainQueries: true, Logger: zap.New(core)})
+	repo.explain = func(_ context.Context, _ string, _ []interface{}) ([]string, error) {
+		return nil, errors.New("permission denied")
+	}
+
+	repo.logQueryPlan(context.Background(), "list", db.Model(&model.DeliveryAssignment{}))
+
+	assert.Empty(t, logs.FilterMessage("Query plan").All())
+	assert.Len(t, logs.FilterMessage("Failed to explain query").All(), 1)
+}
+
+func TestUpdate_RefreshesServerFields(t *testing.T) {
+	id := uuid.New()
+	createdAt := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	dbUpdatedAt := time.Date(2026, 2, 1, 9, 30, 0, 0, time.UTC)
+
+	var executed string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		return fakeResult{
+			columns: []string{"id", "created_at", "updated_at"},
+			rows:    [][]driver.Value{{id.String(), createdAt, dbUpdatedAt}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	driverID := "DRIVER-123"
+	assignment := &domain.DeliveryAssignment{
+		ID:        id,
+		OrderID:   "ORDER-123",
+		DriverID:  &driverID,
+		Status:    domain.DeliveryStatusAssigned,
+		Notes:     "Handle with care",
+		CreatedAt: createdAt,
+		UpdatedAt: dbUpdatedAt.Add(-time.Hour), // stale value from the caller
+	}
+
+	err := repo.Update(context.Background(), assignment)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(executed, "UPDATE"))
+	assert.Contains(t, executed, "RETURNING")
+	assert.Equal(t, dbUpdatedAt, assignment.UpdatedAt)
+	assert.Equal(t, createdAt, assignment.CreatedAt)
+	assert.Equal(t, id, assignment.ID)
+	// Caller-owned fields are untouched
+	assert.Equal(t, "ORDER-123", assignment.OrderID)
+	assert.Equal(t, &driverID, assignment.DriverID)
+	assert.Equal(t, "Handle with care", assignment.Notes)
+}
+
+func TestUpdate_NotFound(t *testing.T) {
+	db := openFakeDB(t, func(_ string, _ []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{columns: []string{"id", "created_at", "updated_at"}}, nil
+	})
+	repo := NewRepository(db)
+
+	err := repo.Update(context.Background(), &domain.DeliveryAssignment{ID: uuid.New()})
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUpdateDriverETA(t *testing.T) {
+	id := uuid.New()
+	eta := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := eta.Add(-time.Hour)
+
+	var executed string
+	var args []driver.NamedValue
+	db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+		executed, args = query, a
+		return fakeResult{
+			columns: []string{"id", "status", "driver_eta", "updated_at"},
+			rows:    [][]driver.Value{{id.String(), string(domain.DeliveryStatusInTransit), eta, updatedAt}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	assignment, err := repo.UpdateDriverETA(context.Background(), id, eta, updatedAt)
+
+	require.NoError(t, err)
+	assert.Equal(t, id, assignment.ID)
+	require.NotNil(t, assignment.DriverETA)
+	assert.Equal(t, eta, *assignment.DriverETA)
+	assert.Equal(t, updatedAt, assignment.UpdatedAt)
+
+	// Only the ETA and timestamp are written, and only while the package is on its way
+	assert.Contains(t, executed, `SET "driver_eta"=$1,"updated_at"=$2 WHERE`)
+	assert.Contains(t, executed, "status IN")
+	assert.Contains(t, executed, "RETURNING *")
+	var values []driver.Value
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	assert.Contains(t, values, domain.DeliveryStatusPickedUp)
+	assert.Contains(t, values, domain.DeliveryStatusInTransit)
+	assert.NotContains(t, values, domain.DeliveryStatusDelivered)
+
+	db = openFakeDB(t, func(_ string, _ []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{columns: []string{"id"}}, nil
+	})
+	_, err = NewRepository(db).UpdateDriverETA(context.Background(), id, eta, updatedAt)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	id := uuid.New()
+
+	var executed string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		return fakeResult{
+			columns: []string{"id", "status"},
+			rows:    [][]driver.Value{{id.String(), string(domain.DeliveryStatusAssigned)}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	assignment, err := repo.GetByIDForUpdate(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(executed, "FOR UPDATE"), executed)
+	assert.Equal(t, id, assignment.ID)
+	assert.Equal(t, domain.DeliveryStatusAssigned, assignment.Status)
+
+	db = openFakeDB(t, func(_ string, _ []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{columns: []string{"id"}}, nil
+	})
+	_, err = NewRepository(db).GetByIDForUpdate(context.Background(), id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestGetStatusByID(t *testing.T) {
+	id := uuid.New()
+	updatedAt := time.Date(2026, 2, 1, 9, 30, 0, 0, time.UTC)
+
+	var executed string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		return fakeResult{
+			columns: []string{"id", "status", "updated_at"},
+			rows:    [][]driver.Value{{id.String(), string(domain.DeliveryStatusInTransit), updatedAt}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	info, err := repo.GetStatusByID(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(executed, `SELECT "id","status","updated_at" FROM`), executed)
+	assert.Equal(t, &domain.DeliveryStatusInfo{
+		ID:        id,
+		Status:    domain.DeliveryStatusInTransit,
+		UpdatedAt: updatedAt,
+	}, info)
+}
+
+func TestGetStatusByID_NotFound(t *testing.T) {
+	db := openFakeDB(t, func(_ string, _ []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{columns: []string{"id", "status", "updated_at"}}, nil
+	})
+	repo := NewRepository(db)
+
+	info, err := repo.GetStatusByID(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, info)
+}
+
+func TestGetDriverLeaderboard(t *testing.T) {
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+
+	var executed string
+	var args []driver.NamedValue
+	db := openFakeDB(t, func(query string, queryArgs []driver.NamedValue) (fakeResult, error) {
+		executed, args = query, queryArgs
+		// Rows as Postgres returns them for the ORDER BY below
+		return fakeResult{
+			columns: []string{"driver_id", "completed_deliveries", "on_time_delivery_rate"},
+			rows: [][]driver.Value{
+				{"DRIVER-B", int64(12), 75.0},
+				{"DRIVER-A", int64(9), 100.0},
+				{"DRIVER-C", int64(9), 100.0},
+			},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	leaderboard, err := repo.GetDriverLeaderboard(context.Background(), start, end, 3, 0)
+
+	require.NoError(t, err)
+	assert.Contains(t, executed, "GROUP BY")
+	assert.Contains(t, executed, "ORDER BY completed_deliveries DESC, on_time_delivery_rate DESC, driver_id ASC")
+	assert.Contains(t, executed, "LIMIT 3")
+	require.Len(t, args, 4)
+	assert.Equal(t, domain.DeliveryStatusDelivered, args[1].Value)
+
+	assert.Equal(t, []domain.DriverStats{
+		{Rank: 1, DriverID: "DRIVER-B", CompletedDeliveries: 12, OnTimeDeliveryRate: 75},
+		{Rank: 2, DriverID: "DRIVER-A", CompletedDeliveries: 9, OnTimeDeliveryRate: 100},
+		{Rank: 3, DriverID: "DRIVER-C", CompletedDeliveries: 9, OnTimeDeliveryRate: 100},
+	}, leaderboard)
+}
+
+func TestGetDriverLeaderboard_OnTimeGracePeriod(t *testing.T) {
+	eta := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	grace := 5 * time.Minute
+	delivered := []struct {
+		estimated time.Time
+		actual    time.Time
+	}{
+		{eta, eta.Add(-10 * time.Minute)},   // early
+		{eta, eta.Add(grace)},               // exactly at the end of the grace period
+		{eta, eta.Add(grace + time.Second)}, // just past it
+		{eta, eta.Add(20 * time.Minute)},    // late
+	}
+
+	tests := []struct {
+		name     string
+		grace    time.Duration
+		expected float64
+	}{
+		{name: "no grace", grace: 0, expected: 25},
+		{name: "five minute grace", grace: grace, expected: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+				// Evaluate the on-time condition with the bound grace as Postgres would
+				assert.Contains(t, query, "estimated_delivery_time + make_interval(secs => $1)")
+				grace := time.Duration(args[0].Value.(float64) * float64(time.Second))
+				var onTime int
+				for _, d := range delivered {
+					if !d.actual.After(d.estimated.Add(grace)) {
+						onTime++
+					}
+				}
+				rate := float64(onTime) * 100.0 / float64(len(delivered))
+				return fakeResult{
+					columns: []string{"driver_id", "completed_deliveries", "on_time_delivery_rate"},
+					rows:    [][]driver.Value{{"DRIVER-A", int64(len(delivered)), rate}},
+				}, nil
+			})
+			repo := NewRepository(db)
+
+			leaderboard, err := repo.GetDriverLeaderboard(context.Background(), eta.Add(-24*time.Hour), eta, 10, tt.grace)
+
+			require.NoError(t, err)
+			require.Len(t, leaderboard, 1)
+			assert.InDelta(t, tt.expected, leaderboard[0].OnTimeDeliveryRate, 0.001)
+		})
+	}
+}
+
+func TestListActiveDriverIDs(t *testing.T) {
+	seeded := []struct {
+		driverID string // empty means unassigned (NULL)
+		status   domain.DeliveryStatus
+	}{
+		{"DRIVER-C", domain.DeliveryStatusInTransit},
+		{"DRIVER-A", domain.DeliveryStatusAssigned},
+		{"DRIVER-C", domain.DeliveryStatusPickedUp},
+		{"DRIVER-B", domain.DeliveryStatusDelivered},
+		{"DRIVER-D", domain.DeliveryStatusCancelled},
+		{"DRIVER-E", domain.DeliveryStatusDeadLetter},
+		{"", domain.DeliveryStatusPending},
+	}
+
+	var executed string
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		// Evaluate the status filter against the seeded rows as Postgres would
+		active := map[domain.DeliveryStatus]bool{}
+		for _, arg := range args {
+			if s, ok := arg.Value.(domain.DeliveryStatus); ok {
+				active[s] = true
+			}
+		}
+		distinct := map[string]bool{}
+		for _, row := range seeded {
+			if row.driverID != "" && active[row.status] {
+				distinct[row.driverID] = true
+			}
+		}
+		ids := make([]string, 0, len(distinct))
+		for id := range distinct {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		result := fakeResult{columns: []string{"driver_id"}}
+		for _, id := range ids {
+			result.rows = append(result.rows, []driver.Value{id})
+		}
+		return result, nil
+	})
+	repo := NewRepository(db)
+
+	driverIDs, err := repo.ListActiveDriverIDs(context.Background())
+
+	require.NoError(t, err)
+	assert.Contains(t, executed, "DISTINCT")
+	assert.Contains(t, executed, "driver_id IS NOT NULL")
+	assert.Contains(t, executed, "ORDER BY driver_id ASC")
+	assert.Equal(t, []string{"DRIVER-A", "DRIVER-C"}, driverIDs)
+}
+
+func TestListServedCities(t *testing.T) {
+	// Distinct trimmed non-blank cities per address column, as Postgres would return them
+	distinctCities := map[string][]string{
+		"pickup_address":   {"Beirut", "Tripoli"},
+		"delivery_address": {"Byblos", "Beirut", "Sidon", "Tripoli"},
+	}
+
+	var statements []string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		statements = append(statements, query)
+		for column, cities := range distinctCities {
+			if strings.Contains(query, column) {
+				result := fakeResult{columns: []string{"city"}}
+				for _, city := range cities {
+					result.rows = append(result.rows, []driver.Value{city})
+				}
+				return result, nil
+			}
+		}
+		return fakeResult{}, errors.New("unexpected query: " + query)
+	})
+	repo := NewRepository(db)
+
+	cities, err := repo.ListServedCities(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	for _, stmt := range statements {
+		assert.Contains(t, stmt, "SELECT DISTINCT btrim(")
+		assert.Contains(t, stmt, "->>'city') <> ''")
+		assert.Contains(t, stmt, `"deleted_at" IS NULL`)
+	}
+	assert.Equal(t, []string{"Beirut", "Byblos", "Sidon", "Tripoli"}, cities)
+}
+
+func TestListServedCities_NoDeliveries(t *testing.T) {
+	db := openFakeDB(t, func(string, []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{columns: []string{"city"}}, nil
+	})
+	repo := NewRepository(db)
+
+	cities, err := repo.ListServedCities(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, cities)
+}
+
+func TestDeleteByOrderID(t *testing.T) {
+	var (
+		statements []string
+		args       []driver.NamedValue
+	)
+	firstID, secondID := uuid.New(), uuid.New()
+	db := openFakeDB(t, func(query string, queryArgs []driver.NamedValue) (fakeResult, error) {
+		statements, args = append(statements, query), queryArgs
+		return fakeResult{
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{firstID.String()}, {secondID.String()}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	deleted, err := repo.DeleteByOrderID(context.Background(), "ORDER-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{firstID, secondID}, deleted)
+	require.Len(t, statements, 1)
+	// Soft delete: a single UPDATE setting deleted_at, returning the IDs it deleted
+	assert.True(t, strings.HasPrefix(statements[0], "UPDATE"), statements[0])
+	assert.Contains(t, statements[0], "deleted_at")
+	assert.Contains(t, statements[0], `RETURNING "id"`)
+
+	// Picked-up deliveries are excluded by the statement itself, so one picked up concurrently
+	// is never deleted
+	assert.Contains(t, statements[0], "status NOT IN")
+	var excluded []domain.DeliveryStatus
+	for _, arg := range args {
+		if status, ok := arg.Value.(domain.DeliveryStatus); ok {
+			excluded = append(excluded, status)
+		}
+	}
+	for _, status := range domain.PickedUpStatuses() {
+		assert.Contains(t, excluded, status)
+	}
+	assert.NotContains(t, excluded, domain.DeliveryStatusPending)
+	assert.NotContains(t, excluded, domain.DeliveryStatusAssigned)
+}
+
+func TestDeleteMatching(t *testing.T) {
+	var statements []string
+	var values []interface{}
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		statements = append(statements, query)
+		for _, arg := range args {
+			values = append(values, arg.Value)
+		}
+		return fakeResult{
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{uuid.NewString()}, {uuid.NewString()}, {uuid.NewString()}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	cancelled := domain.DeliveryStatusCancelled
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleted, err := repo.DeleteMatching(context.Background(), service.ListFilters{
+		Status:        &cancelled,
+		CreatedBefore: &cutoff,
+	}, 500)
+
+	require.NoError(t, err)
+	assert.Len(t, deleted, 3)
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], `RETURNING "id"`)
+	// One soft-delete UPDATE limited to a batch of the oldest matching rows
+	assert.True(t, strings.HasPrefix(statements[0], "UPDATE"), statements[0])
+	assert.Contains(t, statements[0], "id IN (SELECT")
+	assert.Contains(t, statements[0], "created_at <")
+	assert.Contains(t, statements[0], "ORDER BY created_at LIMIT 500")
+
+	// Picked-up deliveries are left out of the batch whatever the filters
+	assert.Contains(t, statements[0], "status NOT IN")
+	for _, status := range domain.PickedUpStatuses() {
+		assert.Contains(t, values, status)
+	}
+	assert.Contains(t, values, domain.DeliveryStatusInTransit)
+}
+
+// relatedRowsDB is a fake database holding deliveries and their audit log rows. It evaluates the
+// statements soft deleting them as Postgres would and records every statement.
+type relatedRowsDB struct {
+	deliveries map[uuid.UUID]*relatedDelivery
+	auditLog   map[uuid.UUID]bool // Delivery ID -> audit rows soft deleted
+	statements []string
+}
+
+type relatedDelivery struct {
+	orderID string
+	status  domain.DeliveryStatus
+	deleted bool
+}
+
+func (f *relatedRowsDB) handle(query string, args []driver.NamedValue) (fakeResult, error) {
+	f.statements = append(f.statements, query)
+
+	var (
+		ids      []uuid.UUID
+		orderID  string
+		excluded []domain.DeliveryStatus
+	)
+	for _, arg := range args {
+		switch v := arg.Value.(type) {
+		case uuid.UUID:
+			ids = append(ids, v)
+		case string:
+			orderID = v
+		case domain.DeliveryStatus:
+			excluded = append(excluded, v)
+		}
+	}
+	selects := func(id uuid.UUID, d *relatedDelivery) bool {
+		if d.deleted {
+			return false
+		}
+		if orderID != "" {
+			return d.orderID == orderID && !slices.Contains(excluded, d.status)
+		}
+		return slices.Contains(ids, id)
+	}
+
+	var affected [][]driver.Value
+	switch {
+	case strings.HasPrefix(query, "SELECT") && strings.Contains(query, "FOR UPDATE"):
+		result := fakeResult{columns: []string{"id"}}
+		for id, d := range f.deliveries {
+			if selects(id, d) {
+				result.rows = append(result.rows, []driver.Value{id.String()})
+			}
+		}
+		return result, nil
+	case strings.HasPrefix(query, `UPDATE "delivery_assignments"`):
+		result := fakeResult{columns: []string{"id"}}
+		for id, d := range f.deliveries {
+			if selects(id, d) {
+				d.deleted = true
+				result.rows = append(result.rows, []driver.Value{id.String()})
+			}
+		}
+		return result, nil
+	case strings.HasPrefix(query, `UPDATE "delivery_audit_log"`):
+		for _, id := range ids {
+			if deleted, ok := f.auditLog[id]; ok && !deleted {
+				f.auditLog[id] = true
+				affected = append(affected, nil)
+			}
+		}
+	}
+	return fakeResult{rows: affected}, nil
+}
+
+func TestDelete_RelatedRowsPolicy(t *testing.T) {
+	deliveryID, otherID := uuid.New(), uuid.New()
+	seed := func() *relatedRowsDB {
+		return &relatedRowsDB{
+			deliveries: map[uuid.UUID]*relatedDelivery{
+				deliveryID: {orderID: "ORDER-1", status: domain.DeliveryStatusPending},
+				otherID:    {orderID: "ORDER-2", status: domain.DeliveryStatusPending},
+			},
+			auditLog: map[uuid.UUID]bool{deliveryID: false, otherID: false},
+		}
+	}
+
+	t.Run("audit log retained by default", func(t *testing.T) {
+		fake := seed()
+		repo := NewRepository(openFakeDB(t, fake.handle))
+
+		require.NoError(t, repo.Delete(context.Background(), deliveryID))
+
+		assert.True(t, fake.deliveries[deliveryID].deleted)
+		assert.False(t, fake.auditLog[deliveryID], "the audit trail outlives the delivery")
+		require.Len(t, fake.statements, 1)
+		assert.NotContains(t, fake.statements[0], "delivery_audit_log")
+	})
+
+	t.Run("audit log cascaded in the same transaction", func(t *testing.T) {
+		fake := seed()
+		repo := NewRepositoryWithConfig(openFakeDB(t, fake.handle), Config{CascadeAuditLog: true})
+
+		require.NoError(t, repo.Delete(context.Background(), deliveryID))
+
+		assert.True(t, fake.deliveries[deliveryID].deleted)
+		assert.True(t, fake.auditLog[deliveryID])
+		assert.False(t, fake.deliveries[otherID].deleted)
+		assert.False(t, fake.auditLog[otherID])
+
+		// The deliveries are locked and selected first, so the audit rows deleted are theirs
+		require.Len(t, fake.statements, 3)
+		assert.Contains(t, fake.statements[0], "FOR UPDATE")
+		assert.True(t, strings.HasPrefix(fake.statements[1], `UPDATE "delivery_assignments"`), fake.statements[1])
+		assert.True(t, strings.HasPrefix(fake.statements[2], `UPDATE "delivery_audit_log"`), fake.statements[2])
+		assert.Contains(t, fake.statements[2], "deleted_at")
+	})
+
+	t.Run("cascade by order skips picked-up deliveries", func(t *testing.T) {
+		fake := seed()
+		pickedUpID := uuid.New()
+		fake.deliveries[pickedUpID] = &relatedDelivery{orderID: "ORDER-1", status: domain.DeliveryStatusPickedUp}
+		fake.auditLog[pickedUpID] = false
+		repo := NewRepositoryWithConfig(openFakeDB(t, fake.handle), Config{CascadeAuditLog: true})
+
+		deleted, err := repo.DeleteByOrderID(context.Background(), "ORDER-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{deliveryID}, deleted)
+		assert.True(t, fake.auditLog[deliveryID])
+		assert.False(t, fake.deliveries[pickedUpID].deleted)
+		assert.False(t, fake.auditLog[pickedUpID])
+	})
+
+	t.Run("cascade of a missing delivery is not found", func(t *testing.T) {
+		fake := seed()
+		repo := NewRepositoryWithConfig(openFakeDB(t, fake.handle), Config{CascadeAuditLog: true})
+
+		assert.ErrorIs(t, repo.Delete(context.Background(), uuid.New()), domain.ErrNotFound)
+		assert.Len(t, fake.statements, 1, "nothing is deleted")
+	})
+}
+
+func TestCreate_MergesServerFields(t *testing.T) {
+	dbID := uuid.New()
+	var executed string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		return fakeResult{
+			columns: []string{"id", "timeline_notes"},
+			rows:    [][]driver.Value{{dbID.String(), []byte("[]")}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	now := time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+	assignment, err := domain.NewDeliveryAssignmentWithClock(
+		fixedClock(now),
+		"ORDER-123",
+		domain.Address{City: "New York"},
+		domain.Address{City: "Boston"},
+		now.Add(2*time.Hour),
+		now.Add(4*time.Hour),
+		"Handle with care",
+	)
+	require.NoError(t, err)
+
+	err = repo.Create(context.Background(), assignment)
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(executed, "INSERT"))
+	assert.Equal(t, dbID, assignment.ID)
+	assert.Equal(t, now, assignment.CreatedAt)
+	assert.Equal(t, "ORDER-123", assignment.OrderID)
+	assert.Equal(t, "Boston", assignment.DeliveryAddress.City)
+	assert.Equal(t, "Handle with care", assignment.Notes)
+
+	// The injected clock survives the round trip
+	require.NoError(t, assignment.AssignDriver("DRIVER-123"))
+	assert.Equal(t, now, assignment.UpdatedAt)
+}
+
+func TestCreate_SecondActiveDeliveryForOrder(t *testing.T) {
+	// Emulates uq_delivery_assignments_active_order: the first active delivery for an order is
+	// accepted and any further one is rejected by PostgreSQL
+	active := map[string]bool{}
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		for _, arg := range args {
+			orderID, ok := arg.Value.(string)
+			if !ok || !strings.HasPrefix(orderID, "ORDER-") {
+				continue
+			}
+			if active[orderID] {
+				return fakeResult{}, &pgconn.PgError{
+					Code:           "23505",
+					Message:        "duplicate key value violates unique constraint",
+					ConstraintName: "uq_delivery_assignments_active_order",
+				}
+			}
+			active[orderID] = true
+		}
+		return fakeResult{
+			columns: []string{"id", "timeline_notes"},
+			rows:    [][]driver.Value{{uuid.New().String(), []byte("[]")}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	newAssignment := func() *domain.DeliveryAssignment {
+		assignment, err := domain.NewDeliveryAssignment("ORDER-123", domain.Address{}, domain.Address{},
+			time.Now().Add(time.Hour), time.Now().Add(2*time.Hour), "")
+		require.NoError(t, err)
+		return assignment
+	}
+
+	require.NoError(t, repo.Create(context.Background(), newAssignment()))
+
+	err := repo.Create(context.Background(), newAssignment())
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+func TestCreate_OtherUniqueViolationNotTranslated(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23505", ConstraintName: "delivery_assignments_pkey"}
+	db := openFakeDB(t, func(string, []driver.NamedValue) (fakeResult, error) {
+		return fakeResult{}, pgErr
+	})
+	repo := NewRepository(db)
+
+	assignment, err := domain.NewDeliveryAssignment("ORDER-123",
+		domain.Address{}, domain.Address{}, time.Now(), time.Now().Add(time.Hour), "")
+	require.NoError(t, err)
+
+	err = repo.Create(context.Background(), assignment)
+
+	assert.ErrorIs(t, err, pgErr)
+	assert.NotErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+// fixedClock always returns the same instant
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestGetMetrics_AverageLifecycleDuration(t *testing.T) {
+	var lifecycleQuery string
+	var lifecycleArgs []driver.NamedValue
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		switch {
+		case strings.Contains(query, "avg_lifecycle_minutes"):
+			lifecycleQuery, lifecycleArgs = query, args
+			return fakeResult{columns: []string{"avg_lifecycle_minutes"}, rows: [][]driver.Value{{92.5}}}, nil
+		case strings.Contains(query, "count(*)"):
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(4)}}}, nil
+		default:
+			return fakeResult{}, nil
+		}
+	})
+	repo := NewRepository(db)
+
+	driverID := "DRIVER-1"
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	metrics, err := repo.GetMetrics(context.Background(), start, start.Add(24*time.Hour), &driverID, 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, metrics.Errors)
+	assert.Equal(t, 92.5, metrics.AverageLifecycleDurationMinutes)
+
+	assert.Contains(t, lifecycleQuery, "actual_delivery_time - created_at")
+	values := make([]interface{}, 0, len(lifecycleArgs))
+	for _, arg := range lifecycleArgs {
+		values = append(values, arg.Value)
+	}
+	assert.Contains(t, values, domain.DeliveryStatusDelivered, "only delivered assignments are averaged")
+	assert.Contains(t, values, driverID, "the driver filter applies")
+}
+
+func TestSessionActor(t *testing.T) {
+	actor := domain.Actor{UserID: "user-42", TenantID: "tenant-7"}
+
+	// record opens a fake database that records every statement and reports one affected row
+	record := func(t *testing.T) (*repository, *[]string, *[][]driver.NamedValue) {
+		var queries []string
+		var args [][]driver.NamedValue
+		db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+			queries = append(queries, query)
+			args = append(args, a)
+			return fakeResult{rows: [][]driver.Value{{}}}, nil
+		})
+		return newRepository(db, Config{}), &queries, &args
+	}
+
+	t.Run("set at the start of a transaction", func(t *testing.T) {
+		repo, queries, args := record(t)
+		ctx := domain.ContextWithActor(context.Background(), actor)
+
+		err := repo.WithTransaction(ctx, func(txRepo service.DeliveryRepository) error {
+			return txRepo.Delete(ctx, uuid.New())
+		})
+
+		require.NoError(t, err)
+		require.Len(t, *queries, 2, "the actor is set once per transaction")
+		assert.Contains(t, (*queries)[0], "set_config('app.current_user', $1, true)")
+		assert.Contains(t, (*queries)[0], "set_config('app.tenant_id', $2, true)")
+		assert.Equal(t, "user-42", (*args)[0][0].Value)
+		assert.Equal(t, "tenant-7", (*args)[0][1].Value)
+		assert.Contains(t, (*queries)[1], "UPDATE")
+	})
+
+	t.Run("single statement wrapped in a transaction", func(t *testing.T) {
+		repo, queries, _ := record(t)
+		ctx := domain.ContextWithActor(context.Background(), actor)
+
+		require.NoError(t, repo.Delete(ctx, uuid.New()))
+
+		require.Len(t, *queries, 2)
+		assert.Contains(t, (*queries)[0], "set_config")
+		assert.Contains(t, (*queries)[1], "UPDATE")
+	})
+
+	t.Run("not set without an actor", func(t *testing.T) {
+		repo, queries, _ := record(t)
+
+		require.NoError(t, repo.Delete(context.Background(), uuid.New()))
+
+		require.Len(t, *queries, 1)
+		assert.NotContains(t, (*queries)[0], "set_config")
+	})
+}
+
+func TestWithTransaction_Timeout(t *testing.T) {
+	var queries []string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		queries = append(queries, query)
+		return fakeResult{rows: [][]driver.Value{{}}}, nil
+	})
+	repo := newRepository(db, Config{TransactionTimeout: 20 * time.Millisecond})
+
+	ctx := context.Background()
+	var stmtErr error
+	err := repo.WithTransaction(ctx, func(txRepo service.DeliveryRepository) error {
+		time.Sleep(100 * time.Millisecond) // Deliberately outlive the timeout
+		stmtErr = txRepo.Delete(ctx, uuid.New())
+		return stmtErr
+	})
+
+	require.ErrorIs(t, err, domain.ErrTimeout)
+	// The transaction was rolled back at the deadline, so the late statement never ran
+	assert.ErrorIs(t, stmtErr, sql.ErrTxDone)
+	assert.Empty(t, queries)
+
+	// Transactions within the limit commit as usual
+	err = repo.WithTransaction(ctx, func(txRepo service.DeliveryRepository) error {
+		return txRepo.Delete(ctx, uuid.New())
+	})
+	require.NoError(t, err)
+	assert.Len(t, queries, 1)
+}
+
+func TestList_RequiredVehicleTypeFilter(t *testing.T) {
+	var queries []string
+	var args [][]driver.NamedValue
+	db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+		queries = append(queries, query)
+		args = append(args, a)
+		if strings.Contains(query, "count(*)") {
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}, nil
+		}
+		return fakeResult{}, nil
+	})
+	repo := NewRepository(db)
+
+	vehicleType := "REFRIGERATED"
+	_, _, err := repo.List(context.Background(), service.ListFilters{Page: 1, PageSize: 20, RequiredVehicleType: &vehicleType})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, queries)
+	for i, query := range queries {
+		assert.Contains(t, query, "required_vehicle_type = $1")
+		assert.Equal(t, "REFRIGERATED", args[i][0].Value)
+	}
+}
+
+func TestList_HasDriverFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasDriver bool
+		condition string
+	}{
+		{name: "with driver", hasDriver: true, condition: "driver_id IS NOT NULL"},
+		{name: "without driver", hasDriver: false, condition: "driver_id IS NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			var args [][]driver.NamedValue
+			db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+				queries = append(queries, query)
+				args = append(args, a)
+				if strings.Contains(query, "count(*)") {
+					return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}, nil
+				}
+				return fakeResult{}, nil
+			})
+			repo := NewRepository(db)
+
+			// Combined with the other filters by AND
+			vehicleType := "VAN"
+			_, _, err := repo.List(context.Background(), service.ListFilters{
+				Page:                1,
+				PageSize:            20,
+				HasDriver:           &tt.hasDriver,
+				RequiredVehicleType: &vehicleType,
+			})
+			require.NoError(t, err)
+
+			require.NotEmpty(t, queries)
+			for i, query := range queries {
+				assert.Contains(t, query, tt.condition+" AND required_vehicle_type = $1")
+				assert.Equal(t, "VAN", args[i][0].Value)
+			}
+		})
+	}
+}
+
+func TestGetMetrics_OnTimeGracePeriod(t *testing.T) {
+	eta := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	delivered := []struct {
+		estimated time.Time
+		actual    time.Time
+	}{
+		{eta, eta.Add(-10 * time.Minute)}, // early
+		{eta, eta.Add(2 * time.Minute)},   // slightly late
+		{eta, eta.Add(20 * time.Minute)},  // late
+	}
+
+	tests := []struct {
+		name     string
+		grace    time.Duration
+		expected float64
+	}{
+		{name: "no grace", grace: 0, expected: 100.0 / 3},
+		{name: "five minute grace", grace: 5 * time.Minute, expected: 200.0 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+				switch {
+				case strings.Contains(query, "as on_time"):
+					// Evaluate the on-time condition with the bound grace as Postgres would
+					assert.Contains(t, query, "estimated_delivery_time + make_interval(secs => $1)")
+					grace := time.Duration(args[0].Value.(float64) * float64(time.Second))
+					var onTime int64
+					for _, d := range delivered {
+						if !d.actual.After(d.estimated.Add(grace)) {
+							onTime++
+						}
+					}
+					return fakeResult{columns: []string{"on_time", "total"}, rows: [][]driver.Value{{onTime, int64(len(delivered))}}}, nil
+				case strings.Contains(query, "count(*)"):
+					return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(len(delivered))}}}, nil
+				default:
+					return fakeResult{}, nil
+				}
+			})
+			repo := NewRepository(db)
+
+			metrics, err := repo.GetMetrics(context.Background(), eta.Add(-24*time.Hour), eta, nil, tt.grace)
+
+			require.NoError(t, err)
+			assert.Empty(t, metrics.Errors)
+			assert.InDelta(t, tt.expected, metrics.OnTimeDeliveryRate, 0.001)
+		})
+	}
+}
+
+// modifiedRow is a delivery row as seen by the change feed
+type modifiedRow struct {
+	id        uuid.UUID
+	updatedAt time.Time
+	deletedAt *time.Time
+}
+
+// changeFeedDB evaluates the change feed query against rows as Postgres would: the
+// (modified_at, id) row comparison, the ordering and the LIMIT
+func changeFeedDB(t *testing.T, rows []modifiedRow, executed *string) *gorm.DB {
+	t.Helper()
+
+	return openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		*executed = query
+		afterTime := args[0].Value.(time.Time)
+		afterID := args[1].Value.(uuid.UUID)
+		limit := len(rows)
+		if _, after, ok := strings.Cut(query, "LIMIT "); ok {
+			limit, _ = strconv.Atoi(after)
+		}
+
+		modifiedAt := func(row modifiedRow) time.Time {
+			if row.deletedAt != nil && row.deletedAt.After(row.updatedAt) {
+				return *row.deletedAt
+			}
+			return row.updatedAt
+		}
+		var matched []modifiedRow
+		for _, row := range rows {
+			modified := modifiedAt(row)
+			if modified.After(afterTime) || modified.Equal(afterTime) && bytes.Compare(row.id[:], afterID[:]) > 0 {
+				matched = append(matched, row)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			if mi, mj := modifiedAt(matched[i]), modifiedAt(matched[j]); !mi.Equal(mj) {
+				return mi.Before(mj)
+			}
+			return bytes.Compare(matched[i].id[:], matched[j].id[:]) < 0
+		})
+
+		result := fakeResult{columns: []string{"id", "updated_at", "deleted_at"}}
+		for _, row := range matched[:min(limit, len(matched))] {
+			var deleted driver.Value
+			if row.deletedAt != nil {
+				deleted = *row.deletedAt
+			}
+			result.rows = append(result.rows, []driver.Value{row.id.String(), row.updatedAt, deleted})
+		}
+		return result, nil
+	})
+}
+
+func TestListModifiedSince(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recentID, oldID, deletedID, boundaryID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	deletedAt := since.Add(time.Hour)
+	seeded := []modifiedRow{
+		{recentID, since.Add(time.Minute), nil},
+		{oldID, since.Add(-time.Hour), nil},
+		{deletedID, since.Add(-time.Hour), &deletedAt}, // Soft delete leaves updated_at untouched
+		{boundaryID, since, nil},                       // Already seen by a client that synced up to since
+	}
+
+	var executed string
+	repo := NewRepository(changeFeedDB(t, seeded, &executed))
+
+	assignments, err := repo.ListModifiedSince(context.Background(), domain.SyncCursorSince(since), 10)
+
+	require.NoError(t, err)
+	assert.NotContains(t, executed, "deleted_at IS NULL", "soft-deleted rows must be included")
+	assert.Contains(t, executed, "LIMIT 10")
+	require.Len(t, assignments, 2)
+	assert.Equal(t, recentID, assignments[0].ID)
+	assert.Nil(t, assignments[0].DeletedAt)
+	assert.Equal(t, deletedID, assignments[1].ID)
+	require.NotNil(t, assignments[1].DeletedAt)
+	assert.True(t, deletedAt.Equal(*assignments[1].DeletedAt))
+}
+
+func TestListModifiedSince_TiesAcrossBatches(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	boundary := since.Add(time.Minute)
+
+	// More rows share the boundary time than fit in one batch
+	var seeded []modifiedRow
+	for range 5 {
+		seeded = append(seeded, modifiedRow{id: uuid.New(), updatedAt: boundary})
+	}
+	seeded = append(seeded, modifiedRow{id: uuid.New(), updatedAt: boundary.Add(time.Second)})
+
+	var executed string
+	repo := NewRepository(changeFeedDB(t, seeded, &executed))
+
+	seen := make(map[uuid.UUID]int)
+	after := domain.SyncCursorSince(since)
+	for batches := 0; ; batches++ {
+		require.Less(t, batches, 10, "the feed must make progress")
+
+		assignments, err := repo.ListModifiedSince(context.Background(), after, 2)
+		require.NoError(t, err)
+		if len(assignments) == 0 {
+			break
+		}
+		for _, assignment := range assignments {
+			seen[assignment.ID]++
+		}
+		last := assignments[len(assignments)-1]
+		after = domain.SyncCursor{ModifiedAt: last.ModifiedAt(), ID: last.ID}
+	}
+
+	assert.Contains(t, executed, "id) > (")
+	require.Len(t, seen, len(seeded), "no row sharing the boundary time is skipped")
+	for id, count := range seen {
+		assert.Equal(t, 1, count, "row %s returned more than once", id)
+	}
+}
+
+// streamDB serves the rows of ids, sorted, as the primary-key batches of FindInBatches and counts the
+// queries it receives
+func streamDB(t *testing.T, ids []uuid.UUID, queries *int) *gorm.DB {
+	t.Helper()
+
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	return openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		*queries++
+		var after *uuid.UUID
+		for _, arg := range args {
+			if id, ok := arg.Value.(uuid.UUID); ok {
+				after = &id
+			}
+		}
+		limit := len(sorted)
+		if _, rest, ok := strings.Cut(query, "LIMIT "); ok {
+			limit, _ = strconv.Atoi(rest)
+		}
+
+		result := fakeResult{columns: []string{"id", "order_id"}}
+		for _, id := range sorted {
+			if len(result.rows) == limit {
+				break
+			}
+			if after == nil || bytes.Compare(id[:], after[:]) > 0 {
+				result.rows = append(result.rows, []driver.Value{id.String(), "ORDER-" + id.String()[:8]})
+			}
+		}
+		return result, nil
+	})
+}
+
+func TestStreamAll(t *testing.T) {
+	// Enough rows for a full batch and a partial one
+	ids := make([]uuid.UUID, constants.ExportBatchSize+2)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	t.Run("visits every row in primary key order", func(t *testing.T) {
+		var queries int
+		repo := NewRepository(streamDB(t, ids, &queries))
+
+		var visited []uuid.UUID
+		err := repo.StreamAll(context.Background(), func(a *domain.DeliveryAssignment) error {
+			assert.Equal(t, "ORDER-"+a.ID.String()[:8], a.OrderID)
+			visited = append(visited, a.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, queries, "rows are fetched in batches")
+		assert.ElementsMatch(t, ids, visited)
+		assert.True(t, slices.IsSortedFunc(visited, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) }))
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		var queries int
+		repo := NewRepository(streamDB(t, ids, &queries))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		visited := 0
+		err := repo.StreamAll(ctx, func(*domain.DeliveryAssignment) error {
+			visited++
+			if visited == 3 {
+				cancel()
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 3, visited, "no row is visited after cancellation")
+		assert.Equal(t, 1, queries, "no further batch is fetched")
+	})
+
+	t.Run("stops at the first error from fn", func(t *testing.T) {
+		var queries int
+		repo := NewRepository(streamDB(t, ids, &queries))
+		errStop := errors.New("stop")
+
+		visited := 0
+		err := repo.StreamAll(context.Background(), func(*domain.DeliveryAssignment) error {
+			visited++
+			return errStop
+		})
+
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, visited)
+		assert.Equal(t, 1, queries)
+	})
+}
+
+func TestList_TagFilter(t *testing.T) {
+	var queries []string
+	var args [][]driver.NamedValue
+	db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+		queries = append(queries, query)
+		args = append(args, a)
+		if strings.Contains(query, "count(*)") {
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}, nil
+		}
+		return fakeResult{}, nil
+	})
+	repo := NewRepository(db)
+
+	tag := "vip"
+	_, _, err := repo.List(context.Background(), service.ListFilters{Page: 1, PageSize: 20, Tag: &tag})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, queries)
+	for i, query := range queries {
+		assert.Contains(t, query, "tags @> $1::jsonb")
+		assert.Equal(t, `["vip"]`, args[i][0].Value)
+	}
+}
+
+func TestGetMetricsForDrivers(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	var executed []string
+	var driverArgs []string
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		executed = append(executed, query)
+		if strings.Contains(query, "failure_reason_code") {
+			return fakeResult{
+				columns: []string{"driver_id", "failure_reason_code", "count"},
+				rows: [][]driver.Value{
+					{"DRIVER-A", "WRONG_ADDRESS", int64(1)},
+					{"DRIVER-B", "REFUSED", int64(2)},
+				},
+			}, nil
+		}
+		for _, arg := range args {
+			if s, ok := arg.Value.(string); ok && strings.HasPrefix(s, "DRIVER-") {
+				driverArgs = append(driverArgs, s)
+			}
+		}
+		// DRIVER-C has no deliveries in the range, so the grouped query returns no row for it
+		return fakeResult{
+			columns: []string{
+				"driver_id", "total_deliveries", "completed_deliveries", "failed_deliveries",
+				"cancelled_deliveries", "dead_letter_deliveries", "average_delivery_time_minutes",
+				"average_lifecycle_duration_minutes", "on_time_delivery_rate", "average_rating",
+			},
+			rows: [][]driver.Value{
+				{"DRIVER-A", int64(10), int64(8), int64(1), int64(1), int64(0), 22.5, 95.0, 75.0, 4.5},
+				{"DRIVER-B", int64(3), int64(3), int64(0), int64(0), int64(0), 18.0, 60.0, 100.0, 5.0},
+			},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	metrics, err := repo.GetMetricsForDrivers(context.Background(), []string{"DRIVER-A", "DRIVER-B", "DRIVER-C"}, start, end, 5*time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, executed, 2, "all drivers are computed together, not one query each")
+	assert.Contains(t, executed[0], "driver_id IN (")
+	assert.Contains(t, executed[0], "GROUP BY")
+	assert.Contains(t, executed[1], "driver_id IN (")
+	assert.Contains(t, executed[1], "GROUP BY driver_id, failure_reason_code")
+	assert.Equal(t, []string{"DRIVER-A", "DRIVER-B", "DRIVER-C"}, driverArgs)
+
+	require.Len(t, metrics, 3)
+	assert.Equal(t, &domain.DeliveryMetrics{
+		TotalDeliveries:                 10,
+		CompletedDeliveries:             8,
+		FailedDeliveries:                1,
+		CancelledDeliveries:             1,
+		AverageDeliveryTimeMinutes:      22.5,
+		AverageLifecycleDurationMinutes: 95,
+		OnTimeDeliveryRate:              75,
+		AverageRating:                   4.5,
+		FailuresByReason:                map[string]int32{"WRONG_ADDRESS": 1},
+	}, metrics["DRIVER-A"])
+	assert.Equal(t, int32(3), metrics["DRIVER-B"].CompletedDeliveries)
+	assert.Equal(t, map[string]int32{"REFUSED": 2}, metrics["DRIVER-B"].FailuresByReason)
+	assert.Equal(t, &domain.DeliveryMetrics{}, metrics["DRIVER-C"], "requested drivers without deliveries get zero metrics")
+}
+
+// aggregateColumns are the columns of the metrics aggregate query and summary rows
+var aggregateColumns = []string{
+	"total_deliveries", "completed_deliveries", "failed_deliveries", "cancelled_deliveries",
+	"dead_letter_deliveries", "delivery_time_sum_minutes", "delivery_time_count", "lifecycle_sum_minutes",
+	"delivered_count", "on_time_count", "rating_sum", "rating_count",
+}
+
+func TestRefreshMetricsSummary(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var aggregateArgs, upsertArgs []driver.NamedValue
+	var upsert string
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		switch {
+		case strings.Contains(query, "GROUP BY \"failure_reason_code\""):
+			return fakeResult{
+				columns: []string{"failure_reason_code", "count"},
+				rows:    [][]driver.Value{{"WRONG_ADDRESS", int64(1)}},
+			}, nil
+		case strings.Contains(query, "FROM \"delivery_assignments\""):
+			aggregateArgs = args
+			return fakeResult{
+				columns: aggregateColumns,
+				rows:    [][]driver.Value{{int64(10), int64(8), int64(1), int64(1), int64(0), 200.0, int64(8), 720.0, int64(8), int64(6), int64(36), int64(8)}},
+			}, nil
+		case strings.Contains(query, "INSERT INTO \"delivery_metrics_daily\""):
+			upsert, upsertArgs = query, args
+		}
+		return fakeResult{}, nil
+	})
+	refreshedAt := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	repo := NewRepositoryWithConfig(db, Config{Clock: fixedClock(refreshedAt)})
+
+	// Any time of the day refreshes the whole UTC day
+	err := repo.RefreshMetricsSummary(context.Background(), day.Add(15*time.Hour), 5*time.Minute)
+
+	require.NoError(t, err)
+	values := make([]interface{}, 0, len(aggregateArgs))
+	for _, arg := range aggregateArgs {
+		values = append(values, arg.Value)
+	}
+	assert.Contains(t, values, day, "the aggregate starts at midnight UTC")
+	assert.Contains(t, values, day.Add(24*time.Hour))
+	assert.Contains(t, values, 300.0, "the grace period is applied to the on-time count")
+
+	assert.Contains(t, upsert, "ON CONFLICT (\"day\") DO UPDATE")
+	require.NotEmpty(t, upsertArgs)
+	assert.Equal(t, day, upsertArgs[0].Value)
+	assert.Equal(t, 300.0, upsertArgs[1].Value)
+	assert.Equal(t, int64(10), upsertArgs[2].Value)
+	assert.Equal(t, model.FailureCounts{"WRONG_ADDRESS": 1}, upsertArgs[len(upsertArgs)-1].Value,
+		"failures per reason code are summarized")
+	upsertValues := make([]interface{}, 0, len(upsertArgs))
+	for _, arg := range upsertArgs {
+		upsertValues = append(upsertValues, arg.Value)
+	}
+	assert.Contains(t, upsertValues, refreshedAt, "the refresh is stamped by the injected clock")
+}
+
+func TestGetMetrics_FromSummary(t *testing.T) {
+	// Mar 2 and Mar 3 are whole days; the morning of Mar 1 is partial
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 3, 23, 59, 59, 999999000, time.UTC)
+
+	summaryRow := func(day time.Time, total, delivered, onTime int64, lifecycleSum float64) []driver.Value {
+		return []driver.Value{day, 0.0, total, delivered, int64(0), int64(0), int64(0), 0.0, int64(0), lifecycleSum, delivered, onTime, int64(0), int64(0),
+			[]byte(`{"REFUSED":1,"DAMAGED":0}`)}
+	}
+
+	tests := []struct {
+		name        string
+		summaryRows [][]driver.Value
+		fromSummary bool
+	}{
+		{
+			name: "every whole day summarized",
+			summaryRows: [][]driver.Value{
+				summaryRow(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 4, 4, 4, 240),
+				summaryRow(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), 4, 4, 2, 480),
+			},
+			fromSummary: true,
+		},
+		{
+			name: "missing day falls back to live",
+			summaryRows: [][]driver.Value{
+				summaryRow(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 4, 4, 4, 240),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var summaryQuery string
+			var liveQueries []string
+			var partialArgs []driver.NamedValue
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+				switch {
+				case strings.Contains(query, "FROM \"delivery_metrics_daily\""):
+					summaryQuery = query
+					assert.Equal(t, "2026-03-02", args[0].Value)
+					assert.Equal(t, "2026-03-04", args[1].Value)
+					return fakeResult{
+						columns: append(append([]string{"day", "on_time_grace_seconds"}, aggregateColumns...), "failures_by_reason"),
+						rows:    tt.summaryRows,
+					}, nil
+				case strings.Contains(query, "GROUP BY \"failure_reason_code\""):
+					// Counted live only for the partial morning of Mar 1 when summarized
+					return fakeResult{
+						columns: []string{"failure_reason_code", "count"},
+						rows:    [][]driver.Value{{"DAMAGED", int64(1)}},
+					}, nil
+				case strings.Contains(query, "AS delivered_count"):
+					partialArgs = args
+					// The partial morning of Mar 1: two delivered, one of them on time
+					return fakeResult{
+						columns: aggregateColumns,
+						rows:    [][]driver.Value{{int64(2), int64(2), int64(0), int64(0), int64(0), 0.0, int64(0), 80.0, int64(2), int64(1), int64(0), int64(0)}},
+					}, nil
+				default:
+					liveQueries = append(liveQueries, query)
+					return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(99)}}}, nil
+				}
+			})
+			repo := NewRepository(db)
+
+			metrics, err := repo.GetMetrics(context.Background(), start, end, nil, 0)
+
+			require.NoError(t, err)
+			assert.Contains(t, summaryQuery, "day >= $1::date AND day < $2::date")
+			if !tt.fromSummary {
+				assert.NotEmpty(t, liveQueries)
+				assert.Equal(t, int32(99), metrics.TotalDeliveries)
+				return
+			}
+
+			assert.Empty(t, liveQueries, "summarized days are not queried live")
+			require.NotEmpty(t, partialArgs)
+			assert.Equal(t, start, partialArgs[len(partialArgs)-2].Value, "only the partial day is queried live")
+			assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), partialArgs[len(partialArgs)-1].Value)
+
+			assert.Equal(t, int32(10), metrics.TotalDeliveries)
+			assert.Equal(t, int32(10), metrics.CompletedDeliveries)
+			assert.InDelta(t, 80.0, metrics.AverageLifecycleDurationMinutes, 0.001)
+			assert.InDelta(t, 70.0, metrics.OnTimeDeliveryRate, 0.001)
+			assert.Equal(t, map[string]int32{"DAMAGED": 1, "REFUSED": 2}, metrics.FailuresByReason)
+		})
+	}
+}
+
+func TestGetMetrics_DriverFilterSkipsSummary(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	driverID := "DRIVER-1"
+
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		assert.NotContains(t, query, "delivery_metrics_daily", "the summary is fleet-wide only")
+		return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}, nil
+	})
+	repo := NewRepository(db)
+
+	_, err := repo.GetMetrics(context.Background(), start, start.Add(72*time.Hour), &driverID, 0)
+	require.NoError(t, err)
+}
+
+func TestRecordDriverLocation(t *testing.T) {
+	recordedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var insert string
+	var args []driver.NamedValue
+	db := openFakeDB(t, func(query string, a []driver.NamedValue) (fakeResult, error) {
+		insert, args = query, a
+		return fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}, nil
+	})
+	repo := NewRepository(db)
+
+	err := repo.RecordDriverLocation(context.Background(), "DRIVER-1", 40.7128, -74.006, recordedAt)
+
+	require.NoError(t, err)
+	assert.Contains(t, insert, `INSERT INTO "driver_locations"`)
+	require.GreaterOrEqual(t, len(args), 4)
+	assert.Equal(t, "DRIVER-1", args[0].Value)
+	assert.Equal(t, 40.7128, args[1].Value)
+	assert.Equal(t, -74.006, args[2].Value)
+	assert.Equal(t, recordedAt, args[3].Value)
+}
+
+func TestGetLatestDriverLocation(t *testing.T) {
+	recordedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var executed string
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		executed = query
+		if args[0].Value == "DRIVER-UNKNOWN" {
+			return fakeResult{columns: []string{"id"}}, nil
+		}
+		return fakeResult{
+			columns: []string{"id", "driver_id", "latitude", "longitude", "recorded_at"},
+			rows:    [][]driver.Value{{int64(7), "DRIVER-1", 40.7128, -74.006, recordedAt}},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	location, err := repo.GetLatestDriverLocation(context.Background(), "DRIVER-1")
+
+	require.NoError(t, err)
+	assert.Contains(t, executed, "ORDER BY recorded_at DESC, id DESC")
+	assert.Equal(t, &domain.DriverLocation{
+		DriverID:   "DRIVER-1",
+		Latitude:   40.7128,
+		Longitude:  -74.006,
+		RecordedAt: recordedAt,
+	}, location)
+
+	_, err = repo.GetLatestDriverLocation(context.Background(), "DRIVER-UNKNOWN")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestStatusLegacySpelling(t *testing.T) {
+	t.Run("read path canonicalizes stored spelling", func(t *testing.T) {
+		id := uuid.New()
+		db := openFakeDB(t, func(string, []driver.NamedValue) (fakeResult, error) {
+			return fakeResult{
+				columns: []string{"id", "status", "updated_at"},
+				rows:    [][]driver.Value{{id.String(), "CANCELLED", time.Now()}},
+			}, nil
+		})
+
+		info, err := NewRepository(db).GetStatusByID(context.Background(), id)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeliveryStatusCancelled, info.Status)
+	})
+
+	t.Run("status filter matches both spellings", func(t *testing.T) {
+		var statusArgs []interface{}
+		db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+			if strings.Contains(query, "count(*)") {
+				statusArgs = statusArgs[:0]
+				for _, arg := range args {
+					statusArgs = append(statusArgs, arg.Value)
+				}
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}, nil
+			}
+			return fakeResult{}, nil
+		})
+
+		status := domain.DeliveryStatusCancelled
+		_, _, err := NewRepository(db).List(context.Background(), service.ListFilters{Page: 1, PageSize: 20, Status: &status})
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []interface{}{domain.DeliveryStatusCancelled, domain.DeliveryStatus("CANCELLED")}, statusArgs)
+	})
+
+	t.Run("metrics count both spellings", func(t *testing.T) {
+		db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+			switch {
+			case strings.Contains(query, "GROUP BY"):
+				return fakeResult{
+					columns: []string{"status", "count"},
+					rows:    [][]driver.Value{{"CANCELED", int64(2)}, {"CANCELLED", int64(3)}},
+				}, nil
+			case strings.Contains(query, "count(*)"):
+				return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(5)}}}, nil
+			default:
+				return fakeResult{}, nil
+			}
+		})
+		start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		driverID := "DRIVER-1"
+
+		metrics, err := NewRepository(db).GetMetrics(context.Background(), start, start.Add(time.Hour), &driverID, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), metrics.CancelledDeliveries)
+	})
+}
+
+func TestListWithStatusBreakdown(t *testing.T) {
+	var queries []string
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		queries = append(queries, query)
+		switch {
+		case strings.Contains(query, "GROUP BY"):
+			return fakeResult{
+				columns: []string{"status", "count"},
+				rows: [][]driver.Value{
+					{"PENDING", int64(3)},
+					{"CANCELED", int64(2)},
+					{"CANCELLED", int64(1)}, // Legacy spelling
+				},
+			}, nil
+		case strings.Contains(query, "count(*)"):
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(6)}}}, nil
+		default:
+			return fakeResult{}, nil
+		}
+	})
+	repo := NewRepository(db)
+
+	_, total, breakdown, err := repo.ListWithStatusBreakdown(context.Background(), service.ListFilters{Page: 1, PageSize: 20})
+
+	require.NoError(t, err)
+	assert.Equal(t, service.ListTotal{Count: 6}, total)
+	assert.Equal(t, map[domain.DeliveryStatus]int64{
+		domain.DeliveryStatusPending:   3,
+		domain.DeliveryStatusCancelled: 3,
+	}, breakdown)
+
+	// The breakdown, count and page are read in one snapshot
+	require.NotEmpty(t, queries)
+	assert.Equal(t, "BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY", queries[0])
+	assert.Len(t, queries, 4)
+}
+
+func TestList_CountTimeout(t *testing.T) {
+	const countTimeout = 20 * time.Millisecond
+
+	listWithCount := func(t *testing.T, ctx context.Context, count func() (fakeResult, error)) (service.ListTotal, []string, bool, error) {
+		t.Helper()
+		var queries []string
+		db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+			queries = append(queries, query)
+			if strings.Contains(query, "count(*)") {
+				return count()
+			}
+			return fakeResult{}, nil
+		})
+		repo := newRepository(db, Config{CountTimeout: countTimeout})
+		estimated := false
+		repo.explain = func(_ context.Context, sql string, _ []interface{}) ([]string, error) {
+			estimated = true
+			assert.Contains(t, sql, "tags @> ")
+			return []string{"Seq Scan on delivery_assignments  (cost=0.00..2041.00 rows=48213 width=712)"}, nil
+		}
+
+		tag := "vip"
+		_, total, err := repo.List(ctx, service.ListFilters{Page: 1, PageSize: 20, Tag: &tag})
+		return total, queries, estimated, err
+	}
+
+	t.Run("fast count is exact", func(t *testing.T) {
+		total, queries, _, err := listWithCount(t, context.Background(), func() (fakeResult, error) {
+			return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(12)}}}, nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, service.ListTotal{Count: 12}, total)
+		// The count is a single statement, without a transaction or SET LOCAL around it
+		require.NotEmpty(t, queries)
+		assert.Contains(t, queries[0], "count(*)")
+	})
+
+	t.Run("slow count falls back to the estimate", func(t *testing.T) {
+		total, _, estimated, err := listWithCount(t, context.Background(), func() (fakeResult, error) {
+			// The driver cancels the statement once the count's deadline passes
+			time.Sleep(2 * countTimeout)
+			return fakeResult{}, &pgconn.PgError{Code: queryCanceledCode, Message: "canceling statement due to user request"}
+		})
+
+		require.NoError(t, err)
+		assert.True(t, estimated)
+		assert.Equal(t, service.ListTotal{Count: 48213, IsEstimate: true}, total)
+	})
+
+	t.Run("count canceled by the caller is not estimated", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, _, estimated, err := listWithCount(t, ctx, func() (fakeResult, error) {
+			cancel()
+			return fakeResult{}, &pgconn.PgError{Code: queryCanceledCode, Message: "canceling statement due to user request"}
+		})
+
+		assert.Error(t, err)
+		assert.False(t, estimated)
+	})
+
+	t.Run("other count errors are returned", func(t *testing.T) {
+		db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+			if strings.Contains(query, "count(*)") {
+				return fakeResult{}, errors.New("connection reset")
+			}
+			return fakeResult{}, nil
+		})
+
+		_, _, err := NewRepositoryWithConfig(db, Config{CountTimeout: countTimeout}).List(context.Background(), service.ListFilters{Page: 1, PageSize: 20})
+
+		assert.ErrorContains(t, err, "connection reset")
+	})
+}
+
+func TestGetMetrics_FailuresByReason(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	driverID := "DRIVER-1"
+
+	var failureQuery string
+	var failureArgs []driver.NamedValue
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		if strings.Contains(query, "failure_reason_code") {
+			failureQuery, failureArgs = query, args
+			return fakeResult{
+				columns: []string{"failure_reason_code", "count"},
+				rows:    [][]driver.Value{{"WRONG_ADDRESS", int64(3)}, {"REFUSED", int64(1)}},
+			}, nil
+		}
+		return fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}}, nil
+	})
+	repo := NewRepository(db)
+
+	metrics, err := repo.GetMetrics(context.Background(), start, start.Add(time.Hour), &driverID, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"WRONG_ADDRESS": 3, "REFUSED": 1}, metrics.FailuresByReason)
+
+	// Dead-lettered deliveries failed first and keep their reason
+	assert.Contains(t, failureQuery, "GROUP BY \"failure_reason_code\"")
+	assert.Contains(t, failureQuery, "driver_id = $3")
+	values := make([]interface{}, 0, len(failureArgs))
+	for _, arg := range failureArgs {
+		values = append(values, arg.Value)
+	}
+	assert.Contains(t, values, domain.DeliveryStatusFailed)
+	assert.Contains(t, values, domain.DeliveryStatusDeadLetter)
+}
+
+func TestClaimNextPending(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	var queries []string
+	pending := true
+	db := openFakeDB(t, func(query string, _ []driver.NamedValue) (fakeResult, error) {
+		queries = append(queries, query)
+		switch {
+		case strings.HasPrefix(query, "SELECT") && pending:
+			return fakeResult{
+				columns: []string{"id", "order_id", "status", "created_at"},
+				rows:    [][]driver.Value{{id.String(), "ORDER-1", string(domain.DeliveryStatusPending), now}},
+			}, nil
+		case strings.HasPrefix(query, "SELECT"):
+			return fakeResult{columns: []string{"id"}}, nil
+		default:
+			return fakeResult{
+				columns: []string{"id", "created_at", "updated_at"},
+				rows:    [][]driver.Value{{id.String(), now, now}},
+			}, nil
+		}
+	})
+	repo := NewRepository(db)
+
+	claimed, err := repo.ClaimNextPending(context.Background(), "DRIVER-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, id, claimed.ID)
+	assert.Equal(t, domain.DeliveryStatusAssigned, claimed.Status)
+	require.NotNil(t, claimed.DriverID)
+	assert.Equal(t, "DRIVER-1", *claimed.DriverID)
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0],
+		"ORDER BY CASE priority WHEN 'HIGH' THEN 3 WHEN 'NORMAL' THEN 2 WHEN 'LOW' THEN 1 ELSE 0 END DESC, created_at ASC, id ASC")
+	assert.Contains(t, queries[0], "FOR UPDATE SKIP LOCKED")
+	assert.Contains(t, queries[1], "UPDATE")
+
+	// Nothing pending
+	pending = false
+	_, err = repo.ClaimNextPending(context.Background(), "DRIVER-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestClaimNextPending_PriorityOrder(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(pgdriver.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, pgmigrate.Migrate(db))
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	// An old LOW delivery waits behind a newer HIGH one; both predate anything else pending
+	create := func(priority domain.DeliveryPriority, createdAt time.Time) *domain.DeliveryAssignment {
+		assignment, err := domain.NewDeliveryAssignmentWithClock(
+			fixedClock(createdAt),
+			"CLAIM-"+uuid.NewString(),
+			domain.Address{Street: "1 Pickup St", City: "Springfield"},
+			domain.Address{Street: "2 Dropoff Ave", City: "Springfield"},
+			time.Now().Add(time.Hour),
+			time.Now().Add(2*time.Hour),
+			"",
+		)
+		require.NoError(t, err)
+		assignment.Priority = priority
+		require.NoError(t, repo.Create(ctx, assignment))
+		t.Cleanup(func() { _ = repo.Delete(ctx, assignment.ID) })
+		return assignment
+	}
+	create(domain.PriorityLow, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	high := create(domain.PriorityHigh, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	err = repo.WithTransaction(ctx, func(txRepo service.DeliveryRepository) error {
+		claimed, err := txRepo.ClaimNextPending(ctx, "DRIVER-A")
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, high.ID, claimed.ID)
+		return errors.New("rollback")
+	})
+	assert.EqualError(t, err, "rollback")
+}
+
+func TestEscalateWaitingPending(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+
+	var query string
+	var args []driver.NamedValue
+	db := openFakeDB(t, func(q string, a []driver.NamedValue) (fakeResult, error) {
+		query, args = q, a
+		return fakeResult{
+			columns: []string{"id", "order_id", "status", "priority", "created_at", "updated_at"},
+			rows: [][]driver.Value{
+				{id.String(), "ORDER-1", string(domain.DeliveryStatusPending), string(domain.PriorityNormal), now.Add(-2 * time.Hour), now},
+			},
+		}, nil
+	})
+	repo := NewRepository(db)
+
+	escalated, err := repo.EscalateWaitingPending(context.Background(), domain.PriorityLow, domain.PriorityNormal, cutoff, now, 50)
+
+	require.NoError(t, err)
+	require.Len(t, escalated, 1)
+	assert.Equal(t, id, escalated[0].ID)
+	assert.Equal(t, domain.PriorityNormal, escalated[0].Priority)
+
+	// One statement that rechecks the status and priority of every row it updates
+	assert.True(t, strings.HasPrefix(query, "UPDATE"), query)
+	assert.Contains(t, query, `WHERE id IN (SELECT "id" FROM`)
+	assert.Contains(t, query, "ORDER BY created_at ASC LIMIT 50)")
+	assert.Contains(t, query, "AND (status = $")
+	assert.Contains(t, query, "RETURNING *")
+	var values []driver.Value
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	assert.Contains(t, values, domain.PriorityNormal)
+	assert.Contains(t, values, domain.PriorityLow)
+	assert.Contains(t, values, cutoff)
+}
+
+// TestClaimNextPending_Concurrent runs against a disposable PostgreSQL database named by
+// TEST_DATABASE_DSN, since the fake database cannot lock rows
+func TestClaimNextPending_Concurrent(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(pgdriver.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, pgmigrate.Migrate(db))
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	// HIGH priority and created long ago, so they are claimed first whatever else is in the database
+	created := make(map[uuid.UUID]bool)
+	for i := 0; i < 2; i++ {
+		assignment, err := domain.NewDeliveryAssignmentWithClock(
+			fixedClock(time.Date(2000, 1, 1, 0, 0, i, 0, time.UTC)),
+			"CLAIM-"+uuid.NewString(),
+			domain.Address{Street: "1 Pickup St", City: "Springfield"},
+			domain.Address{Street: "2 Dropoff Ave", City: "Springfield"},
+			time.Now().Add(time.Hour),
+			time.Now().Add(2*time.Hour),
+			"",
+		)
+		require.NoError(t, err)
+		assignment.Priority = domain.PriorityHigh
+		require.NoError(t, repo.Create(ctx, assignment))
+		created[assignment.ID] = true
+		t.Cleanup(func() { _ = repo.Delete(ctx, assignment.ID) })
+	}
+
+	// Both claimers hold their transaction open until the other has claimed too
+	var wg sync.WaitGroup
+	claims := make(chan uuid.UUID, 2)
+	for _, driverID := range []string{"DRIVER-A", "DRIVER-B"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.WithTransaction(ctx, func(txRepo service.DeliveryRepository) error {
+				claimed, err := txRepo.ClaimNextPending(ctx, driverID)
+				if err != nil {
+					return err
+				}
+				claims <- claimed.ID
+				for deadline := time.Now().Add(5 * time.Second); len(claims) < 2 && time.Now().Before(deadline); {
+					time.Sleep(10 * time.Millisecond)
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	close(claims)
+
+	first, second := <-claims, <-claims
+	assert.NotEqual(t, first, second, "the same delivery was claimed twice")
+	assert.True(t, created[first])
+	assert.True(t, created[second])
+}
+
+func TestListOverdueAssigned(t *testing.T) {
+	var statement string
+	var values []interface{}
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeResult, error) {
+		statement = query
+		for _, arg := range args {
+			values = append(values, arg.Value)
+		}
+		return fakeResult{}, nil
+	})
+	repo := NewRepository(db)
+
+	cutoff := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	_, err := repo.ListOverdueAssigned(context.Background(), cutoff, 100)
+
+	require.NoError(t, err)
+	// A driver assigned after the scheduled pickup, e.g. a promoted backup, is due from then
+	assert.Contains(t, statement, "scheduled_pickup_time <")
+	assert.Contains(t, statement, "driver_assigned_at IS NULL OR driver_assigned_at <")
+	assert.Contains(t, values, domain.DeliveryStatusAssigned)
+	assert.Contains(t, values, cutoff)
+}
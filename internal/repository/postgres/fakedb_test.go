@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeResult is the canned response of the fake database to a single query
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeHandler answers queries sent to the fake database
+type fakeHandler func(query string, args []driver.NamedValue) (fakeResult, error)
+
+// openFakeDB returns a GORM handle whose statements are answered by handler instead of PostgreSQL
+func openFakeDB(t *testing.T, handler fakeHandler) *gorm.DB {
+	t.Helper()
+
+	sqlDB := sql.OpenDB(fakeConnector{handler: handler})
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+type fakeConnector struct {
+	handler fakeHandler
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{handler: c.handler}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: use the connector")
+}
+
+type fakeConn struct {
+	handler fakeHandler
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+// CheckNamedValue accepts any argument, like the pgx driver does
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+// BeginTx reports non-default transaction options to the handler as the BEGIN statement PostgreSQL
+// would receive, so tests can assert them
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.Isolation == driver.IsolationLevel(sql.LevelDefault) && !opts.ReadOnly {
+		return fakeTx{}, nil
+	}
+
+	stmt := "BEGIN"
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		stmt += " ISOLATION LEVEL " + strings.ToUpper(sql.IsolationLevel(opts.Isolation).String())
+	}
+	if opts.ReadOnly {
+		stmt += " READ ONLY"
+	}
+	if _, err := c.handler(stmt, nil); err != nil {
+		return nil, err
+	}
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.handler(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{result: result}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.handler(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(result.rows)), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	result fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RawJSON is a JSON document stored as JSONB as-is
+type RawJSON json.RawMessage
+
+// Scan implements the sql.Scanner interface for RawJSON
+func (j *RawJSON) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	*j = append((*j)[:0], bytes...)
+	return nil
+}
+
+// Value implements the driver.Valuer interface for RawJSON
+func (j RawJSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return []byte("[]"), nil
+	}
+	return []byte(j), nil
+}
+
+// AuditLogEntry is a stored audit entry for a delivery mutation. Entries are append-only; whether
+// they are soft deleted along with their delivery is the repository's delete policy.
+type AuditLogEntry struct {
+	ID          int64          `gorm:"primaryKey;autoIncrement"`
+	DeliveryID  *uuid.UUID     `gorm:"type:uuid;index:idx_delivery_audit_log_delivery,priority:1"` // nil for deletes naming no single delivery
+	OrderID     string         `gorm:"type:varchar(100)"`
+	Action      string         `gorm:"type:varchar(50);not null"`
+	UserID      string         `gorm:"type:varchar(255)"`
+	TenantID    string         `gorm:"type:varchar(255)"`
+	TraceID     string         `gorm:"type:varchar(255)"`
+	Changes     RawJSON        `gorm:"type:jsonb;not null;default:'[]'"`
+	Affected    int64          `gorm:"not null;default:0"`
+	DeliveryIDs RawJSON        `gorm:"type:jsonb;not null;default:'[]'"` // Deliveries deleted by a delete by order or bulk delete
+	Filters     RawJSON        `gorm:"type:jsonb;not null;default:'{}'"` // Filters of a bulk delete, by name
+	RecordedAt  time.Time      `gorm:"not null;index:idx_delivery_audit_log_delivery,priority:2"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// TableName specifies the table name for AuditLogEntry
+func (AuditLogEntry) TableName() string {
+	return "delivery_audit_log"
+}
@@ -31,22 +31,146 @@ func (a *Address) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
 
+// TimedNotes is a custom type for storing timeline notes as a JSONB array in PostgreSQL
+type TimedNotes []domain.TimedNote
+
+// Scan implements the sql.Scanner interface for TimedNotes
+func (n *TimedNotes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, n)
+}
+
+// Value implements the driver.Valuer interface for TimedNotes
+func (n TimedNotes) Value() (driver.Value, error) {
+	if n == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(n)
+}
+
+// Tags is a custom type for storing delivery tags as a JSONB array in PostgreSQL
+type Tags []string
+
+// Scan implements the sql.Scanner interface for Tags
+func (t *Tags) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+// Value implements the driver.Valuer interface for Tags
+func (t Tags) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t)
+}
+
+// NotifyPrefs is a custom type for storing a recipient's notification statuses as a JSONB array in PostgreSQL
+type NotifyPrefs []domain.DeliveryStatus
+
+// Scan implements the sql.Scanner interface for NotifyPrefs
+func (n *NotifyPrefs) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, n)
+}
+
+// Value implements the driver.Valuer interface for NotifyPrefs
+func (n NotifyPrefs) Value() (driver.Value, error) {
+	if n == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(n)
+}
+
+// Packages is a custom type for storing a delivery's packages as a JSONB array in PostgreSQL
+type Packages []domain.Package
+
+// Scan implements the sql.Scanner interface for Packages
+func (p *Packages) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value implements the driver.Valuer interface for Packages
+func (p Packages) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Metadata is a custom type for storing a delivery's metadata as a JSONB object in PostgreSQL
+type Metadata map[string]any
+
+// Scan implements the sql.Scanner interface for Metadata
+func (m *Metadata) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, m)
+}
+
+// Value implements the driver.Valuer interface for Metadata
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
 // DeliveryAssignment is the GORM model for delivery_assignments table
 type DeliveryAssignment struct {
-	ID                    uuid.UUID             `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	OrderID               string                `gorm:"type:varchar(100);not null;index"`
-	DriverID              *string               `gorm:"type:varchar(100);index"`
-	Status                domain.DeliveryStatus `gorm:"type:varchar(50);not null;index"`
-	PickupAddress         Address               `gorm:"type:jsonb;not null"`
-	DeliveryAddress       Address               `gorm:"type:jsonb;not null"`
-	ScheduledPickupTime   time.Time             `gorm:"not null;index"`
-	EstimatedDeliveryTime time.Time             `gorm:"not null"`
-	ActualPickupTime      *time.Time
-	ActualDeliveryTime    *time.Time
-	Notes                 string         `gorm:"type:text"`
-	CreatedAt             time.Time      `gorm:"not null;index"`
-	UpdatedAt             time.Time      `gorm:"not null"`
-	DeletedAt             gorm.DeletedAt `gorm:"index"`
+	ID                           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID                      string    `gorm:"type:varchar(100);not null;index"`
+	DriverID                     *string   `gorm:"type:varchar(100);index"`
+	BackupDriverID               *string   `gorm:"type:varchar(100)"`
+	DriverAssignedAt             *time.Time
+	Status                       domain.DeliveryStatus   `gorm:"type:varchar(50);not null;index"`
+	Priority                     domain.DeliveryPriority `gorm:"type:varchar(16);not null;default:'NORMAL'"`
+	PickupAddress                Address                 `gorm:"type:jsonb;not null"`
+	DeliveryAddress              Address                 `gorm:"type:jsonb;not null"`
+	ScheduledPickupTime          time.Time               `gorm:"not null;index"`
+	EstimatedDeliveryTime        time.Time               `gorm:"not null"`
+	EstimatedDeliveryWindowStart *time.Time
+	EstimatedDeliveryWindowEnd   *time.Time
+	DriverETA                    *time.Time
+	ActualPickupTime             *time.Time
+	ActualDeliveryTime           *time.Time
+	Notes                        string     `gorm:"type:text"`
+	Rating                       *int       `gorm:"type:smallint"`
+	Feedback                     *string    `gorm:"type:text"`
+	TimelineNotes                TimedNotes `gorm:"type:jsonb;not null;default:'[]'"`
+	DeadLetterReason             *string    `gorm:"type:text"`
+	FailureReasonCode            *string    `gorm:"type:varchar(64)"`
+	TimeZone                     string     `gorm:"type:varchar(64);not null;default:''"`
+	AllowConcurrent              bool       `gorm:"not null;default:false"`
+	RequiredVehicleType          *string    `gorm:"type:varchar(32);index:idx_delivery_assignments_required_vehicle_type,where:required_vehicle_type IS NOT NULL"`
+	PickupCode                   string     `gorm:"type:varchar(16);not null;default:''"`
+	FailedPickupAttempts         int        `gorm:"not null;default:0"`
+	PickupLockedUntil            *time.Time
+	Tags                         Tags           `gorm:"type:jsonb;not null;default:'[]';index:idx_delivery_assignments_tags,type:gin"`
+	Packages                     Packages       `gorm:"type:jsonb;not null;default:'[]'"`
+	RecipientEmail               *string        `gorm:"type:varchar(254)"`
+	NotifyPrefs                  NotifyPrefs    `gorm:"type:jsonb;not null;default:'[]'"`
+	Metadata                     Metadata       `gorm:"type:jsonb;not null;default:'{}'"`
+	ParentDeliveryID             *uuid.UUID     `gorm:"type:uuid;index"`
+	AttemptNumber                int            `gorm:"not null;default:1"`
+	CreatedAt                    time.Time      `gorm:"not null;index"`
+	UpdatedAt                    time.Time      `gorm:"not null"`
+	DeletedAt                    gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName specifies the table name for DeliveryAssignment
@@ -56,38 +180,93 @@ func (DeliveryAssignment) TableName() string {
 
 // ToEntity converts the GORM model to domain entity
 func (d *DeliveryAssignment) ToEntity() *domain.DeliveryAssignment {
-	return &domain.DeliveryAssignment{
-		ID:                    d.ID,
-		OrderID:               d.OrderID,
-		DriverID:              d.DriverID,
-		Status:                d.Status,
-		PickupAddress:         domain.Address(d.PickupAddress),
-		DeliveryAddress:       domain.Address(d.DeliveryAddress),
-		ScheduledPickupTime:   d.ScheduledPickupTime,
-		EstimatedDeliveryTime: d.EstimatedDeliveryTime,
-		ActualPickupTime:      d.ActualPickupTime,
-		ActualDeliveryTime:    d.ActualDeliveryTime,
-		Notes:                 d.Notes,
-		CreatedAt:             d.CreatedAt,
-		UpdatedAt:             d.UpdatedAt,
+	entity := &domain.DeliveryAssignment{
+		ID:                           d.ID,
+		OrderID:                      d.OrderID,
+		DriverID:                     d.DriverID,
+		BackupDriverID:               d.BackupDriverID,
+		DriverAssignedAt:             d.DriverAssignedAt,
+		Status:                       d.Status.Canonical(), // Rows written before the spelling was unified
+		Priority:                     d.Priority,
+		PickupAddress:                domain.Address(d.PickupAddress),
+		DeliveryAddress:              domain.Address(d.DeliveryAddress),
+		ScheduledPickupTime:          d.ScheduledPickupTime,
+		EstimatedDeliveryTime:        d.EstimatedDeliveryTime,
+		EstimatedDeliveryWindowStart: d.EstimatedDeliveryWindowStart,
+		EstimatedDeliveryWindowEnd:   d.EstimatedDeliveryWindowEnd,
+		DriverETA:                    d.DriverETA,
+		ActualPickupTime:             d.ActualPickupTime,
+		ActualDeliveryTime:           d.ActualDeliveryTime,
+		Notes:                        d.Notes,
+		Rating:                       d.Rating,
+		Feedback:                     d.Feedback,
+		TimelineNotes:                d.TimelineNotes,
+		DeadLetterReason:             d.DeadLetterReason,
+		FailureReasonCode:            d.FailureReasonCode,
+		TimeZone:                     d.TimeZone,
+		AllowConcurrent:              d.AllowConcurrent,
+		RequiredVehicleType:          d.RequiredVehicleType,
+		PickupCode:                   d.PickupCode,
+		FailedPickupAttempts:         d.FailedPickupAttempts,
+		PickupLockedUntil:            d.PickupLockedUntil,
+		Tags:                         d.Tags,
+		Packages:                     d.Packages,
+		RecipientEmail:               d.RecipientEmail,
+		NotifyPrefs:                  domain.NotifyPrefs(d.NotifyPrefs),
+		Metadata:                     d.Metadata,
+		ParentDeliveryID:             d.ParentDeliveryID,
+		AttemptNumber:                d.AttemptNumber,
+		CreatedAt:                    d.CreatedAt,
+		UpdatedAt:                    d.UpdatedAt,
 	}
+
+	if d.DeletedAt.Valid {
+		deletedAt := d.DeletedAt.Time
+		entity.DeletedAt = &deletedAt
+	}
+
+	return entity
 }
 
 // FromEntity converts domain entity to GORM model
 func FromEntity(e *domain.DeliveryAssignment) *DeliveryAssignment {
 	return &DeliveryAssignment{
-		ID:                    e.ID,
-		OrderID:               e.OrderID,
-		DriverID:              e.DriverID,
-		Status:                e.Status,
-		PickupAddress:         Address(e.PickupAddress),
-		DeliveryAddress:       Address(e.DeliveryAddress),
-		ScheduledPickupTime:   e.ScheduledPickupTime,
-		EstimatedDeliveryTime: e.EstimatedDeliveryTime,
-		ActualPickupTime:      e.ActualPickupTime,
-		ActualDeliveryTime:    e.ActualDeliveryTime,
-		Notes:                 e.Notes,
-		CreatedAt:             e.CreatedAt,
-		UpdatedAt:             e.UpdatedAt,
+		ID:                           e.ID,
+		OrderID:                      e.OrderID,
+		DriverID:                     e.DriverID,
+		BackupDriverID:               e.BackupDriverID,
+		DriverAssignedAt:             e.DriverAssignedAt,
+		Status:                       e.Status.Canonical(),
+		Priority:                     e.Priority,
+		PickupAddress:                Address(e.PickupAddress),
+		DeliveryAddress:              Address(e.DeliveryAddress),
+		ScheduledPickupTime:          e.ScheduledPickupTime,
+		EstimatedDeliveryTime:        e.EstimatedDeliveryTime,
+		EstimatedDeliveryWindowStart: e.EstimatedDeliveryWindowStart,
+		EstimatedDeliveryWindowEnd:   e.EstimatedDeliveryWindowEnd,
+		DriverETA:                    e.DriverETA,
+		ActualPickupTime:             e.ActualPickupTime,
+		ActualDeliveryTime:           e.ActualDeliveryTime,
+		Notes:                        e.Notes,
+		Rating:                       e.Rating,
+		Feedback:                     e.Feedback,
+		TimelineNotes:                TimedNotes(e.TimelineNotes),
+		DeadLetterReason:             e.DeadLetterReason,
+		FailureReasonCode:            e.FailureReasonCode,
+		TimeZone:                     e.TimeZone,
+		AllowConcurrent:              e.AllowConcurrent,
+		RequiredVehicleType:          e.RequiredVehicleType,
+		PickupCode:                   e.PickupCode,
+		FailedPickupAttempts:         e.FailedPickupAttempts,
+		PickupLockedUntil:            e.PickupLockedUntil,
+		Tags:                         Tags(e.Tags),
+		Packages:                     Packages(e.Packages),
+		RecipientEmail:               e.RecipientEmail,
+		NotifyPrefs:                  NotifyPrefs(e.NotifyPrefs),
+		Metadata:                     Metadata(e.Metadata),
+		ParentDeliveryID:             e.ParentDeliveryID,
+		AttemptNumber:                e.AttemptNumber,
+		CreatedAt:                    e.CreatedAt,
+		UpdatedAt:                    e.UpdatedAt,
 	}
 }
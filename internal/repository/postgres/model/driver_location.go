@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// DriverLocation is a stored location ping. Pings are append-only.
+type DriverLocation struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	DriverID   string    `gorm:"type:varchar(100);not null;index:idx_driver_locations_driver_recorded,priority:1"`
+	Latitude   float64   `gorm:"not null"`
+	Longitude  float64   `gorm:"not null"`
+	RecordedAt time.Time `gorm:"not null;index:idx_driver_locations_driver_recorded,priority:2,sort:desc"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for DriverLocation
+func (DriverLocation) TableName() string {
+	return "driver_locations"
+}
+
+// ToEntity converts a database model to a domain entity
+func (l *DriverLocation) ToEntity() *domain.DriverLocation {
+	return &domain.DriverLocation{
+		DriverID:   l.DriverID,
+		Latitude:   l.Latitude,
+		Longitude:  l.Longitude,
+		RecordedAt: l.RecordedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// FailureCounts is a custom type for storing failures per reason code as a JSONB object in PostgreSQL
+type FailureCounts map[string]int64
+
+// Scan implements the sql.Scanner interface for FailureCounts
+func (f *FailureCounts) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// Value implements the driver.Valuer interface for FailureCounts
+func (f FailureCounts) Value() (driver.Value, error) {
+	if f == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(f)
+}
+
+// MetricsAggregate holds delivery metrics as sums and counts so that aggregates of several
+// periods can be added up before the averages are taken
+type MetricsAggregate struct {
+	TotalDeliveries        int64         `gorm:"not null;default:0"`
+	CompletedDeliveries    int64         `gorm:"not null;default:0"`
+	FailedDeliveries       int64         `gorm:"not null;default:0"`
+	CancelledDeliveries    int64         `gorm:"not null;default:0"`
+	DeadLetterDeliveries   int64         `gorm:"not null;default:0"`
+	DeliveryTimeSumMinutes float64       `gorm:"not null;default:0"` // Pickup to delivery, over DeliveryTimeCount deliveries
+	DeliveryTimeCount      int64         `gorm:"not null;default:0"`
+	LifecycleSumMinutes    float64       `gorm:"not null;default:0"` // Created to delivered, over DeliveredCount deliveries
+	DeliveredCount         int64         `gorm:"not null;default:0"` // DELIVERED with an actual delivery time
+	OnTimeCount            int64         `gorm:"not null;default:0"` // Of DeliveredCount
+	RatingSum              int64         `gorm:"not null;default:0"`
+	RatingCount            int64         `gorm:"not null;default:0"`
+	FailuresByReason       FailureCounts `gorm:"type:jsonb;not null;default:'{}'"` // FAILED and DEAD_LETTER per reason code
+}
+
+// Add adds other to a
+func (a *MetricsAggregate) Add(other MetricsAggregate) {
+	a.TotalDeliveries += other.TotalDeliveries
+	a.CompletedDeliveries += other.CompletedDeliveries
+	a.FailedDeliveries += other.FailedDeliveries
+	a.CancelledDeliveries += other.CancelledDeliveries
+	a.DeadLetterDeliveries += other.DeadLetterDeliveries
+	a.DeliveryTimeSumMinutes += other.DeliveryTimeSumMinutes
+	a.DeliveryTimeCount += other.DeliveryTimeCount
+	a.LifecycleSumMinutes += other.LifecycleSumMinutes
+	a.DeliveredCount += other.DeliveredCount
+	a.OnTimeCount += other.OnTimeCount
+	a.RatingSum += other.RatingSum
+	a.RatingCount += other.RatingCount
+	for code, count := range other.FailuresByReason {
+		if a.FailuresByReason == nil {
+			a.FailuresByReason = make(FailureCounts, len(other.FailuresByReason))
+		}
+		a.FailuresByReason[code] += count
+	}
+}
+
+// ToEntity converts the aggregate to domain metrics
+func (a MetricsAggregate) ToEntity() *domain.DeliveryMetrics {
+	metrics := &domain.DeliveryMetrics{
+		TotalDeliveries:      int32(a.TotalDeliveries),
+		CompletedDeliveries:  int32(a.CompletedDeliveries),
+		FailedDeliveries:     int32(a.FailedDeliveries),
+		CancelledDeliveries:  int32(a.CancelledDeliveries),
+		DeadLetterDeliveries: int32(a.DeadLetterDeliveries),
+	}
+	if a.DeliveryTimeCount > 0 {
+		metrics.AverageDeliveryTimeMinutes = a.DeliveryTimeSumMinutes / float64(a.DeliveryTimeCount)
+	}
+	if a.DeliveredCount > 0 {
+		metrics.AverageLifecycleDurationMinutes = a.LifecycleSumMinutes / float64(a.DeliveredCount)
+		metrics.OnTimeDeliveryRate = float64(a.OnTimeCount) / float64(a.DeliveredCount) * 100
+	}
+	if a.RatingCount > 0 {
+		metrics.AverageRating = float64(a.RatingSum) / float64(a.RatingCount)
+	}
+	metrics.FailuresByReason = make(map[string]int32, len(a.FailuresByReason))
+	for code, count := range a.FailuresByReason {
+		metrics.FailuresByReason[code] = int32(count)
+	}
+	return metrics
+}
+
+// DeliveryMetricsDaily is the precomputed metrics of the deliveries created on one UTC day
+type DeliveryMetricsDaily struct {
+	Day                time.Time `gorm:"type:date;primaryKey"`
+	OnTimeGraceSeconds float64   `gorm:"not null"` // Grace period the on-time count was computed with
+	MetricsAggregate   `gorm:"embedded"`
+	RefreshedAt        time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for DeliveryMetricsDaily
+func (DeliveryMetricsDaily) TableName() string {
+	return "delivery_metrics_daily"
+}
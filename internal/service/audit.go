@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// AuditEntry records one saved mutation: what was done, to which delivery, by whom and when,
+// and the fields it changed. Background workers act without an actor, so UserID is empty for them.
+type AuditEntry struct {
+	Time        time.Time         `json:"time"`
+	Action      string            `json:"action"` // Operation name, e.g. constants.OpUpdateStatus
+	UserID      string            `json:"user_id,omitempty"`
+	TenantID    string            `json:"tenant_id,omitempty"`
+	TraceID     string            `json:"trace_id,omitempty"`
+	DeliveryID  string            `json:"delivery_id,omitempty"`
+	OrderID     string            `json:"order_id,omitempty"`
+	Changes     []FieldChange     `json:"changes,omitempty"`      // By JSON field name, sorted; empty for deletes
+	Affected    int64             `json:"affected,omitempty"`     // Deliveries deleted by a delete by order or bulk delete
+	DeliveryIDs []string          `json:"delivery_ids,omitempty"` // IDs of the deliveries Affected counts
+	Filters     map[string]string `json:"filters,omitempty"`      // Filters a bulk delete matched by, by name
+}
+
+// FieldChange is one field's value before and after a mutation, as JSON. Before is null for a
+// created delivery and for fields that were unset.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditLogger receives an entry for every mutation once it is saved. It is called on the request
+// path, so implementations must not block for long. A returned error is logged; the mutation has
+// already been saved and is not undone.
+type AuditLogger interface {
+	Audit(ctx context.Context, entry AuditEntry) error
+}
+
+// nopAuditLogger discards every entry
+type nopAuditLogger struct{}
+
+func (nopAuditLogger) Audit(context.Context, AuditEntry) error { return nil }
+
+// JSONAuditLogger writes each audit entry as one line of JSON. It only ever appends, so when w is
+// a file opened with O_APPEND the file is the audit trail. It is safe for concurrent use.
+type JSONAuditLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditLogger creates an audit logger writing JSON lines to w
+func NewJSONAuditLogger(w io.Writer) *JSONAuditLogger {
+	return &JSONAuditLogger{w: w}
+}
+
+// Audit writes entry as a single line
+func (l *JSONAuditLogger) Audit(_ context.Context, entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	// One write per entry, so concurrent entries never interleave
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// auditState is a delivery's fields by JSON name, captured before a mutation so its audit entry
+// can show what changed. Fields excluded from JSON, such as the pickup code, are never audited.
+type auditState map[string]json.RawMessage
+
+// captureAuditState snapshots assignment; nil yields an empty state (nothing existed)
+func captureAuditState(assignment *domain.DeliveryAssignment) auditState {
+	state := auditState{}
+	if assignment == nil {
+		return state
+	}
+	encoded, err := json.Marshal(assignment)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(encoded, &state)
+	return state
+}
+
+// diffAuditState returns the fields whose value differs between before and after, sorted by name
+func diffAuditState(before, after auditState) []FieldChange {
+	fields := slices.Collect(maps.Keys(before))
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []FieldChange
+	for _, field := range fields {
+		if bytes.Equal(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field, Before: jsonOrNull(before[field]), After: jsonOrNull(after[field])})
+	}
+	return changes
+}
+
+// jsonOrNull returns raw, or JSON null for a field that was absent
+func jsonOrNull(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return json.RawMessage("null")
+	}
+	return raw
+}
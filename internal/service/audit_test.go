@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// readAuditEntries decodes the JSON lines written to buf
+func readAuditEntries(t *testing.T, buf *bytes.Buffer) []service.AuditEntry {
+	t.Helper()
+
+	var entries []service.AuditEntry
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry service.AuditEntry
+		require.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// auditChanges maps each changed field to its before and after JSON
+func auditChanges(entry service.AuditEntry) map[string][2]string {
+	changes := make(map[string][2]string, len(entry.Changes))
+	for _, change := range entry.Changes {
+		changes[change.Field] = [2]string{string(change.Before), string(change.After)}
+	}
+	return changes
+}
+
+func TestAuditLog_CreateAndUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger := zap.NewNop()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now: now}
+	cfg.AuditLogger = service.NewJSONAuditLogger(&buf)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: "user-1", TenantID: "tenant-1"})
+	ctx = domain.ContextWithTraceID(ctx, "trace-1")
+
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	created, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+		OrderID:               "ORDER-123",
+		PickupAddress:         domain.Address{City: "New York"},
+		DeliveryAddress:       domain.Address{City: "Boston"},
+		ScheduledPickupTime:   now.Add(1 * time.Hour),
+		EstimatedDeliveryTime: now.Add(3 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetByID(gomock.Any(), created.ID).Return(created, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), created).Return(nil)
+
+	_, err = uc.AssignDriver(ctx, created.ID, "DRIVER-1")
+	require.NoError(t, err)
+
+	entries := readAuditEntries(t, &buf)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		assert.True(t, now.Equal(entry.Time))
+		assert.Equal(t, "user-1", entry.UserID)
+		assert.Equal(t, "tenant-1", entry.TenantID)
+		assert.Equal(t, "trace-1", entry.TraceID)
+		assert.Equal(t, created.ID.String(), entry.DeliveryID)
+		assert.Equal(t, "ORDER-123", entry.OrderID)
+	}
+
+	// A create changes every field from nothing
+	create := entries[0]
+	assert.Equal(t, constants.OpCreate, create.Action)
+	createChanges := auditChanges(create)
+	assert.Equal(t, [2]string{"null", `"ORDER-123"`}, createChanges["order_id"])
+	assert.Equal(t, [2]string{"null", `"PENDING"`}, createChanges["status"])
+	assert.NotContains(t, createChanges, "pickup_code")
+
+	// An update lists only what it changed
+	update := entries[1]
+	assert.Equal(t, constants.OpAssignDriver, update.Action)
+	assert.Equal(t, map[string][2]string{
+		"driver_id":          {"null", `"DRIVER-1"`},
+		"driver_assigned_at": {"null", `"2026-03-01T09:00:00Z"`},
+		"status":             {`"PENDING"`, `"ASSIGNED"`},
+	}, auditChanges(update))
+}
+
+func TestAuditLog_Deletes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	var buf bytes.Buffer
+	cfg := service.DefaultConfig()
+	cfg.AuditLogger = service.NewJSONAuditLogger(&buf)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	firstID, secondID, thirdID := uuid.New(), uuid.New(), uuid.New()
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-123").Return([]*domain.DeliveryAssignment{
+		{ID: firstID, Status: domain.DeliveryStatusPending},
+		{ID: secondID, Status: domain.DeliveryStatusAssigned},
+	}, nil)
+	mockRepo.EXPECT().DeleteByOrderID(ctx, "ORDER-123").Return([]uuid.UUID{firstID, secondID}, nil)
+
+	_, err := uc.DeleteDeliveriesByOrderID(ctx, "ORDER-123")
+	require.NoError(t, err)
+
+	cancelled := domain.DeliveryStatusCancelled
+	filters := service.ListFilters{Status: &cancelled}
+	mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1), nil).Times(2)
+	mockRepo.EXPECT().DeleteMatching(ctx, gomock.Any(), gomock.Any()).Return([]uuid.UUID{thirdID}, nil)
+
+	preview, err := uc.BulkDelete(ctx, filters, "")
+	require.NoError(t, err)
+	_, err = uc.BulkDelete(ctx, filters, preview.ConfirmToken)
+	require.NoError(t, err)
+
+	entries := readAuditEntries(t, &buf)
+	require.Len(t, entries, 2)
+
+	byOrder := entries[0]
+	assert.Equal(t, constants.OpDeleteByOrder, byOrder.Action)
+	assert.Equal(t, "ORDER-123", byOrder.OrderID)
+	assert.Equal(t, int64(2), byOrder.Affected)
+	assert.Equal(t, []string{firstID.String(), secondID.String()}, byOrder.DeliveryIDs)
+	assert.Empty(t, byOrder.Filters)
+
+	// A bulk delete also records the filters that matched the deliveries
+	bulk := entries[1]
+	assert.Equal(t, constants.OpBulkDelete, bulk.Action)
+	assert.Equal(t, int64(1), bulk.Affected)
+	assert.Equal(t, []string{thirdID.String()}, bulk.DeliveryIDs)
+	assert.Equal(t, string(cancelled.Canonical()), bulk.Filters["status"])
+}
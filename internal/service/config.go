@@ -0,0 +1,146 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// Config holds tunable business rules for the delivery use case
+type Config struct {
+	// UppercaseIDs normalizes order and driver IDs to upper case so lookups are case-insensitive
+	UppercaseIDs bool
+
+	// MaxNotesLength caps the length of delivery notes (0 disables the check)
+	MaxNotesLength int
+
+	// DefaultNotes are handling instructions used as a new delivery's notes when it has none.
+	// With PrependDefaultNotes they also go before notes the caller sent. Empty disables both.
+	DefaultNotes        string
+	PrependDefaultNotes bool
+
+	// Limits caps the number of items each batch operation accepts
+	Limits Limits
+
+	// MaxActivePerOrder caps the number of non-terminal deliveries per order (0 disables the check)
+	MaxActivePerOrder int
+
+	// MaxAttempts caps delivery attempts: reattempting a failed delivery that was already the
+	// last allowed attempt dead-letters it instead (0 disables the cap)
+	MaxAttempts int
+
+	// PickupLockout refuses pickup for a while after too many wrong pickup codes in a row
+	PickupLockout domain.PickupLockout
+
+	// MinScheduleAdvance is how far in the future a pickup must be scheduled at minimum
+	MinScheduleAdvance time.Duration
+
+	// MaxScheduleAdvance is the scheduling horizon for pickups (0 disables the check)
+	MaxScheduleAdvance time.Duration
+
+	// AllowPastScheduling lets imports that ask for it (CreateDeliveryInput.AllowPastScheduling)
+	// create deliveries with pickups in the past, for backfilling history
+	AllowPastScheduling bool
+
+	// DeliveryWindowSlack derives the delivery window as the estimate plus/minus this value
+	// when the client does not send one (0 disables derivation)
+	DeliveryWindowSlack time.Duration
+
+	// MaxDeliveryDistanceKm is the longest straight-line distance between pickup and delivery that
+	// CheckDeliveryFeasibility accepts (0 disables the check). AverageSpeedKmh estimates the travel
+	// time it compares against the delivery window (0 uses the default).
+	MaxDeliveryDistanceKm float64
+	AverageSpeedKmh       float64
+
+	// OnTimeGracePeriod is how late a delivery may be and still count as on time in metrics
+	OnTimeGracePeriod time.Duration
+
+	// MaxConcurrentMetrics caps the metrics aggregations (GetDeliveryMetrics, GetMetricsForDrivers)
+	// running at once; a call over the cap waits up to MetricsQueueTimeout for a slot and is then
+	// rejected with domain.ErrResourceExhausted (0 disables the limit)
+	MaxConcurrentMetrics int
+	MetricsQueueTimeout  time.Duration
+
+	// AllowedVehicleTypes lists the vehicle types a delivery may require (case-insensitive)
+	AllowedVehicleTypes []string
+
+	// FailureReasonCodes lists the reason codes a failed delivery may be given (case-insensitive)
+	FailureReasonCodes []string
+
+	// BusinessHours restricts scheduled pickups and estimated deliveries to operating hours, read
+	// in the delivery's time zone (empty allows any time)
+	BusinessHours domain.BusinessHours
+
+	// MetadataValidator checks the metadata of new deliveries for the tenant creating them, such
+	// as against MetadataSchemas (nil accepts any metadata)
+	MetadataValidator MetadataValidator
+
+	// Clock supplies timestamps for entity state changes (nil uses the system clock)
+	Clock domain.Clock
+
+	// IDGenerator creates the IDs of new deliveries (nil uses random UUIDv4)
+	IDGenerator domain.IDGenerator
+
+	// Notifier receives status changes once they are saved (nil discards them)
+	Notifier Notifier
+
+	// AuditLogger receives an entry for every saved mutation, with the fields it changed (nil
+	// discards them)
+	AuditLogger AuditLogger
+
+	// Broadcaster also receives every saved status change, unthrottled, for WatchDriverDeliveries
+	// (nil disables watching)
+	Broadcaster *StatusBroadcaster
+}
+
+// Limits holds the maximum batch size of each batch operation. Requests over a limit are
+// rejected with a validation error naming the limit (see validateBatchSize).
+type Limits struct {
+	// BatchGet caps the number of distinct IDs in a batch get
+	BatchGet int
+
+	// SyncBatch caps the number of deliveries requested from ListModifiedSince
+	SyncBatch int
+
+	// DriverMetrics caps the number of drivers in a GetMetricsForDrivers call
+	DriverMetrics int
+
+	// ImportRows caps the number of rows in one imported file, across all of its chunks
+	ImportRows int
+}
+
+// DefaultConfig returns the default use case configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxNotesLength: constants.DefaultMaxNotesLength,
+		Limits: Limits{
+			BatchGet:      constants.DefaultMaxBatchSize,
+			SyncBatch:     constants.MaxSyncBatchSize,
+			DriverMetrics: constants.DefaultMaxDriverMetricsBatch,
+			ImportRows:    constants.DefaultMaxImportRows,
+		},
+		MaxActivePerOrder: constants.DefaultMaxActivePerOrder,
+		MaxAttempts:       constants.DefaultMaxAttempts,
+		PickupLockout: domain.PickupLockout{
+			MaxAttempts: constants.DefaultMaxPickupAttempts,
+			Duration:    constants.DefaultPickupLockout,
+		},
+		MinScheduleAdvance:  constants.MinScheduleAdvance,
+		MaxScheduleAdvance:  constants.MaxScheduleAdvance,
+		DeliveryWindowSlack: constants.DefaultDeliveryWindowSlack,
+		OnTimeGracePeriod:   constants.DefaultOnTimeGracePeriod,
+
+		MaxDeliveryDistanceKm: constants.DefaultMaxDeliveryDistanceKm,
+		AverageSpeedKmh:       constants.DefaultAverageSpeedKmh,
+
+		MaxConcurrentMetrics: constants.DefaultMaxConcurrentMetrics,
+		MetricsQueueTimeout:  constants.DefaultMetricsQueueTimeout,
+
+		AllowedVehicleTypes: strings.Split(constants.DefaultVehicleTypes, ","),
+		FailureReasonCodes:  strings.Split(constants.DefaultFailureReasonCodes, ","),
+		Clock:               domain.SystemClock,
+		IDGenerator:         domain.UUIDv4,
+	}
+}
@@ -2,12 +2,22 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/pkg/validator"
 )
 
 //go:generate mockgen -destination=../mocks/usecase_mock.go -package=mocks  github.com/mohamadchoker/order-delivery-service/internal/service DeliveryUseCase
@@ -16,11 +26,90 @@ import (
 type DeliveryUseCase interface {
 	CreateDeliveryAssignment(ctx context.Context, input CreateDeliveryInput) (*domain.DeliveryAssignment, error)
 	GetDeliveryAssignment(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error)
-	UpdateDeliveryStatus(ctx context.Context, id uuid.UUID, status domain.DeliveryStatus, notes string) (*domain.DeliveryAssignment, error)
-	ListDeliveryAssignments(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, int64, error)
+	GetDeliveryStatus(ctx context.Context, id uuid.UUID) (*domain.DeliveryStatusInfo, error)
+	ImportDeliveries(ctx context.Context, inputs []CreateDeliveryInput, fileRows int) (*ImportResult, error)
+	GetSLADeadline(ctx context.Context, id uuid.UUID) (*domain.DeliverySLA, error)
+	BatchGetDeliveryAssignments(ctx context.Context, ids []uuid.UUID) ([]*domain.DeliveryAssignment, error)
+	CloneDeliveryAssignment(ctx context.Context, id uuid.UUID, scheduledPickupTime, estimatedDeliveryTime time.Time) (*domain.DeliveryAssignment, error)
+	UpdateDeliveryStatus(ctx context.Context, id uuid.UUID, status domain.DeliveryStatus, notes, pickupCode, failureReasonCode string) (*domain.DeliveryAssignment, error)
+	ListDeliveryAssignments(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, ListTotal, error)
+	ListDeliveryAssignmentsWithStatusBreakdown(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, ListTotal, map[domain.DeliveryStatus]int64, error)
 	AssignDriver(ctx context.Context, id uuid.UUID, driverID string) (*domain.DeliveryAssignment, error)
+	AssignBackupDriver(ctx context.Context, id uuid.UUID, driverID string) (*domain.DeliveryAssignment, error)
 	GetDeliveryMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string) (*domain.DeliveryMetrics, error)
+	GetMetricsForDrivers(ctx context.Context, driverIDs []string, startTime, endTime time.Time) (map[string]*domain.DeliveryMetrics, error)
+	RefreshMetricsSummary(ctx context.Context, day time.Time) error
+	GetDriverLeaderboard(ctx context.Context, startTime, endTime time.Time, limit int) ([]domain.DriverStats, error)
+	ListActiveDriverIDs(ctx context.Context) ([]string, error)
+	ListServedCities(ctx context.Context) ([]string, error)
+	RecordDriverLocation(ctx context.Context, location domain.DriverLocation) error
+	GetDriverLocation(ctx context.Context, driverID string) (*domain.DriverLocation, error)
+	WatchDriverDeliveries(ctx context.Context, driverID string) (<-chan StatusChange, error)
+	ListModifiedSince(ctx context.Context, since time.Time, cursor string, limit int) ([]*domain.DeliveryAssignment, domain.SyncCursor, error)
+	GetStatusTransitionGraph(ctx context.Context) []domain.StatusTransitions
 	DeleteDeliveryAssignment(ctx context.Context, id uuid.UUID) error
+	DeleteDeliveriesByOrderID(ctx context.Context, orderID string) (int64, error)
+	BulkDelete(ctx context.Context, filters ListFilters, confirmToken string) (*BulkDeleteResult, error)
+	AppendNote(ctx context.Context, id uuid.UUID, note string) (*domain.DeliveryAssignment, error)
+	UpdateDriverETA(ctx context.Context, id uuid.UUID, eta time.Time) (*domain.DeliveryAssignment, error)
+	SplitDelivery(ctx context.Context, id uuid.UUID, remainingPackages []domain.Package) (parent, child *domain.DeliveryAssignment, err error)
+	ReattemptDelivery(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error)
+	ReplayEvents(ctx context.Context, id uuid.UUID) (int, error)
+	AddTags(ctx context.Context, id uuid.UUID, tags []string) (*domain.DeliveryAssignment, error)
+	RemoveTags(ctx context.Context, id uuid.UUID, tags []string) (*domain.DeliveryAssignment, error)
+	ExportDeliveries(ctx context.Context, fn func(*domain.DeliveryAssignment) error) error
+	SubmitFeedback(ctx context.Context, id uuid.UUID, rating int, feedback string) (*domain.DeliveryAssignment, error)
+	MarkDeadLetter(ctx context.Context, id uuid.UUID, reason string) (*domain.DeliveryAssignment, error)
+	UnassignOverdueDeliveries(ctx context.Context, cutoff time.Time) ([]*domain.DeliveryAssignment, error)
+	EscalatePendingPriority(ctx context.Context, from, to domain.DeliveryPriority, cutoff time.Time) ([]*domain.DeliveryAssignment, error)
+	CheckDeliveryFeasibility(ctx context.Context, input FeasibilityInput) (*FeasibilityResult, error)
+}
+
+// ImportRowError is an imported row that was not saved; Row is its index in the imported inputs
+type ImportRowError struct {
+	Row int
+	Err error
+}
+
+// ImportedDelivery is an imported row that was saved, with the pickup code the driver must
+// present, which is only returned here
+type ImportedDelivery struct {
+	Row        int
+	ID         uuid.UUID
+	PickupCode string
+}
+
+// ImportResult summarizes an import; Created and Errors are ordered by row
+type ImportResult struct {
+	Succeeded int
+	Created   []ImportedDelivery
+	Errors    []ImportRowError
+}
+
+// BulkDeleteResult reports a bulk delete. Without a confirmation token nothing is deleted and
+// ConfirmToken is the token to send back to delete the Matched deliveries.
+type BulkDeleteResult struct {
+	Matched      int64
+	ConfirmToken string
+	Deleted      int64
+}
+
+// FeasibilityInput describes a delivery a planner is considering; both addresses need coordinates
+type FeasibilityInput struct {
+	PickupAddress         domain.Address
+	DeliveryAddress       domain.Address
+	ScheduledPickupTime   time.Time
+	EstimatedDeliveryTime time.Time
+}
+
+// FeasibilityResult reports whether a delivery can be made. Reasons explains every check that
+// failed and is empty when Feasible is true.
+type FeasibilityResult struct {
+	Feasible          bool
+	DistanceKm        float64
+	RequiredDuration  time.Duration // Travel time at the configured average speed
+	AvailableDuration time.Duration // Time between scheduled pickup and estimated delivery
+	Reasons           []string
 }
 
 // CreateDeliveryInput contains input for creating a delivery assignment
@@ -31,6 +120,40 @@ type CreateDeliveryInput struct {
 	ScheduledPickupTime   time.Time
 	EstimatedDeliveryTime time.Time
 	Notes                 string
+	TimeZone              string // Merchant's IANA time zone used for display; empty means UTC
+
+	// Optional delivery window; both or neither must be set. When omitted the window is derived
+	// from EstimatedDeliveryTime and the configured slack.
+	EstimatedDeliveryWindowStart *time.Time
+	EstimatedDeliveryWindowEnd   *time.Time
+
+	// AllowConcurrent lets this delivery be active alongside another delivery for the same order;
+	// otherwise the repository rejects a second active delivery with domain.ErrAlreadyExists
+	AllowConcurrent bool
+
+	// RequiredVehicleType restricts the delivery to drivers with this vehicle type; nil means any.
+	// It must be one of Config.AllowedVehicleTypes (case-insensitive).
+	RequiredVehicleType *string
+
+	// Tags are labels for ops ("vip", "reattempt"); they are trimmed, lower-cased and de-duplicated
+	Tags []string
+
+	// Priority is the initial dispatch priority; empty means NORMAL. PENDING deliveries are
+	// escalated from it over time (see EscalatePendingPriority).
+	Priority domain.DeliveryPriority
+
+	// RecipientEmail is where the recipient is emailed on status changes; nil or blank means not
+	// emailed. NotifyPrefs limits the statuses they are notified of; empty means all.
+	RecipientEmail *string
+	NotifyPrefs    []domain.DeliveryStatus
+
+	// Metadata holds tenant-defined details as a JSON object; when set it must pass
+	// Config.MetadataValidator for the caller's tenant
+	Metadata map[string]any
+
+	// AllowPastScheduling lifts the minimum schedule advance so historical deliveries can be
+	// backfilled. Only the import sets it, and only servers with Config.AllowPastScheduling honor it.
+	AllowPastScheduling bool
 }
 
 // ListDeliveryInput contains input for listing delivery assignments
@@ -39,24 +162,287 @@ type ListDeliveryInput struct {
 	PageSize int
 	Status   *domain.DeliveryStatus
 	DriverID *string
+	Activity domain.ActivityFilter // Combined with Status by intersection; empty means any
+
+	RequiredVehicleType *string
+	Tag                 *string
+	HasDriver           *bool // true: a driver is assigned, false: none is; nil means either
+	CreatedBefore       *time.Time
 }
 
 // deliveryUseCase implements DeliveryUseCase
 type deliveryUseCase struct {
-	repo   DeliveryRepository
-	logger *zap.Logger
+	repo    DeliveryRepository
+	logger  *zap.Logger
+	config  Config
+	metrics *metricsLimiter
 }
 
-// NewDeliveryUseCase creates a new delivery use case
+// NewDeliveryUseCase creates a new delivery use case with the default configuration
 func NewDeliveryUseCase(repo DeliveryRepository, logger *zap.Logger) DeliveryUseCase {
+	return NewDeliveryUseCaseWithConfig(repo, logger, DefaultConfig())
+}
+
+// NewDeliveryUseCaseWithConfig creates a new delivery use case with explicit configuration
+func NewDeliveryUseCaseWithConfig(repo DeliveryRepository, logger *zap.Logger, cfg Config) DeliveryUseCase {
+	if cfg.Clock == nil {
+		cfg.Clock = domain.SystemClock
+	}
+	if cfg.IDGenerator == nil {
+		cfg.IDGenerator = domain.UUIDv4
+	}
+	if cfg.Notifier == nil {
+		cfg.Notifier = nopNotifier{}
+	}
+	if cfg.AuditLogger == nil {
+		cfg.AuditLogger = nopAuditLogger{}
+	}
+	if cfg.MetadataValidator == nil {
+		cfg.MetadataValidator = nopMetadataValidator{}
+	}
+	if cfg.AverageSpeedKmh <= 0 {
+		cfg.AverageSpeedKmh = constants.DefaultAverageSpeedKmh
+	}
+
 	return &deliveryUseCase{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		logger:  logger,
+		config:  cfg,
+		metrics: newMetricsLimiter(cfg.MaxConcurrentMetrics, cfg.MetricsQueueTimeout),
+	}
+}
+
+// normalizeID trims surrounding whitespace and, if configured, upper-cases an order or driver ID
+func (u *deliveryUseCase) normalizeID(id string) string {
+	id = strings.TrimSpace(id)
+	if u.config.UppercaseIDs {
+		id = strings.ToUpper(id)
+	}
+	return id
+}
+
+// normalizeOptionalID normalizes an optional ID, leaving nil untouched
+func (u *deliveryUseCase) normalizeOptionalID(id *string) *string {
+	if id == nil {
+		return nil
+	}
+	normalized := u.normalizeID(*id)
+	return &normalized
+}
+
+// applyDefaultNotes fills in or prepends the configured default notes. Notes that already start
+// with the default, such as those of a cloned delivery, are left alone.
+func (u *deliveryUseCase) applyDefaultNotes(notes string) string {
+	if u.config.DefaultNotes == "" {
+		return notes
+	}
+	if strings.TrimSpace(notes) == "" {
+		return u.config.DefaultNotes
+	}
+	if !u.config.PrependDefaultNotes || strings.HasPrefix(notes, u.config.DefaultNotes) {
+		return notes
+	}
+	return u.config.DefaultNotes + "\n" + notes
+}
+
+// validateNotes enforces the configured maximum notes length
+func (u *deliveryUseCase) validateNotes(notes string) error {
+	v := validator.New()
+	v.ValidateStringLength("notes", notes, 0, u.config.MaxNotesLength)
+	return toValidationError(v)
+}
+
+// toValidationError converts the first collected validator error into a field-level domain error
+func toValidationError(v *validator.Validator) error {
+	var errs validator.ValidationErrors
+	if !errors.As(v.Errors(), &errs) || len(errs) == 0 {
+		return nil
+	}
+	return &domain.ValidationError{
+		Field:   errs[0].Field,
+		Message: errs[0].Message,
+	}
+}
+
+// validateBatchSize checks that a batch operation has at least one item and at most limit.
+// Every batch operation uses it so they all reject oversized requests the same way.
+func validateBatchSize(v *validator.Validator, field string, size, limit int) {
+	if size == 0 {
+		v.AddError(field, "at least one item is required")
+	} else if size > limit {
+		v.AddError(field, fmt.Sprintf("must not exceed the batch limit of %d (got %d)", limit, size))
+	}
+}
+
+// logError logs a failed data access call. Client cancellations and deadlines are logged
+// as warnings since they are not server faults.
+func (u *deliveryUseCase) logError(ctx context.Context, msg string, err error, fields ...zap.Field) {
+	fields = append([]zap.Field{zap.Error(err)}, fields...)
+	if isContextError(err) {
+		u.log(ctx).Warn(msg, fields...)
+		return
+	}
+	u.log(ctx).Error(msg, fields...)
+}
+
+// log returns the logger for the request carrying ctx, tagged with its trace ID
+func (u *deliveryUseCase) log(ctx context.Context) *zap.Logger {
+	if traceID := domain.TraceIDFromContext(ctx); traceID != "" {
+		return u.logger.With(zap.String("trace_id", traceID))
+	}
+	return u.logger
+}
+
+// notifyStatusChange tells the notifier that assignment's status has been saved
+func (u *deliveryUseCase) notifyStatusChange(ctx context.Context, assignment *domain.DeliveryAssignment) {
+	u.notify(ctx, StatusChange{
+		DeliveryID: assignment.ID,
+		OrderID:    assignment.OrderID,
+		DriverID:   assignment.DriverID,
+		Status:     assignment.Status,
+		ChangedAt:  assignment.UpdatedAt,
+		DriverETA:  assignment.DriverETA,
+		Priority:   assignment.Priority,
+
+		RecipientEmail: assignment.RecipientEmail,
+		NotifyPrefs:    assignment.NotifyPrefs,
+	})
+}
+
+// notifyETAUpdate tells the notifier that assignment's driver ETA has been saved
+func (u *deliveryUseCase) notifyETAUpdate(ctx context.Context, assignment *domain.DeliveryAssignment) {
+	u.notify(ctx, StatusChange{
+		DeliveryID: assignment.ID,
+		OrderID:    assignment.OrderID,
+		DriverID:   assignment.DriverID,
+		Status:     assignment.Status,
+		ChangedAt:  assignment.UpdatedAt,
+		DriverETA:  assignment.DriverETA,
+		ETAUpdate:  true,
+
+		RecipientEmail: assignment.RecipientEmail,
+		NotifyPrefs:    assignment.NotifyPrefs,
+	})
+}
+
+// ReplayEvents re-sends a delivery's status changes to the notifier, marked as replays, for
+// consumers that missed them. Watchers are not sent replays. The changes are derived from the
+// delivery's stored lifecycle timestamps (see replayChanges); it returns how many were sent.
+func (u *deliveryUseCase) ReplayEvents(ctx context.Context, id uuid.UUID) (int, error) {
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	changes := replayChanges(assignment)
+	for _, change := range changes {
+		u.config.Notifier.NotifyStatusChange(ctx, change)
+	}
+
+	u.log(ctx).Info("Replayed delivery events",
+		zap.String("id", id.String()),
+		zap.Int("events", len(changes)),
+	)
+
+	return len(changes), nil
+}
+
+// replayChanges reconstructs a delivery's status changes, oldest first: PENDING at creation,
+// PICKED_UP and DELIVERED at their actual times, and the current status at the last update.
+// Statuses without a stored time (such as ASSIGNED or IN_TRANSIT) are only included when current.
+func replayChanges(assignment *domain.DeliveryAssignment) []StatusChange {
+	change := func(status domain.DeliveryStatus, at time.Time) StatusChange {
+		return StatusChange{
+			DeliveryID:     assignment.ID,
+			OrderID:        assignment.OrderID,
+			DriverID:       assignment.DriverID,
+			Status:         status,
+			ChangedAt:      at,
+			RecipientEmail: assignment.RecipientEmail,
+			NotifyPrefs:    assignment.NotifyPrefs,
+			Replay:         true,
+		}
+	}
+
+	changes := []StatusChange{change(domain.DeliveryStatusPending, assignment.CreatedAt)}
+	if assignment.ActualPickupTime != nil {
+		changes = append(changes, change(domain.DeliveryStatusPickedUp, *assignment.ActualPickupTime))
+	}
+	if assignment.ActualDeliveryTime != nil {
+		changes = append(changes, change(domain.DeliveryStatusDelivered, *assignment.ActualDeliveryTime))
+	}
+	if last := changes[len(changes)-1]; last.Status != assignment.Status.Canonical() {
+		changes = append(changes, change(assignment.Status, assignment.UpdatedAt))
+	}
+	return changes
+}
+
+// notify sends change to the notifier and, when watching is enabled, the broadcaster
+func (u *deliveryUseCase) notify(ctx context.Context, change StatusChange) {
+	u.config.Notifier.NotifyStatusChange(ctx, change)
+	if u.config.Broadcaster != nil {
+		u.config.Broadcaster.NotifyStatusChange(ctx, change)
+	}
+}
+
+// audit stamps entry with the time and, when the request carries them, its actor and trace ID,
+// and sends it to the audit logger. The mutation is already saved, so failures are only logged.
+func (u *deliveryUseCase) audit(ctx context.Context, entry AuditEntry) {
+	entry.Time = u.config.Clock.Now()
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		entry.UserID = actor.UserID
+		entry.TenantID = actor.TenantID
+	}
+	entry.TraceID = domain.TraceIDFromContext(ctx)
+
+	if err := u.config.AuditLogger.Audit(ctx, entry); err != nil {
+		u.log(ctx).Error("Failed to write audit entry",
+			zap.Error(err),
+			zap.String("action", entry.Action),
+			zap.String("id", entry.DeliveryID),
+		)
 	}
 }
 
+// auditChange audits a saved change to assignment; before is its state captured ahead of the
+// change, nil for a delivery that was just created
+func (u *deliveryUseCase) auditChange(ctx context.Context, action string, before auditState, assignment *domain.DeliveryAssignment) {
+	u.audit(ctx, AuditEntry{
+		Action:     action,
+		DeliveryID: assignment.ID.String(),
+		OrderID:    assignment.OrderID,
+		Changes:    diffAuditState(before, captureAuditState(assignment)),
+	})
+}
+
+// isContextError reports whether err was caused by a canceled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // CreateDeliveryAssignment creates a new delivery assignment
 func (u *deliveryUseCase) CreateDeliveryAssignment(ctx context.Context, input CreateDeliveryInput) (*domain.DeliveryAssignment, error) {
+	assignment, err := u.newAssignment(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to repository
+	if err := u.repo.Create(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to create delivery assignment", err,
+			zap.String("order_id", assignment.OrderID),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpCreate, nil, assignment)
+
+	return assignment, nil
+}
+
+// newAssignment validates input and builds the delivery assignment it describes, without saving it
+func (u *deliveryUseCase) newAssignment(ctx context.Context, input CreateDeliveryInput) (*domain.DeliveryAssignment, error) {
+	input.OrderID = u.normalizeID(input.OrderID)
+
 	// Validate input
 	if input.OrderID == "" {
 		return nil, domain.ErrInvalidInput
@@ -66,8 +452,63 @@ func (u *deliveryUseCase) CreateDeliveryAssignment(ctx context.Context, input Cr
 		return nil, domain.ErrInvalidInput
 	}
 
+	input.Notes = u.applyDefaultNotes(input.Notes)
+	if err := u.validateNotes(input.Notes); err != nil {
+		return nil, err
+	}
+
+	// Enforce the scheduling horizon
+	v := validator.New()
+	minAdvance := u.config.MinScheduleAdvance
+	if input.AllowPastScheduling {
+		if !u.config.AllowPastScheduling {
+			v.AddError("allow_past_scheduling", "is disabled on this server")
+		}
+		minAdvance = math.MinInt64 // Backfilled pickups may be any distance in the past
+	}
+	v.ValidateTimeRangeFrom("scheduled_pickup_time", input.ScheduledPickupTime, u.config.Clock.Now(),
+		minAdvance, u.config.MaxScheduleAdvance)
+	v.ValidateTimeZone("time_zone", input.TimeZone)
+	v.ValidateAddressCoordinates("pickup_address", input.PickupAddress.Latitude, input.PickupAddress.Longitude)
+	v.ValidateAddressCoordinates("delivery_address", input.DeliveryAddress.Latitude, input.DeliveryAddress.Longitude)
+	u.validateBusinessHours(v, input.TimeZone, input.ScheduledPickupTime, input.EstimatedDeliveryTime)
+	input.RequiredVehicleType = normalizeVehicleType(input.RequiredVehicleType)
+	if input.RequiredVehicleType != nil {
+		v.ValidateEnum("required_vehicle_type", *input.RequiredVehicleType, u.allowedVehicleTypes())
+	}
+	validateDeliveryWindow(v, input.EstimatedDeliveryWindowStart, input.EstimatedDeliveryWindowEnd)
+	input.Priority = domain.DeliveryPriority(strings.ToUpper(strings.TrimSpace(string(input.Priority))))
+	if input.Priority != "" && !input.Priority.IsValid() {
+		v.AddError("priority", "must be one of: LOW, NORMAL, HIGH")
+	}
+	input.Tags = normalizeTags(input.Tags)
+	validateTags(v, input.Tags)
+	input.RecipientEmail = normalizeEmail(input.RecipientEmail)
+	if input.RecipientEmail != nil {
+		v.ValidateEmail("recipient_email", *input.RecipientEmail)
+	}
+	input.NotifyPrefs = normalizeNotifyPrefs(input.NotifyPrefs)
+	for _, status := range input.NotifyPrefs {
+		v.ValidateEnum("notify_prefs", status, allStatuses())
+	}
+	if len(input.Metadata) > 0 {
+		actor, _ := domain.ActorFromContext(ctx)
+		for _, e := range u.config.MetadataValidator.ValidateMetadata(actor.TenantID, input.Metadata) {
+			v.AddError(e.Field, e.Message)
+		}
+	}
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkActivePerOrder(ctx, input.OrderID); err != nil {
+		return nil, err
+	}
+
 	// Create entity
-	assignment := domain.NewDeliveryAssignment(
+	assignment, err := domain.NewDeliveryAssignmentWithIDs(
+		u.config.Clock,
+		u.config.IDGenerator,
 		input.OrderID,
 		input.PickupAddress,
 		input.DeliveryAddress,
@@ -75,147 +516,1761 @@ func (u *deliveryUseCase) CreateDeliveryAssignment(ctx context.Context, input Cr
 		input.EstimatedDeliveryTime,
 		input.Notes,
 	)
-
-	// Save to repository
-	if err := u.repo.Create(ctx, assignment); err != nil {
-		u.logger.Error("Failed to create delivery assignment",
-			zap.Error(err),
-			zap.String("order_id", input.OrderID),
-		)
+	if err != nil {
+		u.logError(ctx, "Failed to create delivery assignment", err)
+		return nil, err
+	}
+	assignment.TimeZone = input.TimeZone
+	assignment.AllowConcurrent = input.AllowConcurrent
+	assignment.RequiredVehicleType = input.RequiredVehicleType
+	if input.Priority != "" {
+		assignment.Priority = input.Priority
+	}
+	assignment.RecipientEmail = input.RecipientEmail
+	if len(input.NotifyPrefs) > 0 {
+		assignment.NotifyPrefs = input.NotifyPrefs
+	}
+	if len(input.Metadata) > 0 {
+		assignment.Metadata = input.Metadata
+	}
+	if _, err := assignment.AddTags(input.Tags...); err != nil {
 		return nil, err
 	}
+	if input.EstimatedDeliveryWindowStart != nil {
+		assignment.EstimatedDeliveryWindowStart = input.EstimatedDeliveryWindowStart
+		assignment.EstimatedDeliveryWindowEnd = input.EstimatedDeliveryWindowEnd
+	} else {
+		assignment.DeriveDeliveryWindow(u.config.DeliveryWindowSlack)
+	}
 
 	return assignment, nil
 }
 
-// GetDeliveryAssignment retrieves a delivery assignment by ID
-func (u *deliveryUseCase) GetDeliveryAssignment(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
-	assignment, err := u.repo.GetByID(ctx, id)
-	if err != nil {
-		u.logger.Error("Failed to get delivery assignment",
-			zap.Error(err),
-			zap.String("id", id.String()),
-		)
+// ImportDeliveries creates a delivery for every input that passes the CreateDeliveryAssignment
+// checks. Valid rows are inserted in transactions of constants.ImportBatchSize; when a batch is
+// rejected its rows are retried one by one, so a bad row only fails itself. Rows rejected as
+// invalid, duplicate or conflicting are reported in the result; any other failure stops the
+// import, leaving the batches saved so far in place.
+//
+// A file may be imported in several calls. fileRows is the number of rows of the file received
+// so far, inputs included; once it exceeds Config.Limits.ImportRows the call is rejected without
+// saving anything, while rows saved by earlier calls stay.
+func (u *deliveryUseCase) ImportDeliveries(ctx context.Context, inputs []CreateDeliveryInput, fileRows int) (*ImportResult, error) {
+	v := validator.New()
+	validateBatchSize(v, "rows", max(fileRows, len(inputs)), u.config.Limits.ImportRows)
+	if err := toValidationError(v); err != nil {
 		return nil, err
 	}
 
-	return assignment, nil
+	result := &ImportResult{}
+	assignments := make([]*domain.DeliveryAssignment, 0, len(inputs))
+	rows := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		assignment, err := u.newAssignment(ctx, input)
+		if err != nil {
+			if !isImportRowError(err) {
+				return nil, err
+			}
+			result.Errors = append(result.Errors, ImportRowError{Row: i, Err: err})
+			continue
+		}
+		assignments = append(assignments, assignment)
+		rows = append(rows, i)
+	}
+
+	for start := 0; start < len(assignments); start += constants.ImportBatchSize {
+		end := min(start+constants.ImportBatchSize, len(assignments))
+		if err := u.importBatch(ctx, assignments[start:end], rows[start:end], result); err != nil {
+			u.logError(ctx, "Failed to import deliveries", err,
+				zap.Int("imported", result.Succeeded),
+			)
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(result.Created, func(a, b ImportedDelivery) int { return a.Row - b.Row })
+	slices.SortFunc(result.Errors, func(a, b ImportRowError) int { return a.Row - b.Row })
+	return result, nil
 }
 
-// UpdateDeliveryStatus updates the status of a delivery assignment
-func (u *deliveryUseCase) UpdateDeliveryStatus(ctx context.Context, id uuid.UUID, status domain.DeliveryStatus, notes string) (*domain.DeliveryAssignment, error) {
-	// Get existing assignment
-	assignment, err := u.repo.GetByID(ctx, id)
+// importBatch saves assignments in one transaction, falling back to saving them one at a time
+// when a row is rejected. rows holds the input index of each assignment.
+func (u *deliveryUseCase) importBatch(ctx context.Context, assignments []*domain.DeliveryAssignment, rows []int, result *ImportResult) error {
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		for _, assignment := range assignments {
+			if err := repo.Create(ctx, assignment); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for i, assignment := range assignments {
+			result.imported(rows[i], assignment)
+			u.auditChange(ctx, constants.OpImport, nil, assignment)
+		}
+		return nil
+	}
+	if !isImportRowError(err) {
+		return err
+	}
+
+	for i, assignment := range assignments {
+		if err := u.repo.Create(ctx, assignment); err != nil {
+			if !isImportRowError(err) {
+				return err
+			}
+			result.Errors = append(result.Errors, ImportRowError{Row: rows[i], Err: err})
+			continue
+		}
+		result.imported(rows[i], assignment)
+		u.auditChange(ctx, constants.OpImport, nil, assignment)
+	}
+	return nil
+}
+
+// imported records that the input at row was saved as assignment
+func (r *ImportResult) imported(row int, assignment *domain.DeliveryAssignment) {
+	r.Succeeded++
+	r.Created = append(r.Created, ImportedDelivery{Row: row, ID: assignment.ID, PickupCode: assignment.PickupCode})
+}
+
+// isImportRowError reports whether err rejects a single imported row rather than the whole import
+func isImportRowError(err error) bool {
+	return errors.Is(err, domain.ErrInvalidInput) ||
+		errors.Is(err, domain.ErrAlreadyExists) ||
+		errors.Is(err, domain.ErrConflict)
+}
+
+// validateBusinessHours requires the pickup and delivery estimate to fall within business hours
+// in timeZone
+func (u *deliveryUseCase) validateBusinessHours(v *validator.Validator, timeZone string, pickup, delivery time.Time) {
+	u.validateWithinBusinessHours(v, "scheduled_pickup_time", timeZone, pickup)
+	u.validateWithinBusinessHours(v, "estimated_delivery_time", timeZone, delivery)
+}
+
+// validateWithinBusinessHours requires t, reported as field, to fall within business hours in
+// timeZone. An invalid time zone is reported by ValidateTimeZone, so it is skipped here.
+func (u *deliveryUseCase) validateWithinBusinessHours(v *validator.Validator, field, timeZone string, t time.Time) {
+	if len(u.config.BusinessHours) == 0 || timeZone == "Local" {
+		return
+	}
+	loc, err := time.LoadLocation(timeZone) // Empty means UTC
 	if err != nil {
-		return nil, err
+		return
 	}
 
-	// Update status using domain logic
-	if err := assignment.UpdateStatus(status); err != nil {
-		u.logger.Error("Failed to update status",
-			zap.Error(err),
-			zap.String("id", id.String()),
-			zap.String("current_status", string(assignment.Status)),
-			zap.String("new_status", string(status)),
-		)
-		return nil, err
+	if !u.config.BusinessHours.Contains(t, loc) {
+		v.AddError(field, "is outside business hours")
 	}
+}
 
-	// Update notes if provided
-	if notes != "" {
-		assignment.Notes = notes
+// validateDeliveryWindow requires an explicit window to have both bounds with start before end
+func validateDeliveryWindow(v *validator.Validator, start, end *time.Time) {
+	switch {
+	case start == nil && end == nil:
+		return
+	case start == nil:
+		v.AddError("estimated_delivery_window_start", "is required when estimated_delivery_window_end is set")
+	case end == nil:
+		v.AddError("estimated_delivery_window_end", "is required when estimated_delivery_window_start is set")
+	case !end.After(*start):
+		v.AddError("estimated_delivery_window_end", "must be after estimated_delivery_window_start")
 	}
+}
 
-	// Save changes
-	if err := u.repo.Update(ctx, assignment); err != nil {
-		u.logger.Error("Failed to update delivery assignment",
-			zap.Error(err),
-			zap.String("id", id.String()),
-		)
-		return nil, err
+// normalizeVehicleType trims and upper-cases a vehicle type; blank means no requirement
+func normalizeVehicleType(vehicleType *string) *string {
+	if vehicleType == nil {
+		return nil
+	}
+	normalized := normalizeCode(*vehicleType)
+	if normalized == "" {
+		return nil
 	}
+	return &normalized
+}
 
-	return assignment, nil
+// normalizeCode trims and upper-cases a configurable enum value such as a failure reason code
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
 }
 
-// ListDeliveryAssignments retrieves delivery assignments with pagination
-func (u *deliveryUseCase) ListDeliveryAssignments(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, int64, error) {
-	// Set defaults
-	if input.Page < 1 {
-		input.Page = 1
+// normalizeTag trims and lower-cases a tag so "VIP" and " vip" are the same tag
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags normalizes tags and drops duplicates, keeping the first occurrence
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// normalizeEmail trims an email address; blank means none
+func normalizeEmail(email *string) *string {
+	if email == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*email)
+	if trimmed == "" {
+		return nil
 	}
-	if input.PageSize < 1 || input.PageSize > 100 {
-		input.PageSize = 20
+	return &trimmed
+}
+
+// normalizeNotifyPrefs canonicalizes and de-duplicates notification statuses, keeping their order
+func normalizeNotifyPrefs(statuses []domain.DeliveryStatus) domain.NotifyPrefs {
+	normalized := make(domain.NotifyPrefs, 0, len(statuses))
+	for _, status := range statuses {
+		status = status.Canonical()
+		if !slices.Contains(normalized, status) {
+			normalized = append(normalized, status)
+		}
+	}
+	return normalized
+}
+
+// allStatuses returns every delivery status in the form ValidateEnum expects
+func allStatuses() []interface{} {
+	statuses := domain.AllStatuses()
+	allowed := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		allowed[i] = status
+	}
+	return allowed
+}
+
+// validateTags checks normalized tags are non-empty and not too long
+func validateTags(v *validator.Validator, tags []string) {
+	for _, tag := range tags {
+		v.ValidateRequired("tags", tag)
+		v.ValidateStringLength("tags", tag, 0, constants.MaxTagLength)
 	}
+}
+
+// allowedVehicleTypes returns the configured vehicle types, normalized, in the form ValidateEnum expects
+func (u *deliveryUseCase) allowedVehicleTypes() []interface{} {
+	allowed := make([]interface{}, 0, len(u.config.AllowedVehicleTypes))
+	for _, vehicleType := range u.config.AllowedVehicleTypes {
+		if normalized := normalizeVehicleType(&vehicleType); normalized != nil {
+			allowed = append(allowed, *normalized)
+		}
+	}
+	return allowed
+}
 
-	filters := ListFilters(input)
+// allowedFailureReasonCodes returns the configured failure reason codes, normalized for ValidateEnum
+func (u *deliveryUseCase) allowedFailureReasonCodes() []interface{} {
+	allowed := make([]interface{}, 0, len(u.config.FailureReasonCodes))
+	for _, code := range u.config.FailureReasonCodes {
+		if normalized := normalizeCode(code); normalized != "" {
+			allowed = append(allowed, normalized)
+		}
+	}
+	return allowed
+}
+
+// checkActivePerOrder rejects creation once an order already has the configured number of
+// non-terminal deliveries, which usually points at a client retry bug
+func (u *deliveryUseCase) checkActivePerOrder(ctx context.Context, orderID string) error {
+	if u.config.MaxActivePerOrder <= 0 {
+		return nil
+	}
 
-	assignments, totalCount, err := u.repo.List(ctx, filters)
+	existing, err := u.repo.GetByOrderID(ctx, orderID)
 	if err != nil {
-		u.logger.Error("Failed to list delivery assignments", zap.Error(err))
-		return nil, 0, err
+		u.logError(ctx, "Failed to get delivery assignments for order", err,
+			zap.String("order_id", orderID),
+		)
+		return err
 	}
 
-	return assignments, totalCount, nil
+	return u.limitActivePerOrder(ctx, orderID, existing, constants.OpCreate)
 }
 
-// AssignDriver assigns a driver to a delivery assignment
-func (u *deliveryUseCase) AssignDriver(ctx context.Context, id uuid.UUID, driverID string) (*domain.DeliveryAssignment, error) {
-	// Validate driver ID
-	if driverID == "" {
-		return nil, domain.ErrInvalidInput
+// limitActivePerOrder rejects op, which adds a delivery to orderID, when the order's existing
+// deliveries already include the configured number of non-terminal ones
+func (u *deliveryUseCase) limitActivePerOrder(ctx context.Context, orderID string, existing []*domain.DeliveryAssignment, op string) error {
+	if u.config.MaxActivePerOrder <= 0 {
+		return nil
 	}
 
-	// Get existing assignment
+	active := 0
+	for _, assignment := range existing {
+		if assignment.Status.IsActive() {
+			active++
+		}
+	}
+
+	if active >= u.config.MaxActivePerOrder {
+		u.log(ctx).Warn("Rejected delivery creation: too many active deliveries for order",
+			zap.String("order_id", orderID),
+			zap.String("operation", op),
+			zap.Int("active", active),
+			zap.Int("limit", u.config.MaxActivePerOrder),
+		)
+		return &domain.ConflictError{
+			Resource:     constants.ResourceOrder,
+			CurrentState: fmt.Sprintf("%d active deliveries", active),
+			RequestedOp:  op,
+			Message:      fmt.Sprintf("order already has the maximum of %d active deliveries", u.config.MaxActivePerOrder),
+		}
+	}
+
+	return nil
+}
+
+// GetDeliveryAssignment retrieves a delivery assignment by ID
+func (u *deliveryUseCase) GetDeliveryAssignment(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
 	assignment, err := u.repo.GetByID(ctx, id)
 	if err != nil {
+		u.logError(ctx, "Failed to get delivery assignment", err,
+			zap.String("id", id.String()),
+		)
 		return nil, err
 	}
 
-	// Assign driver using domain logic
-	if err := assignment.AssignDriver(driverID); err != nil {
-		u.logger.Error("Failed to assign driver",
-			zap.Error(err),
+	return assignment, nil
+}
+
+// GetDeliveryStatus retrieves only the current status of a delivery assignment
+func (u *deliveryUseCase) GetDeliveryStatus(ctx context.Context, id uuid.UUID) (*domain.DeliveryStatusInfo, error) {
+	info, err := u.repo.GetStatusByID(ctx, id)
+	if err != nil {
+		u.logError(ctx, "Failed to get delivery status", err,
 			zap.String("id", id.String()),
-			zap.String("driver_id", driverID),
 		)
 		return nil, err
 	}
 
-	// Save changes
-	if err := u.repo.Update(ctx, assignment); err != nil {
-		u.logger.Error("Failed to update delivery assignment",
-			zap.Error(err),
+	return info, nil
+}
+
+// GetSLADeadline retrieves a delivery's SLA deadline and the time remaining until it, which is
+// zero once the deadline has passed
+func (u *deliveryUseCase) GetSLADeadline(ctx context.Context, id uuid.UUID) (*domain.DeliverySLA, error) {
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		u.logError(ctx, "Failed to get delivery SLA deadline", err,
 			zap.String("id", id.String()),
 		)
 		return nil, err
 	}
 
-	return assignment, nil
+	deadline := assignment.SLADeadline()
+	return &domain.DeliverySLA{
+		ID:        assignment.ID,
+		Deadline:  deadline,
+		Remaining: max(deadline.Sub(u.config.Clock.Now()), 0),
+	}, nil
 }
 
-// GetDeliveryMetrics retrieves delivery metrics
-func (u *deliveryUseCase) GetDeliveryMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string) (*domain.DeliveryMetrics, error) {
-	// Validate time range
-	if startTime.After(endTime) {
-		return nil, domain.ErrInvalidInput
+// BatchGetDeliveryAssignments retrieves multiple delivery assignments by ID.
+// Duplicate IDs are collapsed and IDs that do not exist are simply absent from the result.
+func (u *deliveryUseCase) BatchGetDeliveryAssignments(ctx context.Context, ids []uuid.UUID) ([]*domain.DeliveryAssignment, error) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	// Validate input
+	v := validator.New()
+	validateBatchSize(v, "ids", len(unique), u.config.Limits.BatchGet)
+	if err := toValidationError(v); err != nil {
+		return nil, err
 	}
 
-	metrics, err := u.repo.GetMetrics(ctx, startTime, endTime, driverID)
+	assignments, err := u.repo.GetByIDs(ctx, unique)
 	if err != nil {
-		u.logger.Error("Failed to get delivery metrics", zap.Error(err))
+		u.logError(ctx, "Failed to batch get delivery assignments", err,
+			zap.Int("count", len(unique)),
+		)
 		return nil, err
 	}
 
-	return metrics, nil
+	return assignments, nil
 }
 
-func (u *deliveryUseCase) DeleteDeliveryAssignment(ctx context.Context, id uuid.UUID) error {
+// CloneDeliveryAssignment creates a new PENDING delivery with the source's order, addresses and
+// notes but fresh times. Driver, status, actual times and feedback are not carried over.
+func (u *deliveryUseCase) CloneDeliveryAssignment(ctx context.Context, id uuid.UUID, scheduledPickupTime, estimatedDeliveryTime time.Time) (*domain.DeliveryAssignment, error) {
+	source, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse the create path so the clone gets the same validation as a new delivery
+	return u.CreateDeliveryAssignment(ctx, CreateDeliveryInput{
+		OrderID:               source.OrderID,
+		PickupAddress:         source.PickupAddress,
+		DeliveryAddress:       source.DeliveryAddress,
+		ScheduledPickupTime:   scheduledPickupTime,
+		EstimatedDeliveryTime: estimatedDeliveryTime,
+		Notes:                 source.Notes,
+		TimeZone:              source.TimeZone,
+		AllowConcurrent:       source.AllowConcurrent,
+		RequiredVehicleType:   source.RequiredVehicleType,
+		Priority:              source.Priority,
+		Tags:                  source.Tags,
+		RecipientEmail:        source.RecipientEmail,
+		NotifyPrefs:           source.NotifyPrefs,
+		Metadata:              source.Metadata,
+	})
+}
+
+// UpdateDeliveryStatus updates the status of a delivery assignment. pickupCode is only used, and
+// then required, when moving to PICKED_UP; failureReasonCode likewise when moving to FAILED, with
+// notes holding the free-text details. A delivery failing its last allowed attempt
+// (Config.MaxAttempts) is moved on to DEAD_LETTER in the same update. Wrong pickup codes are
+// saved as they count towards Config.PickupLockout, after which pickup is refused for a while.
+func (u *deliveryUseCase) UpdateDeliveryStatus(ctx context.Context, id uuid.UUID, status domain.DeliveryStatus, notes, pickupCode, failureReasonCode string) (*domain.DeliveryAssignment, error) {
+	if err := u.validateNotes(notes); err != nil {
+		return nil, err
+	}
+
+	if status == domain.DeliveryStatusFailed {
+		failureReasonCode = normalizeCode(failureReasonCode)
+		v := validator.New()
+		if failureReasonCode != "" {
+			v.ValidateEnum("failure_reason_code", failureReasonCode, u.allowedFailureReasonCodes())
+		}
+		if err := toValidationError(v); err != nil {
+			return nil, err
+		}
+	}
+
+	// The delivery is locked so that concurrent updates, and pickup code guesses in particular, are
+	// applied one at a time
+	var (
+		assignment *domain.DeliveryAssignment
+		before     auditState
+		unchanged  bool
+		saved      bool
+		statusErr  error
+	)
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		locked, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		locked.SetClock(u.config.Clock)
+		before = captureAuditState(locked)
+		unchanged = locked.Status == status
+		assignment = locked
+
+		// Update status using domain logic; pickups must present the code and failures a reason
+		switch status {
+		case domain.DeliveryStatusPickedUp:
+			err = locked.ConfirmPickup(pickupCode, u.config.PickupLockout)
+		case domain.DeliveryStatusFailed:
+			err = locked.Fail(failureReasonCode)
+		default:
+			err = locked.UpdateStatus(status)
+		}
+		if err != nil {
+			u.log(ctx).Error("Failed to update status",
+				zap.Error(err),
+				zap.String("id", id.String()),
+				zap.String("current_status", string(locked.Status)),
+				zap.String("new_status", string(status)),
+			)
+			// A wrong pickup code still counts towards the lockout, so it is saved before the
+			// error is returned
+			if len(diffAuditState(before, captureAuditState(locked))) == 0 {
+				return err
+			}
+			statusErr = err
+			saved = true
+			return repo.Update(ctx, locked)
+		}
+
+		// A failed last attempt will never be reattempted, so it goes straight to DEAD_LETTER
+		if status == domain.DeliveryStatusFailed && !unchanged {
+			deadLettered, err := u.deadLetterExhausted(locked)
+			if err != nil {
+				return err
+			}
+			if deadLettered {
+				u.log(ctx).Info("Delivery dead-lettered after its last attempt",
+					zap.String("id", id.String()),
+					zap.Int("attempt_number", locked.AttemptNumber),
+				)
+			}
+		}
+
+		// Update notes if provided
+		if notes != "" {
+			locked.Notes = notes
+		}
+
+		// A retried request for the current status has nothing to persist
+		if unchanged && notes == "" {
+			return nil
+		}
+
+		saved = true
+		return repo.Update(ctx, locked)
+	})
+	if err != nil {
+		if saved {
+			u.logError(ctx, "Failed to update delivery assignment", err,
+				zap.String("id", id.String()),
+			)
+		}
+		return nil, err
+	}
+	if saved {
+		u.auditChange(ctx, constants.OpUpdateStatus, before, assignment)
+	}
+	if statusErr != nil {
+		return nil, statusErr
+	}
+
+	if !unchanged {
+		u.notifyStatusChange(ctx, assignment)
+	}
+
+	return assignment, nil
+}
+
+// ListDeliveryAssignments retrieves delivery assignments with pagination
+func (u *deliveryUseCase) ListDeliveryAssignments(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, ListTotal, error) {
+	filters, err := u.listFilters(input)
+	if err != nil {
+		return nil, ListTotal{}, err
+	}
+
+	assignments, total, err := u.repo.List(ctx, filters)
+	if err != nil {
+		u.logError(ctx, "Failed to list delivery assignments", err)
+		return nil, ListTotal{}, err
+	}
+
+	return assignments, total, nil
+}
+
+// ListDeliveryAssignmentsWithStatusBreakdown lists delivery assignments like ListDeliveryAssignments
+// and also counts all matching deliveries per status, consistently with the page
+func (u *deliveryUseCase) ListDeliveryAssignmentsWithStatusBreakdown(ctx context.Context, input ListDeliveryInput) ([]*domain.DeliveryAssignment, ListTotal, map[domain.DeliveryStatus]int64, error) {
+	filters, err := u.listFilters(input)
+	if err != nil {
+		return nil, ListTotal{}, nil, err
+	}
+
+	assignments, total, breakdown, err := u.repo.ListWithStatusBreakdown(ctx, filters)
+	if err != nil {
+		u.logError(ctx, "Failed to list delivery assignments", err)
+		return nil, ListTotal{}, nil, err
+	}
+
+	return assignments, total, breakdown, nil
+}
+
+// listFilters validates list input, applies pagination defaults and normalizes the filters
+func (u *deliveryUseCase) listFilters(input ListDeliveryInput) (ListFilters, error) {
+	// Reject clearly invalid pagination instead of silently clamping it
+	v := validator.New()
+	if input.Page < 0 {
+		v.AddError("page", "must not be negative")
+	}
+	if input.PageSize < 0 {
+		v.AddError("page_size", "must not be negative")
+	}
+	if err := toValidationError(v); err != nil {
+		return ListFilters{}, err
+	}
+
+	// Set defaults
+	if input.Page < constants.DefaultPage {
+		input.Page = constants.DefaultPage
+	}
+	if input.PageSize < constants.MinPageSize || input.PageSize > constants.MaxPageSize {
+		input.PageSize = constants.DefaultPageSize
+	}
+
+	input.DriverID = u.normalizeOptionalID(input.DriverID)
+	input.RequiredVehicleType = normalizeVehicleType(input.RequiredVehicleType)
+	if input.Tag != nil {
+		tag := normalizeTag(*input.Tag)
+		input.Tag = &tag
+	}
+
+	return ListFilters(input), nil
+}
+
+// AssignDriver assigns a driver to a delivery assignment
+func (u *deliveryUseCase) AssignDriver(ctx context.Context, id uuid.UUID, driverID string) (*domain.DeliveryAssignment, error) {
+	driverID = u.normalizeID(driverID)
+
+	// Validate driver ID
+	if driverID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+	previousStatus := assignment.Status
+
+	// Assign driver using domain logic
+	if err := assignment.AssignDriver(driverID); err != nil {
+		u.log(ctx).Error("Failed to assign driver",
+			zap.Error(err),
+			zap.String("id", id.String()),
+			zap.String("driver_id", driverID),
+		)
+		return nil, err
+	}
+
+	// Save changes
+	if err := u.repo.Update(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to update delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpAssignDriver, before, assignment)
+
+	if assignment.Status != previousStatus {
+		u.notifyStatusChange(ctx, assignment)
+	}
+
+	return assignment, nil
+}
+
+// AssignBackupDriver sets the driver who takes over a delivery if its primary driver is
+// unassigned. The delivery must already have a primary driver.
+func (u *deliveryUseCase) AssignBackupDriver(ctx context.Context, id uuid.UUID, driverID string) (*domain.DeliveryAssignment, error) {
+	driverID = u.normalizeID(driverID)
+	if driverID == "" {
+		return nil, &domain.ValidationError{Field: "driver_id", Message: "is required"}
+	}
+
+	// Locked so the primary driver cannot be unassigned or change in between: the backup is only
+	// ever set on the delivery as it was checked
+	var (
+		assignment *domain.DeliveryAssignment
+		before     auditState
+	)
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		locked, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		locked.SetClock(u.config.Clock)
+		before = captureAuditState(locked)
+
+		if err := locked.AssignBackupDriver(driverID); err != nil {
+			u.log(ctx).Warn("Failed to assign backup driver",
+				zap.Error(err),
+				zap.String("id", id.String()),
+				zap.String("driver_id", driverID),
+			)
+			return err
+		}
+
+		if err := repo.Update(ctx, locked); err != nil {
+			u.logError(ctx, "Failed to update delivery assignment", err,
+				zap.String("id", id.String()),
+			)
+			return err
+		}
+		assignment = locked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpAssignBackupDriver, before, assignment)
+
+	return assignment, nil
+}
+
+// GetDeliveryMetrics retrieves delivery metrics
+func (u *deliveryUseCase) GetDeliveryMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string) (*domain.DeliveryMetrics, error) {
+	// Validate time range
+	if startTime.After(endTime) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	driverID = u.normalizeOptionalID(driverID)
+
+	release, err := u.metrics.acquire(ctx)
+	if err != nil {
+		u.log(ctx).Warn("Rejected delivery metrics query", zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	metrics, err := u.repo.GetMetrics(ctx, startTime, endTime, driverID, u.config.OnTimeGracePeriod)
+	if err != nil {
+		u.logError(ctx, "Failed to get delivery metrics", err)
+		return nil, err
+	}
+
+	if len(metrics.Errors) > 0 {
+		u.log(ctx).Warn("Returning partial delivery metrics", zap.Strings("errors", metrics.Errors))
+	}
+
+	return metrics, nil
+}
+
+// RefreshMetricsSummary recomputes the daily metrics summary of the UTC day containing day.
+// Only completed days can be summarized; the current day is always computed live.
+func (u *deliveryUseCase) RefreshMetricsSummary(ctx context.Context, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	if day.Add(24 * time.Hour).After(u.config.Clock.Now()) {
+		return &domain.ValidationError{Field: "day", Message: "only completed days can be summarized"}
+	}
+
+	if err := u.repo.RefreshMetricsSummary(ctx, day, u.config.OnTimeGracePeriod); err != nil {
+		u.logError(ctx, "Failed to refresh metrics summary", err, zap.Time("day", day))
+		return err
+	}
+
+	return nil
+}
+
+// GetMetricsForDrivers retrieves metrics for several drivers at once, keyed by driver ID.
+// Duplicate IDs are collapsed; drivers without deliveries in the range get zero metrics.
+func (u *deliveryUseCase) GetMetricsForDrivers(ctx context.Context, driverIDs []string, startTime, endTime time.Time) (map[string]*domain.DeliveryMetrics, error) {
+	unique := make([]string, 0, len(driverIDs))
+	for _, driverID := range driverIDs {
+		driverID = u.normalizeID(driverID)
+		if driverID != "" && !slices.Contains(unique, driverID) {
+			unique = append(unique, driverID)
+		}
+	}
+
+	// Validate input
+	v := validator.New()
+	if startTime.After(endTime) {
+		v.AddError("start_time", "must not be after end_time")
+	}
+	validateBatchSize(v, "driver_ids", len(unique), u.config.Limits.DriverMetrics)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	release, err := u.metrics.acquire(ctx)
+	if err != nil {
+		u.log(ctx).Warn("Rejected metrics query for drivers", zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	metrics, err := u.repo.GetMetricsForDrivers(ctx, unique, startTime, endTime, u.config.OnTimeGracePeriod)
+	if err != nil {
+		u.logError(ctx, "Failed to get metrics for drivers", err,
+			zap.Int("count", len(unique)),
+		)
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// GetDriverLeaderboard retrieves the top drivers by completed deliveries in a time range
+func (u *deliveryUseCase) GetDriverLeaderboard(ctx context.Context, startTime, endTime time.Time, limit int) ([]domain.DriverStats, error) {
+	// Validate input
+	v := validator.New()
+	if startTime.After(endTime) {
+		v.AddError("start_time", "must not be after end_time")
+	}
+	if limit < 0 {
+		v.AddError("limit", "must not be negative")
+	}
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Set defaults
+	if limit == 0 {
+		limit = constants.DefaultLeaderboardSize
+	}
+	if limit > constants.MaxLeaderboardSize {
+		limit = constants.MaxLeaderboardSize
+	}
+
+	release, err := u.metrics.acquire(ctx)
+	if err != nil {
+		u.log(ctx).Warn("Rejected driver leaderboard query", zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	leaderboard, err := u.repo.GetDriverLeaderboard(ctx, startTime, endTime, limit, u.config.OnTimeGracePeriod)
+	if err != nil {
+		u.logError(ctx, "Failed to get driver leaderboard", err)
+		return nil, err
+	}
+
+	return leaderboard, nil
+}
+
+// ListActiveDriverIDs retrieves the sorted IDs of drivers that have a non-terminal delivery
+func (u *deliveryUseCase) ListActiveDriverIDs(ctx context.Context) ([]string, error) {
+	driverIDs, err := u.repo.ListActiveDriverIDs(ctx)
+	if err != nil {
+		u.logError(ctx, "Failed to list active driver IDs", err)
+		return nil, err
+	}
+
+	return driverIDs, nil
+}
+
+// ListServedCities retrieves the sorted cities deliveries are picked up from or delivered to
+func (u *deliveryUseCase) ListServedCities(ctx context.Context) ([]string, error) {
+	cities, err := u.repo.ListServedCities(ctx)
+	if err != nil {
+		u.logError(ctx, "Failed to list served cities", err)
+		return nil, err
+	}
+
+	return cities, nil
+}
+
+// RecordDriverLocation validates and stores a driver's location ping. A zero RecordedAt means the
+// ping was taken now; timestamps further in the future than the allowed clock skew are rejected.
+func (u *deliveryUseCase) RecordDriverLocation(ctx context.Context, location domain.DriverLocation) error {
+	location.DriverID = u.normalizeID(location.DriverID)
+	now := u.config.Clock.Now()
+	if location.RecordedAt.IsZero() {
+		location.RecordedAt = now
+	}
+
+	v := validator.New()
+	v.ValidateRequired("driver_id", location.DriverID)
+	v.ValidateCoordinates("latitude", "longitude", location.Latitude, location.Longitude)
+	if location.RecordedAt.After(now.Add(constants.MaxLocationClockSkew)) {
+		v.AddError("recorded_at", "must not be in the future")
+	}
+	if err := toValidationError(v); err != nil {
+		return err
+	}
+
+	if err := u.repo.RecordDriverLocation(ctx, location.DriverID, location.Latitude, location.Longitude, location.RecordedAt); err != nil {
+		u.logError(ctx, "Failed to record driver location", err, zap.String("driver_id", location.DriverID))
+		return err
+	}
+
+	return nil
+}
+
+// GetDriverLocation retrieves the latest location ping of a driver
+func (u *deliveryUseCase) GetDriverLocation(ctx context.Context, driverID string) (*domain.DriverLocation, error) {
+	driverID = u.normalizeID(driverID)
+	if driverID == "" {
+		return nil, &domain.ValidationError{Field: "driver_id", Message: "is required"}
+	}
+
+	location, err := u.repo.GetLatestDriverLocation(ctx, driverID)
+	if err != nil {
+		u.logError(ctx, "Failed to get driver location", err, zap.String("driver_id", driverID))
+		return nil, err
+	}
+
+	return location, nil
+}
+
+// WatchDriverDeliveries streams the status changes of the deliveries assigned to driverID, including
+// deliveries being assigned to or taken away from the driver, until ctx is done. The channel is
+// closed when the watch ends; if ctx is not done by then, the watcher fell behind.
+func (u *deliveryUseCase) WatchDriverDeliveries(ctx context.Context, driverID string) (<-chan StatusChange, error) {
+	driverID = u.normalizeID(driverID)
+	if driverID == "" {
+		return nil, &domain.ValidationError{Field: "driver_id", Message: "is required"}
+	}
+	if u.config.Broadcaster == nil {
+		return nil, fmt.Errorf("%w: watching deliveries is disabled", domain.ErrUnavailable)
+	}
+
+	return u.config.Broadcaster.SubscribeDriver(ctx, driverID), nil
+}
+
+// GetStatusTransitionGraph returns the status state machine enforced by UpdateDeliveryStatus
+func (u *deliveryUseCase) GetStatusTransitionGraph(_ context.Context) []domain.StatusTransitions {
+	return domain.StatusTransitionGraph()
+}
+
+// ListModifiedSince retrieves deliveries changed after a sync position for incremental sync,
+// including soft-deleted ones. The position is the cursor returned by the previous call or, on the
+// first call, since. The returned cursor is the last delivery in the batch (the starting position
+// when the batch is empty) and is passed on the next call; a full batch means more may follow.
+func (u *deliveryUseCase) ListModifiedSince(ctx context.Context, since time.Time, cursor string, limit int) ([]*domain.DeliveryAssignment, domain.SyncCursor, error) {
+	// Validate input
+	v := validator.New()
+	if limit < 0 {
+		v.AddError("limit", "must not be negative")
+	}
+	after := domain.SyncCursorSince(since)
+	if cursor != "" {
+		parsed, err := domain.ParseSyncCursor(cursor)
+		if err != nil {
+			v.AddError("cursor", "must be a cursor returned by a previous call")
+		}
+		after = parsed
+	}
+	if err := toValidationError(v); err != nil {
+		return nil, domain.SyncCursor{}, err
+	}
+
+	// Set defaults
+	if limit == 0 {
+		limit = min(constants.DefaultSyncBatchSize, u.config.Limits.SyncBatch)
+	}
+	validateBatchSize(v, "limit", limit, u.config.Limits.SyncBatch)
+	if err := toValidationError(v); err != nil {
+		return nil, domain.SyncCursor{}, err
+	}
+
+	assignments, err := u.repo.ListModifiedSince(ctx, after, limit)
+	if err != nil {
+		u.logError(ctx, "Failed to list modified delivery assignments", err)
+		return nil, domain.SyncCursor{}, err
+	}
+
+	next := after
+	if len(assignments) > 0 {
+		last := assignments[len(assignments)-1]
+		next = domain.SyncCursor{ModifiedAt: last.ModifiedAt(), ID: last.ID}
+	}
+
+	return assignments, next, nil
+}
+
+func (u *deliveryUseCase) DeleteDeliveryAssignment(ctx context.Context, id uuid.UUID) error {
 	err := u.repo.Delete(ctx, id)
 	if err != nil {
-		u.logger.Error("Failed to delete delivery assignment")
+		u.logError(ctx, "Failed to delete delivery assignment", err,
+			zap.String("id", id.String()),
+		)
 		return err
 	}
+	u.audit(ctx, AuditEntry{Action: constants.OpDelete, DeliveryID: id.String()})
 
 	return nil
 }
+
+// DeleteDeliveriesByOrderID deletes every delivery for a cancelled order.
+// It refuses if any delivery has already been picked up, since those can no longer be called off.
+// The check and the delete run in one transaction, and the delete itself skips picked-up
+// deliveries: if it deletes a different number than were checked, a delivery changed in between
+// (e.g. was picked up) and nothing is deleted.
+func (u *deliveryUseCase) DeleteDeliveriesByOrderID(ctx context.Context, orderID string) (int64, error) {
+	orderID = u.normalizeID(orderID)
+
+	// Validate input
+	v := validator.New()
+	v.ValidateRequired("order_id", orderID)
+	if err := toValidationError(v); err != nil {
+		return 0, err
+	}
+
+	var deleted []uuid.UUID
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		existing, err := repo.GetByOrderID(ctx, orderID)
+		if err != nil {
+			u.logError(ctx, "Failed to get delivery assignments for order", err,
+				zap.String("order_id", orderID),
+			)
+			return err
+		}
+
+		for _, assignment := range existing {
+			if assignment.Status.IsPickedUp() {
+				return &domain.ConflictError{
+					Resource:     constants.ResourceOrder,
+					CurrentState: string(assignment.Status),
+					RequestedOp:  constants.OpDelete,
+					Message:      fmt.Sprintf("delivery %s has already been picked up", assignment.ID),
+				}
+			}
+		}
+
+		deleted, err = repo.DeleteByOrderID(ctx, orderID)
+		if err != nil {
+			u.logError(ctx, "Failed to delete delivery assignments for order", err,
+				zap.String("order_id", orderID),
+			)
+			return err
+		}
+
+		// Returning an error rolls the delete back
+		if len(deleted) != len(existing) {
+			return &domain.ConflictError{
+				Resource:     constants.ResourceOrder,
+				CurrentState: "modified concurrently",
+				RequestedOp:  constants.OpDelete,
+				Message:      fmt.Sprintf("%d of %d deliveries could be deleted; retry", len(deleted), len(existing)),
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	u.audit(ctx, AuditEntry{
+		Action:      constants.OpDeleteByOrder,
+		OrderID:     orderID,
+		Affected:    int64(len(deleted)),
+		DeliveryIDs: idStrings(deleted),
+	})
+	u.log(ctx).Info("Deleted delivery assignments for order",
+		zap.String("order_id", orderID),
+		zap.Int("deleted", len(deleted)),
+	)
+
+	return int64(len(deleted)), nil
+}
+
+// BulkDelete soft-deletes every delivery matching filters in batches. It is a two-step operation:
+// called without confirmToken it only counts the matches and returns the token for them, which
+// must be echoed back to delete. The token covers the filters and the count, so it is rejected if
+// the filters differ or deliveries started or stopped matching in between.
+func (u *deliveryUseCase) BulkDelete(ctx context.Context, filters ListFilters, confirmToken string) (*BulkDeleteResult, error) {
+	filters, err := u.listFilters(ListDeliveryInput(filters))
+	if err != nil {
+		return nil, err
+	}
+	filters.Page, filters.PageSize = 0, 0
+
+	// Refuse to wipe the whole table through an empty filter; ANY is what an unset activity becomes
+	noActivity := filters.Activity == "" || filters.Activity == domain.ActivityAny
+	if filters.Status == nil && noActivity && filters.DriverID == nil &&
+		filters.RequiredVehicleType == nil && filters.Tag == nil && filters.HasDriver == nil &&
+		filters.CreatedBefore == nil {
+		return nil, &domain.ValidationError{Field: "filters", Message: "at least one filter is required"}
+	}
+
+	matched, err := u.repo.CountMatching(ctx, filters)
+	if err != nil {
+		u.logError(ctx, "Failed to count delivery assignments", err)
+		return nil, err
+	}
+
+	result := &BulkDeleteResult{
+		Matched:      matched,
+		ConfirmToken: bulkDeleteToken(filters, matched),
+	}
+	if confirmToken == "" {
+		return result, nil
+	}
+	if confirmToken != result.ConfirmToken {
+		return nil, &domain.ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: fmt.Sprintf("%d matching", matched),
+			RequestedOp:  constants.OpBulkDelete,
+			Message:      "confirmation token does not match the filters and current count; request a new one",
+		}
+	}
+
+	var deletedIDs []uuid.UUID
+	for {
+		deleted, err := u.repo.DeleteMatching(ctx, filters, constants.BulkDeleteBatchSize)
+		deletedIDs = append(deletedIDs, deleted...)
+		result.Deleted = int64(len(deletedIDs))
+		if err != nil {
+			u.logError(ctx, "Failed to bulk delete delivery assignments", err,
+				zap.Int64("deleted", result.Deleted),
+			)
+			return nil, err
+		}
+		if len(deleted) < constants.BulkDeleteBatchSize {
+			break
+		}
+	}
+
+	u.audit(ctx, AuditEntry{
+		Action:      constants.OpBulkDelete,
+		Affected:    result.Deleted,
+		DeliveryIDs: idStrings(deletedIDs),
+		Filters:     filterFields(filters),
+	})
+	u.log(ctx).Info("Bulk deleted delivery assignments",
+		zap.Int64("matched", matched),
+		zap.Int64("deleted", result.Deleted),
+	)
+
+	return result, nil
+}
+
+// bulkDeleteToken hashes the normalized filters and the number of deliveries they match
+func bulkDeleteToken(filters ListFilters, matched int64) string {
+	fields := filterFields(filters)
+	parts := make([]string, 0, len(fields)+1)
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		parts = append(parts, name+"="+fields[name])
+	}
+	parts = append(parts, fmt.Sprint(matched))
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// filterFields returns the set filters of a bulk delete by name, as recorded in its audit entry.
+// Pagination is not a filter and is left out.
+func filterFields(filters ListFilters) map[string]string {
+	fields := make(map[string]string)
+	if filters.Status != nil {
+		fields["status"] = string(filters.Status.Canonical())
+	}
+	if filters.Activity != "" {
+		fields["activity"] = string(filters.Activity)
+	}
+	if filters.DriverID != nil {
+		fields["driver_id"] = *filters.DriverID
+	}
+	if filters.RequiredVehicleType != nil {
+		fields["required_vehicle_type"] = *filters.RequiredVehicleType
+	}
+	if filters.Tag != nil {
+		fields["tag"] = *filters.Tag
+	}
+	if filters.HasDriver != nil {
+		fields["has_driver"] = fmt.Sprint(*filters.HasDriver)
+	}
+	if filters.CreatedBefore != nil {
+		fields["created_before"] = filters.CreatedBefore.UTC().Format(time.RFC3339Nano)
+	}
+	return fields
+}
+
+// idStrings formats ids for an audit entry
+func idStrings(ids []uuid.UUID) []string {
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = id.String()
+	}
+	return formatted
+}
+
+// SubmitFeedback records the recipient's rating and feedback for a delivered assignment
+func (u *deliveryUseCase) SubmitFeedback(ctx context.Context, id uuid.UUID, rating int, feedback string) (*domain.DeliveryAssignment, error) {
+	// Validate input
+	v := validator.New()
+	v.ValidateRange("rating", rating, constants.MinRating, constants.MaxRating)
+	v.ValidateStringLength("feedback", feedback, 0, u.config.MaxNotesLength)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+
+	// Record feedback using domain logic
+	if err := assignment.SubmitFeedback(rating, feedback); err != nil {
+		u.log(ctx).Error("Failed to submit feedback",
+			zap.Error(err),
+			zap.String("id", id.String()),
+			zap.String("current_status", string(assignment.Status)),
+		)
+		return nil, err
+	}
+
+	// Save changes
+	if err := u.repo.Update(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to update delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpFeedback, before, assignment)
+
+	return assignment, nil
+}
+
+// MarkDeadLetter permanently parks a failed delivery so it is excluded from any retry
+func (u *deliveryUseCase) MarkDeadLetter(ctx context.Context, id uuid.UUID, reason string) (*domain.DeliveryAssignment, error) {
+	reason = strings.TrimSpace(reason)
+
+	// Validate input
+	v := validator.New()
+	v.ValidateRequired("reason", reason)
+	v.ValidateStringLength("reason", reason, 0, u.config.MaxNotesLength)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+
+	// Dead-letter using domain logic
+	if err := assignment.MarkDeadLetter(reason); err != nil {
+		u.log(ctx).Error("Failed to dead-letter delivery",
+			zap.Error(err),
+			zap.String("id", id.String()),
+			zap.String("current_status", string(assignment.Status)),
+		)
+		return nil, err
+	}
+
+	// Save changes
+	if err := u.repo.Update(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to update delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpDeadLetter, before, assignment)
+
+	u.notifyStatusChange(ctx, assignment)
+
+	return assignment, nil
+}
+
+// AppendNote adds a timestamped note to a delivery's timeline without changing its status
+func (u *deliveryUseCase) AppendNote(ctx context.Context, id uuid.UUID, note string) (*domain.DeliveryAssignment, error) {
+	note = strings.TrimSpace(note)
+
+	// Validate input
+	v := validator.New()
+	v.ValidateRequired("note", note)
+	v.ValidateStringLength("note", note, 0, u.config.MaxNotesLength)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+
+	// Append using domain logic, then persist only the new note
+	timed := assignment.AppendNote(note)
+	if err := u.repo.AppendNote(ctx, id, timed); err != nil {
+		u.logError(ctx, "Failed to append timeline note", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpAppendNote, before, assignment)
+
+	return assignment, nil
+}
+
+// UpdateDriverETA saves the driver's latest arrival estimate for a delivery that is on its way and
+// notifies it as an ETA update. The committed EstimatedDeliveryTime is not changed. Only the ETA
+// is written, guarded by the status, so a delivery completed in the meantime is reported as a
+// conflict rather than having its newer state overwritten. The ETA must fall within business
+// hours in the delivery's time zone.
+func (u *deliveryUseCase) UpdateDriverETA(ctx context.Context, id uuid.UUID, eta time.Time) (*domain.DeliveryAssignment, error) {
+	// Get existing assignment
+	existing, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	existing.SetClock(u.config.Clock)
+	before := captureAuditState(existing)
+
+	// Validate using domain logic
+	if err := existing.UpdateDriverETA(eta); err != nil {
+		return nil, err
+	}
+	v := validator.New()
+	u.validateWithinBusinessHours(v, "driver_eta", existing.TimeZone, eta)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Save only the ETA
+	assignment, err := u.repo.UpdateDriverETA(ctx, id, eta, existing.UpdatedAt)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, &domain.ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(existing.Status),
+			RequestedOp:  constants.OpUpdateDriverETA,
+			Message:      "the delivery is no longer on its way",
+		}
+	}
+	if err != nil {
+		u.logError(ctx, "Failed to update driver ETA", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+
+	u.auditChange(ctx, constants.OpUpdateDriverETA, before, assignment)
+	u.notifyETAUpdate(ctx, assignment)
+
+	return assignment, nil
+}
+
+// SplitDelivery records a partial delivery. The delivery becomes DELIVERED and a new PENDING
+// delivery for remainingPackages is created, linked through ParentDeliveryID, in one transaction.
+// The child is scheduled for pickup MinScheduleAdvance from now and keeps the parent's
+// pickup-to-delivery duration; the split is rejected when that falls outside business hours.
+func (u *deliveryUseCase) SplitDelivery(ctx context.Context, id uuid.UUID, remainingPackages []domain.Package) (*domain.DeliveryAssignment, *domain.DeliveryAssignment, error) {
+	// Validate input
+	v := validator.New()
+	validatePackages(v, "remaining_packages", remainingPackages)
+	if err := toValidationError(v); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		parent, child *domain.DeliveryAssignment
+		before        auditState
+	)
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		var err error
+		parent, err = repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		parent.SetClock(u.config.Clock)
+		before = captureAuditState(parent)
+		parent.SetIDGenerator(u.config.IDGenerator)
+
+		pickup := u.config.Clock.Now().Add(u.config.MinScheduleAdvance)
+		estimate := pickup.Add(parent.EstimatedDeliveryTime.Sub(parent.ScheduledPickupTime))
+		child, err = parent.Split(remainingPackages, pickup, estimate)
+		if err != nil {
+			return err
+		}
+		v := validator.New()
+		u.validateBusinessHours(v, child.TimeZone, child.ScheduledPickupTime, child.EstimatedDeliveryTime)
+		if err := toValidationError(v); err != nil {
+			return err
+		}
+		child.DeriveDeliveryWindow(u.config.DeliveryWindowSlack)
+
+		// The parent is saved first so it no longer counts as active for the order
+		if err := repo.Update(ctx, parent); err != nil {
+			return err
+		}
+		return repo.Create(ctx, child)
+	})
+	if err != nil {
+		u.logError(ctx, "Failed to split delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, nil, err
+	}
+
+	u.auditChange(ctx, constants.OpSplit, before, parent)
+	u.auditChange(ctx, constants.OpSplit, nil, child)
+	u.notifyStatusChange(ctx, parent)
+
+	return parent, child, nil
+}
+
+// ReattemptDelivery creates a new PENDING delivery that tries a FAILED one again, linked through
+// ParentDeliveryID with the next AttemptNumber. The failed delivery is kept as it is for audit.
+// Like a split, the reattempt is scheduled for pickup MinScheduleAdvance from now and keeps the
+// failed delivery's pickup-to-delivery duration. The reattempt passes the same business hours
+// and active-per-order checks as a new delivery.
+//
+// The failed delivery is locked for the duration, and a delivery is reattempted only once: a
+// second call conflicts instead of creating another attempt.
+//
+// A failed last attempt is normally dead-lettered when it fails. When the failed delivery is
+// still at or past MaxAttempts here (the limit was lowered since), no reattempt is created: the
+// failed delivery is moved to DEAD_LETTER and returned instead.
+func (u *deliveryUseCase) ReattemptDelivery(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error) {
+	var (
+		reattempt *domain.DeliveryAssignment
+		before    auditState
+	)
+	deadLettered := false
+	err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+		failed, err := repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		failed.SetClock(u.config.Clock)
+		before = captureAuditState(failed)
+		failed.SetIDGenerator(u.config.IDGenerator)
+
+		if deadLettered, err = u.deadLetterExhausted(failed); err != nil {
+			return err
+		}
+		if deadLettered {
+			reattempt = failed
+			return repo.Update(ctx, failed)
+		}
+
+		pickup := u.config.Clock.Now().Add(u.config.MinScheduleAdvance)
+		estimate := pickup.Add(failed.EstimatedDeliveryTime.Sub(failed.ScheduledPickupTime))
+		reattempt, err = failed.Reattempt(pickup, estimate)
+		if err != nil {
+			return err
+		}
+		reattempt.DeriveDeliveryWindow(u.config.DeliveryWindowSlack)
+
+		siblings, err := repo.GetByOrderID(ctx, failed.OrderID)
+		if err != nil {
+			return err
+		}
+		for _, sibling := range siblings {
+			if sibling.ParentDeliveryID != nil && *sibling.ParentDeliveryID == failed.ID && sibling.AttemptNumber > failed.AttemptNumber {
+				return &domain.ConflictError{
+					Resource:     constants.ResourceDeliveryAssignment,
+					CurrentState: string(failed.Status),
+					RequestedOp:  constants.OpReattempt,
+					Message:      fmt.Sprintf("delivery was already reattempted as %s", sibling.ID),
+				}
+			}
+		}
+
+		v := validator.New()
+		u.validateBusinessHours(v, reattempt.TimeZone, reattempt.ScheduledPickupTime, reattempt.EstimatedDeliveryTime)
+		if err := toValidationError(v); err != nil {
+			return err
+		}
+		if err := u.limitActivePerOrder(ctx, failed.OrderID, siblings, constants.OpReattempt); err != nil {
+			return err
+		}
+
+		return repo.Create(ctx, reattempt)
+	})
+	if err != nil {
+		u.logError(ctx, "Failed to reattempt delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+
+	if deadLettered {
+		u.log(ctx).Info("Delivery dead-lettered after its last attempt",
+			zap.String("id", id.String()),
+			zap.Int("attempt_number", reattempt.AttemptNumber),
+		)
+		u.auditChange(ctx, constants.OpDeadLetter, before, reattempt)
+		u.notifyStatusChange(ctx, reattempt)
+		return reattempt, nil
+	}
+
+	u.log(ctx).Info("Delivery reattempted",
+		zap.String("id", id.String()),
+		zap.String("reattempt_id", reattempt.ID.String()),
+		zap.Int("attempt_number", reattempt.AttemptNumber),
+	)
+	u.auditChange(ctx, constants.OpReattempt, nil, reattempt)
+
+	return reattempt, nil
+}
+
+// deadLetterExhausted moves a FAILED delivery that was already attempt Config.MaxAttempts to
+// DEAD_LETTER and reports whether it did. Deliveries from before attempts were counted are
+// treated as the first attempt.
+func (u *deliveryUseCase) deadLetterExhausted(assignment *domain.DeliveryAssignment) (bool, error) {
+	if u.config.MaxAttempts <= 0 || assignment.Status != domain.DeliveryStatusFailed ||
+		max(assignment.AttemptNumber, 1) < u.config.MaxAttempts {
+		return false, nil
+	}
+
+	reason := fmt.Sprintf("max delivery attempts (%d) reached", u.config.MaxAttempts)
+	if err := assignment.MarkDeadLetter(reason); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// validatePackages checks a non-empty list of packages with descriptions and positive quantities
+func validatePackages(v *validator.Validator, field string, packages []domain.Package) {
+	if len(packages) == 0 {
+		v.AddError(field, "at least one package is required")
+	}
+	for i, p := range packages {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		v.ValidateRequired(itemField+".description", p.Description)
+		v.ValidateStringLength(itemField+".description", p.Description, 0, constants.MaxPackageDescriptionLength)
+		if p.Quantity < 1 {
+			v.AddError(itemField+".quantity", "must be at least 1")
+		}
+	}
+}
+
+// AddTags tags a delivery. Tags it already has are ignored, so retries are safe.
+func (u *deliveryUseCase) AddTags(ctx context.Context, id uuid.UUID, tags []string) (*domain.DeliveryAssignment, error) {
+	tags = normalizeTags(tags)
+
+	// Validate input
+	v := validator.New()
+	if len(tags) == 0 {
+		v.AddError("tags", "at least one tag is required")
+	}
+	validateTags(v, tags)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+
+	added, err := assignment.AddTags(tags...)
+	if err != nil || !added {
+		return assignment, err
+	}
+
+	// Save changes
+	if err := u.repo.Update(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to update delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpAddTags, before, assignment)
+
+	return assignment, nil
+}
+
+// RemoveTags removes tags from a delivery. Tags it does not have are ignored, so retries are safe.
+func (u *deliveryUseCase) RemoveTags(ctx context.Context, id uuid.UUID, tags []string) (*domain.DeliveryAssignment, error) {
+	tags = normalizeTags(tags)
+
+	// Validate input
+	v := validator.New()
+	if len(tags) == 0 {
+		v.AddError("tags", "at least one tag is required")
+	}
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	// Get existing assignment
+	assignment, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment.SetClock(u.config.Clock)
+	before := captureAuditState(assignment)
+
+	if !assignment.RemoveTags(tags...) {
+		return assignment, nil
+	}
+
+	// Save changes
+	if err := u.repo.Update(ctx, assignment); err != nil {
+		u.logError(ctx, "Failed to update delivery assignment", err,
+			zap.String("id", id.String()),
+		)
+		return nil, err
+	}
+	u.auditChange(ctx, constants.OpRemoveTags, before, assignment)
+
+	return assignment, nil
+}
+
+// ExportDeliveries streams every delivery assignment to fn for bulk export
+func (u *deliveryUseCase) ExportDeliveries(ctx context.Context, fn func(*domain.DeliveryAssignment) error) error {
+	exported := 0
+	err := u.repo.StreamAll(ctx, func(assignment *domain.DeliveryAssignment) error {
+		if err := fn(assignment); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		u.logError(ctx, "Failed to export delivery assignments", err,
+			zap.Int("exported", exported),
+		)
+		return err
+	}
+
+	u.log(ctx).Info("Exported delivery assignments", zap.Int("exported", exported))
+	return nil
+}
+
+// UnassignOverdueDeliveries unassigns drivers from ASSIGNED deliveries whose pickup was due
+// before cutoff, returning the deliveries that were put back to PENDING or, when they had a
+// backup driver, handed over to the backup (still ASSIGNED). A promoted backup is due from their
+// promotion, so they get the full grace period before being unassigned in turn.
+// Each delivery is re-read and locked in its own transaction before it is changed, so one that a
+// driver picked up or that was rescheduled after it was listed is left alone.
+// Failures on individual deliveries are logged and skipped so one bad row cannot stall the batch.
+func (u *deliveryUseCase) UnassignOverdueDeliveries(ctx context.Context, cutoff time.Time) ([]*domain.DeliveryAssignment, error) {
+	overdue, err := u.repo.ListOverdueAssigned(ctx, cutoff, constants.ReassignBatchSize)
+	if err != nil {
+		u.logError(ctx, "Failed to list overdue assignments", err)
+		return nil, err
+	}
+
+	unassigned := make([]*domain.DeliveryAssignment, 0, len(overdue))
+	for _, candidate := range overdue {
+		var (
+			assignment       *domain.DeliveryAssignment
+			before           auditState
+			previousDriverID *string
+		)
+		err := u.repo.WithTransaction(ctx, func(repo DeliveryRepository) error {
+			locked, err := repo.GetByIDForUpdate(ctx, candidate.ID)
+			if err != nil {
+				return err
+			}
+			if !locked.PickupDueAt().Before(cutoff) {
+				return nil // Rescheduled or given a new driver since it was listed
+			}
+			locked.SetClock(u.config.Clock)
+			before = captureAuditState(locked)
+			previousDriverID = locked.DriverID
+			if err := locked.UnassignDriver(); err != nil {
+				return err
+			}
+			if err := repo.Update(ctx, locked); err != nil {
+				return err
+			}
+			assignment = locked
+			return nil
+		})
+		var conflict *domain.ConflictError
+		switch {
+		case errors.As(err, &conflict):
+			u.log(ctx).Warn("Skipping overdue assignment",
+				zap.Error(err),
+				zap.String("id", candidate.ID.String()),
+			)
+			continue
+		case err != nil:
+			u.logError(ctx, "Failed to update delivery assignment", err,
+				zap.String("id", candidate.ID.String()),
+			)
+			continue
+		case assignment == nil:
+			continue
+		}
+		u.auditChange(ctx, constants.OpAutoUnassign, before, assignment)
+
+		// The driver loses the delivery, so their watchers must hear about it too
+		u.notify(ctx, StatusChange{
+			DeliveryID:       assignment.ID,
+			OrderID:          assignment.OrderID,
+			DriverID:         assignment.DriverID,
+			Status:           assignment.Status,
+			ChangedAt:        assignment.UpdatedAt,
+			PreviousDriverID: previousDriverID,
+			RecipientEmail:   assignment.RecipientEmail,
+			NotifyPrefs:      assignment.NotifyPrefs,
+		})
+		unassigned = append(unassigned, assignment)
+	}
+
+	return unassigned, nil
+}
+
+// EscalatePendingPriority raises PENDING deliveries of priority from that were created before
+// cutoff to priority to, oldest first and at most constants.EscalationBatchSize per call. The
+// escalation is one conditional update, so a delivery claimed or escalated in the meantime keeps
+// its newer state. Each escalation is sent to the notifier.
+func (u *deliveryUseCase) EscalatePendingPriority(ctx context.Context, from, to domain.DeliveryPriority, cutoff time.Time) ([]*domain.DeliveryAssignment, error) {
+	if !to.Outranks(from) {
+		return nil, &domain.ConflictError{
+			Resource:     constants.ResourceDeliveryAssignment,
+			CurrentState: string(from),
+			RequestedOp:  constants.OpEscalate,
+			Message:      fmt.Sprintf("priority %s does not outrank %s", to, from),
+		}
+	}
+
+	escalated, err := u.repo.EscalateWaitingPending(ctx, from, to, cutoff, u.config.Clock.Now(), constants.EscalationBatchSize)
+	if err != nil {
+		u.logError(ctx, "Failed to escalate waiting pending deliveries", err,
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+		)
+		return nil, err
+	}
+
+	for _, assignment := range escalated {
+		previous := *assignment
+		previous.Priority = from
+		u.auditChange(ctx, constants.OpEscalate, captureAuditState(&previous), assignment)
+
+		u.notify(ctx, StatusChange{
+			DeliveryID:        assignment.ID,
+			OrderID:           assignment.OrderID,
+			Status:            assignment.Status,
+			ChangedAt:         assignment.UpdatedAt,
+			Priority:          assignment.Priority,
+			PriorityEscalated: true,
+			RecipientEmail:    assignment.RecipientEmail,
+			NotifyPrefs:       assignment.NotifyPrefs,
+		})
+	}
+
+	return escalated, nil
+}
+
+// CheckDeliveryFeasibility checks, before a delivery is accepted, that its pickup and delivery
+// addresses are within Config.MaxDeliveryDistanceKm of each other in a straight line and that the
+// time between pickup and delivery allows travelling that distance at Config.AverageSpeedKmh.
+// Being a straight-line estimate, a feasible result does not guarantee a route exists.
+func (u *deliveryUseCase) CheckDeliveryFeasibility(_ context.Context, input FeasibilityInput) (*FeasibilityResult, error) {
+	v := validator.New()
+	validateRouteEnd(v, "pickup_address", input.PickupAddress)
+	validateRouteEnd(v, "delivery_address", input.DeliveryAddress)
+	v.ValidateTimeNotZero("scheduled_pickup_time", input.ScheduledPickupTime)
+	v.ValidateTimeNotZero("estimated_delivery_time", input.EstimatedDeliveryTime)
+	if err := toValidationError(v); err != nil {
+		return nil, err
+	}
+
+	distance := input.PickupAddress.DistanceKm(input.DeliveryAddress)
+	result := &FeasibilityResult{
+		DistanceKm:        distance,
+		RequiredDuration:  time.Duration(distance / u.config.AverageSpeedKmh * float64(time.Hour)),
+		AvailableDuration: input.EstimatedDeliveryTime.Sub(input.ScheduledPickupTime),
+	}
+
+	if limit := u.config.MaxDeliveryDistanceKm; limit > 0 && distance > limit {
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("distance of %.1f km exceeds the maximum of %.1f km", distance, limit))
+	}
+	switch {
+	case result.AvailableDuration <= 0:
+		result.Reasons = append(result.Reasons, "estimated delivery time is not after the scheduled pickup time")
+	case result.RequiredDuration > result.AvailableDuration:
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("travel needs %s at %.0f km/h but the window allows only %s",
+				result.RequiredDuration.Round(time.Minute), u.config.AverageSpeedKmh, result.AvailableDuration))
+	}
+	result.Feasible = len(result.Reasons) == 0
+
+	return result, nil
+}
+
+// validateRouteEnd requires an address to have valid coordinates
+func validateRouteEnd(v *validator.Validator, field string, address domain.Address) {
+	if !address.HasCoordinates() {
+		v.AddError(field, "coordinates are required")
+		return
+	}
+	v.ValidateAddressCoordinates(field, address.Latitude, address.Longitude)
+}
@@ -38,6 +38,7 @@ func TestCreateDeliveryAssignment_WithMockgen(t *testing.T) {
 	}
 
 	// Set expectations using gomock
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mockRepo.EXPECT().
 		Create(gomock.Any(), gomock.Any()).
 		Return(nil).
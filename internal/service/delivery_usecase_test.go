@@ -2,6 +2,9 @@ package service_test
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,9 +14,11 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 	"github.com/mohamadchoker/order-delivery-service/internal/domain"
 	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
 	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	"github.com/mohamadchoker/order-delivery-service/pkg/jsonschema"
 )
 
 func TestCreateDeliveryAssignment(t *testing.T) {
@@ -36,6 +41,7 @@ func TestCreateDeliveryAssignment(t *testing.T) {
 		Notes:                 "Test delivery",
 	}
 
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mockRepo.EXPECT().
 		Create(gomock.Any(), gomock.Any()).
 		Return(nil).
@@ -166,8 +172,12 @@ func TestUpdateDeliveryStatus(t *testing.T) {
 		Status:  domain.DeliveryStatus("PENDING"),
 	}
 
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
 	mockRepo.EXPECT().
-		GetByID(ctx, id).
+		GetByIDForUpdate(ctx, id).
 		Return(existingAssignment, nil).
 		Times(1)
 
@@ -176,7 +186,7 @@ func TestUpdateDeliveryStatus(t *testing.T) {
 		Return(nil).
 		Times(1)
 
-	result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatus("ASSIGNED"), "")
+	result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatus("ASSIGNED"), "", "", "")
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -200,8 +210,12 @@ func TestUpdateDeliveryStatus_InvalidTransition(t *testing.T) {
 		Status:  domain.DeliveryStatus("PENDING"),
 	}
 
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
 	mockRepo.EXPECT().
-		GetByID(ctx, id).
+		GetByIDForUpdate(ctx, id).
 		Return(existingAssignment, nil).
 		Times(1)
 
@@ -210,12 +224,106 @@ func TestUpdateDeliveryStatus_InvalidTransition(t *testing.T) {
 		Update(gomock.Any(), gomock.Any()).
 		Times(0)
 
-	result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatus("DELIVERED"), "")
+	result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatus("DELIVERED"), "", "", "")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
 
+func TestUpdateDeliveryStatus_PickupCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		expectError bool
+		expectSave  bool
+	}{
+		{name: "matching code confirms pickup", code: "042917", expectError: false, expectSave: true},
+		{name: "wrong code is rejected and counted", code: "111111", expectError: true, expectSave: true},
+		{name: "missing code is rejected", code: "", expectError: true, expectSave: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+			ctx := context.Background()
+			driverID := "DRIVER-123"
+			existing := &domain.DeliveryAssignment{
+				ID:         uuid.New(),
+				Status:     domain.DeliveryStatusAssigned,
+				DriverID:   &driverID,
+				PickupCode: "042917",
+			}
+
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().GetByIDForUpdate(ctx, existing.ID).Return(existing, nil).Times(1)
+			if tt.expectSave {
+				mockRepo.EXPECT().Update(ctx, existing).Return(nil).Times(1)
+			} else {
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			result, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusPickedUp, "", tt.code, "")
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				assert.Nil(t, result)
+				assert.Equal(t, domain.DeliveryStatusAssigned, existing.Status)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, domain.DeliveryStatusPickedUp, result.Status)
+			}
+		})
+	}
+}
+
+func TestUpdateDeliveryStatus_PickupLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now: now}
+	cfg.PickupLockout = domain.PickupLockout{MaxAttempts: 2, Duration: 15 * time.Minute}
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	driverID := "DRIVER-123"
+	existing := &domain.DeliveryAssignment{
+		ID:         uuid.New(),
+		Status:     domain.DeliveryStatusAssigned,
+		DriverID:   &driverID,
+		PickupCode: "042917",
+	}
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(3)
+	mockRepo.EXPECT().GetByIDForUpdate(ctx, existing.ID).Return(existing, nil).Times(3)
+	// Both wrong guesses are saved; the refused attempt changes nothing
+	mockRepo.EXPECT().Update(ctx, existing).Return(nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusPickedUp, "", "111111", "")
+		require.ErrorIs(t, err, domain.ErrInvalidInput)
+	}
+	require.NotNil(t, existing.PickupLockedUntil)
+	assert.Equal(t, now.Add(15*time.Minute), *existing.PickupLockedUntil)
+
+	_, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusPickedUp, "", "042917", "")
+	assert.ErrorIs(t, err, domain.ErrResourceExhausted)
+	assert.Equal(t, domain.DeliveryStatusAssigned, existing.Status)
+}
+
 func TestAssignDriver(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -270,6 +378,64 @@ func TestAssignDriver_EmptyDriverID(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestAssignBackupDriver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	t.Run("assigned delivery", func(t *testing.T) {
+		id := uuid.New()
+		primary := "DRIVER-1"
+		existing := &domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusAssigned, DriverID: &primary}
+
+		// The delivery is locked while the backup is set on it
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByIDForUpdate(ctx, id).Return(existing, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Update(ctx, existing).Return(nil)
+
+		result, err := uc.AssignBackupDriver(ctx, id, " DRIVER-2 ")
+
+		require.NoError(t, err)
+		require.NotNil(t, result.BackupDriverID)
+		assert.Equal(t, "DRIVER-2", *result.BackupDriverID)
+		assert.Equal(t, "DRIVER-1", *result.DriverID)
+	})
+
+	t.Run("no primary driver", func(t *testing.T) {
+		id := uuid.New()
+		existing := &domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending}
+
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByIDForUpdate(ctx, id).Return(existing, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := uc.AssignBackupDriver(ctx, id, "DRIVER-2")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+		assert.Nil(t, result)
+		assert.Nil(t, existing.BackupDriverID)
+	})
+
+	t.Run("empty driver ID", func(t *testing.T) {
+		result, err := uc.AssignBackupDriver(ctx, uuid.New(), "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+	})
+}
+
 func TestListDeliveryAssignments(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -295,14 +461,14 @@ func TestListDeliveryAssignments(t *testing.T) {
 
 	mockRepo.EXPECT().
 		List(ctx, gomock.Any()).
-		Return(expectedAssignments, int64(1), nil).
+		Return(expectedAssignments, service.ListTotal{Count: 1}, nil).
 		Times(1)
 
-	result, totalCount, err := uc.ListDeliveryAssignments(ctx, input)
+	result, total, err := uc.ListDeliveryAssignments(ctx, input)
 
 	require.NoError(t, err)
 	assert.Len(t, result, 1)
-	assert.Equal(t, int64(1), totalCount)
+	assert.Equal(t, service.ListTotal{Count: 1}, total)
 }
 
 func TestGetDeliveryMetrics(t *testing.T) {
@@ -325,7 +491,7 @@ func TestGetDeliveryMetrics(t *testing.T) {
 	}
 
 	mockRepo.EXPECT().
-		GetMetrics(ctx, startTime, endTime, nil).
+		GetMetrics(ctx, startTime, endTime, nil, constants.DefaultOnTimeGracePeriod).
 		Return(expectedMetrics, nil).
 		Times(1)
 
@@ -355,3 +521,2970 @@ func TestGetDeliveryMetrics_InvalidTimeRange(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Equal(t, domain.ErrInvalidInput, err)
 }
+
+func TestExportDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	seeded := make([]*domain.DeliveryAssignment, 3)
+	for i := range seeded {
+		seeded[i] = &domain.DeliveryAssignment{
+			ID:      uuid.New(),
+			OrderID: "ORDER-123",
+			Status:  domain.DeliveryStatusPending,
+		}
+	}
+
+	mockRepo.EXPECT().
+		StreamAll(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*domain.DeliveryAssignment) error) error {
+			for _, assignment := range seeded {
+				if err := fn(assignment); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).
+		Times(1)
+
+	callbacks := 0
+	err := uc.ExportDeliveries(ctx, func(assignment *domain.DeliveryAssignment) error {
+		assert.Equal(t, seeded[callbacks].ID, assignment.ID)
+		callbacks++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, len(seeded), callbacks)
+}
+
+func TestExportDeliveries_CallbackError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().
+		StreamAll(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*domain.DeliveryAssignment) error) error {
+			return fn(&domain.DeliveryAssignment{ID: uuid.New()})
+		}).
+		Times(1)
+
+	err := uc.ExportDeliveries(ctx, func(*domain.DeliveryAssignment) error {
+		return context.Canceled
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestIDNormalization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, service.Config{UppercaseIDs: true})
+
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("create normalizes order ID", func(t *testing.T) {
+		for _, orderID := range []string{" order-123 ", "ORDER-123"} {
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			mockRepo.EXPECT().
+				Create(ctx, gomock.Any()).
+				Return(nil).
+				Times(1)
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               orderID,
+				ScheduledPickupTime:   now.Add(1 * time.Hour),
+				EstimatedDeliveryTime: now.Add(3 * time.Hour),
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, "ORDER-123", result.OrderID)
+		}
+	})
+
+	t.Run("list normalizes driver ID filter", func(t *testing.T) {
+		driverID := " driver-1 "
+
+		mockRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, int64, error) {
+				require.NotNil(t, filters.DriverID)
+				assert.Equal(t, "DRIVER-1", *filters.DriverID)
+				return nil, 0, nil
+			}).
+			Times(1)
+
+		_, _, err := uc.ListDeliveryAssignments(ctx, service.ListDeliveryInput{DriverID: &driverID})
+
+		require.NoError(t, err)
+		assert.Equal(t, " driver-1 ", driverID, "caller's value must not be mutated")
+	})
+
+	t.Run("assign normalizes driver ID", func(t *testing.T) {
+		id := uuid.New()
+
+		mockRepo.EXPECT().
+			GetByID(ctx, id).
+			Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending}, nil).
+			Times(1)
+		mockRepo.EXPECT().
+			Update(ctx, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		result, err := uc.AssignDriver(ctx, id, " driver-1 ")
+
+		require.NoError(t, err)
+		require.NotNil(t, result.DriverID)
+		assert.Equal(t, "DRIVER-1", *result.DriverID)
+	})
+
+	t.Run("whitespace-only order ID is rejected", func(t *testing.T) {
+		result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+			OrderID:               "   ",
+			ScheduledPickupTime:   now.Add(1 * time.Hour),
+			EstimatedDeliveryTime: now.Add(3 * time.Hour),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+	})
+}
+
+func TestIDNormalization_TrimOnlyByDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+		OrderID:               " order-123 ",
+		ScheduledPickupTime:   now.Add(1 * time.Hour),
+		EstimatedDeliveryTime: now.Add(3 * time.Hour),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "order-123", result.OrderID)
+}
+
+func TestNotesMaxLength(t *testing.T) {
+	const maxNotes = 10
+
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		notes       string
+		expectError bool
+	}{
+		{name: "empty notes", notes: "", expectError: false},
+		{name: "exactly at limit", notes: strings.Repeat("a", maxNotes), expectError: false},
+		{name: "one over limit", notes: strings.Repeat("a", maxNotes+1), expectError: true},
+		{name: "multibyte at limit", notes: strings.Repeat("é", maxNotes), expectError: false},
+		{name: "multibyte one over limit", notes: strings.Repeat("é", maxNotes+1), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("create "+tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, service.Config{MaxNotesLength: maxNotes})
+
+			if !tt.expectError {
+				mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(1 * time.Hour),
+				EstimatedDeliveryTime: now.Add(3 * time.Hour),
+				Notes:                 tt.notes,
+			})
+
+			if tt.expectError {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "notes", validationErr.Field)
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.notes, result.Notes)
+			}
+		})
+
+		t.Run("update status "+tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, service.Config{MaxNotesLength: maxNotes})
+
+			id := uuid.New()
+			if !tt.expectError {
+				mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+						return fn(mockRepo)
+					})
+				mockRepo.EXPECT().
+					GetByIDForUpdate(ctx, id).
+					Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending}, nil).
+					Times(1)
+				mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatusAssigned, tt.notes, "", "")
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_DefaultNotes(t *testing.T) {
+	const defaultNotes = "Ring doorbell, leave at door"
+
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		prepend     bool
+		maxNotes    int
+		notes       string
+		expected    string
+		expectError bool
+	}{
+		{name: "empty notes get the default", notes: "", expected: defaultNotes},
+		{name: "blank notes get the default", notes: "   ", expected: defaultNotes},
+		{name: "caller notes preserved", notes: "Gate code 1234", expected: "Gate code 1234"},
+		{name: "prepended to caller notes", prepend: true, notes: "Gate code 1234", expected: defaultNotes + "\nGate code 1234"},
+		{name: "not prepended twice", prepend: true, notes: defaultNotes + "\nGate code 1234", expected: defaultNotes + "\nGate code 1234"},
+		{name: "prepending respects max length", prepend: true, maxNotes: len(defaultNotes) + 5, notes: "Gate code 1234", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.DefaultNotes = defaultNotes
+			cfg.PrependDefaultNotes = tt.prepend
+			if tt.maxNotes > 0 {
+				cfg.MaxNotesLength = tt.maxNotes
+			}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			if !tt.expectError {
+				mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(1 * time.Hour),
+				EstimatedDeliveryTime: now.Add(3 * time.Hour),
+				Notes:                 tt.notes,
+			})
+
+			if tt.expectError {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "notes", validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result.Notes)
+			}
+		})
+	}
+}
+
+func TestUpdateDeliveryStatus_SameStatusIsNoOp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+	updatedAt := time.Now().Add(-time.Hour)
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().
+		GetByIDForUpdate(ctx, id).
+		Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending, UpdatedAt: updatedAt}, nil).
+		Times(1)
+	// Nothing changed, so nothing is written
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatusPending, "", "", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusPending, result.Status)
+	assert.Equal(t, updatedAt, result.UpdatedAt)
+}
+
+func TestUpdateDeliveryStatus_FailureReasonCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		expected   string
+		errorField string // empty means accepted
+	}{
+		{name: "configured code", code: "WRONG_ADDRESS", expected: "WRONG_ADDRESS"},
+		{name: "normalized code", code: " customer_unavailable ", expected: "CUSTOMER_UNAVAILABLE"},
+		{name: "unknown code", code: "LOST_IN_SPACE", errorField: "failure_reason_code"},
+		{name: "missing code", code: "", errorField: "failure_reason_code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, service.DefaultConfig())
+
+			ctx := context.Background()
+			id := uuid.New()
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				}).AnyTimes()
+			mockRepo.EXPECT().
+				GetByIDForUpdate(ctx, id).
+				Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusInTransit}, nil).
+				AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatusFailed, "Nobody home", "", tt.code)
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.DeliveryStatusFailed, result.Status)
+			require.NotNil(t, result.FailureReasonCode)
+			assert.Equal(t, tt.expected, *result.FailureReasonCode)
+			assert.Equal(t, "Nobody home", result.Notes)
+		})
+	}
+}
+
+func TestUpdateDeliveryStatus_FailedLastAttemptIsDeadLettered(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxAttempts    int
+		attemptNumber  int
+		expectedStatus domain.DeliveryStatus
+	}{
+		{name: "attempts left", maxAttempts: 3, attemptNumber: 2, expectedStatus: domain.DeliveryStatusFailed},
+		{name: "last attempt", maxAttempts: 3, attemptNumber: 3, expectedStatus: domain.DeliveryStatusDeadLetter},
+		{name: "single attempt allowed", maxAttempts: 1, attemptNumber: 0, expectedStatus: domain.DeliveryStatusDeadLetter},
+		{name: "unlimited", maxAttempts: 0, attemptNumber: 10, expectedStatus: domain.DeliveryStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			recorder := &recordingNotifier{}
+			cfg := service.DefaultConfig()
+			cfg.MaxAttempts = tt.maxAttempts
+			cfg.Notifier = recorder
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			id := uuid.New()
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().GetByIDForUpdate(ctx, id).Return(&domain.DeliveryAssignment{
+				ID:            id,
+				Status:        domain.DeliveryStatusInTransit,
+				AttemptNumber: tt.attemptNumber,
+			}, nil)
+			mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+			result, err := uc.UpdateDeliveryStatus(ctx, id, domain.DeliveryStatusFailed, "", "", "WRONG_ADDRESS")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			require.NotNil(t, result.FailureReasonCode)
+			assert.Equal(t, "WRONG_ADDRESS", *result.FailureReasonCode)
+			assert.Equal(t, []domain.DeliveryStatus{tt.expectedStatus}, recorder.statuses())
+			if tt.expectedStatus == domain.DeliveryStatusDeadLetter {
+				require.NotNil(t, result.DeadLetterReason)
+				assert.Contains(t, *result.DeadLetterReason, "max delivery attempts")
+			} else {
+				assert.Nil(t, result.DeadLetterReason)
+			}
+		})
+	}
+}
+
+func TestGetDeliveryStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+	expected := &domain.DeliveryStatusInfo{
+		ID:        id,
+		Status:    domain.DeliveryStatusPickedUp,
+		UpdatedAt: time.Now(),
+	}
+
+	// Must use the status-only query, never the full row
+	mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+	mockRepo.EXPECT().GetStatusByID(ctx, id).Return(expected, nil).Times(1)
+
+	result, err := uc.GetDeliveryStatus(ctx, id)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestGetSLADeadline(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	tests := []struct {
+		name              string
+		estimatedDelivery time.Time
+		expectedRemaining time.Duration
+	}{
+		{
+			name:              "deadline ahead",
+			estimatedDelivery: now.Add(90 * time.Minute),
+			expectedRemaining: 90 * time.Minute,
+		},
+		{
+			name:              "deadline passed",
+			estimatedDelivery: now.Add(-15 * time.Minute),
+			expectedRemaining: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.DeliveryAssignment{
+				ID:                    id,
+				EstimatedDeliveryTime: tt.estimatedDelivery,
+			}, nil)
+
+			sla, err := uc.GetSLADeadline(ctx, id)
+
+			require.NoError(t, err)
+			assert.Equal(t, id, sla.ID)
+			assert.Equal(t, tt.estimatedDelivery, sla.Deadline)
+			assert.Equal(t, tt.expectedRemaining, sla.Remaining)
+		})
+	}
+}
+
+func TestGetDriverLeaderboard(t *testing.T) {
+	start := time.Now().Add(-7 * 24 * time.Hour)
+	end := time.Now()
+
+	tests := []struct {
+		name          string
+		limit         int
+		expectedLimit int
+		expectError   bool
+	}{
+		{name: "default limit", limit: 0, expectedLimit: constants.DefaultLeaderboardSize},
+		{name: "explicit limit", limit: 5, expectedLimit: 5},
+		{name: "limit capped", limit: 1000, expectedLimit: constants.MaxLeaderboardSize},
+		{name: "negative limit", limit: -1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+			ctx := context.Background()
+			if tt.expectError {
+				mockRepo.EXPECT().GetDriverLeaderboard(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().
+					GetDriverLeaderboard(ctx, start, end, tt.expectedLimit, constants.DefaultOnTimeGracePeriod).
+					Return([]domain.DriverStats{{Rank: 1, DriverID: "DRIVER-1", CompletedDeliveries: 3}}, nil).
+					Times(1)
+			}
+
+			result, err := uc.GetDriverLeaderboard(ctx, start, end, tt.limit)
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+		})
+	}
+}
+
+func TestDeleteDeliveriesByOrderID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().
+		GetByOrderID(ctx, "ORDER-123").
+		Return([]*domain.DeliveryAssignment{
+			{ID: ids[0], Status: domain.DeliveryStatusPending},
+			{ID: ids[1], Status: domain.DeliveryStatusAssigned},
+			{ID: ids[2], Status: domain.DeliveryStatusCancelled},
+		}, nil).
+		Times(1)
+	mockRepo.EXPECT().DeleteByOrderID(ctx, "ORDER-123").Return(ids, nil).Times(1)
+
+	deleted, err := uc.DeleteDeliveriesByOrderID(ctx, " ORDER-123 ")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestDeleteDeliveriesByOrderID_PickedUpConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	var txErr error
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			txErr = fn(mockRepo)
+			return txErr
+		})
+	// Both were waiting when checked, but one was picked up before the delete ran, so the delete
+	// skipped it
+	mockRepo.EXPECT().
+		GetByOrderID(ctx, "ORDER-123").
+		Return([]*domain.DeliveryAssignment{
+			{ID: uuid.New(), Status: domain.DeliveryStatusPending},
+			{ID: uuid.New(), Status: domain.DeliveryStatusAssigned},
+		}, nil)
+	mockRepo.EXPECT().DeleteByOrderID(ctx, "ORDER-123").Return([]uuid.UUID{uuid.New()}, nil)
+
+	deleted, err := uc.DeleteDeliveriesByOrderID(ctx, "ORDER-123")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Equal(t, int64(0), deleted)
+	// The transaction fails, so the partial delete is rolled back
+	assert.ErrorIs(t, txErr, domain.ErrConflict)
+}
+
+func TestDeleteDeliveriesByOrderID_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	// Everything was already deleted by the first attempt
+	mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-123").Return([]*domain.DeliveryAssignment{}, nil).Times(1)
+	mockRepo.EXPECT().DeleteByOrderID(ctx, "ORDER-123").Return(nil, nil).Times(1)
+
+	deleted, err := uc.DeleteDeliveriesByOrderID(ctx, "ORDER-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
+
+func TestDeleteDeliveriesByOrderID_PickedUp(t *testing.T) {
+	for _, status := range []domain.DeliveryStatus{
+		domain.DeliveryStatusPickedUp,
+		domain.DeliveryStatusInTransit,
+		domain.DeliveryStatusDelivered,
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+			ctx := context.Background()
+
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().
+				GetByOrderID(ctx, "ORDER-123").
+				Return([]*domain.DeliveryAssignment{
+					{ID: uuid.New(), Status: domain.DeliveryStatusPending},
+					{ID: uuid.New(), Status: status},
+				}, nil).
+				Times(1)
+			mockRepo.EXPECT().DeleteByOrderID(gomock.Any(), gomock.Any()).Times(0)
+
+			deleted, err := uc.DeleteDeliveriesByOrderID(ctx, "ORDER-123")
+
+			assert.ErrorIs(t, err, domain.ErrConflict)
+			assert.Equal(t, int64(0), deleted)
+		})
+	}
+}
+
+func TestBulkDelete(t *testing.T) {
+	cancelled := domain.DeliveryStatusCancelled
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	filters := service.ListFilters{Status: &cancelled, CreatedBefore: &cutoff}
+
+	setup := func(t *testing.T) (*mocks.MockDeliveryRepository, service.DeliveryUseCase) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+		logger, _ := zap.NewDevelopment()
+		return mockRepo, service.NewDeliveryUseCase(mockRepo, logger)
+	}
+
+	ctx := context.Background()
+
+	t.Run("preview returns token without deleting", func(t *testing.T) {
+		mockRepo, uc := setup(t)
+		mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1200), nil).Times(1)
+		mockRepo.EXPECT().DeleteMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := uc.BulkDelete(ctx, filters, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1200), result.Matched)
+		assert.NotEmpty(t, result.ConfirmToken)
+		assert.Zero(t, result.Deleted)
+	})
+
+	t.Run("token mismatch is rejected", func(t *testing.T) {
+		mockRepo, uc := setup(t)
+		mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1200), nil).Times(2)
+		mockRepo.EXPECT().DeleteMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		preview, err := uc.BulkDelete(ctx, filters, "")
+		require.NoError(t, err)
+
+		// The same token no longer matches once the filters differ
+		delivered := domain.DeliveryStatusDelivered
+		result, err := uc.BulkDelete(ctx, service.ListFilters{Status: &delivered, CreatedBefore: &cutoff}, preview.ConfirmToken)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Nil(t, result)
+	})
+
+	t.Run("token is stale once the count changes", func(t *testing.T) {
+		mockRepo, uc := setup(t)
+		gomock.InOrder(
+			mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1200), nil),
+			mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1201), nil),
+		)
+		mockRepo.EXPECT().DeleteMatching(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		preview, err := uc.BulkDelete(ctx, filters, "")
+		require.NoError(t, err)
+
+		_, err = uc.BulkDelete(ctx, filters, preview.ConfirmToken)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("matching token deletes in batches", func(t *testing.T) {
+		mockRepo, uc := setup(t)
+		mockRepo.EXPECT().CountMatching(ctx, gomock.Any()).Return(int64(1200), nil).Times(2)
+		gomock.InOrder(
+			mockRepo.EXPECT().DeleteMatching(ctx, gomock.Any(), constants.BulkDeleteBatchSize).Return(make([]uuid.UUID, 500), nil),
+			mockRepo.EXPECT().DeleteMatching(ctx, gomock.Any(), constants.BulkDeleteBatchSize).Return(make([]uuid.UUID, 500), nil),
+			mockRepo.EXPECT().DeleteMatching(ctx, gomock.Any(), constants.BulkDeleteBatchSize).Return(make([]uuid.UUID, 200), nil),
+		)
+
+		preview, err := uc.BulkDelete(ctx, filters, "")
+		require.NoError(t, err)
+
+		result, err := uc.BulkDelete(ctx, filters, preview.ConfirmToken)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1200), result.Matched)
+		assert.Equal(t, int64(1200), result.Deleted)
+	})
+
+	t.Run("requires a filter", func(t *testing.T) {
+		mockRepo, uc := setup(t)
+		mockRepo.EXPECT().CountMatching(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := uc.BulkDelete(ctx, service.ListFilters{}, "")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestSubmitFeedback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	mockRepo.EXPECT().
+		GetByID(ctx, id).
+		Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusDelivered}, nil).
+		Times(1)
+	mockRepo.EXPECT().
+		Update(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := uc.SubmitFeedback(ctx, id, 4, "On time")
+
+	require.NoError(t, err)
+	require.NotNil(t, result.Rating)
+	assert.Equal(t, 4, *result.Rating)
+}
+
+func TestSubmitFeedback_RatingOutOfRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	for _, rating := range []int{-1, 0, 6} {
+		t.Run(fmt.Sprintf("rating %d", rating), func(t *testing.T) {
+			// Should not hit the repository because validation fails
+			mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := uc.SubmitFeedback(ctx, uuid.New(), rating, "")
+
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "rating", validationErr.Field)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestSubmitFeedback_NotDelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	mockRepo.EXPECT().
+		GetByID(ctx, id).
+		Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusInTransit}, nil).
+		Times(1)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := uc.SubmitFeedback(ctx, id, 5, "")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+	assert.Nil(t, result)
+}
+
+func TestMarkDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	mockRepo.EXPECT().
+		GetByID(ctx, id).
+		Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusFailed}, nil).
+		Times(1)
+	mockRepo.EXPECT().
+		Update(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := uc.MarkDeadLetter(ctx, id, "  recipient moved abroad  ")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusDeadLetter, result.Status)
+	require.NotNil(t, result.DeadLetterReason)
+	assert.Equal(t, "recipient moved abroad", *result.DeadLetterReason)
+}
+
+func TestMarkDeadLetter_NotFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	mockRepo.EXPECT().
+		GetByID(ctx, id).
+		Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusInTransit}, nil).
+		Times(1)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := uc.MarkDeadLetter(ctx, id, "lost")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Nil(t, result)
+}
+
+func TestMarkDeadLetter_ReasonRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	// Should not hit the repository because validation fails
+	mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := uc.MarkDeadLetter(context.Background(), uuid.New(), "   ")
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "reason", validationErr.Field)
+	assert.Nil(t, result)
+}
+
+func TestListDeliveryAssignments_PaginationDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		input            service.ListDeliveryInput
+		expectedPage     int
+		expectedPageSize int
+	}{
+		{
+			name:             "zero values use defaults",
+			input:            service.ListDeliveryInput{},
+			expectedPage:     constants.DefaultPage,
+			expectedPageSize: constants.DefaultPageSize,
+		},
+		{
+			name:             "page size above max falls back to default",
+			input:            service.ListDeliveryInput{Page: 2, PageSize: constants.MaxPageSize + 1},
+			expectedPage:     2,
+			expectedPageSize: constants.DefaultPageSize,
+		},
+		{
+			name:             "page size at max is kept",
+			input:            service.ListDeliveryInput{Page: 1, PageSize: constants.MaxPageSize},
+			expectedPage:     1,
+			expectedPageSize: constants.MaxPageSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+			mockRepo.EXPECT().
+				List(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, int64, error) {
+					assert.Equal(t, tt.expectedPage, filters.Page)
+					assert.Equal(t, tt.expectedPageSize, filters.PageSize)
+					return nil, 0, nil
+				}).
+				Times(1)
+
+			_, _, err := uc.ListDeliveryAssignments(ctx, tt.input)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestListDeliveryAssignments_NegativePagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+
+	for _, input := range []service.ListDeliveryInput{
+		{Page: -1, PageSize: 10},
+		{Page: 1, PageSize: -5},
+	} {
+		mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+
+		result, _, err := uc.ListDeliveryAssignments(ctx, input)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+	}
+}
+
+func TestBatchGetDeliveryAssignments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	existingA := &domain.DeliveryAssignment{ID: uuid.New(), OrderID: "ORDER-1"}
+	existingB := &domain.DeliveryAssignment{ID: uuid.New(), OrderID: "ORDER-2"}
+	missing := uuid.New()
+
+	// Duplicates are collapsed before hitting the repository
+	ids := []uuid.UUID{existingA.ID, missing, existingB.ID, existingA.ID}
+
+	mockRepo.EXPECT().
+		GetByIDs(ctx, []uuid.UUID{existingA.ID, missing, existingB.ID}).
+		Return([]*domain.DeliveryAssignment{existingA, existingB}, nil).
+		Times(1)
+
+	result, err := uc.BatchGetDeliveryAssignments(ctx, ids)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*domain.DeliveryAssignment{existingA, existingB}, result)
+}
+
+func TestBatchGetDeliveryAssignments_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	cfg := service.DefaultConfig()
+	cfg.Limits.BatchGet = 2
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetByIDs(gomock.Any(), gomock.Len(2)).Return(nil, nil)
+
+	for _, ids := range [][]uuid.UUID{
+		nil,
+		{uuid.New(), uuid.New(), uuid.New()},
+	} {
+		result, err := uc.BatchGetDeliveryAssignments(ctx, ids)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, result)
+	}
+
+	// Exactly at the limit is accepted
+	_, err := uc.BatchGetDeliveryAssignments(ctx, []uuid.UUID{uuid.New(), uuid.New()})
+	require.NoError(t, err)
+
+	// One over names the limit
+	_, err = uc.BatchGetDeliveryAssignments(ctx, []uuid.UUID{uuid.New(), uuid.New(), uuid.New()})
+	assert.ErrorContains(t, err, "batch limit of 2")
+}
+
+func TestListModifiedSince_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Limits.SyncBatch = 50
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Exactly at the limit is accepted; no limit defaults to the smaller of the default and the limit
+	start := domain.SyncCursorSince(since)
+	mockRepo.EXPECT().ListModifiedSince(gomock.Any(), start, 50).Return(nil, nil).Times(2)
+	_, next, err := uc.ListModifiedSince(ctx, since, "", 50)
+	require.NoError(t, err)
+	assert.Equal(t, start, next, "an empty batch keeps the position")
+	_, _, err = uc.ListModifiedSince(ctx, since, "", 0)
+	require.NoError(t, err)
+
+	// One over is rejected without querying
+	_, _, err = uc.ListModifiedSince(ctx, since, "", 51)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.ErrorContains(t, err, "batch limit of 50")
+}
+
+func TestListModifiedSince_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+	ctx := context.Background()
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := &domain.DeliveryAssignment{ID: uuid.New(), UpdatedAt: modified}
+	last := &domain.DeliveryAssignment{ID: uuid.New(), UpdatedAt: modified}
+
+	// The next cursor is the last delivery of the batch, so ties on its time are resumed by ID
+	mockRepo.EXPECT().ListModifiedSince(gomock.Any(), domain.SyncCursorSince(time.Time{}), 2).
+		Return([]*domain.DeliveryAssignment{first, last}, nil)
+	_, next, err := uc.ListModifiedSince(ctx, time.Time{}, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SyncCursor{ModifiedAt: modified, ID: last.ID}, next)
+
+	// A cursor takes precedence over since
+	mockRepo.EXPECT().ListModifiedSince(gomock.Any(), gomock.Any(), 2).DoAndReturn(
+		func(_ context.Context, after domain.SyncCursor, _ int) ([]*domain.DeliveryAssignment, error) {
+			assert.True(t, modified.Equal(after.ModifiedAt))
+			assert.Equal(t, last.ID, after.ID)
+			return nil, nil
+		})
+	_, _, err = uc.ListModifiedSince(ctx, modified.Add(time.Hour), next.String(), 2)
+	require.NoError(t, err)
+
+	// A malformed cursor is rejected without querying
+	_, _, err = uc.ListModifiedSince(ctx, time.Time{}, "not-a-cursor", 2)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.ErrorContains(t, err, "cursor")
+}
+
+// fixedClock always returns the same instant
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestUseCaseClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now: now}
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := context.Background()
+
+	t.Run("create uses injected clock", func(t *testing.T) {
+		mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+
+		result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+			OrderID:               "ORDER-123",
+			ScheduledPickupTime:   now.Add(1 * time.Hour),
+			EstimatedDeliveryTime: now.Add(3 * time.Hour),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, now, result.CreatedAt)
+		assert.Equal(t, now, result.UpdatedAt)
+	})
+
+	t.Run("status update uses injected clock for loaded entities", func(t *testing.T) {
+		driverID := "DRIVER-123"
+		existing := &domain.DeliveryAssignment{
+			ID:        uuid.New(),
+			Status:    domain.DeliveryStatusAssigned,
+			DriverID:  &driverID,
+			UpdatedAt: now.Add(-1 * time.Hour),
+		}
+
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByIDForUpdate(ctx, existing.ID).Return(existing, nil).Times(1)
+		mockRepo.EXPECT().Update(ctx, existing).Return(nil).Times(1)
+
+		result, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusPickedUp, "", "", "")
+
+		require.NoError(t, err)
+		require.NotNil(t, result.ActualPickupTime)
+		assert.Equal(t, now, *result.ActualPickupTime)
+		assert.Equal(t, now, result.UpdatedAt)
+	})
+}
+
+func TestCreateDeliveryAssignment_SchedulingHorizon(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		pickupIn    time.Duration
+		expectError bool
+	}{
+		{name: "pickup 2 hours out passes", pickupIn: 2 * time.Hour, expectError: false},
+		{name: "pickup 40 days out is rejected", pickupIn: 40 * 24 * time.Hour, expectError: true},
+		{name: "pickup 10 minutes out is rejected", pickupIn: 10 * time.Minute, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+			ctx := context.Background()
+			input := service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(tt.pickupIn),
+				EstimatedDeliveryTime: now.Add(tt.pickupIn + 2*time.Hour),
+			}
+
+			if tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, input)
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "scheduled_pickup_time", validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_ConfigurableHorizon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now: now}
+	cfg.MaxScheduleAdvance = 60 * 24 * time.Hour
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+
+	result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+		OrderID:               "ORDER-123",
+		ScheduledPickupTime:   now.Add(40 * 24 * time.Hour),
+		EstimatedDeliveryTime: now.Add(40*24*time.Hour + 2*time.Hour),
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestCreateDeliveryAssignment_TimeZone(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		timeZone    string
+		expectError bool
+	}{
+		{name: "empty means UTC", timeZone: "", expectError: false},
+		{name: "IANA zone", timeZone: "America/New_York", expectError: false},
+		{name: "unknown zone", timeZone: "America/Atlantis", expectError: true},
+		{name: "server local zone", timeZone: "Local", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(2 * time.Hour),
+				EstimatedDeliveryTime: now.Add(4 * time.Hour),
+				TimeZone:              tt.timeZone,
+			})
+
+			if tt.expectError {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "time_zone", validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.timeZone, result.TimeZone)
+				// Storage stays in UTC
+				assert.Equal(t, now.Add(2*time.Hour), result.ScheduledPickupTime)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_AllowPastScheduling(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	pickup := now.Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		serverAllow bool
+		inputAllow  bool
+		errorField  string // empty means accepted
+	}{
+		{name: "past pickup rejected by default", errorField: "scheduled_pickup_time"},
+		{name: "past pickup accepted when enabled and requested", serverAllow: true, inputAllow: true},
+		{name: "enabled but not requested", serverAllow: true, errorField: "scheduled_pickup_time"},
+		{name: "requested but disabled", inputAllow: true, errorField: "allow_past_scheduling"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			cfg.AllowPastScheduling = tt.serverAllow
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   pickup,
+				EstimatedDeliveryTime: pickup.Add(time.Hour),
+				AllowPastScheduling:   tt.inputAllow,
+			})
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, pickup, result.ScheduledPickupTime)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_BusinessHours(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) // Sunday
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	hours, err := domain.ParseBusinessHours("MON=08:00-20:00,TUE=08:00-20:00,SAT=22:00-02:00")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		timeZone   string
+		pickup     time.Time
+		delivery   time.Time
+		errorField string // empty means accepted
+	}{
+		{
+			name:     "in hours",
+			pickup:   monday.Add(10 * time.Hour),
+			delivery: monday.Add(12 * time.Hour),
+		},
+		{
+			name:       "pickup before opening",
+			pickup:     monday.Add(6 * time.Hour),
+			delivery:   monday.Add(9 * time.Hour),
+			errorField: "scheduled_pickup_time",
+		},
+		{
+			name:       "delivery after closing",
+			pickup:     monday.Add(18 * time.Hour),
+			delivery:   monday.Add(21 * time.Hour),
+			errorField: "estimated_delivery_time",
+		},
+		{
+			name:     "overnight window past midnight",
+			pickup:   time.Date(2026, 3, 8, 1, 0, 0, 0, time.UTC), // Sunday, in Saturday's window
+			delivery: time.Date(2026, 3, 8, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "hours read in the delivery's time zone",
+			timeZone: "America/New_York",
+			pickup:   monday.Add(13 * time.Hour), // 08:00 EST
+			delivery: monday.Add(24 * time.Hour), // 19:00 EST
+		},
+		{
+			name:       "off hours in the delivery's time zone",
+			timeZone:   "America/New_York",
+			pickup:     monday.Add(10 * time.Hour), // 05:00 EST
+			delivery:   monday.Add(15 * time.Hour),
+			errorField: "scheduled_pickup_time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			cfg.BusinessHours = hours
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   tt.pickup,
+				EstimatedDeliveryTime: tt.delivery,
+				TimeZone:              tt.timeZone,
+			})
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Equal(t, "is outside business hours", validationErr.Message)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_DeliveryWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	estimate := now.Add(4 * time.Hour)
+	at := func(d time.Duration) *time.Time {
+		t := estimate.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name          string
+		slack         time.Duration
+		start, end    *time.Time
+		expectStart   *time.Time
+		expectEnd     *time.Time
+		expectErrorOn string
+	}{
+		{name: "derived from estimate", slack: 30 * time.Minute, expectStart: at(-30 * time.Minute), expectEnd: at(30 * time.Minute)},
+		{name: "derivation disabled", slack: 0},
+		{name: "explicit window", slack: 30 * time.Minute, start: at(-time.Hour), end: at(0), expectStart: at(-time.Hour), expectEnd: at(0)},
+		{name: "start equals end", start: at(0), end: at(0), expectErrorOn: "estimated_delivery_window_end"},
+		{name: "start after end", start: at(time.Hour), end: at(0), expectErrorOn: "estimated_delivery_window_end"},
+		{name: "missing end", start: at(0), expectErrorOn: "estimated_delivery_window_end"},
+		{name: "missing start", end: at(0), expectErrorOn: "estimated_delivery_window_start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			cfg.DeliveryWindowSlack = tt.slack
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.expectErrorOn != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:                      "ORDER-123",
+				ScheduledPickupTime:          now.Add(2 * time.Hour),
+				EstimatedDeliveryTime:        estimate,
+				EstimatedDeliveryWindowStart: tt.start,
+				EstimatedDeliveryWindowEnd:   tt.end,
+			})
+
+			if tt.expectErrorOn != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.expectErrorOn, validationErr.Field)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectStart, result.EstimatedDeliveryWindowStart)
+			assert.Equal(t, tt.expectEnd, result.EstimatedDeliveryWindowEnd)
+		})
+	}
+}
+
+func TestAppendNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	existing := &domain.DeliveryAssignment{
+		ID:     uuid.New(),
+		Status: domain.DeliveryStatusInTransit,
+		Notes:  "Handle with care",
+	}
+
+	// The repository keeps the stored timeline; GetByID returns a fresh copy each time
+	var stored []domain.TimedNote
+	mockRepo.EXPECT().
+		GetByID(ctx, existing.ID).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID) (*domain.DeliveryAssignment, error) {
+			copied := *existing
+			copied.TimelineNotes = append([]domain.TimedNote(nil), stored...)
+			return &copied, nil
+		}).
+		Times(2)
+	mockRepo.EXPECT().
+		AppendNote(ctx, existing.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, note domain.TimedNote) error {
+			stored = append(stored, note)
+			return nil
+		}).
+		Times(2)
+
+	_, err := uc.AppendNote(ctx, existing.ID, "stuck in traffic")
+	require.NoError(t, err)
+
+	result, err := uc.AppendNote(ctx, existing.ID, "  called customer  ")
+	require.NoError(t, err)
+
+	require.Len(t, result.TimelineNotes, 2)
+	assert.Equal(t, "stuck in traffic", result.TimelineNotes[0].Note)
+	assert.Equal(t, "called customer", result.TimelineNotes[1].Note)
+	assert.False(t, result.TimelineNotes[1].CreatedAt.Before(result.TimelineNotes[0].CreatedAt))
+	assert.Equal(t, domain.DeliveryStatusInTransit, result.Status)
+	assert.Equal(t, "Handle with care", result.Notes)
+}
+
+func TestAppendNote_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := uc.AppendNote(context.Background(), uuid.New(), "   ")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Nil(t, result)
+}
+
+func TestUpdateDriverETA(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	estimate := now.Add(time.Hour)
+
+	// now is a Sunday
+	businessHours, err := domain.ParseBusinessHours("SUN=08:00-20:00")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		status    domain.DeliveryStatus
+		eta       time.Time
+		expectErr error
+		// movedOn makes the delivery leave PICKED_UP/IN_TRANSIT between the read and the write
+		movedOn bool
+	}{
+		{name: "picked up", status: domain.DeliveryStatusPickedUp, eta: now.Add(90 * time.Minute)},
+		{name: "in transit", status: domain.DeliveryStatusInTransit, eta: now.Add(20 * time.Minute)},
+		{name: "assigned", status: domain.DeliveryStatusAssigned, eta: now.Add(20 * time.Minute), expectErr: domain.ErrConflict},
+		{name: "delivered", status: domain.DeliveryStatusDelivered, eta: now.Add(20 * time.Minute), expectErr: domain.ErrConflict},
+		{name: "eta in the past", status: domain.DeliveryStatusInTransit, eta: now.Add(-5 * time.Minute), expectErr: domain.ErrInvalidInput},
+		{name: "eta outside business hours", status: domain.DeliveryStatusInTransit, eta: now.Add(12 * time.Hour), expectErr: domain.ErrInvalidInput},
+		{name: "delivered concurrently", status: domain.DeliveryStatusInTransit, eta: now.Add(20 * time.Minute), movedOn: true, expectErr: domain.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			recorder := &recordingNotifier{}
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now}
+			cfg.Notifier = recorder
+			cfg.BusinessHours = businessHours
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			driverID := "DRIVER-1"
+			existing := &domain.DeliveryAssignment{
+				ID:                    uuid.New(),
+				OrderID:               "ORDER-1",
+				DriverID:              &driverID,
+				Status:                tt.status,
+				EstimatedDeliveryTime: estimate,
+			}
+
+			mockRepo.EXPECT().GetByID(ctx, existing.ID).Return(existing, nil)
+			mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+			switch {
+			case tt.movedOn:
+				mockRepo.EXPECT().UpdateDriverETA(ctx, existing.ID, tt.eta, now).Return(nil, domain.ErrNotFound)
+			case tt.expectErr == nil:
+				// Only the ETA is written; the stored delivery comes back as updated
+				mockRepo.EXPECT().UpdateDriverETA(ctx, existing.ID, tt.eta, now).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, eta, updatedAt time.Time) (*domain.DeliveryAssignment, error) {
+						stored := *existing
+						stored.DriverETA = &eta
+						stored.UpdatedAt = updatedAt
+						return &stored, nil
+					})
+			default:
+				mockRepo.EXPECT().UpdateDriverETA(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			result, err := uc.UpdateDriverETA(ctx, existing.ID, tt.eta)
+
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				assert.Nil(t, result)
+				assert.Empty(t, recorder.changes)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, result.DriverETA)
+			assert.Equal(t, tt.eta, *result.DriverETA)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, estimate, result.EstimatedDeliveryTime)
+			assert.Equal(t, now, result.UpdatedAt)
+
+			require.Len(t, recorder.changes, 1)
+			change := recorder.changes[0]
+			assert.True(t, change.ETAUpdate)
+			assert.Equal(t, tt.status, change.Status)
+			require.NotNil(t, change.DriverETA)
+			assert.Equal(t, tt.eta, *change.DriverETA)
+		})
+	}
+}
+
+func TestContextErrorsPropagate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		id := uuid.New()
+		mockRepo.EXPECT().
+			GetByID(ctx, id).
+			DoAndReturn(func(ctx context.Context, _ uuid.UUID) (*domain.DeliveryAssignment, error) {
+				return nil, fmt.Errorf("query failed: %w", ctx.Err())
+			}).
+			Times(1)
+
+		result, err := uc.GetDeliveryAssignment(ctx, id)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("expired deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		mockRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ service.ListFilters) ([]*domain.DeliveryAssignment, int64, error) {
+				return nil, 0, ctx.Err()
+			}).
+			Times(1)
+
+		result, _, err := uc.ListDeliveryAssignments(ctx, service.ListDeliveryInput{})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, result)
+	})
+}
+
+func TestCloneDeliveryAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	now := time.Now()
+	driverID := "DRIVER-123"
+	rating := 5
+	pickedUp := now.Add(-26 * time.Hour)
+	delivered := now.Add(-24 * time.Hour)
+	source := &domain.DeliveryAssignment{
+		ID:                 uuid.New(),
+		OrderID:            "ORDER-123",
+		DriverID:           &driverID,
+		Status:             domain.DeliveryStatusDelivered,
+		PickupAddress:      domain.Address{City: "New York"},
+		DeliveryAddress:    domain.Address{City: "Boston"},
+		ActualPickupTime:   &pickedUp,
+		ActualDeliveryTime: &delivered,
+		Notes:              "Leave at reception",
+		Rating:             &rating,
+	}
+
+	newPickup := now.Add(2 * time.Hour)
+	newDelivery := now.Add(4 * time.Hour)
+
+	mockRepo.EXPECT().GetByID(ctx, source.ID).Return(source, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+
+	clone, err := uc.CloneDeliveryAssignment(ctx, source.ID, newPickup, newDelivery)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, domain.DeliveryStatusPending, clone.Status)
+	assert.Nil(t, clone.DriverID)
+	assert.Nil(t, clone.ActualPickupTime)
+	assert.Nil(t, clone.ActualDeliveryTime)
+	assert.Nil(t, clone.Rating)
+	assert.Equal(t, source.OrderID, clone.OrderID)
+	assert.Equal(t, source.PickupAddress, clone.PickupAddress)
+	assert.Equal(t, source.DeliveryAddress, clone.DeliveryAddress)
+	assert.Equal(t, source.Notes, clone.Notes)
+	assert.Equal(t, newPickup, clone.ScheduledPickupTime)
+	assert.Equal(t, newDelivery, clone.EstimatedDeliveryTime)
+}
+
+func TestCloneDeliveryAssignment_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	uc := service.NewDeliveryUseCase(mockRepo, logger)
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	mockRepo.EXPECT().GetByID(ctx, id).Return(nil, domain.ErrNotFound).Times(1)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	clone, err := uc.CloneDeliveryAssignment(ctx, id, time.Now().Add(2*time.Hour), time.Now().Add(4*time.Hour))
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, clone)
+}
+
+func TestCreateDeliveryAssignment_MaxActivePerOrder(t *testing.T) {
+	now := time.Now()
+	input := service.CreateDeliveryInput{
+		OrderID:               "ORDER-123",
+		ScheduledPickupTime:   now.Add(1 * time.Hour),
+		EstimatedDeliveryTime: now.Add(3 * time.Hour),
+	}
+
+	// existing builds assignments for the order with the given statuses
+	existing := func(statuses ...domain.DeliveryStatus) []*domain.DeliveryAssignment {
+		assignments := make([]*domain.DeliveryAssignment, len(statuses))
+		for i, s := range statuses {
+			assignments[i] = &domain.DeliveryAssignment{ID: uuid.New(), OrderID: input.OrderID, Status: s}
+		}
+		return assignments
+	}
+
+	tests := []struct {
+		name        string
+		existing    []*domain.DeliveryAssignment
+		expectError bool
+	}{
+		{
+			name:        "below limit",
+			existing:    existing(domain.DeliveryStatusPending),
+			expectError: false,
+		},
+		{
+			name:        "terminal deliveries do not count",
+			existing:    existing(domain.DeliveryStatusDelivered, domain.DeliveryStatusFailed, domain.DeliveryStatusCancelled),
+			expectError: false,
+		},
+		{
+			name:        "at limit",
+			existing:    existing(domain.DeliveryStatusPending, domain.DeliveryStatusInTransit),
+			expectError: true,
+		},
+		{
+			name:        "over limit",
+			existing:    existing(domain.DeliveryStatusPending, domain.DeliveryStatusAssigned, domain.DeliveryStatusPickedUp),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			logger, _ := zap.NewDevelopment()
+			cfg := service.DefaultConfig()
+			cfg.MaxActivePerOrder = 2
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(ctx, input.OrderID).Return(tt.existing, nil).Times(1)
+			if tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, input)
+
+			if tt.expectError {
+				assert.ErrorIs(t, err, domain.ErrConflict)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, result)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_MaxActivePerOrderDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	cfg := service.DefaultConfig()
+	cfg.MaxActivePerOrder = 0
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Times(0)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+
+	result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+		OrderID:               "ORDER-123",
+		ScheduledPickupTime:   now.Add(1 * time.Hour),
+		EstimatedDeliveryTime: now.Add(3 * time.Hour),
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+func TestCreateDeliveryAssignment_RequiredVehicleType(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	vehicle := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		vehicleType *string
+		expected    *string
+		expectError bool
+	}{
+		{name: "no requirement", vehicleType: nil, expected: nil, expectError: false},
+		{name: "allowed type", vehicleType: vehicle("VAN"), expected: vehicle("VAN"), expectError: false},
+		{name: "normalized to upper case", vehicleType: vehicle(" refrigerated "), expected: vehicle("REFRIGERATED"), expectError: false},
+		{name: "blank means no requirement", vehicleType: vehicle("  "), expected: nil, expectError: false},
+		{name: "unknown type", vehicleType: vehicle("HOVERCRAFT"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.expectError {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(2 * time.Hour),
+				EstimatedDeliveryTime: now.Add(4 * time.Hour),
+				RequiredVehicleType:   tt.vehicleType,
+			})
+
+			if tt.expectError {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "required_vehicle_type", validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, result.RequiredVehicleType)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_Coordinates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		pickup     domain.Address
+		delivery   domain.Address
+		errorField string
+	}{
+		{name: "valid", pickup: domain.Address{Latitude: 40.7128, Longitude: -74.006}, delivery: domain.Address{Latitude: 40.7306, Longitude: -73.9352}},
+		{name: "not provided", pickup: domain.Address{}, delivery: domain.Address{}},
+		{name: "pickup latitude out of range", pickup: domain.Address{Latitude: 91, Longitude: -74.006}, errorField: "pickup_address.latitude"},
+		{name: "pickup longitude out of range", pickup: domain.Address{Latitude: 40.7128, Longitude: -181}, errorField: "pickup_address.longitude"},
+		{name: "delivery latitude out of range", delivery: domain.Address{Latitude: -90.5, Longitude: 10}, errorField: "delivery_address.latitude"},
+		{name: "delivery longitude out of range", delivery: domain.Address{Latitude: 10, Longitude: 200}, errorField: "delivery_address.longitude"},
+		{name: "delivery swapped", delivery: domain.Address{Latitude: -122.4194, Longitude: 37.7749}, errorField: "delivery_address.latitude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				PickupAddress:         tt.pickup,
+				DeliveryAddress:       tt.delivery,
+				ScheduledPickupTime:   now.Add(2 * time.Hour),
+				EstimatedDeliveryTime: now.Add(4 * time.Hour),
+			})
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_RecipientNotifications(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	email := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		email         *string
+		prefs         []domain.DeliveryStatus
+		expectedEmail *string
+		expectedPrefs domain.NotifyPrefs
+		errorField    string
+	}{
+		{name: "no email", email: nil},
+		{name: "valid email", email: email("jane@example.com"), expectedEmail: email("jane@example.com")},
+		{name: "trimmed", email: email(" jane@example.com "), expectedEmail: email("jane@example.com")},
+		{name: "blank means none", email: email("  "), expectedEmail: nil},
+		{name: "missing domain", email: email("jane@"), errorField: "recipient_email"},
+		{name: "display name", email: email("Jane <jane@example.com>"), errorField: "recipient_email"},
+		{
+			name:          "prefs de-duplicated",
+			email:         email("jane@example.com"),
+			prefs:         []domain.DeliveryStatus{domain.DeliveryStatusDelivered, domain.DeliveryStatusPickedUp, domain.DeliveryStatusDelivered},
+			expectedEmail: email("jane@example.com"),
+			expectedPrefs: domain.NotifyPrefs{domain.DeliveryStatusDelivered, domain.DeliveryStatusPickedUp},
+		},
+		{name: "unknown pref", prefs: []domain.DeliveryStatus{"LOST"}, errorField: "notify_prefs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(2 * time.Hour),
+				EstimatedDeliveryTime: now.Add(4 * time.Hour),
+				RecipientEmail:        tt.email,
+				NotifyPrefs:           tt.prefs,
+			})
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedEmail, result.RecipientEmail)
+				assert.Equal(t, tt.expectedPrefs, result.NotifyPrefs)
+			}
+		})
+	}
+}
+
+func TestCreateDeliveryAssignment_Metadata(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	schema, err := jsonschema.Parse([]byte(`{
+		"type": "object",
+		"required": ["gate_code"],
+		"properties": {"gate_code": {"type": "string"}, "floor": {"type": "integer"}}
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		tenantID   string
+		metadata   map[string]any
+		errorField string
+	}{
+		{name: "matches the schema", tenantID: "tenant-7", metadata: map[string]any{"gate_code": "4711", "floor": float64(3)}},
+		{name: "missing gate code", tenantID: "tenant-7", metadata: map[string]any{"floor": float64(3)}, errorField: "metadata.gate_code"},
+		{name: "gate code not a string", tenantID: "tenant-7", metadata: map[string]any{"gate_code": float64(4711)}, errorField: "metadata.gate_code"},
+		{name: "no metadata is not checked", tenantID: "tenant-7"},
+		{name: "tenant without a schema", tenantID: "tenant-8", metadata: map[string]any{"floor": "third"}},
+		{name: "caller without a tenant", metadata: map[string]any{"floor": "third"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now: now}
+			cfg.MetadataValidator = service.MetadataSchemas{"tenant-7": schema}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := domain.ContextWithActor(context.Background(), domain.Actor{UserID: "ops", TenantID: tt.tenantID})
+			mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			if tt.errorField != "" {
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(1)
+			}
+
+			result, err := uc.CreateDeliveryAssignment(ctx, service.CreateDeliveryInput{
+				OrderID:               "ORDER-123",
+				ScheduledPickupTime:   now.Add(2 * time.Hour),
+				EstimatedDeliveryTime: now.Add(4 * time.Hour),
+				Metadata:              tt.metadata,
+			})
+
+			if tt.errorField != "" {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.errorField, validationErr.Field)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.metadata, result.Metadata)
+			}
+		})
+	}
+}
+
+func TestListDeliveryAssignments_RequiredVehicleType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+	vehicleType := "van"
+	mockRepo.EXPECT().
+		List(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filters service.ListFilters) ([]*domain.DeliveryAssignment, int64, error) {
+			require.NotNil(t, filters.RequiredVehicleType)
+			assert.Equal(t, "VAN", *filters.RequiredVehicleType)
+			return nil, 0, nil
+		})
+
+	_, _, err := uc.ListDeliveryAssignments(context.Background(), service.ListDeliveryInput{RequiredVehicleType: &vehicleType})
+	require.NoError(t, err)
+}
+
+func TestAddTags_Idempotent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	stored := &domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending}
+	mockRepo.EXPECT().GetByID(ctx, id).DoAndReturn(func(context.Context, uuid.UUID) (*domain.DeliveryAssignment, error) {
+		copied := *stored
+		return &copied, nil
+	}).Times(2)
+	// Only the first call changes anything, so only it is saved
+	mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *domain.DeliveryAssignment) error {
+		stored = a
+		return nil
+	}).Times(1)
+
+	first, err := uc.AddTags(ctx, id, []string{" VIP ", "promo", "vip"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip", "promo"}, first.Tags)
+
+	second, err := uc.AddTags(ctx, id, []string{"Promo"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip", "promo"}, second.Tags)
+}
+
+func TestRemoveTags_Idempotent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+	ctx := context.Background()
+	id := uuid.New()
+
+	stored := &domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending, Tags: []string{"vip", "promo"}}
+	mockRepo.EXPECT().GetByID(ctx, id).DoAndReturn(func(context.Context, uuid.UUID) (*domain.DeliveryAssignment, error) {
+		copied := *stored
+		return &copied, nil
+	}).Times(2)
+	mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *domain.DeliveryAssignment) error {
+		stored = a
+		return nil
+	}).Times(1)
+
+	first, err := uc.RemoveTags(ctx, id, []string{"VIP"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"promo"}, first.Tags)
+
+	second, err := uc.RemoveTags(ctx, id, []string{"vip"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"promo"}, second.Tags)
+}
+
+func TestAddTags_InvalidTag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+
+	// Should not hit the repository because validation fails
+	mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+
+	for _, tags := range [][]string{nil, {"  "}, {strings.Repeat("x", constants.MaxTagLength+1)}} {
+		_, err := uc.AddTags(context.Background(), uuid.New(), tags)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	}
+}
+
+func TestGetMetricsForDrivers_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Limits.DriverMetrics = 2
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	// Duplicates collapse, so two distinct drivers are within the limit
+	mockRepo.EXPECT().
+		GetMetricsForDrivers(ctx, []string{"DRIVER-A", "DRIVER-B"}, start, end, cfg.OnTimeGracePeriod).
+		Return(map[string]*domain.DeliveryMetrics{"DRIVER-A": {}, "DRIVER-B": {}}, nil)
+	_, err := uc.GetMetricsForDrivers(ctx, []string{"DRIVER-A", "DRIVER-B", "DRIVER-A"}, start, end)
+	require.NoError(t, err)
+
+	for _, driverIDs := range [][]string{nil, {" "}, {"DRIVER-A", "DRIVER-B", "DRIVER-C"}} {
+		_, err := uc.GetMetricsForDrivers(ctx, driverIDs, start, end)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	}
+}
+
+func TestSplitDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now}
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	id := uuid.New()
+	driverID := "DRIVER-1"
+	pickedUp := now.Add(-time.Hour)
+	parent := &domain.DeliveryAssignment{
+		ID:                    id,
+		OrderID:               "ORDER-1",
+		DriverID:              &driverID,
+		Status:                domain.DeliveryStatusInTransit,
+		ScheduledPickupTime:   now.Add(-2 * time.Hour),
+		EstimatedDeliveryTime: now.Add(-30 * time.Minute),
+		ActualPickupTime:      &pickedUp,
+		Tags:                  []string{"vip"},
+		Packages:              []domain.Package{{Description: "Box", Quantity: 1}, {Description: "Drinks crate", Quantity: 3}},
+	}
+	remaining := []domain.Package{{Description: "Drinks crate", Quantity: 2}}
+
+	// Both writes happen inside one transaction
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().GetByID(ctx, id).Return(parent, nil)
+	updated := mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, a *domain.DeliveryAssignment) error {
+		assert.Equal(t, domain.DeliveryStatusDelivered, a.Status)
+		// The moved packages leave the parent in the same transaction, so none is counted twice
+		assert.Equal(t, []domain.Package{{Description: "Box", Quantity: 1}, {Description: "Drinks crate", Quantity: 1}}, a.Packages)
+		return nil
+	})
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).After(updated)
+
+	gotParent, child, err := uc.SplitDelivery(ctx, id, remaining)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusDelivered, gotParent.Status)
+	require.NotNil(t, gotParent.ActualDeliveryTime)
+	assert.Equal(t, now, *gotParent.ActualDeliveryTime)
+
+	assert.Equal(t, domain.DeliveryStatusPending, child.Status)
+	require.NotNil(t, child.ParentDeliveryID)
+	assert.Equal(t, id, *child.ParentDeliveryID)
+	assert.Equal(t, "ORDER-1", child.OrderID)
+	assert.Nil(t, child.DriverID, "the child is dispatched again")
+	assert.Equal(t, remaining, child.Packages)
+	assert.Equal(t, []string{"vip"}, child.Tags)
+	assert.Equal(t, now.Add(cfg.MinScheduleAdvance), child.ScheduledPickupTime)
+	assert.Equal(t, 90*time.Minute, child.EstimatedDeliveryTime.Sub(child.ScheduledPickupTime), "keeps the parent's pickup-to-delivery duration")
+	assert.NotEmpty(t, child.PickupCode)
+}
+
+func TestSplitDelivery_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("invalid packages", func(t *testing.T) {
+		for _, packages := range [][]domain.Package{nil, {{Description: "", Quantity: 1}}, {{Description: "Box", Quantity: 0}}} {
+			_, _, err := uc.SplitDelivery(ctx, uuid.New(), packages)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		}
+	})
+
+	t.Run("not in transit", func(t *testing.T) {
+		id := uuid.New()
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.DeliveryAssignment{ID: id, Status: domain.DeliveryStatusPending}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := uc.SplitDelivery(ctx, id, []domain.Package{{Description: "Box", Quantity: 1}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+	})
+
+	t.Run("more packages than carried", func(t *testing.T) {
+		id := uuid.New()
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.DeliveryAssignment{
+			ID:       id,
+			Status:   domain.DeliveryStatusInTransit,
+			Packages: []domain.Package{{Description: "Box", Quantity: 1}},
+		}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := uc.SplitDelivery(ctx, id, []domain.Package{{Description: "Box", Quantity: 2}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, "remaining_packages[0]")
+	})
+
+	t.Run("every package remaining", func(t *testing.T) {
+		id := uuid.New()
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.DeliveryAssignment{
+			ID:       id,
+			Status:   domain.DeliveryStatusInTransit,
+			Packages: []domain.Package{{Description: "Box", Quantity: 1}, {Description: "Drinks crate", Quantity: 2}},
+		}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := uc.SplitDelivery(ctx, id, []domain.Package{{Description: "Drinks crate", Quantity: 2}, {Description: "Box", Quantity: 1}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, "at least one package")
+	})
+
+	t.Run("outside business hours", func(t *testing.T) {
+		// The clock is a Sunday evening, so the child could only be picked up after hours
+		now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+		businessHours, err := domain.ParseBusinessHours("SUN=09:00-17:00")
+		require.NoError(t, err)
+		cfg := service.DefaultConfig()
+		cfg.Clock = fixedClock{now}
+		cfg.BusinessHours = businessHours
+		uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+		id := uuid.New()
+		mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+				return fn(mockRepo)
+			})
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.DeliveryAssignment{
+			ID:                    id,
+			Status:                domain.DeliveryStatusInTransit,
+			ScheduledPickupTime:   now.Add(-2 * time.Hour),
+			EstimatedDeliveryTime: now.Add(-time.Hour),
+			Packages:              []domain.Package{{Description: "Box", Quantity: 2}},
+		}, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err = uc.SplitDelivery(ctx, id, []domain.Package{{Description: "Box", Quantity: 1}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, "outside business hours")
+	})
+}
+
+func TestReattemptDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now}
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	driverID := "DRIVER-1"
+	reason := "WRONG_ADDRESS"
+	failed := &domain.DeliveryAssignment{
+		ID:                    uuid.New(),
+		OrderID:               "ORDER-1",
+		DriverID:              &driverID,
+		Status:                domain.DeliveryStatusFailed,
+		ScheduledPickupTime:   now.Add(-3 * time.Hour),
+		EstimatedDeliveryTime: now.Add(-time.Hour),
+		FailureReasonCode:     &reason,
+		Tags:                  []string{"vip"},
+		AttemptNumber:         1,
+	}
+
+	// The reattempt is created inside a transaction; the failed delivery is not touched
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetByIDForUpdate(ctx, failed.ID).Return(failed, nil)
+	mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-1").Return([]*domain.DeliveryAssignment{failed}, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil).Times(2)
+
+	reattempt, err := uc.ReattemptDelivery(ctx, failed.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusFailed, failed.Status)
+	assert.NotEqual(t, failed.ID, reattempt.ID)
+	assert.Equal(t, domain.DeliveryStatusPending, reattempt.Status)
+	require.NotNil(t, reattempt.ParentDeliveryID)
+	assert.Equal(t, failed.ID, *reattempt.ParentDeliveryID)
+	assert.Equal(t, 2, reattempt.AttemptNumber)
+	assert.Equal(t, "ORDER-1", reattempt.OrderID)
+	assert.Nil(t, reattempt.DriverID, "the reattempt is dispatched again")
+	assert.Nil(t, reattempt.FailureReasonCode)
+	assert.Equal(t, []string{"vip"}, reattempt.Tags)
+	assert.Equal(t, now.Add(cfg.MinScheduleAdvance), reattempt.ScheduledPickupTime)
+	assert.Equal(t, 2*time.Hour, reattempt.EstimatedDeliveryTime.Sub(reattempt.ScheduledPickupTime))
+	assert.NotEmpty(t, reattempt.PickupCode)
+
+	// Reattempting the reattempt once it fails counts up again
+	reattempt.Status = domain.DeliveryStatusFailed
+	mockRepo.EXPECT().GetByIDForUpdate(ctx, reattempt.ID).Return(reattempt, nil)
+	mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-1").Return([]*domain.DeliveryAssignment{failed, reattempt}, nil)
+
+	third, err := uc.ReattemptDelivery(ctx, reattempt.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, reattempt.ID, *third.ParentDeliveryID)
+	assert.Equal(t, 3, third.AttemptNumber)
+}
+
+func TestReattemptDelivery_MaxAttempts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		maxAttempts    int
+		attemptNumber  int
+		expectedStatus domain.DeliveryStatus
+	}{
+		{name: "below the limit", maxAttempts: 3, attemptNumber: 2, expectedStatus: domain.DeliveryStatusPending},
+		{name: "last attempt failed", maxAttempts: 3, attemptNumber: 3, expectedStatus: domain.DeliveryStatusDeadLetter},
+		{name: "over the limit after lowering it", maxAttempts: 2, attemptNumber: 3, expectedStatus: domain.DeliveryStatusDeadLetter},
+		{name: "unlimited", maxAttempts: 0, attemptNumber: 10, expectedStatus: domain.DeliveryStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			recorder := &recordingNotifier{}
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{now}
+			cfg.MaxAttempts = tt.maxAttempts
+			cfg.Notifier = recorder
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			failed := &domain.DeliveryAssignment{
+				ID:                    uuid.New(),
+				OrderID:               "ORDER-1",
+				Status:                domain.DeliveryStatusFailed,
+				ScheduledPickupTime:   now.Add(-3 * time.Hour),
+				EstimatedDeliveryTime: now.Add(-time.Hour),
+				AttemptNumber:         tt.attemptNumber,
+			}
+
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().GetByIDForUpdate(ctx, failed.ID).Return(failed, nil)
+			if tt.expectedStatus == domain.DeliveryStatusDeadLetter {
+				mockRepo.EXPECT().Update(ctx, failed).Return(nil)
+				mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			} else {
+				mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-1").Return([]*domain.DeliveryAssignment{failed}, nil)
+				mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+			}
+
+			result, err := uc.ReattemptDelivery(ctx, failed.ID)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			if tt.expectedStatus == domain.DeliveryStatusDeadLetter {
+				assert.Equal(t, failed.ID, result.ID, "no new attempt is created")
+				require.NotNil(t, result.DeadLetterReason)
+				assert.Contains(t, *result.DeadLetterReason, "max delivery attempts")
+				assert.Equal(t, []domain.DeliveryStatus{domain.DeliveryStatusDeadLetter}, recorder.statuses())
+			} else {
+				assert.Equal(t, tt.attemptNumber+1, result.AttemptNumber)
+			}
+		})
+	}
+}
+
+func TestReattemptDelivery_NotFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+	ctx := context.Background()
+
+	for _, status := range []domain.DeliveryStatus{
+		domain.DeliveryStatusPending,
+		domain.DeliveryStatusInTransit,
+		domain.DeliveryStatusDelivered,
+		domain.DeliveryStatusDeadLetter,
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			id := uuid.New()
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().GetByIDForUpdate(ctx, id).Return(&domain.DeliveryAssignment{ID: id, Status: status}, nil)
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := uc.ReattemptDelivery(ctx, id)
+
+			assert.ErrorIs(t, err, domain.ErrConflict)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestReattemptDelivery_Rejected(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// now is a Sunday
+	businessHours, err := domain.ParseBusinessHours("SUN=09:00-17:00")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		clock     time.Time
+		maxActive int
+		siblings  func(failed *domain.DeliveryAssignment) []*domain.DeliveryAssignment
+		expectErr error
+		expectMsg string
+	}{
+		{
+			name:  "already reattempted",
+			clock: now,
+			siblings: func(failed *domain.DeliveryAssignment) []*domain.DeliveryAssignment {
+				parentID := failed.ID
+				return []*domain.DeliveryAssignment{failed, {
+					ID:               uuid.New(),
+					OrderID:          failed.OrderID,
+					Status:           domain.DeliveryStatusPending,
+					ParentDeliveryID: &parentID,
+					AttemptNumber:    2,
+				}}
+			},
+			expectErr: domain.ErrConflict,
+			expectMsg: "already reattempted",
+		},
+		{
+			name:  "outside business hours",
+			clock: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC),
+			siblings: func(failed *domain.DeliveryAssignment) []*domain.DeliveryAssignment {
+				return []*domain.DeliveryAssignment{failed}
+			},
+			expectErr: domain.ErrInvalidInput,
+			expectMsg: "outside business hours",
+		},
+		{
+			name:      "too many active deliveries for the order",
+			clock:     now,
+			maxActive: 1,
+			siblings: func(failed *domain.DeliveryAssignment) []*domain.DeliveryAssignment {
+				return []*domain.DeliveryAssignment{failed, {ID: uuid.New(), OrderID: failed.OrderID, Status: domain.DeliveryStatusAssigned}}
+			},
+			expectErr: domain.ErrConflict,
+			expectMsg: "maximum of 1 active deliveries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			cfg := service.DefaultConfig()
+			cfg.Clock = fixedClock{tt.clock}
+			cfg.BusinessHours = businessHours
+			if tt.maxActive > 0 {
+				cfg.MaxActivePerOrder = tt.maxActive
+			}
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			failed := &domain.DeliveryAssignment{
+				ID:                    uuid.New(),
+				OrderID:               "ORDER-1",
+				Status:                domain.DeliveryStatusFailed,
+				ScheduledPickupTime:   tt.clock.Add(-3 * time.Hour),
+				EstimatedDeliveryTime: tt.clock.Add(-2 * time.Hour),
+				AttemptNumber:         1,
+			}
+
+			mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+					return fn(mockRepo)
+				})
+			mockRepo.EXPECT().GetByIDForUpdate(ctx, failed.ID).Return(failed, nil)
+			mockRepo.EXPECT().GetByOrderID(ctx, "ORDER-1").Return(tt.siblings(failed), nil)
+			mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := uc.ReattemptDelivery(ctx, failed.ID)
+
+			assert.ErrorIs(t, err, tt.expectErr)
+			assert.ErrorContains(t, err, tt.expectMsg)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestRefreshMetricsSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now}
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	yesterday := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Any instant of a completed day refreshes that UTC day
+	mockRepo.EXPECT().RefreshMetricsSummary(ctx, yesterday, cfg.OnTimeGracePeriod).Return(nil)
+	require.NoError(t, uc.RefreshMetricsSummary(ctx, yesterday.Add(18*time.Hour)))
+
+	// The current day is still changing and is always computed live
+	err := uc.RefreshMetricsSummary(ctx, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestRecordDriverLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Clock = fixedClock{now}
+	cfg.UppercaseIDs = true
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+	ctx := context.Background()
+
+	// A missing device timestamp means the ping was taken on receipt
+	mockRepo.EXPECT().RecordDriverLocation(ctx, "DRIVER-1", 40.7128, -74.006, now).Return(nil)
+	require.NoError(t, uc.RecordDriverLocation(ctx, domain.DriverLocation{DriverID: " driver-1 ", Latitude: 40.7128, Longitude: -74.006}))
+
+	invalid := []struct {
+		name     string
+		location domain.DriverLocation
+		field    string
+	}{
+		{name: "missing driver", location: domain.DriverLocation{Latitude: 1, Longitude: 1}, field: "driver_id"},
+		{name: "latitude out of bounds", location: domain.DriverLocation{DriverID: "D", Latitude: -90.5}, field: "latitude"},
+		{name: "longitude out of bounds", location: domain.DriverLocation{DriverID: "D", Longitude: 180.5}, field: "longitude"},
+		{name: "NaN latitude", location: domain.DriverLocation{DriverID: "D", Latitude: math.NaN()}, field: "latitude"},
+		{name: "future timestamp", location: domain.DriverLocation{DriverID: "D", RecordedAt: now.Add(time.Hour)}, field: "recorded_at"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.RecordDriverLocation(ctx, tt.location)
+
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestGetDriverLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	uc := service.NewDeliveryUseCase(mockRepo, zap.NewNop())
+	ctx := context.Background()
+
+	latest := &domain.DriverLocation{DriverID: "DRIVER-1", Latitude: 1, Longitude: 2}
+	mockRepo.EXPECT().GetLatestDriverLocation(ctx, "DRIVER-1").Return(latest, nil)
+
+	location, err := uc.GetDriverLocation(ctx, " DRIVER-1 ")
+	require.NoError(t, err)
+	assert.Equal(t, latest, location)
+
+	_, err = uc.GetDriverLocation(ctx, " ")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestGetDeliveryMetrics_ConcurrencyLimit(t *testing.T) {
+	startTime := time.Now().Add(-24 * time.Hour)
+	endTime := time.Now()
+
+	// newUseCase returns a use case allowing two concurrent metrics queries, whose repository
+	// holds each query until unblock is closed; started receives one value per running query
+	newUseCase := func(t *testing.T, queueTimeout time.Duration) (uc service.DeliveryUseCase, started chan struct{}, unblock chan struct{}) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+		started = make(chan struct{}, 3)
+		unblock = make(chan struct{})
+		mockRepo.EXPECT().
+			GetMetrics(gomock.Any(), startTime, endTime, nil, gomock.Any()).
+			DoAndReturn(func(context.Context, time.Time, time.Time, *string, time.Duration) (*domain.DeliveryMetrics, error) {
+				started <- struct{}{}
+				<-unblock
+				return &domain.DeliveryMetrics{}, nil
+			}).
+			AnyTimes()
+
+		cfg := service.DefaultConfig()
+		cfg.MaxConcurrentMetrics = 2
+		cfg.MetricsQueueTimeout = queueTimeout
+		return service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg), started, unblock
+	}
+
+	// fillSlots starts two queries and waits until both hold a slot
+	fillSlots := func(uc service.DeliveryUseCase, started chan struct{}) chan error {
+		results := make(chan error, 2)
+		for range 2 {
+			go func() {
+				_, err := uc.GetDeliveryMetrics(context.Background(), startTime, endTime, nil)
+				results <- err
+			}()
+		}
+		<-started
+		<-started
+		return results
+	}
+
+	t.Run("rejected when the limit is reached", func(t *testing.T) {
+		uc, started, unblock := newUseCase(t, 0)
+		results := fillSlots(uc, started)
+
+		metrics, err := uc.GetDeliveryMetrics(context.Background(), startTime, endTime, nil)
+
+		require.ErrorIs(t, err, domain.ErrResourceExhausted)
+		assert.Nil(t, metrics)
+
+		close(unblock)
+		require.NoError(t, <-results)
+		require.NoError(t, <-results)
+
+		// Released slots are reusable
+		_, err = uc.GetDeliveryMetrics(context.Background(), startTime, endTime, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("queued until a slot frees", func(t *testing.T) {
+		uc, started, unblock := newUseCase(t, time.Minute)
+		results := fillSlots(uc, started)
+
+		queued := make(chan error, 1)
+		go func() {
+			_, err := uc.GetDeliveryMetrics(context.Background(), startTime, endTime, nil)
+			queued <- err
+		}()
+
+		select {
+		case <-started:
+			t.Fatal("query over the limit ran before a slot was released")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(unblock)
+		require.NoError(t, <-results)
+		require.NoError(t, <-results)
+		require.NoError(t, <-queued)
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		uc, started, unblock := newUseCase(t, 20*time.Millisecond)
+		results := fillSlots(uc, started)
+		defer func() {
+			close(unblock)
+			<-results
+			<-results
+		}()
+
+		_, err := uc.GetDeliveryMetrics(context.Background(), startTime, endTime, nil)
+
+		require.ErrorIs(t, err, domain.ErrResourceExhausted)
+	})
+
+	t.Run("leaderboard shares the limit", func(t *testing.T) {
+		uc, started, unblock := newUseCase(t, 0)
+		results := fillSlots(uc, started)
+		defer func() {
+			close(unblock)
+			<-results
+			<-results
+		}()
+
+		_, err := uc.GetDriverLeaderboard(context.Background(), startTime, endTime, 10)
+
+		require.ErrorIs(t, err, domain.ErrResourceExhausted)
+	})
+}
+
+func TestCheckDeliveryFeasibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	logger, _ := zap.NewDevelopment()
+	cfg := service.DefaultConfig()
+	cfg.MaxDeliveryDistanceKm = 50
+	cfg.AverageSpeedKmh = 30
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, logger, cfg)
+
+	ctx := context.Background()
+	pickupTime := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	// About 9.1 km apart, across Paris
+	pickup := domain.Address{City: "Paris", Latitude: 48.8809, Longitude: 2.3553}
+	dropoff := domain.Address{City: "Paris", Latitude: 48.8448, Longitude: 2.2431}
+
+	t.Run("feasible", func(t *testing.T) {
+		result, err := uc.CheckDeliveryFeasibility(ctx, service.FeasibilityInput{
+			PickupAddress:         pickup,
+			DeliveryAddress:       dropoff,
+			ScheduledPickupTime:   pickupTime,
+			EstimatedDeliveryTime: pickupTime.Add(time.Hour),
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Feasible)
+		assert.Empty(t, result.Reasons)
+		assert.InDelta(t, 9.1, result.DistanceKm, 0.1)
+		assert.InDelta(t, 18*time.Minute, result.RequiredDuration, float64(time.Minute))
+		assert.Equal(t, time.Hour, result.AvailableDuration)
+	})
+
+	t.Run("window too short", func(t *testing.T) {
+		result, err := uc.CheckDeliveryFeasibility(ctx, service.FeasibilityInput{
+			PickupAddress:         pickup,
+			DeliveryAddress:       dropoff,
+			ScheduledPickupTime:   pickupTime,
+			EstimatedDeliveryTime: pickupTime.Add(5 * time.Minute),
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Feasible)
+		require.Len(t, result.Reasons, 1)
+		assert.Contains(t, result.Reasons[0], "window allows only 5m0s")
+	})
+
+	t.Run("out of range and impossible window", func(t *testing.T) {
+		lyon := domain.Address{City: "Lyon", Latitude: 45.7640, Longitude: 4.8357}
+		result, err := uc.CheckDeliveryFeasibility(ctx, service.FeasibilityInput{
+			PickupAddress:         pickup,
+			DeliveryAddress:       lyon,
+			ScheduledPickupTime:   pickupTime,
+			EstimatedDeliveryTime: pickupTime.Add(-time.Hour),
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Feasible)
+		require.Len(t, result.Reasons, 2)
+		assert.Contains(t, result.Reasons[0], "exceeds the maximum of 50.0 km")
+		assert.Contains(t, result.Reasons[1], "not after the scheduled pickup time")
+	})
+
+	t.Run("coordinates required", func(t *testing.T) {
+		_, err := uc.CheckDeliveryFeasibility(ctx, service.FeasibilityInput{
+			PickupAddress:         domain.Address{City: "Paris"},
+			DeliveryAddress:       dropoff,
+			ScheduledPickupTime:   pickupTime,
+			EstimatedDeliveryTime: pickupTime.Add(time.Hour),
+		})
+
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "pickup_address", validationErr.Field)
+	})
+}
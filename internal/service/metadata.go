@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/mohamadchoker/order-delivery-service/pkg/jsonschema"
+	"github.com/mohamadchoker/order-delivery-service/pkg/validator"
+)
+
+// MetadataValidator checks the metadata of a new delivery against the rules of the tenant
+// creating it. It returns one error per problem, named after the offending key
+// (e.g. "metadata.gate_code"); none means the metadata is accepted.
+type MetadataValidator interface {
+	ValidateMetadata(tenantID string, metadata map[string]any) validator.ValidationErrors
+}
+
+// nopMetadataValidator accepts any metadata
+type nopMetadataValidator struct{}
+
+func (nopMetadataValidator) ValidateMetadata(string, map[string]any) validator.ValidationErrors {
+	return nil
+}
+
+// MetadataSchemas validates metadata against the JSON schema configured for its tenant, keyed by
+// tenant ID. Tenants without a schema, and callers without a tenant, may send any metadata.
+type MetadataSchemas map[string]*jsonschema.Schema
+
+func (s MetadataSchemas) ValidateMetadata(tenantID string, metadata map[string]any) validator.ValidationErrors {
+	schema, ok := s[tenantID]
+	if !ok || tenantID == "" {
+		return nil
+	}
+	return schema.Validate("metadata", metadata)
+}
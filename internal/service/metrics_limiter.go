@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// metricsLimiter caps how many metrics aggregations run at once so a burst of dashboard
+// refreshes cannot overload the database. A call over the limit waits up to queueTimeout for a
+// slot and then fails with domain.ErrResourceExhausted.
+type metricsLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+// newMetricsLimiter returns a limiter allowing limit concurrent calls, or nil (no limit) for 0
+func newMetricsLimiter(limit int, queueTimeout time.Duration) *metricsLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &metricsLimiter{
+		slots:        make(chan struct{}, limit),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire takes a slot, returning the func that releases it. A nil limiter never blocks.
+func (l *metricsLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release = func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if l.queueTimeout <= 0 {
+		return nil, l.exhausted()
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, l.exhausted()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// exhausted returns the error for a shed call. The limiter cannot tell when a slot frees up, so
+// callers are asked to wait the queue timeout, which a slot just failed to free up within.
+func (l *metricsLimiter) exhausted() error {
+	return &domain.RetryAfterError{
+		RetryAfter: max(l.queueTimeout, constants.MinRetryAfter),
+		Err:        fmt.Errorf("%w: too many concurrent metrics queries (limit %d)", domain.ErrResourceExhausted, cap(l.slots)),
+	}
+}
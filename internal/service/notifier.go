@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// StatusChange describes a saved change to a delivery assignment's status
+type StatusChange struct {
+	DeliveryID uuid.UUID
+	OrderID    string
+	DriverID   *string
+	Status     domain.DeliveryStatus
+	ChangedAt  time.Time
+
+	// PreviousDriverID is the driver the change took the delivery away from, if any
+	PreviousDriverID *string
+
+	// RecipientEmail and NotifyPrefs are the delivery's recipient notification settings
+	RecipientEmail *string
+	NotifyPrefs    domain.NotifyPrefs
+
+	// Replay marks a change re-sent by ReplayEvents rather than one that just happened;
+	// consumers that already saw it can dedupe on DeliveryID, Status and ChangedAt
+	Replay bool
+
+	// DriverETA is the driver's latest arrival estimate, if they sent one. ETAUpdate marks a
+	// change sent by UpdateDriverETA: only DriverETA changed and Status is the current status.
+	DriverETA *time.Time
+	ETAUpdate bool
+
+	// Priority is the delivery's dispatch priority. PriorityEscalated marks a change sent when a
+	// waiting delivery was escalated: only Priority changed and Status is the current status.
+	Priority          domain.DeliveryPriority
+	PriorityEscalated bool
+}
+
+// statusUnchanged reports whether the change updated something other than the status
+func (c StatusChange) statusUnchanged() bool {
+	return c.ETAUpdate || c.PriorityEscalated
+}
+
+// NotifiesRecipient reports whether the recipient should hear about this change: it is a new
+// status rather than a replay, ETA update or escalation, they have an email address, and the
+// status is one they asked to be notified of
+func (c StatusChange) NotifiesRecipient() bool {
+	return !c.Replay && !c.statusUnchanged() && c.RecipientEmail != nil && c.NotifyPrefs.Includes(c.Status)
+}
+
+// Notifier receives status changes after they have been persisted.
+// It is called on the request path, so implementations must not block for long.
+type Notifier interface {
+	NotifyStatusChange(ctx context.Context, change StatusChange)
+}
+
+// nopNotifier discards every status change
+type nopNotifier struct{}
+
+func (nopNotifier) NotifyStatusChange(context.Context, StatusChange) {}
+
+// logNotifier writes every status change to the log
+type logNotifier struct {
+	logger *zap.Logger
+}
+
+// NewLogNotifier creates a notifier that logs status changes at debug level
+func NewLogNotifier(logger *zap.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) NotifyStatusChange(_ context.Context, change StatusChange) {
+	n.logger.Debug("Delivery status changed",
+		zap.String("id", change.DeliveryID.String()),
+		zap.String("order_id", change.OrderID),
+		zap.String("status", string(change.Status)),
+		zap.Time("changed_at", change.ChangedAt),
+		zap.Bool("replay", change.Replay),
+		zap.Bool("eta_update", change.ETAUpdate),
+		zap.String("priority", string(change.Priority)),
+		zap.Bool("priority_escalated", change.PriorityEscalated),
+	)
+}
+
+// recipientNotifier forwards only the changes the delivery's recipient wants to hear about
+type recipientNotifier struct {
+	next Notifier
+}
+
+// NewRecipientNotifier wraps a notifier that messages recipients, such as an email sender, so it
+// only receives changes for which StatusChange.NotifiesRecipient holds. Wrap it outside any
+// ThrottledNotifier, or a wanted status may be coalesced into one the recipient opted out of.
+func NewRecipientNotifier(next Notifier) Notifier {
+	return &recipientNotifier{next: next}
+}
+
+func (n *recipientNotifier) NotifyStatusChange(ctx context.Context, change StatusChange) {
+	if change.NotifiesRecipient() {
+		n.next.NotifyStatusChange(ctx, change)
+	}
+}
+
+// recipientLogNotifier logs the notifications a recipient is sent
+type recipientLogNotifier struct {
+	logger *zap.Logger
+}
+
+// NewRecipientLogNotifier creates a notifier that logs, at info level, each notification sent to a
+// recipient. It stands in for a sender such as email; wrap it with NewRecipientNotifier. The
+// recipient's address is not logged.
+func NewRecipientLogNotifier(logger *zap.Logger) Notifier {
+	return &recipientLogNotifier{logger: logger}
+}
+
+func (n *recipientLogNotifier) NotifyStatusChange(_ context.Context, change StatusChange) {
+	n.logger.Info("Notifying recipient",
+		zap.String("id", change.DeliveryID.String()),
+		zap.String("order_id", change.OrderID),
+		zap.String("status", string(change.Status)),
+	)
+}
+
+// fanoutNotifier forwards every status change to each of its notifiers in order
+type fanoutNotifier []Notifier
+
+// NewFanoutNotifier creates a notifier that forwards every status change to each of notifiers
+func NewFanoutNotifier(notifiers ...Notifier) Notifier {
+	return fanoutNotifier(notifiers)
+}
+
+func (n fanoutNotifier) NotifyStatusChange(ctx context.Context, change StatusChange) {
+	for _, notifier := range n {
+		notifier.NotifyStatusChange(ctx, change)
+	}
+}
+
+// ThrottledNotifier forwards at most one status change per assignment per interval.
+// Changes arriving within the interval, ETA updates included, are coalesced and only the latest
+// is sent when it ends.
+// Terminal statuses bypass the throttle and replace any change still waiting. Replays bypass it
+// without affecting live changes.
+type ThrottledNotifier struct {
+	next     Notifier
+	interval time.Duration
+
+	mu      sync.Mutex
+	windows map[uuid.UUID]*throttleWindow
+	closed  bool
+}
+
+// throttleWindow is open for an assignment for one interval after a change was forwarded
+type throttleWindow struct {
+	timer      *time.Timer
+	pending    *StatusChange
+	pendingCtx context.Context
+}
+
+// NewThrottledNotifier wraps next so each assignment is notified at most once per interval
+func NewThrottledNotifier(next Notifier, interval time.Duration) *ThrottledNotifier {
+	return &ThrottledNotifier{
+		next:     next,
+		interval: interval,
+		windows:  make(map[uuid.UUID]*throttleWindow),
+	}
+}
+
+// NotifyStatusChange forwards change now or holds it until the assignment's window ends.
+// Forwarding happens under the lock so a delayed change can never overtake a newer one.
+func (t *ThrottledNotifier) NotifyStatusChange(ctx context.Context, change StatusChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if change.Replay {
+		t.next.NotifyStatusChange(ctx, change)
+		return
+	}
+
+	window := t.windows[change.DeliveryID]
+
+	if t.closed || change.Status.IsTerminal() {
+		if window != nil {
+			window.timer.Stop()
+			delete(t.windows, change.DeliveryID)
+		}
+		t.next.NotifyStatusChange(ctx, change)
+		return
+	}
+
+	if window != nil {
+		// An ETA update or escalation coalesced into a held status change must not hide that the
+		// status changed
+		if change.statusUnchanged() && window.pending != nil && !window.pending.statusUnchanged() {
+			change.ETAUpdate = false
+			change.PriorityEscalated = false
+		}
+		// The request may finish before the window does; keep its values but not its deadline
+		window.pending = &change
+		window.pendingCtx = context.WithoutCancel(ctx)
+		return
+	}
+
+	t.next.NotifyStatusChange(ctx, change)
+	t.openWindow(change.DeliveryID)
+}
+
+// openWindow starts the throttle interval for id
+func (t *ThrottledNotifier) openWindow(id uuid.UUID) {
+	window := &throttleWindow{}
+	window.timer = time.AfterFunc(t.interval, func() { t.closeWindow(id, window) })
+	t.windows[id] = window
+}
+
+// closeWindow sends the latest held change, if any, which starts a new window
+func (t *ThrottledNotifier) closeWindow(id uuid.UUID, window *throttleWindow) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// The window may have been replaced by a terminal status or Close in the meantime
+	if t.windows[id] != window {
+		return
+	}
+	delete(t.windows, id)
+
+	if window.pending != nil {
+		t.next.NotifyStatusChange(window.pendingCtx, *window.pending)
+		t.openWindow(id)
+	}
+}
+
+// Close sends every held change immediately; later changes are forwarded without throttling
+func (t *ThrottledNotifier) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	for id, window := range t.windows {
+		window.timer.Stop()
+		if window.pending != nil {
+			t.next.NotifyStatusChange(window.pendingCtx, *window.pending)
+		}
+		delete(t.windows, id)
+	}
+}
+
+// StatusBroadcaster fans status changes out to subscribers watching a driver's deliveries.
+// Each subscriber has a bounded buffer; one that falls behind is dropped and its channel closed
+// rather than blocking the request path.
+type StatusBroadcaster struct {
+	bufferSize int
+
+	mu          sync.Mutex
+	subscribers map[string]map[*driverSubscription]struct{}
+}
+
+// driverSubscription is one watcher of a driver's deliveries
+type driverSubscription struct {
+	changes chan StatusChange
+}
+
+// NewStatusBroadcaster creates a broadcaster whose subscribers buffer up to bufferSize changes
+func NewStatusBroadcaster(bufferSize int) *StatusBroadcaster {
+	return &StatusBroadcaster{
+		bufferSize:  bufferSize,
+		subscribers: make(map[string]map[*driverSubscription]struct{}),
+	}
+}
+
+// SubscribeDriver returns the changes of deliveries assigned to driverID, or taken away from it,
+// until ctx is done. The channel is closed when the subscription ends, including when the
+// subscriber fell behind; check ctx to tell the two apart.
+func (b *StatusBroadcaster) SubscribeDriver(ctx context.Context, driverID string) <-chan StatusChange {
+	sub := &driverSubscription{changes: make(chan StatusChange, b.bufferSize)}
+
+	b.mu.Lock()
+	if b.subscribers[driverID] == nil {
+		b.subscribers[driverID] = make(map[*driverSubscription]struct{})
+	}
+	b.subscribers[driverID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(driverID, sub)
+	}()
+
+	return sub.changes
+}
+
+// NotifyStatusChange delivers change to the subscribers of its current and previous driver
+func (b *StatusBroadcaster) NotifyStatusChange(_ context.Context, change StatusChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if change.DriverID != nil {
+		b.publish(*change.DriverID, change)
+	}
+	if change.PreviousDriverID != nil && (change.DriverID == nil || *change.PreviousDriverID != *change.DriverID) {
+		b.publish(*change.PreviousDriverID, change)
+	}
+}
+
+// publish sends change to every subscriber of driverID, dropping those whose buffer is full.
+// Callers must hold b.mu.
+func (b *StatusBroadcaster) publish(driverID string, change StatusChange) {
+	for sub := range b.subscribers[driverID] {
+		select {
+		case sub.changes <- change:
+		default:
+			b.remove(driverID, sub)
+		}
+	}
+}
+
+// remove ends a subscription once. Callers must hold b.mu.
+func (b *StatusBroadcaster) remove(driverID string, sub *driverSubscription) {
+	subs := b.subscribers[driverID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, driverID)
+	}
+	close(sub.changes)
+}
@@ -0,0 +1,434 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// recordingNotifier collects every status change it receives
+type recordingNotifier struct {
+	mu      sync.Mutex
+	changes []service.StatusChange
+}
+
+func (n *recordingNotifier) NotifyStatusChange(_ context.Context, change service.StatusChange) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.changes = append(n.changes, change)
+}
+
+func (n *recordingNotifier) statuses() []domain.DeliveryStatus {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	statuses := make([]domain.DeliveryStatus, 0, len(n.changes))
+	for _, change := range n.changes {
+		statuses = append(statuses, change.Status)
+	}
+	return statuses
+}
+
+func TestThrottledNotifier_CoalescesRapidUpdates(t *testing.T) {
+	recorder := &recordingNotifier{}
+	throttled := service.NewThrottledNotifier(recorder, 50*time.Millisecond)
+	defer throttled.Close()
+
+	ctx := context.Background()
+	id := uuid.New()
+	for _, status := range []domain.DeliveryStatus{
+		domain.DeliveryStatusAssigned,
+		domain.DeliveryStatusPending,
+		domain.DeliveryStatusAssigned,
+		domain.DeliveryStatusPickedUp,
+		domain.DeliveryStatusInTransit,
+	} {
+		throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: status})
+	}
+
+	// The first change goes out at once; the rest are held for the interval
+	assert.Equal(t, []domain.DeliveryStatus{domain.DeliveryStatusAssigned}, recorder.statuses())
+
+	// Only the latest held change is sent when the interval ends
+	require.Eventually(t, func() bool { return len(recorder.statuses()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, domain.DeliveryStatusInTransit, recorder.statuses()[1])
+
+	// Other assignments are throttled independently
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: uuid.New(), Status: domain.DeliveryStatusAssigned})
+	assert.Len(t, recorder.statuses(), 3)
+}
+
+func TestThrottledNotifier_TerminalBypassesThrottle(t *testing.T) {
+	recorder := &recordingNotifier{}
+	throttled := service.NewThrottledNotifier(recorder, time.Hour)
+	defer throttled.Close()
+
+	ctx := context.Background()
+	id := uuid.New()
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: domain.DeliveryStatusPickedUp})
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: domain.DeliveryStatusInTransit})
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: domain.DeliveryStatusDelivered})
+
+	// The held IN_TRANSIT is superseded by the terminal status, which is sent immediately
+	assert.Equal(t, []domain.DeliveryStatus{domain.DeliveryStatusPickedUp, domain.DeliveryStatusDelivered}, recorder.statuses())
+}
+
+func TestThrottledNotifier_CloseFlushesHeldChanges(t *testing.T) {
+	recorder := &recordingNotifier{}
+	throttled := service.NewThrottledNotifier(recorder, time.Hour)
+
+	ctx := context.Background()
+	id := uuid.New()
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: domain.DeliveryStatusAssigned})
+	throttled.NotifyStatusChange(ctx, service.StatusChange{DeliveryID: id, Status: domain.DeliveryStatusPickedUp})
+
+	throttled.Close()
+
+	assert.Equal(t, []domain.DeliveryStatus{domain.DeliveryStatusAssigned, domain.DeliveryStatusPickedUp}, recorder.statuses())
+}
+
+func TestRecipientNotifier_RespectsPrefs(t *testing.T) {
+	recorder := &recordingNotifier{}
+	notifier := service.NewRecipientNotifier(recorder)
+
+	ctx := context.Background()
+	email := "jane@example.com"
+	prefs := domain.NotifyPrefs{domain.DeliveryStatusPickedUp, domain.DeliveryStatusDelivered}
+
+	for _, change := range []service.StatusChange{
+		// No email: nothing to send
+		{Status: domain.DeliveryStatusDelivered},
+		// Opted out of ASSIGNED
+		{Status: domain.DeliveryStatusAssigned, RecipientEmail: &email, NotifyPrefs: prefs},
+		{Status: domain.DeliveryStatusPickedUp, RecipientEmail: &email, NotifyPrefs: prefs},
+		{Status: domain.DeliveryStatusDelivered, RecipientEmail: &email, NotifyPrefs: prefs},
+		// No prefs means every status
+		{Status: domain.DeliveryStatusInTransit, RecipientEmail: &email},
+	} {
+		notifier.NotifyStatusChange(ctx, change)
+	}
+
+	assert.Equal(t, []domain.DeliveryStatus{
+		domain.DeliveryStatusPickedUp,
+		domain.DeliveryStatusDelivered,
+		domain.DeliveryStatusInTransit,
+	}, recorder.statuses())
+}
+
+func TestReplayEvents(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	pickedUp := created.Add(time.Hour)
+	delivered := created.Add(2 * time.Hour)
+	driverID := "DRIVER-1"
+
+	tests := []struct {
+		name       string
+		assignment domain.DeliveryAssignment
+		expected   []domain.DeliveryStatus
+	}{
+		{
+			name:       "pending",
+			assignment: domain.DeliveryAssignment{Status: domain.DeliveryStatusPending, UpdatedAt: created},
+			expected:   []domain.DeliveryStatus{domain.DeliveryStatusPending},
+		},
+		{
+			name:       "assigned",
+			assignment: domain.DeliveryAssignment{Status: domain.DeliveryStatusAssigned, DriverID: &driverID},
+			expected:   []domain.DeliveryStatus{domain.DeliveryStatusPending, domain.DeliveryStatusAssigned},
+		},
+		{
+			name:       "in transit",
+			assignment: domain.DeliveryAssignment{Status: domain.DeliveryStatusInTransit, DriverID: &driverID, ActualPickupTime: &pickedUp},
+			expected:   []domain.DeliveryStatus{domain.DeliveryStatusPending, domain.DeliveryStatusPickedUp, domain.DeliveryStatusInTransit},
+		},
+		{
+			name:       "delivered",
+			assignment: domain.DeliveryAssignment{Status: domain.DeliveryStatusDelivered, DriverID: &driverID, ActualPickupTime: &pickedUp, ActualDeliveryTime: &delivered},
+			expected:   []domain.DeliveryStatus{domain.DeliveryStatusPending, domain.DeliveryStatusPickedUp, domain.DeliveryStatusDelivered},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+			recorder := &recordingNotifier{}
+			broadcaster := service.NewStatusBroadcaster(10)
+			cfg := service.DefaultConfig()
+			cfg.Notifier = recorder
+			cfg.Broadcaster = broadcaster
+			uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+			ctx := context.Background()
+			assignment := tt.assignment
+			assignment.ID = uuid.New()
+			assignment.CreatedAt = created
+			mockRepo.EXPECT().GetByID(ctx, assignment.ID).Return(&assignment, nil)
+			watchCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			watch := broadcaster.SubscribeDriver(watchCtx, driverID)
+
+			replayed, err := uc.ReplayEvents(ctx, assignment.ID)
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), replayed)
+			assert.Equal(t, tt.expected, recorder.statuses())
+			for _, change := range recorder.changes {
+				assert.True(t, change.Replay)
+				assert.Equal(t, assignment.ID, change.DeliveryID)
+			}
+			assert.Equal(t, created, recorder.changes[0].ChangedAt)
+			assert.Empty(t, watch, "watchers are not sent replays")
+		})
+	}
+}
+
+func TestReplayedChanges_BypassThrottleAndRecipients(t *testing.T) {
+	forwarded := &recordingNotifier{}
+	throttled := service.NewThrottledNotifier(forwarded, time.Hour)
+	defer throttled.Close()
+	emailed := &recordingNotifier{}
+	recipients := service.NewRecipientNotifier(emailed)
+
+	ctx := context.Background()
+	id := uuid.New()
+	email := "jane@example.com"
+	for _, status := range []domain.DeliveryStatus{domain.DeliveryStatusPending, domain.DeliveryStatusAssigned, domain.DeliveryStatusPickedUp} {
+		change := service.StatusChange{DeliveryID: id, Status: status, RecipientEmail: &email, Replay: true}
+		throttled.NotifyStatusChange(ctx, change)
+		recipients.NotifyStatusChange(ctx, change)
+	}
+
+	// Replays are not coalesced, and recipients are not emailed again
+	assert.Len(t, forwarded.statuses(), 3)
+	assert.Empty(t, emailed.statuses())
+}
+
+func TestETAUpdates_ThrottleAndRecipients(t *testing.T) {
+	forwarded := &recordingNotifier{}
+	throttled := service.NewThrottledNotifier(forwarded, time.Hour)
+	emailed := &recordingNotifier{}
+	recipients := service.NewRecipientNotifier(emailed)
+
+	ctx := context.Background()
+	id := uuid.New()
+	email := "jane@example.com"
+	eta := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	later := eta.Add(15 * time.Minute)
+	for _, change := range []service.StatusChange{
+		{DeliveryID: id, Status: domain.DeliveryStatusPickedUp, RecipientEmail: &email},
+		{DeliveryID: id, Status: domain.DeliveryStatusInTransit, RecipientEmail: &email},
+		{DeliveryID: id, Status: domain.DeliveryStatusInTransit, RecipientEmail: &email, DriverETA: &eta, ETAUpdate: true},
+		{DeliveryID: id, Status: domain.DeliveryStatusInTransit, RecipientEmail: &email, DriverETA: &later, ETAUpdate: true},
+	} {
+		throttled.NotifyStatusChange(ctx, change)
+		recipients.NotifyStatusChange(ctx, change)
+	}
+	throttled.Close()
+
+	// ETA updates coalesce into the held IN_TRANSIT change, which is still sent as a status change
+	require.Len(t, forwarded.changes, 2)
+	held := forwarded.changes[1]
+	assert.Equal(t, domain.DeliveryStatusInTransit, held.Status)
+	assert.False(t, held.ETAUpdate)
+	require.NotNil(t, held.DriverETA)
+	assert.Equal(t, later, *held.DriverETA)
+
+	// Recipients are emailed about status changes only
+	assert.Equal(t, []domain.DeliveryStatus{domain.DeliveryStatusPickedUp, domain.DeliveryStatusInTransit}, emailed.statuses())
+}
+
+func TestUpdateDeliveryStatus_NotifiesStatusChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	recorder := &recordingNotifier{}
+	cfg := service.DefaultConfig()
+	cfg.Notifier = recorder
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx := context.Background()
+	existing := &domain.DeliveryAssignment{
+		ID:      uuid.New(),
+		OrderID: "ORDER-123",
+		Status:  domain.DeliveryStatusPending,
+	}
+
+	mockRepo.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetByIDForUpdate(ctx, existing.ID).Return(existing, nil).Times(2)
+	mockRepo.EXPECT().Update(ctx, existing).Return(nil).Times(1)
+
+	_, err := uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusCancelled, "", "", "")
+	require.NoError(t, err)
+
+	// Re-sending the current status is a no-op and is not announced again
+	_, err = uc.UpdateDeliveryStatus(ctx, existing.ID, domain.DeliveryStatusCancelled, "", "", "")
+	require.NoError(t, err)
+
+	require.Len(t, recorder.changes, 1)
+	assert.Equal(t, existing.ID, recorder.changes[0].DeliveryID)
+	assert.Equal(t, "ORDER-123", recorder.changes[0].OrderID)
+	assert.Equal(t, domain.DeliveryStatusCancelled, recorder.changes[0].Status)
+}
+
+func TestWatchDriverDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Broadcaster = service.NewStatusBroadcaster(8)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := uc.WatchDriverDeliveries(ctx, " DRIVER-1 ")
+	require.NoError(t, err)
+
+	driverID, otherDriverID := "DRIVER-1", "DRIVER-2"
+	mine := &domain.DeliveryAssignment{ID: uuid.New(), DriverID: &driverID, Status: domain.DeliveryStatusAssigned}
+	other := &domain.DeliveryAssignment{ID: uuid.New(), DriverID: &otherDriverID, Status: domain.DeliveryStatusAssigned}
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), mine.ID).Return(mine, nil)
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), other.ID).Return(other, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	_, err = uc.UpdateDeliveryStatus(ctx, other.ID, domain.DeliveryStatusCancelled, "", "", "")
+	require.NoError(t, err)
+	_, err = uc.UpdateDeliveryStatus(ctx, mine.ID, domain.DeliveryStatusCancelled, "", "", "")
+	require.NoError(t, err)
+
+	// Only the watched driver's delivery is streamed
+	select {
+	case change := <-changes:
+		assert.Equal(t, mine.ID, change.DeliveryID)
+		assert.Equal(t, domain.DeliveryStatusCancelled, change.Status)
+	case <-time.After(time.Second):
+		t.Fatal("status change was not streamed")
+	}
+	assert.Empty(t, changes)
+
+	// Ending the watch closes the channel
+	cancel()
+	_, open := <-changes
+	assert.False(t, open)
+}
+
+func TestWatchDriverDeliveries_Unassigned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Broadcaster = service.NewStatusBroadcaster(8)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := uc.WatchDriverDeliveries(ctx, "DRIVER-1")
+	require.NoError(t, err)
+
+	driverID := "DRIVER-1"
+	overdue := &domain.DeliveryAssignment{ID: uuid.New(), DriverID: &driverID, Status: domain.DeliveryStatusAssigned}
+	mockRepo.EXPECT().ListOverdueAssigned(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*domain.DeliveryAssignment{overdue}, nil)
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), overdue.ID).Return(overdue, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), overdue).Return(nil)
+
+	_, err = uc.UnassignOverdueDeliveries(ctx, time.Now())
+	require.NoError(t, err)
+
+	// The driver hears that the delivery was taken away from them
+	require.Len(t, changes, 1)
+	change := <-changes
+	assert.Equal(t, overdue.ID, change.DeliveryID)
+	assert.Nil(t, change.DriverID)
+	require.NotNil(t, change.PreviousDriverID)
+	assert.Equal(t, "DRIVER-1", *change.PreviousDriverID)
+	assert.Equal(t, domain.DeliveryStatusPending, change.Status)
+}
+
+func TestWatchDriverDeliveries_BackupPromoted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	cfg := service.DefaultConfig()
+	cfg.Broadcaster = service.NewStatusBroadcaster(8)
+	uc := service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	primaryChanges, err := uc.WatchDriverDeliveries(ctx, "DRIVER-1")
+	require.NoError(t, err)
+	backupChanges, err := uc.WatchDriverDeliveries(ctx, "DRIVER-2")
+	require.NoError(t, err)
+
+	primary, backup := "DRIVER-1", "DRIVER-2"
+	overdue := &domain.DeliveryAssignment{ID: uuid.New(), DriverID: &primary, BackupDriverID: &backup, Status: domain.DeliveryStatusAssigned}
+	mockRepo.EXPECT().ListOverdueAssigned(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*domain.DeliveryAssignment{overdue}, nil)
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		})
+	mockRepo.EXPECT().GetByIDForUpdate(gomock.Any(), overdue.ID).Return(overdue, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), overdue).Return(nil)
+
+	unassigned, err := uc.UnassignOverdueDeliveries(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, unassigned, 1)
+	assert.Equal(t, domain.DeliveryStatusAssigned, overdue.Status)
+	assert.Nil(t, overdue.BackupDriverID)
+
+	// The primary loses the delivery and the backup takes it over
+	for _, changes := range []<-chan service.StatusChange{primaryChanges, backupChanges} {
+		require.Len(t, changes, 1)
+		change := <-changes
+		require.NotNil(t, change.DriverID)
+		assert.Equal(t, "DRIVER-2", *change.DriverID)
+		require.NotNil(t, change.PreviousDriverID)
+		assert.Equal(t, "DRIVER-1", *change.PreviousDriverID)
+		assert.Equal(t, domain.DeliveryStatusAssigned, change.Status)
+	}
+}
+
+func TestStatusBroadcaster_DropsSlowSubscriber(t *testing.T) {
+	broadcaster := service.NewStatusBroadcaster(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	driverID := "DRIVER-1"
+	changes := broadcaster.SubscribeDriver(ctx, driverID)
+	broadcaster.NotifyStatusChange(ctx, service.StatusChange{DriverID: &driverID, Status: domain.DeliveryStatusPickedUp})
+	broadcaster.NotifyStatusChange(ctx, service.StatusChange{DriverID: &driverID, Status: domain.DeliveryStatusInTransit})
+
+	// The buffered change is still delivered, then the channel is closed while ctx is live
+	change, open := <-changes
+	require.True(t, open)
+	assert.Equal(t, domain.DeliveryStatusPickedUp, change.Status)
+	_, open = <-changes
+	assert.False(t, open)
+	assert.NoError(t, ctx.Err())
+}
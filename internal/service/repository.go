@@ -21,26 +21,125 @@ type DeliveryRepository interface {
 	// GetByID retrieves a delivery assignment by ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error)
 
+	// GetByIDForUpdate retrieves a delivery assignment by ID and locks its row until the transaction
+	// ends, so a read-modify-write cannot overwrite a concurrent change. Call it on the repository
+	// passed to a WithTransaction callback; outside one the lock is released straight away.
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.DeliveryAssignment, error)
+
+	// GetStatusByID retrieves only the status and last update time of a delivery assignment
+	GetStatusByID(ctx context.Context, id uuid.UUID) (*domain.DeliveryStatusInfo, error)
+
+	// GetByOrderID retrieves all delivery assignments for an order
+	GetByOrderID(ctx context.Context, orderID string) ([]*domain.DeliveryAssignment, error)
+
+	// GetByIDs retrieves the delivery assignments matching ids in a single query; missing IDs are omitted
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.DeliveryAssignment, error)
+
 	// Update updates an existing delivery assignment
 	Update(ctx context.Context, assignment *domain.DeliveryAssignment) error
 
-	// List retrieves delivery assignments with filters and pagination
-	List(ctx context.Context, filters ListFilters) ([]*domain.DeliveryAssignment, int64, error)
+	// AppendNote atomically appends a note to a delivery's timeline
+	AppendNote(ctx context.Context, id uuid.UUID, note domain.TimedNote) error
+
+	// UpdateDriverETA sets only the driver ETA and updatedAt of a PICKED_UP or IN_TRANSIT delivery
+	// and returns it as updated. ErrNotFound means it does not exist or is no longer on its way.
+	UpdateDriverETA(ctx context.Context, id uuid.UUID, eta, updatedAt time.Time) (*domain.DeliveryAssignment, error)
+
+	// List retrieves delivery assignments with filters and pagination. The total may be an
+	// estimate when counting the matches exactly takes too long.
+	List(ctx context.Context, filters ListFilters) ([]*domain.DeliveryAssignment, ListTotal, error)
+
+	// ListWithStatusBreakdown is List plus the number of matching deliveries per status, all read
+	// from one consistent snapshot; its total is always exact
+	ListWithStatusBreakdown(ctx context.Context, filters ListFilters) ([]*domain.DeliveryAssignment, ListTotal, map[domain.DeliveryStatus]int64, error)
+
+	// GetMetrics retrieves delivery metrics for a time range. Deliveries up to onTimeGrace past
+	// their estimate still count as on time.
+	GetMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string, onTimeGrace time.Duration) (*domain.DeliveryMetrics, error)
+
+	// RefreshMetricsSummary recomputes the daily metrics summary of the UTC day containing day.
+	// GetMetrics serves fleet-wide ranges from the summary where every whole day is present.
+	RefreshMetricsSummary(ctx context.Context, day time.Time, onTimeGrace time.Duration) error
+
+	// GetMetricsForDrivers retrieves metrics for each of driverIDs in a single grouped query.
+	// Every requested driver is present in the result; drivers without deliveries get zero metrics.
+	GetMetricsForDrivers(ctx context.Context, driverIDs []string, startTime, endTime time.Time, onTimeGrace time.Duration) (map[string]*domain.DeliveryMetrics, error)
+
+	// GetDriverLeaderboard retrieves the top limit drivers by completed deliveries, then on-time rate, then driver ID.
+	// Deliveries up to onTimeGrace late count as on time, as in GetMetrics.
+	GetDriverLeaderboard(ctx context.Context, startTime, endTime time.Time, limit int, onTimeGrace time.Duration) ([]domain.DriverStats, error)
+
+	// ListActiveDriverIDs retrieves the distinct IDs of drivers with a non-terminal delivery, sorted ascending
+	ListActiveDriverIDs(ctx context.Context) ([]string, error)
+
+	// ListServedCities retrieves the distinct non-blank pickup and delivery cities, sorted ascending
+	ListServedCities(ctx context.Context) ([]string, error)
 
-	// GetMetrics retrieves delivery metrics for a time range
-	GetMetrics(ctx context.Context, startTime, endTime time.Time, driverID *string) (*domain.DeliveryMetrics, error)
+	// RecordDriverLocation stores a driver's location ping
+	RecordDriverLocation(ctx context.Context, driverID string, latitude, longitude float64, recordedAt time.Time) error
+
+	// GetLatestDriverLocation retrieves the driver's ping with the latest recorded time
+	GetLatestDriverLocation(ctx context.Context, driverID string) (*domain.DriverLocation, error)
+
+	// ListModifiedSince retrieves up to limit deliveries created, updated or soft-deleted after the
+	// cursor, ordered by change time then ID. Soft-deleted rows are included with DeletedAt set.
+	ListModifiedSince(ctx context.Context, after domain.SyncCursor, limit int) ([]*domain.DeliveryAssignment, error)
+
+	// StreamAll iterates over all delivery assignments in batches, calling fn for each one.
+	// Iteration stops at the first error returned by fn or when ctx is canceled.
+	StreamAll(ctx context.Context, fn func(*domain.DeliveryAssignment) error) error
+
+	// ListOverdueAssigned retrieves up to limit ASSIGNED deliveries whose pickup was due before cutoff:
+	// both the scheduled pickup time and, when set, the time the driver was assigned are before it
+	ListOverdueAssigned(ctx context.Context, cutoff time.Time, limit int) ([]*domain.DeliveryAssignment, error)
+
+	// EscalateWaitingPending raises up to limit of the oldest PENDING deliveries of priority from created
+	// before cutoff to priority to, stamping them escalatedAt, and returns them as updated. It is one
+	// conditional update, so deliveries claimed or escalated concurrently are not touched.
+	EscalateWaitingPending(ctx context.Context, from, to domain.DeliveryPriority, cutoff, escalatedAt time.Time, limit int) ([]*domain.DeliveryAssignment, error)
+
+	// ClaimNextPending atomically assigns the most urgent, then oldest, PENDING delivery to driverID and returns it.
+	// Concurrent claims never get the same delivery; ErrNotFound means none is pending.
+	ClaimNextPending(ctx context.Context, driverID string) (*domain.DeliveryAssignment, error)
 
 	// Delete soft-deletes a delivery assignment
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// DeleteByOrderID soft-deletes the delivery assignments for an order that have not been picked up and
+	// returns the IDs of those deleted. Picked-up deliveries are skipped by the delete itself, so none is
+	// deleted even if it is picked up concurrently.
+	DeleteByOrderID(ctx context.Context, orderID string) ([]uuid.UUID, error)
+
+	// CountMatching counts the delivery assignments matching filters that DeleteMatching may delete,
+	// leaving out picked-up ones; pagination is ignored
+	CountMatching(ctx context.Context, filters ListFilters) (int64, error)
+
+	// DeleteMatching soft-deletes up to limit delivery assignments matching filters, oldest first,
+	// and returns the IDs of those deleted. Deliveries a driver has picked up are never deleted, even
+	// when the filters name their status. Pagination is ignored.
+	DeleteMatching(ctx context.Context, filters ListFilters, limit int) ([]uuid.UUID, error)
+
 	// WithTransaction executes a function within a database transaction
 	WithTransaction(ctx context.Context, fn func(repo DeliveryRepository) error) error
 }
 
+// ListTotal is the number of deliveries matching a list. IsEstimate is set when the exact count
+// took too long and Count is the query planner's estimate instead.
+type ListTotal struct {
+	Count      int64
+	IsEstimate bool
+}
+
 // ListFilters defines filters for listing delivery assignments
 type ListFilters struct {
 	Page     int
 	PageSize int
 	Status   *domain.DeliveryStatus
 	DriverID *string
+	Activity domain.ActivityFilter // Combined with Status by intersection; empty means any
+
+	RequiredVehicleType *string
+	Tag                 *string // Only deliveries carrying this tag
+	HasDriver           *bool   // true: a driver is assigned, false: none is; nil means either
+	CreatedBefore       *time.Time
 }
@@ -0,0 +1,14 @@
+package grpc
+
+import (
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+// AdminMethods lists the methods middleware.AdminUnaryInterceptor reserves for admin API keys
+func AdminMethods() []string {
+	return []string{
+		pb.DeliveryService_BulkDeleteDeliveries_FullMethodName,
+		// Replays reach every downstream consumer again
+		pb.DeliveryService_ReplayEvents_FullMethodName,
+	}
+}
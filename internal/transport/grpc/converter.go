@@ -1,18 +1,96 @@
 package grpc
 
 import (
+	"context"
 	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
 	pb "github.com/mohamadchoker/order-delivery-service/proto"
 )
 
 // Proto to Domain conversions
 
+// maxEchoedIDLength bounds how much of an invalid ID is echoed back in error messages
+const maxEchoedIDLength = 40
+
+// parseID parses a delivery ID from a request field. Malformed values and the nil UUID are
+// rejected with InvalidArgument naming the field and the (truncated, quoted) offending value.
+func parseID(field, raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %s is not a valid UUID", field, sanitizeID(raw))
+	}
+	if id == uuid.Nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: the nil UUID does not identify a delivery", field)
+	}
+	return id, nil
+}
+
+// sanitizeID quotes raw for safe inclusion in an error message, escaping control characters and
+// invalid UTF-8 and truncating long values
+func sanitizeID(raw string) string {
+	if utf8.RuneCountInString(raw) <= maxEchoedIDLength {
+		return strconv.Quote(raw)
+	}
+	runes := []rune(raw)
+	return strconv.Quote(string(runes[:maxEchoedIDLength])) + "..."
+}
+
+// createInputFromProto converts a create request to use case input
+func createInputFromProto(req *pb.CreateDeliveryAssignmentRequest) service.CreateDeliveryInput {
+	input := service.CreateDeliveryInput{
+		OrderID:               req.OrderId,
+		PickupAddress:         protoToAddress(req.PickupAddress),
+		DeliveryAddress:       protoToAddress(req.DeliveryAddress),
+		ScheduledPickupTime:   req.ScheduledPickupTime.AsTime(),
+		EstimatedDeliveryTime: req.EstimatedDeliveryTime.AsTime(),
+		Notes:                 req.Notes,
+		TimeZone:              req.TimeZone,
+		AllowConcurrent:       req.AllowConcurrent,
+		Tags:                  req.Tags,
+		Priority:              protoPriorityToDomain(req.Priority),
+	}
+	if req.RequiredVehicleType != "" {
+		input.RequiredVehicleType = &req.RequiredVehicleType
+	}
+	if req.RecipientEmail != "" {
+		input.RecipientEmail = &req.RecipientEmail
+	}
+	for _, status := range req.NotifyPrefs {
+		// UNSPECIFIED has no domain status; leave it blank so validation rejects it
+		if status == pb.DeliveryStatus_UNSPECIFIED {
+			input.NotifyPrefs = append(input.NotifyPrefs, "")
+			continue
+		}
+		input.NotifyPrefs = append(input.NotifyPrefs, protoStatusToDomain(status))
+	}
+	if req.Metadata != nil {
+		input.Metadata = req.Metadata.AsMap()
+	}
+	if req.EstimatedDeliveryWindowStart != nil {
+		start := req.EstimatedDeliveryWindowStart.AsTime()
+		input.EstimatedDeliveryWindowStart = &start
+	}
+	if req.EstimatedDeliveryWindowEnd != nil {
+		end := req.EstimatedDeliveryWindowEnd.AsTime()
+		input.EstimatedDeliveryWindowEnd = &end
+	}
+	return input
+}
+
 func protoToAddress(p *pb.Address) domain.Address {
 	if p == nil {
 		return domain.Address{}
@@ -28,6 +106,16 @@ func protoToAddress(p *pb.Address) domain.Address {
 	}
 }
 
+func protoToPackage(p *pb.Package) domain.Package {
+	if p == nil {
+		return domain.Package{}
+	}
+	return domain.Package{
+		Description: p.Description,
+		Quantity:    int(p.Quantity),
+	}
+}
+
 func protoStatusToDomain(s pb.DeliveryStatus) domain.DeliveryStatus {
 	switch s {
 	case pb.DeliveryStatus_PENDING:
@@ -44,11 +132,40 @@ func protoStatusToDomain(s pb.DeliveryStatus) domain.DeliveryStatus {
 		return domain.DeliveryStatusFailed
 	case pb.DeliveryStatus_CANCELLED:
 		return domain.DeliveryStatusCancelled
+	case pb.DeliveryStatus_DEAD_LETTER:
+		return domain.DeliveryStatusDeadLetter
 	default:
 		return domain.DeliveryStatusPending
 	}
 }
 
+func protoActivityToDomain(a pb.ActivityFilter) domain.ActivityFilter {
+	switch a {
+	case pb.ActivityFilter_ACTIVITY_ACTIVE:
+		return domain.ActivityActive
+	case pb.ActivityFilter_ACTIVITY_TERMINAL:
+		return domain.ActivityTerminal
+	case pb.ActivityFilter_ACTIVITY_DEAD_LETTER:
+		return domain.ActivityDeadLetter
+	default:
+		return domain.ActivityAny
+	}
+}
+
+// protoPriorityToDomain maps UNSPECIFIED to the empty priority, which create treats as NORMAL
+func protoPriorityToDomain(p pb.DeliveryPriority) domain.DeliveryPriority {
+	switch p {
+	case pb.DeliveryPriority_PRIORITY_LOW:
+		return domain.PriorityLow
+	case pb.DeliveryPriority_PRIORITY_NORMAL:
+		return domain.PriorityNormal
+	case pb.DeliveryPriority_PRIORITY_HIGH:
+		return domain.PriorityHigh
+	default:
+		return ""
+	}
+}
+
 // Domain to Proto conversions
 
 func addressToProto(a domain.Address) *pb.Address {
@@ -64,7 +181,7 @@ func addressToProto(a domain.Address) *pb.Address {
 }
 
 func domainStatusToProto(s domain.DeliveryStatus) pb.DeliveryStatus {
-	switch s {
+	switch s.Canonical() {
 	case domain.DeliveryStatusPending:
 		return pb.DeliveryStatus_PENDING
 	case domain.DeliveryStatusAssigned:
@@ -79,11 +196,26 @@ func domainStatusToProto(s domain.DeliveryStatus) pb.DeliveryStatus {
 		return pb.DeliveryStatus_FAILED
 	case domain.DeliveryStatusCancelled:
 		return pb.DeliveryStatus_CANCELLED
+	case domain.DeliveryStatusDeadLetter:
+		return pb.DeliveryStatus_DEAD_LETTER
 	default:
 		return pb.DeliveryStatus_UNSPECIFIED
 	}
 }
 
+func domainPriorityToProto(p domain.DeliveryPriority) pb.DeliveryPriority {
+	switch p {
+	case domain.PriorityLow:
+		return pb.DeliveryPriority_PRIORITY_LOW
+	case domain.PriorityNormal:
+		return pb.DeliveryPriority_PRIORITY_NORMAL
+	case domain.PriorityHigh:
+		return pb.DeliveryPriority_PRIORITY_HIGH
+	default:
+		return pb.DeliveryPriority_PRIORITY_UNSPECIFIED
+	}
+}
+
 func deliveryToProto(d *domain.DeliveryAssignment) *pb.DeliveryAssignment {
 	proto := &pb.DeliveryAssignment{
 		Id:                    d.ID.String(),
@@ -96,11 +228,37 @@ func deliveryToProto(d *domain.DeliveryAssignment) *pb.DeliveryAssignment {
 		Notes:                 d.Notes,
 		CreatedAt:             timestamppb.New(d.CreatedAt),
 		UpdatedAt:             timestamppb.New(d.UpdatedAt),
+		TimeZone:              d.TimeZone,
+		AllowConcurrent:       d.AllowConcurrent,
+		Tags:                  d.Tags,
+		Priority:              domainPriorityToProto(d.Priority),
+
+		ScheduledPickupTimeLocal:   d.LocalScheduledPickupTime().Format(time.RFC3339),
+		EstimatedDeliveryTimeLocal: d.LocalEstimatedDeliveryTime().Format(time.RFC3339),
 	}
 
 	if d.DriverID != nil {
 		proto.DriverId = *d.DriverID
 	}
+	if d.BackupDriverID != nil {
+		proto.BackupDriverId = *d.BackupDriverID
+	}
+
+	if d.RequiredVehicleType != nil {
+		proto.RequiredVehicleType = *d.RequiredVehicleType
+	}
+
+	if d.EstimatedDeliveryWindowStart != nil {
+		proto.EstimatedDeliveryWindowStart = timestamppb.New(*d.EstimatedDeliveryWindowStart)
+	}
+
+	if d.EstimatedDeliveryWindowEnd != nil {
+		proto.EstimatedDeliveryWindowEnd = timestamppb.New(*d.EstimatedDeliveryWindowEnd)
+	}
+
+	if d.DriverETA != nil {
+		proto.DriverEta = timestamppb.New(*d.DriverETA)
+	}
 
 	if d.ActualPickupTime != nil {
 		proto.ActualPickupTime = timestamppb.New(*d.ActualPickupTime)
@@ -110,9 +268,80 @@ func deliveryToProto(d *domain.DeliveryAssignment) *pb.DeliveryAssignment {
 		proto.ActualDeliveryTime = timestamppb.New(*d.ActualDeliveryTime)
 	}
 
+	for _, n := range d.TimelineNotes {
+		proto.TimelineNotes = append(proto.TimelineNotes, &pb.TimelineNote{
+			Note:      n.Note,
+			CreatedAt: timestamppb.New(n.CreatedAt),
+		})
+	}
+
+	if d.Rating != nil {
+		proto.Rating = int32(*d.Rating)
+	}
+
+	if d.Feedback != nil {
+		proto.Feedback = *d.Feedback
+	}
+
+	if d.DeadLetterReason != nil {
+		proto.DeadLetterReason = *d.DeadLetterReason
+	}
+
+	if d.FailureReasonCode != nil {
+		proto.FailureReasonCode = *d.FailureReasonCode
+	}
+
+	proto.AttemptNumber = int32(d.AttemptNumber)
+
+	if d.RecipientEmail != nil {
+		proto.RecipientEmail = *d.RecipientEmail
+	}
+
+	for _, status := range d.NotifyPrefs {
+		proto.NotifyPrefs = append(proto.NotifyPrefs, domainStatusToProto(status))
+	}
+
+	if len(d.Metadata) > 0 {
+		// Metadata is decoded from JSON, so it always converts
+		proto.Metadata, _ = structpb.NewStruct(d.Metadata)
+	}
+
+	if d.DeletedAt != nil {
+		proto.DeletedAt = timestamppb.New(*d.DeletedAt)
+	}
+
+	for _, p := range d.Packages {
+		proto.Packages = append(proto.Packages, &pb.Package{
+			Description: p.Description,
+			Quantity:    int32(p.Quantity),
+		})
+	}
+
+	if d.ParentDeliveryID != nil {
+		proto.ParentDeliveryId = d.ParentDeliveryID.String()
+	}
+
 	return proto
 }
 
+// deliveryWithPickupCodeToProto also includes the pickup code, for responses to the sender only
+func deliveryWithPickupCodeToProto(d *domain.DeliveryAssignment) *pb.DeliveryAssignment {
+	proto := deliveryToProto(d)
+	proto.PickupCode = d.PickupCode
+	return proto
+}
+
+func statusTransitionsToProto(t domain.StatusTransitions) *pb.StatusTransitions {
+	next := make([]pb.DeliveryStatus, len(t.To))
+	for i, s := range t.To {
+		next[i] = domainStatusToProto(s)
+	}
+	return &pb.StatusTransitions{
+		Status:      domainStatusToProto(t.From),
+		AllowedNext: next,
+	}
+}
+
 // Error handling
 
 func handleError(err error) error {
@@ -125,7 +354,147 @@ func handleError(err error) error {
 		return status.Error(codes.FailedPrecondition, err.Error())
 	case errors.Is(err, domain.ErrAlreadyExists):
 		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, domain.ErrTimeout):
+		return status.Error(codes.DeadlineExceeded, "request deadline exceeded")
+	case errors.Is(err, domain.ErrUnavailable):
+		return withRetryInfo(status.New(codes.Unavailable, "service temporarily unavailable"), err)
+	case errors.Is(err, domain.ErrResourceExhausted):
+		return withRetryInfo(status.New(codes.ResourceExhausted, "server busy; retry later"), err)
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
 }
+
+// withRetryInfo attaches the retry delay of a domain.RetryAfterError to st as google.rpc.RetryInfo,
+// which middleware.RetryAfterUnaryInterceptor also returns in the retry-after header
+func withRetryInfo(st *status.Status, err error) error {
+	var retryErr *domain.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		return st.Err()
+	}
+
+	delay := max(retryErr.RetryAfter, constants.MinRetryAfter)
+	detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func statusInfoToProto(s *domain.DeliveryStatusInfo) *pb.DeliveryStatusInfo {
+	return &pb.DeliveryStatusInfo{
+		Id:        s.ID.String(),
+		Status:    domainStatusToProto(s.Status),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+	}
+}
+
+func slaToProto(s *domain.DeliverySLA) *pb.DeliverySLA {
+	return &pb.DeliverySLA{
+		Id:        s.ID.String(),
+		Deadline:  timestamppb.New(s.Deadline),
+		Remaining: durationpb.New(s.Remaining),
+	}
+}
+
+func feasibilityToProto(r *service.FeasibilityResult) *pb.CheckDeliveryFeasibilityResponse {
+	return &pb.CheckDeliveryFeasibilityResponse{
+		Feasible:          r.Feasible,
+		DistanceKm:        r.DistanceKm,
+		RequiredDuration:  durationpb.New(r.RequiredDuration),
+		AvailableDuration: durationpb.New(r.AvailableDuration),
+		Reasons:           r.Reasons,
+	}
+}
+
+func metricsToProto(m *domain.DeliveryMetrics) *pb.DeliveryMetrics {
+	return &pb.DeliveryMetrics{
+		TotalDeliveries:                 m.TotalDeliveries,
+		CompletedDeliveries:             m.CompletedDeliveries,
+		FailedDeliveries:                m.FailedDeliveries,
+		CancelledDeliveries:             m.CancelledDeliveries,
+		DeadLetterDeliveries:            m.DeadLetterDeliveries,
+		FailuresByReason:                m.FailuresByReason,
+		AverageDeliveryTimeMinutes:      m.AverageDeliveryTimeMinutes,
+		AverageLifecycleDurationMinutes: m.AverageLifecycleDurationMinutes,
+		OnTimeDeliveryRate:              m.OnTimeDeliveryRate,
+		AverageRating:                   m.AverageRating,
+		Errors:                          m.Errors,
+	}
+}
+
+func driverStatsToProto(s domain.DriverStats) *pb.DriverStats {
+	return &pb.DriverStats{
+		Rank:                s.Rank,
+		DriverId:            s.DriverID,
+		CompletedDeliveries: s.CompletedDeliveries,
+		OnTimeDeliveryRate:  s.OnTimeDeliveryRate,
+	}
+}
+
+// statusChangeToProto converts a saved status change to a stream event
+func statusChangeToProto(c service.StatusChange) *pb.DeliveryStatusEvent {
+	event := &pb.DeliveryStatusEvent{
+		DeliveryId: c.DeliveryID.String(),
+		OrderId:    c.OrderID,
+		Status:     domainStatusToProto(c.Status),
+		ChangedAt:  timestamppb.New(c.ChangedAt),
+		EtaUpdate:  c.ETAUpdate,
+	}
+	if c.DriverID != nil {
+		event.DriverId = *c.DriverID
+	}
+	if c.DriverETA != nil {
+		event.DriverEta = timestamppb.New(*c.DriverETA)
+	}
+	return event
+}
+
+// statusBreakdownToProto keys per-status counts by proto enum name; nil stays nil
+func statusBreakdownToProto(breakdown map[domain.DeliveryStatus]int64) map[string]int64 {
+	if breakdown == nil {
+		return nil
+	}
+
+	counts := make(map[string]int64, len(breakdown))
+	for s, count := range breakdown {
+		counts[domainStatusToProto(s).String()] += count
+	}
+	return counts
+}
+
+// driverLocationFromProto converts a protobuf ping to domain, leaving RecordedAt zero when unset
+func driverLocationFromProto(p *pb.DriverLocation) domain.DriverLocation {
+	location := domain.DriverLocation{
+		DriverID:  p.DriverId,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+	}
+	if p.RecordedAt != nil {
+		location.RecordedAt = p.RecordedAt.AsTime()
+	}
+	return location
+}
+
+// driverLocationToProto converts a domain location ping to protobuf
+func driverLocationToProto(l *domain.DriverLocation) *pb.DriverLocation {
+	return &pb.DriverLocation{
+		DriverId:   l.DriverID,
+		Latitude:   l.Latitude,
+		Longitude:  l.Longitude,
+		RecordedAt: timestamppb.New(l.RecordedAt),
+	}
+}
+
+// serverInfoToProto converts build info to protobuf, measuring uptime up to now
+func serverInfoToProto(info BuildInfo, now time.Time) *pb.ServerInfo {
+	return &pb.ServerInfo{
+		Version:   info.Version,
+		BuildDate: info.BuildDate,
+		GitCommit: info.GitCommit,
+		StartedAt: timestamppb.New(info.StartedAt),
+		Uptime:    durationpb.New(now.Sub(info.StartedAt)),
+	}
+}
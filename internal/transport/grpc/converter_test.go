@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
+
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+func TestHandleError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{name: "not found", err: domain.ErrNotFound, expected: codes.NotFound},
+		{name: "invalid input", err: &domain.ValidationError{Field: "id", Message: "is required"}, expected: codes.InvalidArgument},
+		{name: "canceled", err: context.Canceled, expected: codes.Canceled},
+		{name: "wrapped canceled", err: fmt.Errorf("query failed: %w", context.Canceled), expected: codes.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: codes.DeadlineExceeded},
+		{name: "domain timeout", err: domain.ErrTimeout, expected: codes.DeadlineExceeded},
+		{name: "resource exhausted", err: fmt.Errorf("%w: limit reached", domain.ErrResourceExhausted), expected: codes.ResourceExhausted},
+		{name: "unknown", err: errors.New("boom"), expected: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, status.Code(handleError(tt.err)))
+		})
+	}
+}
+
+func TestHandleError_RetryInfo(t *testing.T) {
+	err := handleError(&domain.RetryAfterError{
+		RetryAfter: 20 * time.Second,
+		Err:        fmt.Errorf("%w: database circuit breaker is open", domain.ErrUnavailable),
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	delay, ok := middleware.RetryDelay(err)
+	require.True(t, ok)
+	assert.Equal(t, 20*time.Second, delay)
+
+	// Never suggest retrying immediately
+	delay, ok = middleware.RetryDelay(handleError(&domain.RetryAfterError{Err: domain.ErrResourceExhausted}))
+	require.True(t, ok)
+	assert.Equal(t, constants.MinRetryAfter, delay)
+
+	// Without a hint there is no delay to suggest
+	_, ok = middleware.RetryDelay(handleError(domain.ErrUnavailable))
+	assert.False(t, ok)
+}
+
+func TestStatusConversion_CancelledSpellings(t *testing.T) {
+	// Both spellings map to the proto enum, and the enum maps back to the domain spelling
+	for _, stored := range []domain.DeliveryStatus{"CANCELED", "CANCELLED"} {
+		assert.Equal(t, pb.DeliveryStatus_CANCELLED, domainStatusToProto(stored), stored)
+	}
+	assert.Equal(t, domain.DeliveryStatusCancelled, protoStatusToDomain(pb.DeliveryStatus_CANCELLED))
+}
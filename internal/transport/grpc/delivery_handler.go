@@ -2,14 +2,25 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
 	"github.com/mohamadchoker/order-delivery-service/internal/service"
+	"github.com/mohamadchoker/order-delivery-service/pkg/metrics"
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
 
 	pb "github.com/mohamadchoker/order-delivery-service/proto"
 )
@@ -17,53 +28,77 @@ import (
 // Handler implements the gRPC DeliveryService
 type Handler struct {
 	pb.UnimplementedDeliveryServiceServer
-	useCase service.DeliveryUseCase
-	logger  *zap.Logger
+	useCase   service.DeliveryUseCase
+	logger    *zap.Logger
+	buildInfo BuildInfo
+	clock     domain.Clock
+	metrics   *metrics.Metrics
+}
+
+// BuildInfo describes the running binary, as reported by GetServerInfo
+type BuildInfo struct {
+	Version   string
+	BuildDate string
+	GitCommit string
+	StartedAt time.Time
 }
 
 // NewHandler creates a new gRPC handler
 func NewHandler(useCase service.DeliveryUseCase, logger *zap.Logger) *Handler {
+	return NewHandlerWithBuildInfo(useCase, logger, BuildInfo{})
+}
+
+// NewHandlerWithBuildInfo creates a new gRPC handler that reports the given build info
+func NewHandlerWithBuildInfo(useCase service.DeliveryUseCase, logger *zap.Logger, info BuildInfo) *Handler {
+	if info.StartedAt.IsZero() {
+		info.StartedAt = domain.SystemClock.Now()
+	}
+
 	return &Handler{
-		useCase: useCase,
-		logger:  logger,
+		useCase:   useCase,
+		logger:    logger,
+		buildInfo: info,
+		clock:     domain.SystemClock,
 	}
 }
 
 // CreateDeliveryAssignment creates a new delivery assignment
 func (h *Handler) CreateDeliveryAssignment(ctx context.Context, req *pb.CreateDeliveryAssignmentRequest) (*pb.DeliveryAssignment, error) {
-	// Validate request
-	if req.OrderId == "" {
-		return nil, status.Error(codes.InvalidArgument, "order_id is required")
-	}
-	if req.PickupAddress == nil || req.DeliveryAddress == nil {
-		return nil, status.Error(codes.InvalidArgument, "pickup_address and delivery_address are required")
+	// Required fields are checked by the validation interceptor (see RequiredFields)
+
+	// Create delivery assignment
+	assignment, err := h.useCase.CreateDeliveryAssignment(ctx, createInputFromProto(req))
+	if err != nil {
+		return nil, handleError(err)
 	}
+	h.metrics.RecordDeliveryCreated(assignment.DeliveryAddress.Country)
+
+	return deliveryWithPickupCodeToProto(assignment), nil
+}
 
-	// Convert proto to domain
-	input := service.CreateDeliveryInput{
-		OrderID:               req.OrderId,
+// CheckDeliveryFeasibility estimates whether a prospective delivery can be made in its window
+func (h *Handler) CheckDeliveryFeasibility(ctx context.Context, req *pb.CheckDeliveryFeasibilityRequest) (*pb.CheckDeliveryFeasibilityResponse, error) {
+	// Required fields are checked by the validation interceptor (see RequiredFields)
+
+	result, err := h.useCase.CheckDeliveryFeasibility(ctx, service.FeasibilityInput{
 		PickupAddress:         protoToAddress(req.PickupAddress),
 		DeliveryAddress:       protoToAddress(req.DeliveryAddress),
 		ScheduledPickupTime:   req.ScheduledPickupTime.AsTime(),
 		EstimatedDeliveryTime: req.EstimatedDeliveryTime.AsTime(),
-		Notes:                 req.Notes,
-	}
-
-	// Create delivery assignment
-	assignment, err := h.useCase.CreateDeliveryAssignment(ctx, input)
+	})
 	if err != nil {
 		return nil, handleError(err)
 	}
 
-	return deliveryToProto(assignment), nil
+	return feasibilityToProto(result), nil
 }
 
 // GetDeliveryAssignment retrieves a delivery assignment by ID
 func (h *Handler) GetDeliveryAssignment(ctx context.Context, req *pb.GetDeliveryAssignmentRequest) (*pb.DeliveryAssignment, error) {
 	// Parse UUID
-	id, err := uuid.Parse(req.Id)
+	id, err := parseID("id", req.Id)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid id format")
+		return nil, err
 	}
 
 	// Get delivery assignment
@@ -75,19 +110,102 @@ func (h *Handler) GetDeliveryAssignment(ctx context.Context, req *pb.GetDelivery
 	return deliveryToProto(assignment), nil
 }
 
+// GetDeliveryStatus retrieves only the current status of a delivery
+func (h *Handler) GetDeliveryStatus(ctx context.Context, req *pb.GetDeliveryStatusRequest) (*pb.DeliveryStatusInfo, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get delivery status
+	info, err := h.useCase.GetDeliveryStatus(ctx, id)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return statusInfoToProto(info), nil
+}
+
+// GetSLADeadline retrieves the SLA deadline of a delivery and the time remaining until it
+func (h *Handler) GetSLADeadline(ctx context.Context, req *pb.GetSLADeadlineRequest) (*pb.DeliverySLA, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	sla, err := h.useCase.GetSLADeadline(ctx, id)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return slaToProto(sla), nil
+}
+
+// BatchGetDeliveries retrieves multiple delivery assignments by ID
+func (h *Handler) BatchGetDeliveries(ctx context.Context, req *pb.BatchGetDeliveriesRequest) (*pb.BatchGetDeliveriesResponse, error) {
+	// Parse UUIDs
+	ids := make([]uuid.UUID, len(req.Ids))
+	for i, raw := range req.Ids {
+		id, err := parseID("ids", raw)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	// Get delivery assignments
+	assignments, err := h.useCase.BatchGetDeliveryAssignments(ctx, ids)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	deliveries := make([]*pb.DeliveryAssignment, len(assignments))
+	for i, assignment := range assignments {
+		deliveries[i] = deliveryToProto(assignment)
+	}
+
+	return &pb.BatchGetDeliveriesResponse{
+		Deliveries: deliveries,
+	}, nil
+}
+
+// CloneDeliveryAssignment copies a delivery into a new PENDING delivery with fresh times
+func (h *Handler) CloneDeliveryAssignment(ctx context.Context, req *pb.CloneDeliveryAssignmentRequest) (*pb.DeliveryAssignment, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if req.ScheduledPickupTime == nil || req.EstimatedDeliveryTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "scheduled_pickup_time and estimated_delivery_time are required")
+	}
+
+	// Clone delivery assignment
+	assignment, err := h.useCase.CloneDeliveryAssignment(ctx, id,
+		req.ScheduledPickupTime.AsTime(),
+		req.EstimatedDeliveryTime.AsTime(),
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryWithPickupCodeToProto(assignment), nil
+}
+
 // UpdateDeliveryStatus updates the status of a delivery
 func (h *Handler) UpdateDeliveryStatus(ctx context.Context, req *pb.UpdateDeliveryStatusRequest) (*pb.DeliveryAssignment, error) {
 	// Parse UUID
-	id, err := uuid.Parse(req.Id)
+	id, err := parseID("id", req.Id)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid id format")
+		return nil, err
 	}
 
 	// Convert proto status to domain status
 	domainStatus := protoStatusToDomain(req.Status)
 
 	// Update status
-	assignment, err := h.useCase.UpdateDeliveryStatus(ctx, id, domainStatus, req.Notes)
+	assignment, err := h.useCase.UpdateDeliveryStatus(ctx, id, domainStatus, req.Notes, req.PickupCode, req.FailureReasonCode)
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -99,8 +217,10 @@ func (h *Handler) UpdateDeliveryStatus(ctx context.Context, req *pb.UpdateDelive
 func (h *Handler) ListDeliveryAssignments(ctx context.Context, req *pb.ListDeliveryAssignmentsRequest) (*pb.ListDeliveryAssignmentsResponse, error) {
 	// Prepare input
 	input := service.ListDeliveryInput{
-		Page:     int(req.Page),
-		PageSize: int(req.PageSize),
+		Page:      int(req.Page),
+		PageSize:  int(req.PageSize),
+		Activity:  protoActivityToDomain(req.Activity),
+		HasDriver: req.HasDriver,
 	}
 
 	if req.Status != pb.DeliveryStatus_UNSPECIFIED {
@@ -112,8 +232,26 @@ func (h *Handler) ListDeliveryAssignments(ctx context.Context, req *pb.ListDeliv
 		input.DriverID = &req.DriverId
 	}
 
-	// List assignments
-	assignments, totalCount, err := h.useCase.ListDeliveryAssignments(ctx, input)
+	if req.RequiredVehicleType != "" {
+		input.RequiredVehicleType = &req.RequiredVehicleType
+	}
+
+	if req.Tag != "" {
+		input.Tag = &req.Tag
+	}
+
+	// List assignments, counting per status only when asked since it costs an extra query
+	var (
+		assignments []*domain.DeliveryAssignment
+		total       service.ListTotal
+		breakdown   map[domain.DeliveryStatus]int64
+		err         error
+	)
+	if req.IncludeStatusBreakdown {
+		assignments, total, breakdown, err = h.useCase.ListDeliveryAssignmentsWithStatusBreakdown(ctx, input)
+	} else {
+		assignments, total, err = h.useCase.ListDeliveryAssignments(ctx, input)
+	}
 	if err != nil {
 		return nil, handleError(err)
 	}
@@ -125,23 +263,21 @@ func (h *Handler) ListDeliveryAssignments(ctx context.Context, req *pb.ListDeliv
 	}
 
 	return &pb.ListDeliveryAssignmentsResponse{
-		Assignments: protoAssignments,
-		TotalCount:  int32(totalCount),
-		Page:        req.Page,
-		PageSize:    req.PageSize,
+		Assignments:          protoAssignments,
+		TotalCount:           int32(total.Count),
+		TotalCountIsEstimate: total.IsEstimate,
+		Page:                 req.Page,
+		PageSize:             req.PageSize,
+		StatusBreakdown:      statusBreakdownToProto(breakdown),
 	}, nil
 }
 
 // AssignDriver assigns a driver to a delivery
 func (h *Handler) AssignDriver(ctx context.Context, req *pb.AssignDriverRequest) (*pb.DeliveryAssignment, error) {
 	// Parse UUID
-	id, err := uuid.Parse(req.Id)
+	id, err := parseID("id", req.Id)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid id format")
-	}
-
-	if req.DriverId == "" {
-		return nil, status.Error(codes.InvalidArgument, "driver_id is required")
+		return nil, err
 	}
 
 	// Assign driver
@@ -153,6 +289,21 @@ func (h *Handler) AssignDriver(ctx context.Context, req *pb.AssignDriverRequest)
 	return deliveryToProto(assignment), nil
 }
 
+// AssignBackupDriver assigns a backup driver to a delivery
+func (h *Handler) AssignBackupDriver(ctx context.Context, req *pb.AssignBackupDriverRequest) (*pb.DeliveryAssignment, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := h.useCase.AssignBackupDriver(ctx, id, req.DriverId)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
 // GetDeliveryMetrics retrieves delivery metrics
 func (h *Handler) GetDeliveryMetrics(ctx context.Context, req *pb.GetDeliveryMetricsRequest) (*pb.DeliveryMetrics, error) {
 	startTime := req.StartTime.AsTime()
@@ -173,20 +324,209 @@ func (h *Handler) GetDeliveryMetrics(ctx context.Context, req *pb.GetDeliveryMet
 		return nil, handleError(err)
 	}
 
-	return &pb.DeliveryMetrics{
-		TotalDeliveries:            metrics.TotalDeliveries,
-		CompletedDeliveries:        metrics.CompletedDeliveries,
-		FailedDeliveries:           metrics.FailedDeliveries,
-		CancelledDeliveries:        metrics.CancelledDeliveries,
-		AverageDeliveryTimeMinutes: metrics.AverageDeliveryTimeMinutes,
-		OnTimeDeliveryRate:         metrics.OnTimeDeliveryRate,
+	return metricsToProto(metrics), nil
+}
+
+// GetMetricsForDrivers retrieves metrics for several drivers in a single query
+func (h *Handler) GetMetricsForDrivers(ctx context.Context, req *pb.GetMetricsForDriversRequest) (*pb.GetMetricsForDriversResponse, error) {
+	metrics, err := h.useCase.GetMetricsForDrivers(ctx, req.DriverIds, req.StartTime.AsTime(), req.EndTime.AsTime())
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	byDriver := make(map[string]*pb.DeliveryMetrics, len(metrics))
+	for driverID, driverMetrics := range metrics {
+		byDriver[driverID] = metricsToProto(driverMetrics)
+	}
+
+	return &pb.GetMetricsForDriversResponse{
+		Metrics: byDriver,
+	}, nil
+}
+
+// GetDriverLeaderboard ranks drivers by completed deliveries for a time range
+func (h *Handler) GetDriverLeaderboard(ctx context.Context, req *pb.GetDriverLeaderboardRequest) (*pb.DriverLeaderboard, error) {
+	leaderboard, err := h.useCase.GetDriverLeaderboard(
+		ctx,
+		req.StartTime.AsTime(),
+		req.EndTime.AsTime(),
+		int(req.Limit),
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	drivers := make([]*pb.DriverStats, len(leaderboard))
+	for i, stats := range leaderboard {
+		drivers[i] = driverStatsToProto(stats)
+	}
+
+	return &pb.DriverLeaderboard{
+		Drivers: drivers,
+	}, nil
+}
+
+// ListServedCities lists the cities covered by deliveries
+func (h *Handler) ListServedCities(ctx context.Context, _ *pb.ListServedCitiesRequest) (*pb.ListServedCitiesResponse, error) {
+	cities, err := h.useCase.ListServedCities(ctx)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.ListServedCitiesResponse{
+		Cities: cities,
+	}, nil
+}
+
+// ListActiveDriverIDs lists drivers with active work for dispatch
+func (h *Handler) ListActiveDriverIDs(ctx context.Context, _ *pb.ListActiveDriverIDsRequest) (*pb.ListActiveDriverIDsResponse, error) {
+	driverIDs, err := h.useCase.ListActiveDriverIDs(ctx)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.ListActiveDriverIDsResponse{
+		DriverIds: driverIDs,
+	}, nil
+}
+
+// ListModifiedDeliveries returns deliveries changed since the client's last sync
+func (h *Handler) ListModifiedDeliveries(ctx context.Context, req *pb.ListModifiedDeliveriesRequest) (*pb.ListModifiedDeliveriesResponse, error) {
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+
+	assignments, next, err := h.useCase.ListModifiedSince(ctx, since, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	deliveries := make([]*pb.DeliveryAssignment, len(assignments))
+	for i, assignment := range assignments {
+		deliveries[i] = deliveryToProto(assignment)
+	}
+
+	return &pb.ListModifiedDeliveriesResponse{
+		Deliveries: deliveries,
+		Watermark:  timestamppb.New(next.ModifiedAt),
+		NextCursor: next.String(),
+	}, nil
+}
+
+// SplitDelivery delivers part of a delivery and reschedules the remaining packages
+func (h *Handler) SplitDelivery(ctx context.Context, req *pb.SplitDeliveryRequest) (*pb.SplitDeliveryResponse, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make([]domain.Package, len(req.RemainingPackages))
+	for i, p := range req.RemainingPackages {
+		remaining[i] = protoToPackage(p)
+	}
+
+	parent, child, err := h.useCase.SplitDelivery(ctx, id, remaining)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.SplitDeliveryResponse{
+		Parent: deliveryToProto(parent),
+		Child:  deliveryWithPickupCodeToProto(child),
+	}, nil
+}
+
+// ReplayEvents re-sends a delivery's status change events
+func (h *Handler) ReplayEvents(ctx context.Context, req *pb.ReplayEventsRequest) (*pb.ReplayEventsResponse, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := h.useCase.ReplayEvents(ctx, id)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.ReplayEventsResponse{Replayed: int32(replayed)}, nil
+}
+
+// WithMetrics makes the handler record business metrics (creates by country, reattempts) to m
+func (h *Handler) WithMetrics(m *metrics.Metrics) *Handler {
+	h.metrics = m
+	return h
+}
+
+// ReattemptDelivery creates a new delivery retrying a failed one
+func (h *Handler) ReattemptDelivery(ctx context.Context, req *pb.ReattemptDeliveryRequest) (*pb.DeliveryAssignment, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	reattempt, err := h.useCase.ReattemptDelivery(ctx, id)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	h.metrics.RecordDeliveryOperation(constants.OpReattempt, string(reattempt.Status))
+	h.metrics.RecordDeliveryAttempt(reattempt.AttemptNumber, reattempt.Status == domain.DeliveryStatusDeadLetter)
+
+	// Out of attempts: the failed delivery itself was dead-lettered
+	if reattempt.Status == domain.DeliveryStatusDeadLetter {
+		return deliveryToProto(reattempt), nil
+	}
+	return deliveryWithPickupCodeToProto(reattempt), nil
+}
+
+// AddTags tags a delivery
+func (h *Handler) AddTags(ctx context.Context, req *pb.AddTagsRequest) (*pb.DeliveryAssignment, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := h.useCase.AddTags(ctx, id, req.Tags)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// RemoveTags removes tags from a delivery
+func (h *Handler) RemoveTags(ctx context.Context, req *pb.RemoveTagsRequest) (*pb.DeliveryAssignment, error) {
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := h.useCase.RemoveTags(ctx, id, req.Tags)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// GetStatusTransitionGraph returns the delivery status state machine for client introspection
+func (h *Handler) GetStatusTransitionGraph(ctx context.Context, _ *pb.GetStatusTransitionGraphRequest) (*pb.StatusTransitionGraph, error) {
+	graph := h.useCase.GetStatusTransitionGraph(ctx)
+
+	statuses := make([]*pb.StatusTransitions, len(graph))
+	for i, transitions := range graph {
+		statuses[i] = statusTransitionsToProto(transitions)
+	}
+
+	return &pb.StatusTransitionGraph{
+		Statuses: statuses,
 	}, nil
 }
 
 func (h *Handler) DeleteDeliveryAssignment(ctx context.Context, req *pb.DeleteDeliveryAssignmentRequest) (*empty.Empty, error) {
-	id, err := uuid.Parse(req.Id)
+	id, err := parseID("id", req.Id)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid delivery ID")
+		return nil, err
 	}
 
 	if err := h.useCase.DeleteDeliveryAssignment(ctx, id); err != nil {
@@ -195,3 +535,268 @@ func (h *Handler) DeleteDeliveryAssignment(ctx context.Context, req *pb.DeleteDe
 
 	return &empty.Empty{}, nil
 }
+
+// DeleteDeliveriesByOrder deletes all deliveries for a cancelled order
+func (h *Handler) DeleteDeliveriesByOrder(ctx context.Context, req *pb.DeleteDeliveriesByOrderRequest) (*pb.DeleteDeliveriesByOrderResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	deleted, err := h.useCase.DeleteDeliveriesByOrderID(ctx, req.OrderId)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.DeleteDeliveriesByOrderResponse{
+		DeletedCount: deleted,
+	}, nil
+}
+
+// BulkDeleteDeliveries previews or, with a confirmation token, soft-deletes the deliveries matching the filters
+func (h *Handler) BulkDeleteDeliveries(ctx context.Context, req *pb.BulkDeleteDeliveriesRequest) (*pb.BulkDeleteDeliveriesResponse, error) {
+	filters := service.ListFilters{
+		Activity:  protoActivityToDomain(req.Activity),
+		HasDriver: req.HasDriver,
+	}
+
+	if req.Status != pb.DeliveryStatus_UNSPECIFIED {
+		domainStatus := protoStatusToDomain(req.Status)
+		filters.Status = &domainStatus
+	}
+
+	if req.DriverId != "" {
+		filters.DriverID = &req.DriverId
+	}
+
+	if req.RequiredVehicleType != "" {
+		filters.RequiredVehicleType = &req.RequiredVehicleType
+	}
+
+	if req.Tag != "" {
+		filters.Tag = &req.Tag
+	}
+
+	if req.CreatedBefore != nil {
+		createdBefore := req.CreatedBefore.AsTime()
+		filters.CreatedBefore = &createdBefore
+	}
+
+	result, err := h.useCase.BulkDelete(ctx, filters, req.ConfirmToken)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return &pb.BulkDeleteDeliveriesResponse{
+		MatchedCount: result.Matched,
+		ConfirmToken: result.ConfirmToken,
+		DeletedCount: result.Deleted,
+	}, nil
+}
+
+// SubmitFeedback records the recipient's rating and feedback for a delivery
+func (h *Handler) SubmitFeedback(ctx context.Context, req *pb.SubmitFeedbackRequest) (*pb.DeliveryAssignment, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submit feedback
+	assignment, err := h.useCase.SubmitFeedback(ctx, id, int(req.Rating), req.Feedback)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// MarkDeadLetter permanently parks a failed delivery so it is never retried
+func (h *Handler) MarkDeadLetter(ctx context.Context, req *pb.MarkDeadLetterRequest) (*pb.DeliveryAssignment, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dead-letter delivery
+	assignment, err := h.useCase.MarkDeadLetter(ctx, id, req.Reason)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// AppendNote adds a timestamped note to a delivery's timeline
+func (h *Handler) AppendNote(ctx context.Context, req *pb.AppendNoteRequest) (*pb.DeliveryAssignment, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append note
+	assignment, err := h.useCase.AppendNote(ctx, id, req.Note)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// UpdateDriverETA records the driver's latest arrival estimate for a delivery on its way
+func (h *Handler) UpdateDriverETA(ctx context.Context, req *pb.UpdateDriverETARequest) (*pb.DeliveryAssignment, error) {
+	// Parse UUID
+	id, err := parseID("id", req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if req.DriverEta == nil {
+		return nil, status.Error(codes.InvalidArgument, "driver_eta is required")
+	}
+
+	// Update driver ETA
+	assignment, err := h.useCase.UpdateDriverETA(ctx, id, req.DriverEta.AsTime())
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return deliveryToProto(assignment), nil
+}
+
+// ExportDeliveries streams all delivery assignments for bulk export
+func (h *Handler) ExportDeliveries(_ *pb.ExportDeliveriesRequest, stream pb.DeliveryService_ExportDeliveriesServer) error {
+	err := h.useCase.ExportDeliveries(stream.Context(), func(assignment *domain.DeliveryAssignment) error {
+		return stream.Send(deliveryToProto(assignment))
+	})
+	if err != nil {
+		return handleError(err)
+	}
+
+	return nil
+}
+
+// StreamDriverLocation records every ping of a client stream and reports how many were accepted.
+// Invalid pings are skipped; any other failure ends the stream.
+func (h *Handler) StreamDriverLocation(stream pb.DeliveryService_StreamDriverLocationServer) error {
+	var accepted, rejected int32
+	for {
+		ping, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&pb.StreamDriverLocationResponse{
+				Accepted: accepted,
+				Rejected: rejected,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := h.useCase.RecordDriverLocation(stream.Context(), driverLocationFromProto(ping)); err != nil {
+			if !errors.Is(err, domain.ErrInvalidInput) {
+				return handleError(err)
+			}
+			h.logger.Debug("Rejected driver location ping",
+				zap.Error(err),
+				zap.String("driver_id", ping.DriverId),
+			)
+			rejected++
+			continue
+		}
+		accepted++
+	}
+}
+
+// ImportDeliveries imports each chunk of a client stream as it arrives and reports the rows that
+// failed. Rows are checked for the same required fields as CreateDeliveryAssignment. A failure
+// other than a rejected row ends the stream; rows imported before it are kept.
+func (h *Handler) ImportDeliveries(stream pb.DeliveryService_ImportDeliveriesServer) error {
+	required := RequiredFields()[pb.DeliveryService_CreateDeliveryAssignment_FullMethodName]
+	response := &pb.ImportDeliveriesResponse{}
+	var received int32
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			slices.SortFunc(response.Errors, func(a, b *pb.ImportRowError) int { return int(a.Row - b.Row) })
+			slices.SortFunc(response.Created, func(a, b *pb.ImportedDelivery) int { return int(a.Row - b.Row) })
+			response.Failed = int32(len(response.Errors))
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		inputs := make([]service.CreateDeliveryInput, 0, len(chunk.Rows))
+		rows := make([]int32, 0, len(chunk.Rows))
+		for _, req := range chunk.Rows {
+			received++
+			if missing := middleware.MissingFields(req, required); len(missing) > 0 {
+				response.Errors = append(response.Errors, &pb.ImportRowError{
+					Row:   received,
+					Error: fmt.Sprintf("%s is required", strings.Join(missing, ", ")),
+				})
+				continue
+			}
+			input := createInputFromProto(req)
+			input.AllowPastScheduling = chunk.AllowPastScheduling
+			inputs = append(inputs, input)
+			rows = append(rows, received)
+		}
+
+		// Called even when every row was rejected, so the file size limit still applies
+		result, err := h.useCase.ImportDeliveries(stream.Context(), inputs, int(received))
+		if err != nil {
+			return handleError(err)
+		}
+		response.Succeeded += int32(result.Succeeded)
+		for _, created := range result.Created {
+			response.Created = append(response.Created, &pb.ImportedDelivery{
+				Row:        rows[created.Row],
+				Id:         created.ID.String(),
+				PickupCode: created.PickupCode,
+			})
+		}
+		for _, rowErr := range result.Errors {
+			response.Errors = append(response.Errors, &pb.ImportRowError{
+				Row:   rows[rowErr.Row],
+				Error: rowErr.Err.Error(),
+			})
+		}
+	}
+}
+
+// WatchDriverDeliveries streams the status changes of a driver's deliveries until the client goes
+// away. A client that falls behind is disconnected and should reload the driver's deliveries.
+func (h *Handler) WatchDriverDeliveries(req *pb.WatchDriverDeliveriesRequest, stream pb.DeliveryService_WatchDriverDeliveriesServer) error {
+	ctx := stream.Context()
+	changes, err := h.useCase.WatchDriverDeliveries(ctx, req.DriverId)
+	if err != nil {
+		return handleError(err)
+	}
+
+	for change := range changes {
+		if err := stream.Send(statusChangeToProto(change)); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.ResourceExhausted, "watcher fell behind; reconnect and reload the driver's deliveries")
+}
+
+// GetDriverLocation retrieves a driver's most recent location
+func (h *Handler) GetDriverLocation(ctx context.Context, req *pb.GetDriverLocationRequest) (*pb.DriverLocation, error) {
+	location, err := h.useCase.GetDriverLocation(ctx, req.DriverId)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return driverLocationToProto(location), nil
+}
+
+// GetServerInfo reports the build and uptime of the running instance
+func (h *Handler) GetServerInfo(_ context.Context, _ *pb.GetServerInfoRequest) (*pb.ServerInfo, error) {
+	return serverInfoToProto(h.buildInfo, h.clock.Now()), nil
+}
@@ -0,0 +1,369 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+func TestGetServerInfo(t *testing.T) {
+	startedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	h := NewHandlerWithBuildInfo(nil, zap.NewNop(), BuildInfo{
+		Version:   "v1.2.3",
+		BuildDate: "2024-01-15T09:00:00Z",
+		GitCommit: "abc1234",
+		StartedAt: startedAt,
+	})
+	h.clock = fixedClock(startedAt.Add(90 * time.Minute))
+
+	info, err := h.GetServerInfo(context.Background(), &pb.GetServerInfoRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, "2024-01-15T09:00:00Z", info.BuildDate)
+	assert.Equal(t, "abc1234", info.GitCommit)
+	assert.Equal(t, startedAt, info.StartedAt.AsTime())
+	assert.Equal(t, 90*time.Minute, info.Uptime.AsDuration())
+}
+
+func TestParseID_RejectedInHandlers(t *testing.T) {
+	// The use case is never reached for an invalid ID
+	h := NewHandler(nil, zap.NewNop())
+
+	tests := []struct {
+		name        string
+		id          string
+		wantMessage string
+	}{
+		{
+			name:        "malformed",
+			id:          "not-a-uuid",
+			wantMessage: `invalid id: "not-a-uuid" is not a valid UUID`,
+		},
+		{
+			name:        "malformed value is truncated and escaped",
+			id:          strings.Repeat("x", 60) + "\n",
+			wantMessage: `invalid id: "` + strings.Repeat("x", 40) + `"... is not a valid UUID`,
+		},
+		{
+			name:        "nil UUID",
+			id:          "00000000-0000-0000-0000-000000000000",
+			wantMessage: "invalid id: the nil UUID does not identify a delivery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.GetDeliveryAssignment(context.Background(), &pb.GetDeliveryAssignmentRequest{Id: tt.id})
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			assert.Equal(t, tt.wantMessage, st.Message())
+		})
+	}
+}
+
+func TestParseID_BatchNamesField(t *testing.T) {
+	h := NewHandler(nil, zap.NewNop())
+
+	_, err := h.BatchGetDeliveries(context.Background(), &pb.BatchGetDeliveriesRequest{
+		Ids: []string{uuid.NewString(), uuid.Nil.String()},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "invalid ids")
+}
+
+func TestParseID_Valid(t *testing.T) {
+	want := uuid.New()
+
+	got, err := parseID("id", want.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// fakeLocationStream replays pings to StreamDriverLocation and captures the response
+type fakeLocationStream struct {
+	grpc.ServerStream
+	pings    []*pb.DriverLocation
+	response *pb.StreamDriverLocationResponse
+}
+
+func (s *fakeLocationStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeLocationStream) Recv() (*pb.DriverLocation, error) {
+	if len(s.pings) == 0 {
+		return nil, io.EOF
+	}
+	ping := s.pings[0]
+	s.pings = s.pings[1:]
+	return ping, nil
+}
+
+func (s *fakeLocationStream) SendAndClose(response *pb.StreamDriverLocationResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestStreamDriverLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recordedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	mockRepo.EXPECT().RecordDriverLocation(gomock.Any(), "DRIVER-1", 40.7, -74.0, recordedAt).Return(nil)
+	mockRepo.EXPECT().RecordDriverLocation(gomock.Any(), "DRIVER-1", 40.8, -74.1, recordedAt.Add(time.Second)).Return(nil)
+
+	h := NewHandler(service.NewDeliveryUseCase(mockRepo, zap.NewNop()), zap.NewNop())
+	stream := &fakeLocationStream{pings: []*pb.DriverLocation{
+		{DriverId: "DRIVER-1", Latitude: 40.7, Longitude: -74.0, RecordedAt: timestamppb.New(recordedAt)},
+		{DriverId: "DRIVER-1", Latitude: 91, Longitude: -74.0, RecordedAt: timestamppb.New(recordedAt)}, // Out of bounds
+		{DriverId: "DRIVER-1", Latitude: 40.8, Longitude: -74.1, RecordedAt: timestamppb.New(recordedAt.Add(time.Second))},
+	}}
+
+	require.NoError(t, h.StreamDriverLocation(stream))
+	require.NotNil(t, stream.response)
+	assert.Equal(t, int32(2), stream.response.Accepted)
+	assert.Equal(t, int32(1), stream.response.Rejected, "an invalid ping is skipped without ending the stream")
+}
+
+func TestStreamDriverLocation_StorageFailureEndsStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	mockRepo.EXPECT().RecordDriverLocation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("connection refused"))
+
+	h := NewHandler(service.NewDeliveryUseCase(mockRepo, zap.NewNop()), zap.NewNop())
+	stream := &fakeLocationStream{pings: []*pb.DriverLocation{
+		{DriverId: "DRIVER-1", Latitude: 1, Longitude: 1},
+		{DriverId: "DRIVER-1", Latitude: 2, Longitude: 2},
+	}}
+
+	err := h.StreamDriverLocation(stream)
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Nil(t, stream.response)
+}
+
+// fakeImportStream replays chunks to ImportDeliveries and captures the response
+type fakeImportStream struct {
+	grpc.ServerStream
+	chunks   []*pb.ImportDeliveriesRequest
+	response *pb.ImportDeliveriesResponse
+}
+
+func (s *fakeImportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeImportStream) Recv() (*pb.ImportDeliveriesRequest, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *fakeImportStream) SendAndClose(response *pb.ImportDeliveriesResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestImportDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pickup := time.Now().Add(2 * time.Hour)
+	row := func(orderID, timeZone string) *pb.CreateDeliveryAssignmentRequest {
+		return &pb.CreateDeliveryAssignmentRequest{
+			OrderId:               orderID,
+			PickupAddress:         &pb.Address{Street: "1 Pickup St", City: "Springfield"},
+			DeliveryAddress:       &pb.Address{Street: "2 Dropoff Ave", City: "Springfield"},
+			ScheduledPickupTime:   timestamppb.New(pickup),
+			EstimatedDeliveryTime: timestamppb.New(pickup.Add(time.Hour)),
+			TimeZone:              timeZone,
+		}
+	}
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(2) // One per chunk
+	// ORDER-B is rejected by the database, failing its batch; the batch is then retried row by row
+	var created []string
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, a *domain.DeliveryAssignment) error {
+			if a.OrderID == "ORDER-B" {
+				return domain.ErrAlreadyExists
+			}
+			created = append(created, a.OrderID)
+			return nil
+		}).Times(4)
+
+	h := NewHandler(service.NewDeliveryUseCase(mockRepo, zap.NewNop()), zap.NewNop())
+	stream := &fakeImportStream{chunks: []*pb.ImportDeliveriesRequest{
+		{Rows: []*pb.CreateDeliveryAssignmentRequest{row("ORDER-A", ""), row("", "")}},
+		{Rows: []*pb.CreateDeliveryAssignmentRequest{row("ORDER-B", ""), row("ORDER-C", "Mars/Olympus_Mons"), row("ORDER-D", "")}},
+	}}
+
+	require.NoError(t, h.ImportDeliveries(stream))
+	require.NotNil(t, stream.response)
+	assert.Equal(t, int32(2), stream.response.Succeeded)
+	assert.Equal(t, int32(3), stream.response.Failed)
+	require.Len(t, stream.response.Errors, 3)
+	assert.Equal(t, int32(2), stream.response.Errors[0].Row)
+	assert.Contains(t, stream.response.Errors[0].Error, "order_id is required")
+	assert.Equal(t, int32(3), stream.response.Errors[1].Row)
+	assert.Contains(t, stream.response.Errors[1].Error, "already exists")
+	assert.Equal(t, int32(4), stream.response.Errors[2].Row)
+	assert.Contains(t, stream.response.Errors[2].Error, "time_zone")
+	assert.Equal(t, []string{"ORDER-A", "ORDER-D"}, created, "a rejected row does not fail the rest of its batch")
+
+	// Each imported row comes back with its pickup code
+	require.Len(t, stream.response.Created, 2)
+	for i, row := range []int32{1, 5} {
+		imported := stream.response.Created[i]
+		assert.Equal(t, row, imported.Row)
+		assert.NotEmpty(t, imported.Id)
+		assert.Len(t, imported.PickupCode, constants.PickupCodeLength)
+	}
+}
+
+func TestImportDeliveries_FileTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pickup := time.Now().Add(2 * time.Hour)
+	row := &pb.CreateDeliveryAssignmentRequest{
+		OrderId:               "ORDER-A",
+		PickupAddress:         &pb.Address{Street: "1 Pickup St", City: "Springfield"},
+		DeliveryAddress:       &pb.Address{Street: "2 Dropoff Ave", City: "Springfield"},
+		ScheduledPickupTime:   timestamppb.New(pickup),
+		EstimatedDeliveryTime: timestamppb.New(pickup.Add(time.Hour)),
+	}
+
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(service.DeliveryRepository) error) error {
+			return fn(mockRepo)
+		}).Times(1) // Only the chunk within the limit
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	cfg := service.DefaultConfig()
+	cfg.Limits.ImportRows = 3
+	h := NewHandler(service.NewDeliveryUseCaseWithConfig(mockRepo, zap.NewNop(), cfg), zap.NewNop())
+	stream := &fakeImportStream{chunks: []*pb.ImportDeliveriesRequest{
+		{Rows: []*pb.CreateDeliveryAssignmentRequest{row, row}},
+		{Rows: []*pb.CreateDeliveryAssignmentRequest{row, row}},
+	}}
+
+	err := h.ImportDeliveries(stream)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "batch limit of 3")
+	assert.Nil(t, stream.response)
+}
+
+// fakeWatchStream captures the events sent by WatchDriverDeliveries
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*pb.DeliveryStatusEvent
+}
+
+func (s *fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchStream) Send(event *pb.DeliveryStatusEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestWatchDriverDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	driverID := "DRIVER-1"
+	deliveryID := uuid.New()
+	changedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// The use case closes the channel when the watcher falls behind
+	changes := make(chan service.StatusChange, 1)
+	changes <- service.StatusChange{
+		DeliveryID: deliveryID,
+		OrderID:    "ORDER-1",
+		DriverID:   &driverID,
+		Status:     domain.DeliveryStatusPickedUp,
+		ChangedAt:  changedAt,
+	}
+	close(changes)
+	useCase := mocks.NewMockDeliveryUseCase(ctrl)
+	useCase.EXPECT().WatchDriverDeliveries(ctx, "DRIVER-1").Return((<-chan service.StatusChange)(changes), nil)
+
+	h := NewHandler(useCase, zap.NewNop())
+	stream := &fakeWatchStream{ctx: ctx}
+
+	err := h.WatchDriverDeliveries(&pb.WatchDriverDeliveriesRequest{DriverId: "DRIVER-1"}, stream)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Len(t, stream.events, 1)
+	assert.Equal(t, deliveryID.String(), stream.events[0].DeliveryId)
+	assert.Equal(t, "DRIVER-1", stream.events[0].DriverId)
+	assert.Equal(t, pb.DeliveryStatus_PICKED_UP, stream.events[0].Status)
+	assert.Equal(t, changedAt, stream.events[0].ChangedAt.AsTime())
+}
+
+func TestBulkDeleteDeliveries_EmptyFiltersRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No repository call is expected: neither a preview nor a delete may run
+	mockRepo := mocks.NewMockDeliveryRepository(ctrl)
+	h := NewHandler(service.NewDeliveryUseCase(mockRepo, zap.NewNop()), zap.NewNop())
+
+	for _, req := range []*pb.BulkDeleteDeliveriesRequest{
+		{},
+		{Activity: pb.ActivityFilter_ACTIVITY_ANY, ConfirmToken: "echoed-token"},
+	} {
+		_, err := h.BulkDeleteDeliveries(context.Background(), req)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "request %v", req)
+		assert.ErrorContains(t, err, "at least one filter is required")
+	}
+}
@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+// RequiredFields lists the request fields checked by middleware.ValidationUnaryInterceptor
+// before dispatch, so handlers do not repeat presence checks
+func RequiredFields() middleware.RequiredFields {
+	return middleware.RequiredFields{
+		pb.DeliveryService_CreateDeliveryAssignment_FullMethodName: {
+			"order_id",
+			"pickup_address",
+			"delivery_address",
+			"scheduled_pickup_time",
+			"estimated_delivery_time",
+		},
+		pb.DeliveryService_CheckDeliveryFeasibility_FullMethodName: {
+			"pickup_address",
+			"delivery_address",
+			"scheduled_pickup_time",
+			"estimated_delivery_time",
+		},
+		pb.DeliveryService_UpdateDeliveryStatus_FullMethodName: {"id", "status"},
+		pb.DeliveryService_AssignDriver_FullMethodName:         {"id", "driver_id"},
+		pb.DeliveryService_AssignBackupDriver_FullMethodName:   {"id", "driver_id"},
+		pb.DeliveryService_GetDriverLocation_FullMethodName:    {"driver_id"},
+	}
+}
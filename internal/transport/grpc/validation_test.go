@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mohamadchoker/order-delivery-service/pkg/middleware"
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+func TestRequiredFields_ExistOnRequests(t *testing.T) {
+	methods := pb.File_proto_delivery_proto.Services().ByName("DeliveryService").Methods()
+
+	for fullMethod, fields := range RequiredFields() {
+		name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+		method := methods.ByName(protoreflect.Name(name))
+		require.NotNil(t, method, "unknown method %s", fullMethod)
+
+		for _, field := range fields {
+			assert.NotNil(t, method.Input().Fields().ByName(protoreflect.Name(field)),
+				"%s has no field %q", method.Input().FullName(), field)
+		}
+	}
+}
+
+func TestValidationInterceptor_MultipleMissingFields(t *testing.T) {
+	interceptor := middleware.ValidationUnaryInterceptor(RequiredFields())
+	info := &grpc.UnaryServerInfo{FullMethod: pb.DeliveryService_CreateDeliveryAssignment_FullMethodName}
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	req := &pb.CreateDeliveryAssignmentRequest{
+		DeliveryAddress:       &pb.Address{City: "Boston"},
+		ScheduledPickupTime:   timestamppb.Now(),
+		EstimatedDeliveryTime: timestamppb.Now(),
+	}
+
+	_, err := interceptor(context.Background(), req, info, handler)
+
+	require.Error(t, err)
+	assert.False(t, called)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "order_id is required; pickup_address is required", st.Message())
+
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	var fields []string
+	for _, v := range badRequest.FieldViolations {
+		fields = append(fields, v.Field)
+	}
+	assert.Equal(t, []string{"order_id", "pickup_address"}, fields)
+}
+
+func TestValidationInterceptor_Passes(t *testing.T) {
+	interceptor := middleware.ValidationUnaryInterceptor(RequiredFields())
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		req    interface{}
+	}{
+		{
+			name:   "assign driver with all fields",
+			method: pb.DeliveryService_AssignDriver_FullMethodName,
+			req:    &pb.AssignDriverRequest{Id: "b5f3c2a1-0000-4000-8000-000000000000", DriverId: "DRIVER-1"},
+		},
+		{
+			name:   "method without rules",
+			method: pb.DeliveryService_ListDeliveryAssignments_FullMethodName,
+			req:    &pb.ListDeliveryAssignmentsRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			resp, err := interceptor(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+			assert.True(t, called)
+		})
+	}
+}
+
+func TestValidationInterceptor_UnspecifiedStatus(t *testing.T) {
+	interceptor := middleware.ValidationUnaryInterceptor(RequiredFields())
+	info := &grpc.UnaryServerInfo{FullMethod: pb.DeliveryService_UpdateDeliveryStatus_FullMethodName}
+
+	_, err := interceptor(context.Background(), &pb.UpdateDeliveryStatusRequest{Id: "b5f3c2a1-0000-4000-8000-000000000000"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "status is required", status.Convert(err).Message())
+}
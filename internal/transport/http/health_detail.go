@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// HealthDetailPath is where the dependency details are served
+const HealthDetailPath = "/health/detail"
+
+// DatabaseHealth reports the database details shown by the health detail endpoint
+type DatabaseHealth interface {
+	ServerVersion(ctx context.Context) (string, error)
+	ORMVersion() string
+	Stats() sql.DBStats
+}
+
+// healthDetail is the JSON body of the health detail endpoint
+type healthDetail struct {
+	Postgres    postgresDetail `json:"postgres"`
+	GORMVersion string         `json:"gorm_version"`
+	Pool        poolDetail     `json:"pool"`
+}
+
+type postgresDetail struct {
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type poolDetail struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// HealthDetailHandler serves the PostgreSQL server version, GORM version and connection pool
+// statistics as JSON, to confirm database compatibility during upgrades
+type HealthDetailHandler struct {
+	db     DatabaseHealth
+	logger *zap.Logger
+}
+
+// NewHealthDetailHandler creates the health detail handler
+func NewHealthDetailHandler(db DatabaseHealth, logger *zap.Logger) *HealthDetailHandler {
+	return &HealthDetailHandler{db: db, logger: logger}
+}
+
+// ServeHTTP handles GET /health/detail. The response is 503 with the error in postgres.error
+// when the server version cannot be read; the remaining fields are still filled in.
+func (h *HealthDetailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats := h.db.Stats()
+	detail := healthDetail{
+		GORMVersion: h.db.ORMVersion(),
+		Pool: poolDetail{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		},
+	}
+
+	status := http.StatusOK
+	version, err := h.db.ServerVersion(r.Context())
+	if err != nil {
+		h.logger.Warn("Health detail could not read the database version", zap.Error(err))
+		detail.Postgres.Error = err.Error()
+		status = http.StatusServiceUnavailable
+	} else {
+		detail.Postgres.Version = version
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(detail); err != nil {
+		h.logger.Error("Failed to write health detail", zap.Error(err))
+	}
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubDatabaseHealth returns canned database details
+type stubDatabaseHealth struct {
+	version string
+	err     error
+	stats   sql.DBStats
+}
+
+func (s stubDatabaseHealth) ServerVersion(context.Context) (string, error) { return s.version, s.err }
+
+func (s stubDatabaseHealth) ORMVersion() string { return "v1.25.12" }
+
+func (s stubDatabaseHealth) Stats() sql.DBStats { return s.stats }
+
+func TestHealthDetailHandler(t *testing.T) {
+	handler := NewHealthDetailHandler(stubDatabaseHealth{
+		version: "PostgreSQL 16.2 on x86_64-pc-linux-gnu",
+		stats: sql.DBStats{
+			MaxOpenConnections: 25,
+			OpenConnections:    4,
+			InUse:              1,
+			Idle:               3,
+			WaitCount:          2,
+			WaitDuration:       1500 * time.Millisecond,
+		},
+	}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthDetailPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{"version": "PostgreSQL 16.2 on x86_64-pc-linux-gnu"}, body["postgres"])
+	assert.Equal(t, "v1.25.12", body["gorm_version"])
+	pool := body["pool"].(map[string]interface{})
+	assert.Equal(t, float64(25), pool["max_open_connections"])
+	assert.Equal(t, float64(3), pool["idle"])
+	assert.Equal(t, float64(1500), pool["wait_duration_ms"])
+}
+
+func TestHealthDetailHandler_DatabaseDown(t *testing.T) {
+	handler := NewHealthDetailHandler(stubDatabaseHealth{err: errors.New("connection refused")}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthDetailPath, nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body healthDetail
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "connection refused", body.Postgres.Error)
+	assert.Empty(t, body.Postgres.Version)
+	assert.Equal(t, "v1.25.12", body.GORMVersion)
+}
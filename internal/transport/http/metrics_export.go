@@ -0,0 +1,162 @@
+// Package http contains plain HTTP handlers served next to the gRPC gateway or on the metrics
+// server, for responses that do not fit a gRPC message, such as file downloads.
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/service"
+)
+
+// MetricsExportPath is where the metrics CSV download is served
+const MetricsExportPath = "/export/metrics.csv"
+
+// dateLayout is accepted for the from/to parameters in addition to RFC 3339
+const dateLayout = "2006-01-02"
+
+var metricsCSVHeader = []string{
+	"date",
+	"total_deliveries",
+	"completed_deliveries",
+	"failed_deliveries",
+	"cancelled_deliveries",
+	"dead_letter_deliveries",
+	"average_delivery_time_minutes",
+	"on_time_delivery_rate",
+	"average_rating",
+	"errors",
+}
+
+// MetricsExportHandler streams per-day delivery metrics as CSV
+type MetricsExportHandler struct {
+	useCase service.DeliveryUseCase
+	logger  *zap.Logger
+}
+
+// NewMetricsExportHandler creates the metrics CSV export handler
+func NewMetricsExportHandler(useCase service.DeliveryUseCase, logger *zap.Logger) *MetricsExportHandler {
+	return &MetricsExportHandler{useCase: useCase, logger: logger}
+}
+
+// ServeHTTP handles GET /export/metrics.csv?from=&to=[&driver_id=].
+// from is inclusive and to exclusive; both accept RFC 3339 or YYYY-MM-DD (UTC). Every day is
+// computed before anything is written (at most MaxMetricsExportDays rows), so a failure is
+// answered with an error status rather than a truncated file.
+func (h *MetricsExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseExportTime(query.Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseExportTime(query.Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) > constants.MaxMetricsExportDays*24*time.Hour {
+		http.Error(w, fmt.Sprintf("range must not exceed %d days", constants.MaxMetricsExportDays), http.StatusBadRequest)
+		return
+	}
+
+	var driverID *string
+	if id := query.Get("driver_id"); id != "" {
+		driverID = &id
+	}
+
+	records := [][]string{metricsCSVHeader}
+	for dayStart := from; dayStart.Before(to); {
+		dayEnd := dayStart.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if dayEnd.After(to) {
+			dayEnd = to
+		}
+
+		// The metrics query includes both bounds; stop just short of the next day
+		metrics, err := h.useCase.GetDeliveryMetrics(r.Context(), dayStart, dayEnd.Add(-time.Microsecond), driverID)
+		if err != nil {
+			h.logger.Error("Metrics export failed", zap.Error(err), zap.Time("day", dayStart))
+			writeExportError(w, err)
+			return
+		}
+		records = append(records, metricsRecord(dayStart, metrics))
+
+		dayStart = dayEnd
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="metrics-%s-%s.csv"`,
+		from.Format(dateLayout), to.Format(dateLayout)))
+	if err := csv.NewWriter(w).WriteAll(records); err != nil {
+		h.logger.Error("Failed to write metrics export", zap.Error(err))
+	}
+}
+
+// writeExportError answers a failed export with the status matching err: 429 with Retry-After
+// when metrics queries are being shed, 400 for invalid input and 500 otherwise
+func writeExportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrResourceExhausted):
+		var retryErr *domain.RetryAfterError
+		if errors.As(err, &retryErr) {
+			seconds := int64(math.Ceil(max(retryErr.RetryAfter, constants.MinRetryAfter).Seconds()))
+			w.Header().Set(constants.RetryAfterHeader, strconv.FormatInt(seconds, 10))
+		}
+		http.Error(w, "too many concurrent metrics queries; retry later", http.StatusTooManyRequests)
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "failed to compute metrics", http.StatusInternalServerError)
+	}
+}
+
+// metricsRecord formats one day of metrics as a CSV record matching metricsCSVHeader
+func metricsRecord(day time.Time, m *domain.DeliveryMetrics) []string {
+	return []string{
+		day.Format(dateLayout),
+		strconv.Itoa(int(m.TotalDeliveries)),
+		strconv.Itoa(int(m.CompletedDeliveries)),
+		strconv.Itoa(int(m.FailedDeliveries)),
+		strconv.Itoa(int(m.CancelledDeliveries)),
+		strconv.Itoa(int(m.DeadLetterDeliveries)),
+		strconv.FormatFloat(m.AverageDeliveryTimeMinutes, 'f', 2, 64),
+		strconv.FormatFloat(m.OnTimeDeliveryRate, 'f', 2, 64),
+		strconv.FormatFloat(m.AverageRating, 'f', 2, 64),
+		strings.Join(m.Errors, ";"),
+	}
+}
+
+// parseExportTime parses an RFC 3339 timestamp or a YYYY-MM-DD date in UTC
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("is required")
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("must be RFC 3339 or YYYY-MM-DD")
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/mocks"
+)
+
+func TestMetricsExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mocks.NewMockDeliveryUseCase(ctrl)
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	gomock.InOrder(
+		useCase.EXPECT().
+			GetDeliveryMetrics(gomock.Any(), day1, day2.Add(-time.Microsecond), nil).
+			Return(&domain.DeliveryMetrics{TotalDeliveries: 10, CompletedDeliveries: 8, OnTimeDeliveryRate: 87.5}, nil),
+		useCase.EXPECT().
+			GetDeliveryMetrics(gomock.Any(), day2, day2.Add(24*time.Hour-time.Microsecond), nil).
+			Return(&domain.DeliveryMetrics{TotalDeliveries: 3, FailedDeliveries: 1, Errors: []string{"average_rating"}}, nil),
+	)
+
+	server := httptest.NewServer(NewMetricsExportHandler(useCase, zap.NewNop()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + MetricsExportPath + "?from=2026-03-01&to=2026-03-03")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="metrics-2026-03-01-2026-03-03.csv"`, resp.Header.Get("Content-Disposition"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, metricsCSVHeader, records[0])
+	assert.Equal(t, []string{"2026-03-01", "10", "8", "0", "0", "0", "0.00", "87.50", "0.00", ""}, records[1])
+	assert.Equal(t, []string{"2026-03-02", "3", "0", "1", "0", "0", "0.00", "0.00", "0.00", "average_rating"}, records[2])
+}
+
+func TestMetricsExportHandler_PartialDayAndDriver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mocks.NewMockDeliveryUseCase(ctrl)
+	from := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	midnight := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	driverID := "DRIVER-7"
+
+	gomock.InOrder(
+		useCase.EXPECT().GetDeliveryMetrics(gomock.Any(), from, midnight.Add(-time.Microsecond), &driverID).
+			Return(&domain.DeliveryMetrics{}, nil),
+		useCase.EXPECT().GetDeliveryMetrics(gomock.Any(), midnight, to.Add(-time.Microsecond), &driverID).
+			Return(&domain.DeliveryMetrics{}, nil),
+	)
+
+	req := httptest.NewRequest(http.MethodGet,
+		MetricsExportPath+"?from=2026-03-01T18:00:00Z&to=2026-03-02T06:00:00Z&driver_id=DRIVER-7", nil)
+	rec := httptest.NewRecorder()
+
+	NewMetricsExportHandler(useCase, zap.NewNop()).ServeHTTP(rec, req)
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
+
+func TestMetricsExportHandler_InvalidRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing from", query: "?to=2026-03-02"},
+		{name: "malformed to", query: "?from=2026-03-01&to=tomorrow"},
+		{name: "empty range", query: "?from=2026-03-02&to=2026-03-02"},
+		{name: "too long", query: "?from=2024-01-01&to=2026-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The use case must not be called for an invalid request
+			useCase := mocks.NewMockDeliveryUseCase(ctrl)
+			rec := httptest.NewRecorder()
+
+			NewMetricsExportHandler(useCase, zap.NewNop()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsExportPath+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestMetricsExportHandler_UseCaseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{name: "database failure", err: errors.New("database unavailable"), status: http.StatusInternalServerError},
+		{
+			name: "metrics queries shed",
+			err: &domain.RetryAfterError{
+				RetryAfter: 1500 * time.Millisecond,
+				Err:        fmt.Errorf("%w: too many concurrent metrics queries", domain.ErrResourceExhausted),
+			},
+			status:     http.StatusTooManyRequests,
+			retryAfter: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			useCase := mocks.NewMockDeliveryUseCase(ctrl)
+			gomock.InOrder(
+				useCase.EXPECT().GetDeliveryMetrics(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+					Return(&domain.DeliveryMetrics{TotalDeliveries: 1}, nil),
+				useCase.EXPECT().GetDeliveryMetrics(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+					Return(nil, tt.err),
+			)
+			rec := httptest.NewRecorder()
+
+			NewMetricsExportHandler(useCase, zap.NewNop()).ServeHTTP(rec,
+				httptest.NewRequest(http.MethodGet, MetricsExportPath+"?from=2026-03-01&to=2026-03-04", nil))
+
+			// No partial file is sent as if it were complete
+			assert.Equal(t, tt.status, rec.Code)
+			assert.NotEqual(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+			assert.NotContains(t, rec.Body.String(), "2026-03-01")
+			assert.Equal(t, tt.retryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+-- Drop rating and feedback columns
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS feedback,
+    DROP COLUMN IF EXISTS rating;
@@ -0,0 +1,7 @@
+-- Add recipient rating and feedback captured after delivery
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    ADD COLUMN IF NOT EXISTS feedback TEXT;
+
+COMMENT ON COLUMN delivery_assignments.rating IS 'Recipient rating (1-5) submitted after delivery';
+COMMENT ON COLUMN delivery_assignments.feedback IS 'Optional free-text recipient feedback';
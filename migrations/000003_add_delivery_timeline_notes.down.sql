@@ -0,0 +1,3 @@
+-- Drop timeline notes column
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS timeline_notes;
@@ -0,0 +1,5 @@
+-- Add append-only timeline notes left during a delivery
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS timeline_notes JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN delivery_assignments.timeline_notes IS 'Append-only, timestamped notes in the order they were added';
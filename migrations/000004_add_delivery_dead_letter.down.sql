@@ -0,0 +1,3 @@
+-- Drop dead letter reason column
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS dead_letter_reason;
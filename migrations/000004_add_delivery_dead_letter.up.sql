@@ -0,0 +1,5 @@
+-- Add the reason a failed delivery was moved to DEAD_LETTER
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS dead_letter_reason TEXT;
+
+COMMENT ON COLUMN delivery_assignments.dead_letter_reason IS 'Why a FAILED delivery was permanently dead-lettered';
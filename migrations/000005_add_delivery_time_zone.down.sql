@@ -0,0 +1,3 @@
+-- Drop time zone column
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,5 @@
+-- Add the merchant's time zone used to display scheduled times
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN delivery_assignments.time_zone IS 'IANA time zone for display only; times are stored in UTC (empty = UTC)';
@@ -0,0 +1,7 @@
+-- Drop estimated delivery window columns
+ALTER TABLE delivery_assignments
+    DROP CONSTRAINT IF EXISTS chk_delivery_window_order;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS estimated_delivery_window_end,
+    DROP COLUMN IF EXISTS estimated_delivery_window_start;
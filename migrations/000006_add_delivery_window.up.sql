@@ -0,0 +1,12 @@
+-- Add the customer-facing estimated delivery window
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS estimated_delivery_window_start TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS estimated_delivery_window_end TIMESTAMP;
+
+ALTER TABLE delivery_assignments
+    ADD CONSTRAINT chk_delivery_window_order
+    CHECK (estimated_delivery_window_start IS NULL
+        OR estimated_delivery_window_end IS NULL
+        OR estimated_delivery_window_start < estimated_delivery_window_end);
+
+COMMENT ON COLUMN delivery_assignments.estimated_delivery_window_start IS 'Start of the window shown to customers (NULL = no window)';
@@ -0,0 +1,5 @@
+-- Drop the active-delivery-per-order constraint
+DROP INDEX IF EXISTS uq_delivery_assignments_active_order;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS allow_concurrent;
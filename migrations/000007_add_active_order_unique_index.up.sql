@@ -0,0 +1,36 @@
+-- Allow a delivery to opt out of the one-active-delivery-per-order rule (e.g. split shipments)
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS allow_concurrent BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Orders that already have several live, non-terminal deliveries would make the index below
+-- fail to build. They can be listed beforehand with:
+--
+--   SELECT order_id, COUNT(*) FROM delivery_assignments
+--   WHERE deleted_at IS NULL AND status NOT IN ('DELIVERED', 'FAILED', 'CANCELLED', 'DEAD_LETTER')
+--   GROUP BY order_id HAVING COUNT(*) > 1;
+--
+-- Rather than cancel or delete any of them, the newest delivery of each such order stays under
+-- the rule and the older ones are grandfathered in as concurrent deliveries.
+UPDATE delivery_assignments
+SET allow_concurrent = TRUE
+WHERE id IN (
+    SELECT id
+    FROM (
+        SELECT id,
+               ROW_NUMBER() OVER (PARTITION BY order_id ORDER BY created_at DESC, id DESC) AS newest
+        FROM delivery_assignments
+        WHERE deleted_at IS NULL
+          AND NOT allow_concurrent
+          AND status NOT IN ('DELIVERED', 'FAILED', 'CANCELLED', 'DEAD_LETTER')
+    ) active
+    WHERE newest > 1
+);
+
+-- At most one live, non-terminal delivery per order unless it explicitly allows concurrency
+CREATE UNIQUE INDEX IF NOT EXISTS uq_delivery_assignments_active_order
+    ON delivery_assignments(order_id)
+    WHERE deleted_at IS NULL
+      AND NOT allow_concurrent
+      AND status NOT IN ('DELIVERED', 'FAILED', 'CANCELLED', 'DEAD_LETTER');
+
+COMMENT ON COLUMN delivery_assignments.allow_concurrent IS 'Exempts the delivery from uq_delivery_assignments_active_order';
@@ -0,0 +1,3 @@
+-- Drop address GIN indexes
+DROP INDEX IF EXISTS idx_delivery_assignments_delivery_address_gin;
+DROP INDEX IF EXISTS idx_delivery_assignments_pickup_address_gin;
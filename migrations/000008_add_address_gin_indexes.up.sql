@@ -0,0 +1,5 @@
+-- Support JSONB containment lookups on addresses
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_pickup_address_gin
+    ON delivery_assignments USING GIN (pickup_address);
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_delivery_address_gin
+    ON delivery_assignments USING GIN (delivery_address);
@@ -0,0 +1,3 @@
+-- Drop pickup confirmation code
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS pickup_code;
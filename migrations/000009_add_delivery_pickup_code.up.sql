@@ -0,0 +1,5 @@
+-- Add the code the driver must present to confirm pickup
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS pickup_code VARCHAR(16) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN delivery_assignments.pickup_code IS 'Pickup confirmation code shared with the sender (empty = not required)';
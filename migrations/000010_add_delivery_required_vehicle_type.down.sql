@@ -0,0 +1,5 @@
+-- Drop required vehicle type
+DROP INDEX IF EXISTS idx_delivery_assignments_required_vehicle_type;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS required_vehicle_type;
@@ -0,0 +1,9 @@
+-- Add the vehicle type a delivery requires (e.g. REFRIGERATED)
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS required_vehicle_type VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_required_vehicle_type
+    ON delivery_assignments(required_vehicle_type)
+    WHERE required_vehicle_type IS NOT NULL;
+
+COMMENT ON COLUMN delivery_assignments.required_vehicle_type IS 'Vehicle type the driver must have (NULL = any)';
@@ -0,0 +1,5 @@
+-- Drop delivery tags
+DROP INDEX IF EXISTS idx_delivery_assignments_tags;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,9 @@
+-- Add ops labels (e.g. "vip", "reattempt") to deliveries
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+
+-- Serves the tags @> '["tag"]' containment filter in List
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_tags
+    ON delivery_assignments USING GIN (tags);
+
+COMMENT ON COLUMN delivery_assignments.tags IS 'Lower-case ops labels as a JSON array of strings';
@@ -0,0 +1,6 @@
+-- Drop partial delivery support
+DROP INDEX IF EXISTS idx_delivery_assignments_parent_delivery_id;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS parent_delivery_id,
+    DROP COLUMN IF EXISTS packages;
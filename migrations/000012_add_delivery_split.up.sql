@@ -0,0 +1,11 @@
+-- Support partial deliveries: the rest of an order is carried by a child delivery
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS packages JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS parent_delivery_id UUID REFERENCES delivery_assignments(id);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_parent_delivery_id
+    ON delivery_assignments(parent_delivery_id)
+    WHERE parent_delivery_id IS NOT NULL;
+
+COMMENT ON COLUMN delivery_assignments.packages IS 'Packages carried as a JSON array of {description, quantity}';
+COMMENT ON COLUMN delivery_assignments.parent_delivery_id IS 'Delivery this one was split from (NULL = not a split)';
@@ -0,0 +1,2 @@
+-- Drop the daily metrics summary
+DROP TABLE IF EXISTS delivery_metrics_daily;
@@ -0,0 +1,21 @@
+-- Precomputed metrics per UTC day so historical metric ranges do not scan delivery_assignments
+CREATE TABLE IF NOT EXISTS delivery_metrics_daily (
+    day DATE PRIMARY KEY,
+    on_time_grace_seconds DOUBLE PRECISION NOT NULL,
+    total_deliveries BIGINT NOT NULL DEFAULT 0,
+    completed_deliveries BIGINT NOT NULL DEFAULT 0,
+    failed_deliveries BIGINT NOT NULL DEFAULT 0,
+    cancelled_deliveries BIGINT NOT NULL DEFAULT 0,
+    dead_letter_deliveries BIGINT NOT NULL DEFAULT 0,
+    delivery_time_sum_minutes DOUBLE PRECISION NOT NULL DEFAULT 0,
+    delivery_time_count BIGINT NOT NULL DEFAULT 0,
+    lifecycle_sum_minutes DOUBLE PRECISION NOT NULL DEFAULT 0,
+    delivered_count BIGINT NOT NULL DEFAULT 0,
+    on_time_count BIGINT NOT NULL DEFAULT 0,
+    rating_sum BIGINT NOT NULL DEFAULT 0,
+    rating_count BIGINT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE delivery_metrics_daily IS 'Delivery metrics of the deliveries created on each UTC day, kept as sums and counts';
+COMMENT ON COLUMN delivery_metrics_daily.on_time_grace_seconds IS 'Grace period on_time_count was computed with; rows with another grace are not used';
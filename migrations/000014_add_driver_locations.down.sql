@@ -0,0 +1,2 @@
+-- Drop driver location pings
+DROP TABLE IF EXISTS driver_locations;
@@ -0,0 +1,17 @@
+-- Location pings reported by drivers' devices for live tracking
+CREATE TABLE IF NOT EXISTS driver_locations (
+    id BIGSERIAL PRIMARY KEY,
+    driver_id VARCHAR(100) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_driver_locations_latitude CHECK (latitude BETWEEN -90 AND 90),
+    CONSTRAINT chk_driver_locations_longitude CHECK (longitude BETWEEN -180 AND 180)
+);
+
+-- Latest ping per driver
+CREATE INDEX IF NOT EXISTS idx_driver_locations_driver_recorded
+    ON driver_locations(driver_id, recorded_at DESC);
+
+COMMENT ON COLUMN driver_locations.recorded_at IS 'When the device took the fix (may be earlier than created_at for buffered pings)';
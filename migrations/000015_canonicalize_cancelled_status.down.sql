@@ -0,0 +1,9 @@
+-- Restore the previous active-order index. Rewritten statuses stay CANCELED, which every
+-- version of the service reads.
+DROP INDEX IF EXISTS uq_delivery_assignments_active_order;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_delivery_assignments_active_order
+    ON delivery_assignments(order_id)
+    WHERE deleted_at IS NULL
+      AND NOT allow_concurrent
+      AND status NOT IN ('DELIVERED', 'FAILED', 'CANCELLED', 'DEAD_LETTER');
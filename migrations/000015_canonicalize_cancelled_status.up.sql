@@ -0,0 +1,12 @@
+-- Store the cancelled status under the domain spelling CANCELED only. Rows written with the
+-- proto enum spelling CANCELLED are rewritten, and the active-order index, which listed the
+-- wrong spelling and so kept cancelled deliveries "active", is rebuilt.
+UPDATE delivery_assignments SET status = 'CANCELED' WHERE status = 'CANCELLED';
+
+DROP INDEX IF EXISTS uq_delivery_assignments_active_order;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_delivery_assignments_active_order
+    ON delivery_assignments(order_id)
+    WHERE deleted_at IS NULL
+      AND NOT allow_concurrent
+      AND status NOT IN ('DELIVERED', 'FAILED', 'CANCELED', 'DEAD_LETTER');
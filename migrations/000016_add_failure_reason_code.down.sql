@@ -0,0 +1,6 @@
+-- Drop failure reason codes
+ALTER TABLE delivery_metrics_daily
+    DROP COLUMN IF EXISTS failures_by_reason;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS failure_reason_code;
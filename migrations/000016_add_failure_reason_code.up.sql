@@ -0,0 +1,11 @@
+-- Record a structured reason when a delivery fails so failures can be aggregated
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS failure_reason_code VARCHAR(64);
+
+COMMENT ON COLUMN delivery_assignments.failure_reason_code IS 'Why the delivery failed, one of DELIVERY_FAILURE_REASON_CODES (NULL = not failed or failed before codes existed)';
+
+-- Days summarized before this migration report no failure reasons until they are refreshed
+ALTER TABLE delivery_metrics_daily
+    ADD COLUMN IF NOT EXISTS failures_by_reason JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN delivery_metrics_daily.failures_by_reason IS 'FAILED and DEAD_LETTER deliveries per failure reason code';
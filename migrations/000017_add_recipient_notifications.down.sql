@@ -0,0 +1,4 @@
+-- Drop recipient notification settings
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS notify_prefs,
+    DROP COLUMN IF EXISTS recipient_email;
@@ -0,0 +1,7 @@
+-- Let recipients be emailed on status changes, optionally only for some statuses
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS recipient_email VARCHAR(254),
+    ADD COLUMN IF NOT EXISTS notify_prefs JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN delivery_assignments.recipient_email IS 'Where the recipient is emailed on status changes (NULL = not emailed)';
+COMMENT ON COLUMN delivery_assignments.notify_prefs IS 'Statuses the recipient is notified of, as a JSON array of status names (empty = all)';
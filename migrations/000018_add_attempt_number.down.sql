@@ -0,0 +1,3 @@
+-- Drop the delivery attempt counter
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS attempt_number;
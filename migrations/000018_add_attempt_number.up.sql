@@ -0,0 +1,5 @@
+-- Count delivery attempts; a reattempt is a new delivery linked through parent_delivery_id
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS attempt_number INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN delivery_assignments.attempt_number IS '1 for the first attempt, incremented on each reattempt of a failed delivery';
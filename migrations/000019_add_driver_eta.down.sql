@@ -0,0 +1,3 @@
+-- Drop the driver's arrival estimate
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS driver_eta;
@@ -0,0 +1,5 @@
+-- Store the driver's live arrival estimate separately from the committed estimated_delivery_time
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS driver_eta TIMESTAMP;
+
+COMMENT ON COLUMN delivery_assignments.driver_eta IS 'Latest arrival estimate pushed by the driver while en route (NULL = none sent)';
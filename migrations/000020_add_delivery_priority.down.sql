@@ -0,0 +1,5 @@
+-- Drop the dispatch priority
+DROP INDEX IF EXISTS idx_delivery_assignments_pending_priority;
+
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,10 @@
+-- Add the dispatch priority, raised automatically while a delivery waits in PENDING
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(16) NOT NULL DEFAULT 'NORMAL';
+
+-- Serves the escalation worker's scan for long-waiting PENDING deliveries per priority
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_pending_priority
+    ON delivery_assignments(priority, created_at)
+    WHERE status = 'PENDING';
+
+COMMENT ON COLUMN delivery_assignments.priority IS 'Dispatch priority: LOW, NORMAL or HIGH';
@@ -0,0 +1,3 @@
+-- Drop the backup driver
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS backup_driver_id;
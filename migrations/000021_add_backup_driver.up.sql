@@ -0,0 +1,5 @@
+-- Add the backup driver, promoted to primary when the primary driver is unassigned
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS backup_driver_id VARCHAR(100);
+
+COMMENT ON COLUMN delivery_assignments.backup_driver_id IS 'Driver who takes over if the primary driver is unassigned (NULL = none)';
@@ -0,0 +1,2 @@
+-- Drop the delivery audit log
+DROP TABLE IF EXISTS delivery_audit_log;
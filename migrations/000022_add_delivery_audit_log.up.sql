@@ -0,0 +1,25 @@
+-- Audit entries for delivery mutations (service.AuditEntry), when the audit log is kept in the
+-- database. Rows are only ever appended; deleted_at lets the repository's delete policy for related
+-- data hide them along with their delivery.
+CREATE TABLE IF NOT EXISTS delivery_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id UUID,
+    order_id VARCHAR(100),
+    action VARCHAR(50) NOT NULL,
+    user_id VARCHAR(255),
+    tenant_id VARCHAR(255),
+    trace_id VARCHAR(255),
+    changes JSONB NOT NULL DEFAULT '[]',
+    affected BIGINT NOT NULL DEFAULT 0,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A delivery's history, oldest first
+CREATE INDEX IF NOT EXISTS idx_delivery_audit_log_delivery
+    ON delivery_audit_log(delivery_id, recorded_at);
+CREATE INDEX IF NOT EXISTS idx_delivery_audit_log_deleted_at
+    ON delivery_audit_log(deleted_at);
+
+COMMENT ON COLUMN delivery_audit_log.delivery_id IS 'NULL for deletes by order and bulk deletes, which name no single delivery';
+COMMENT ON COLUMN delivery_audit_log.affected IS 'Deliveries deleted by a delete by order or bulk delete';
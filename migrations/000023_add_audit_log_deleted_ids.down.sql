@@ -0,0 +1,3 @@
+-- Drop the deleted delivery IDs and bulk delete filters from the audit log
+ALTER TABLE delivery_audit_log DROP COLUMN IF EXISTS filters;
+ALTER TABLE delivery_audit_log DROP COLUMN IF EXISTS delivery_ids;
@@ -0,0 +1,7 @@
+-- Record which deliveries a delete by order or bulk delete removed, and the filters a bulk delete
+-- matched them by, so those entries can be traced back to the rows they hid
+ALTER TABLE delivery_audit_log ADD COLUMN IF NOT EXISTS delivery_ids JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE delivery_audit_log ADD COLUMN IF NOT EXISTS filters JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN delivery_audit_log.delivery_ids IS 'Deliveries deleted by a delete by order or bulk delete';
+COMMENT ON COLUMN delivery_audit_log.filters IS 'Filters a bulk delete matched its deliveries by, by name';
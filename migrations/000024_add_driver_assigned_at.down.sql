@@ -0,0 +1,2 @@
+-- Drop the driver assignment time
+ALTER TABLE delivery_assignments DROP COLUMN IF EXISTS driver_assigned_at;
@@ -0,0 +1,6 @@
+-- Record when the current driver was assigned, so a driver assigned after the scheduled pickup,
+-- or a backup promoted to primary, is given the reassign grace period from then
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS driver_assigned_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN delivery_assignments.driver_assigned_at IS 'When driver_id was assigned or promoted from backup (NULL = no driver, or assigned before this column existed)';
@@ -0,0 +1,4 @@
+-- Drop the pickup code lockout
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS pickup_locked_until,
+    DROP COLUMN IF EXISTS failed_pickup_attempts;
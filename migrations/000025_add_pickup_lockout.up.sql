@@ -0,0 +1,8 @@
+-- Count wrong pickup codes so that a delivery's code cannot be guessed: after too many in a row,
+-- pickup is refused until pickup_locked_until
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS failed_pickup_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pickup_locked_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN delivery_assignments.failed_pickup_attempts IS 'Wrong pickup codes since the last pickup or lockout';
+COMMENT ON COLUMN delivery_assignments.pickup_locked_until IS 'Pickup is refused until then after too many wrong codes (NULL = not locked)';
@@ -0,0 +1,3 @@
+-- Drop delivery metadata
+ALTER TABLE delivery_assignments
+    DROP COLUMN IF EXISTS metadata;
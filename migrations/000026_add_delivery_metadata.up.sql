@@ -0,0 +1,5 @@
+-- Let tenants attach their own details to a delivery, such as a gate code
+ALTER TABLE delivery_assignments
+    ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN delivery_assignments.metadata IS 'Tenant-defined details as a JSON object, checked against the tenant''s metadata schema when set';
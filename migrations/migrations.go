@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so the server binary can apply them
+// without the migration files on disk.
+package migrations
+
+import "embed"
+
+// FS holds the versioned migration files ({version}_{title}.up.sql / .down.sql)
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,193 @@
+// Package circuitbreaker stops calling a failing dependency for a while so callers fail fast
+// instead of each waiting for it to time out.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Execute while the breaker is rejecting calls
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the breaker state
+type State int
+
+const (
+	// StateClosed lets every call through and counts consecutive failures
+	StateClosed State = iota
+	// StateOpen rejects every call until the cooldown has passed
+	StateOpen
+	// StateHalfOpen lets a single probe call through to test whether the dependency recovered
+	StateHalfOpen
+)
+
+// String returns the state name for logs
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings configures a Breaker
+type Settings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before probing
+	Cooldown time.Duration
+
+	// IsFailure decides which errors count against the dependency (nil counts every error).
+	// Errors it rejects are returned unchanged and count as successes.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called after every state change, outside the breaker's lock (optional)
+	OnStateChange func(from, to State)
+
+	// Now supplies the current time (nil uses time.Now)
+	Now func() time.Time
+}
+
+// Breaker is a consecutive-failure circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	settings Settings
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // A half-open probe is in flight
+}
+
+// New creates a closed breaker
+func New(settings Settings) *Breaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return err != nil }
+	}
+	if settings.Now == nil {
+		settings.Now = time.Now
+	}
+	return &Breaker{settings: settings}
+}
+
+// State returns the current state, moving an open breaker whose cooldown passed to half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	from := b.state
+	to := b.currentState()
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return to
+}
+
+// RetryAfter returns how long until an open breaker lets a probe through, or 0 when it is not open
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != StateOpen {
+		return 0
+	}
+	return max(b.settings.Cooldown-b.settings.Now().Sub(b.openedAt), 0)
+}
+
+// Execute runs fn unless the breaker is open, in which case it returns ErrOpen without calling fn
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.before(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.after(err != nil && b.settings.IsFailure(err))
+	return err
+}
+
+// before admits or rejects a call
+func (b *Breaker) before() error {
+	b.mu.Lock()
+	from := b.state
+	state := b.currentState()
+
+	admitted := true
+	switch state {
+	case StateOpen:
+		admitted = false
+	case StateHalfOpen:
+		// Only one probe at a time; everything else keeps failing fast
+		admitted = !b.probing
+		b.probing = true
+	}
+	b.mu.Unlock()
+
+	b.notify(from, state)
+	if !admitted {
+		return ErrOpen
+	}
+	return nil
+}
+
+// after records the outcome of an admitted call
+func (b *Breaker) after(failed bool) {
+	b.mu.Lock()
+	from := b.state
+	if b.state == StateOpen {
+		// The call was admitted before another call opened the breaker; only the probe decides now
+		b.mu.Unlock()
+		return
+	}
+	if b.state == StateHalfOpen {
+		b.probing = false
+	}
+
+	switch {
+	case !failed:
+		b.failures = 0
+		b.state = StateClosed
+	case b.state == StateHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.settings.FailureThreshold {
+			b.open()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// currentState moves an open breaker to half-open once the cooldown has passed. Callers hold mu.
+func (b *Breaker) currentState() State {
+	if b.state == StateOpen && b.settings.Now().Sub(b.openedAt) >= b.settings.Cooldown {
+		b.state = StateHalfOpen
+		b.probing = false
+	}
+	return b.state
+}
+
+// open trips the breaker. Callers hold mu.
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.settings.Now()
+	b.failures = 0
+}
+
+// notify reports a state change, if any
+func (b *Breaker) notify(from, to State) {
+	if from != to && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,99 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errBoom = errors.New("boom")
+
+func TestBreaker_OpensAndFailsFast(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var transitions []State
+	b := New(Settings{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+		Now:              func() time.Time { return now },
+		OnStateChange:    func(_, to State) { transitions = append(transitions, to) },
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, b.Execute(func() error { return errBoom }), errBoom)
+	}
+	assert.Equal(t, StateOpen, b.State())
+
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.False(t, called, "an open breaker must not call through")
+
+	// After the cooldown a failed probe reopens the breaker
+	now = now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, b.Execute(func() error { return errBoom }), errBoom)
+	assert.Equal(t, StateOpen, b.State())
+
+	// A successful probe closes it again
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Execute(func() error { return nil }))
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, []State{StateOpen, StateHalfOpen, StateOpen, StateHalfOpen, StateClosed}, transitions)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := New(Settings{FailureThreshold: 2, Cooldown: time.Minute})
+
+	assert.Error(t, b.Execute(func() error { return errBoom }))
+	assert.NoError(t, b.Execute(func() error { return nil }))
+	assert.Error(t, b.Execute(func() error { return errBoom }))
+
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_IgnoredErrorsDoNotCount(t *testing.T) {
+	errIgnored := errors.New("not found")
+	b := New(Settings{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+		IsFailure:        func(err error) bool { return !errors.Is(err, errIgnored) },
+	})
+
+	assert.ErrorIs(t, b.Execute(func() error { return errIgnored }), errIgnored)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_HalfOpenAdmitsSingleProbe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := New(Settings{FailureThreshold: 1, Cooldown: time.Second, Now: func() time.Time { return now }})
+
+	assert.Error(t, b.Execute(func() error { return errBoom }))
+	now = now.Add(time.Second)
+
+	err := b.Execute(func() error {
+		// A concurrent call while the probe is in flight fails fast
+		assert.ErrorIs(t, b.Execute(func() error { return nil }), ErrOpen)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_RetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := New(Settings{FailureThreshold: 1, Cooldown: time.Minute, Now: func() time.Time { return now }})
+	assert.Zero(t, b.RetryAfter())
+
+	_ = b.Execute(func() error { return errBoom })
+	assert.Equal(t, time.Minute, b.RetryAfter())
+
+	now = now.Add(40 * time.Second)
+	assert.Equal(t, 20*time.Second, b.RetryAfter())
+
+	// Once the cooldown has passed the next call probes, so there is nothing to wait for
+	now = now.Add(time.Minute)
+	assert.Zero(t, b.RetryAfter())
+}
@@ -0,0 +1,159 @@
+// Package jsonschema checks decoded JSON values against a small subset of JSON Schema: the
+// type of a value and, for objects, the keys they require and the schemas of their properties.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/mohamadchoker/order-delivery-service/pkg/validator"
+)
+
+// types are the JSON Schema type names a schema may use
+var types = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// Schema is a JSON Schema limited to the type, required and properties keywords. Properties
+// that are not listed may hold any value.
+type Schema struct {
+	Type       string             `json:"type,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+
+	// Annotations are accepted so existing schemas parse, but do not affect validation
+	SchemaURI   string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Parse decodes a schema. Keywords outside the supported subset are rejected rather than
+// ignored, so a schema never looks stricter than it is.
+func Parse(data []byte) (*Schema, error) {
+	var schema Schema
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	if err := schema.check(""); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// check rejects unknown types and required keys on schemas that are not objects
+func (s *Schema) check(path string) error {
+	if s.Type != "" && !slices.Contains(types, s.Type) {
+		return fmt.Errorf("JSON schema%s: unsupported type %q", at(path), s.Type)
+	}
+	if (len(s.Required) > 0 || len(s.Properties) > 0) && s.Type != "" && s.Type != "object" {
+		return fmt.Errorf("JSON schema%s: required and properties need type object, not %q", at(path), s.Type)
+	}
+	for key, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("JSON schema%s: property %q has no schema", at(path), key)
+		}
+		if err := property.check(join(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks value, as decoded from JSON, against the schema and returns one error per
+// violation. field names value in the errors; nested properties are named field.key.
+func (s *Schema) Validate(field string, value any) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+	s.validate(field, value, &errs)
+	return errs
+}
+
+func (s *Schema) validate(field string, value any, errs *validator.ValidationErrors) {
+	if s.Type != "" && !hasType(value, s.Type) {
+		*errs = append(*errs, validator.ValidationError{Field: field, Message: "must be of type " + s.Type})
+		return
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, key := range s.Required {
+		if _, present := object[key]; !present {
+			*errs = append(*errs, validator.ValidationError{Field: join(field, key), Message: "is required"})
+		}
+	}
+
+	// Sorted so the errors come out in the same order every time
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if property, present := object[key]; present {
+			s.Properties[key].validate(join(field, key), property, errs)
+		}
+	}
+}
+
+// hasType reports whether a decoded JSON value is of the named JSON Schema type
+func hasType(value any, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number", "integer":
+		var number float64
+		switch n := value.(type) {
+		case float64:
+			number = n
+		case int:
+			number = float64(n)
+		case int64:
+			number = float64(n)
+		case json.Number:
+			f, err := n.Float64()
+			if err != nil {
+				return false
+			}
+			number = f
+		default:
+			return false
+		}
+		return name == "number" || number == math.Trunc(number)
+	default:
+		return false
+	}
+}
+
+// join names a property of the value named field
+func join(field, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
+// at formats a property path for parse errors
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " at " + path
+}
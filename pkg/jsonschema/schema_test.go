@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mohamadchoker/order-delivery-service/pkg/validator"
+)
+
+func TestParse(t *testing.T) {
+	schema, err := Parse([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["gate_code"],
+		"properties": {"gate_code": {"type": "string", "description": "Code for the building gate"}}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gate_code"}, schema.Required)
+	assert.Equal(t, "string", schema.Properties["gate_code"].Type)
+
+	for _, invalid := range []string{
+		`{"type": "object"`,
+		`{"type": "date"}`,
+		`{"type": "string", "required": ["a"]}`,
+		`{"properties": {"a": {"type": "string", "minLength": 4}}}`,
+		`{"properties": {"a": null}}`,
+	} {
+		_, err := Parse([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Parse([]byte(`{
+		"type": "object",
+		"required": ["gate_code", "floor"],
+		"properties": {
+			"gate_code": {"type": "string"},
+			"floor": {"type": "integer"},
+			"contact": {"type": "object", "required": ["phone"], "properties": {"phone": {"type": "string"}}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		metadata string
+		expected validator.ValidationErrors
+	}{
+		{
+			name:     "valid",
+			metadata: `{"gate_code": "4711", "floor": 3, "contact": {"phone": "+1 555 0100"}, "extra": [1, 2]}`,
+		},
+		{
+			name:     "missing keys",
+			metadata: `{}`,
+			expected: validator.ValidationErrors{
+				{Field: "metadata.gate_code", Message: "is required"},
+				{Field: "metadata.floor", Message: "is required"},
+			},
+		},
+		{
+			name:     "wrong types",
+			metadata: `{"gate_code": 4711, "floor": 2.5}`,
+			expected: validator.ValidationErrors{
+				{Field: "metadata.floor", Message: "must be of type integer"},
+				{Field: "metadata.gate_code", Message: "must be of type string"},
+			},
+		},
+		{
+			name:     "nested object",
+			metadata: `{"gate_code": "4711", "floor": 3, "contact": {"email": "a@example.com"}}`,
+			expected: validator.ValidationErrors{
+				{Field: "metadata.contact.phone", Message: "is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metadata map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.metadata), &metadata))
+
+			assert.Equal(t, tt.expected, schema.Validate("metadata", metadata))
+		})
+	}
+
+	t.Run("not an object", func(t *testing.T) {
+		assert.Equal(t, validator.ValidationErrors{{Field: "metadata", Message: "must be of type object"}},
+			schema.Validate("metadata", "gate 4711"))
+	})
+}
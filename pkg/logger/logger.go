@@ -1,15 +1,24 @@
 package logger
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Supported log encodings
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
 // Config holds logger configuration options
 type Config struct {
 	Level            string
 	Development      bool
 	EnableStacktrace bool
+	Encoding         string // EncodingJSON or EncodingConsole; empty follows Development (console in dev, JSON otherwise)
 }
 
 // New creates a new logger instance
@@ -23,6 +32,27 @@ func New(level string, development bool) (*zap.Logger, error) {
 
 // NewWithConfig creates a new logger instance with explicit configuration
 func NewWithConfig(cfg Config) (*zap.Logger, error) {
+	zapConfig, err := buildZapConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build logger with options
+	var opts []zap.Option
+
+	// Always add caller info (shows file:line)
+	opts = append(opts, zap.AddCaller())
+
+	// Optionally add stack traces
+	if cfg.EnableStacktrace {
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	return zapConfig.Build(opts...)
+}
+
+// buildZapConfig translates cfg into the zap configuration the logger is built from
+func buildZapConfig(cfg Config) (zap.Config, error) {
 	var zapConfig zap.Config
 
 	if cfg.Development {
@@ -35,23 +65,21 @@ func NewWithConfig(cfg Config) (*zap.Logger, error) {
 	zapConfig.DisableStacktrace = true
 	zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 
+	// An explicit encoding overrides the one implied by Development
+	switch cfg.Encoding {
+	case "":
+	case EncodingJSON, EncodingConsole:
+		zapConfig.Encoding = cfg.Encoding
+	default:
+		return zap.Config{}, fmt.Errorf("unsupported log encoding %q (want %q or %q)", cfg.Encoding, EncodingJSON, EncodingConsole)
+	}
+
 	// Set log level
 	zapLevel, err := zap.ParseAtomicLevel(cfg.Level)
 	if err != nil {
-		return nil, err
+		return zap.Config{}, err
 	}
 	zapConfig.Level = zapLevel
 
-	// Build logger with options
-	var opts []zap.Option
-
-	// Always add caller info (shows file:line)
-	opts = append(opts, zap.AddCaller())
-
-	// Optionally add stack traces
-	if cfg.EnableStacktrace {
-		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
-	}
-
-	return zapConfig.Build(opts...)
+	return zapConfig, nil
 }
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildZapConfig_Encoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		development bool
+		encoding    string
+		expected    string
+	}{
+		{name: "dev default", development: true, expected: EncodingConsole},
+		{name: "prod default", development: false, expected: EncodingJSON},
+		{name: "json in dev", development: true, encoding: EncodingJSON, expected: EncodingJSON},
+		{name: "console in prod", development: false, encoding: EncodingConsole, expected: EncodingConsole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zapConfig, err := buildZapConfig(Config{Level: "info", Development: tt.development, Encoding: tt.encoding})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, zapConfig.Encoding)
+
+			log, err := NewWithConfig(Config{Level: "info", Development: tt.development, Encoding: tt.encoding})
+			require.NoError(t, err)
+			assert.NotNil(t, log)
+		})
+	}
+}
+
+func TestBuildZapConfig_UnsupportedEncoding(t *testing.T) {
+	_, err := buildZapConfig(Config{Level: "info", Encoding: "xml"})
+	assert.ErrorContains(t, err, "unsupported log encoding")
+}
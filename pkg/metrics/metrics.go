@@ -2,88 +2,224 @@ package metrics
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	dto "github.com/prometheus/client_model/go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
-
-	"github.com/mohamadchoker/order-delivery-service/internal/constants"
 )
 
-var (
-	// RequestsTotal counts total number of gRPC requests
-	RequestsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "grpc_requests_total",
-			Help:      "Total number of gRPC requests",
-		},
-		[]string{"method", "code"},
-	)
+// Metrics are the service's Prometheus collectors, registered with one registry under one
+// namespace and subsystem. A nil *Metrics records nothing, so components built without metrics
+// (e.g. in tests) need no special casing.
+type Metrics struct {
+	requestsTotal            *prometheus.CounterVec
+	requestDuration          *prometheus.HistogramVec
+	activeRequests           *prometheus.GaugeVec
+	deliveryAssignmentsTotal *prometheus.CounterVec
+	deliveryAttempts         *prometheus.HistogramVec
+	databaseQueriesTotal     *prometheus.CounterVec
+	databaseQueryDuration    *prometheus.HistogramVec
+	sloRequestsTotal         *prometheus.CounterVec
+	sloLatency               *prometheus.SummaryVec
+	deliveriesByCountry      *prometheus.CounterVec
 
-	// RequestDuration tracks request duration in seconds
-	RequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "grpc_request_duration_seconds",
-			Help:      "Duration of gRPC requests in seconds",
-			Buckets:   prometheus.DefBuckets,
-		},
-		[]string{"method"},
-	)
+	countries map[string]bool // Countries labeled as themselves; set by TrackCountries
+}
 
-	// ActiveRequests tracks number of active requests
-	ActiveRequests = promauto.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "grpc_requests_active",
-			Help:      "Number of active gRPC requests",
-		},
-		[]string{"method"},
-	)
+// OtherCountry is the country label of deliveries to countries that are not tracked
+const OtherCountry = "OTHER"
 
-	// DeliveryAssignmentsTotal counts delivery assignments by status
-	DeliveryAssignmentsTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "delivery_assignments_total",
-			Help:      "Total number of delivery assignments",
-		},
-		[]string{"status", "operation"},
-	)
+// MaxTrackedCountries caps the countries TrackCountries accepts, so the country label never has
+// more than MaxTrackedCountries+1 values however many countries deliveries go to
+const MaxTrackedCountries = 20
 
-	// DatabaseQueriesTotal counts database queries
-	DatabaseQueriesTotal = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "database_queries_total",
-			Help:      "Total number of database queries",
-		},
-		[]string{"operation", "status"},
-	)
+// countryCode matches the upper-case ISO 3166-1 alpha-2 codes accepted as tracked countries
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
 
-	// DatabaseQueryDuration tracks database query duration
-	DatabaseQueryDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Namespace: constants.MetricsNamespace,
-			Subsystem: constants.MetricsSubsystem,
-			Name:      "database_query_duration_seconds",
-			Help:      "Duration of database queries in seconds",
-			Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
-		},
-		[]string{"operation"},
+// metricNamePart matches a namespace or subsystem that keeps metric names valid in the classic
+// Prometheus exposition format
+var metricNamePart = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// NewRegistry creates a registry with the Go runtime and process collectors, which the
+// default registry would otherwise have provided
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 	)
-)
+	return registry
+}
+
+// New creates the metrics under namespace and subsystem (empty for none) and registers them with
+// registry, e.g. to give each logical instance of the service its own metric prefix
+func New(registry *prometheus.Registry, namespace, subsystem string) (*Metrics, error) {
+	if !metricNamePart.MatchString(namespace) {
+		return nil, fmt.Errorf("invalid metrics namespace %q: must be letters, digits and underscores", namespace)
+	}
+	if subsystem != "" && !metricNamePart.MatchString(subsystem) {
+		return nil, fmt.Errorf("invalid metrics subsystem %q: must be letters, digits and underscores", subsystem)
+	}
+
+	m := newMetrics(namespace, subsystem)
+	for _, collector := range m.collectors() {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+func newMetrics(namespace, subsystem string) *Metrics {
+	return &Metrics{
+		// Total number of gRPC requests
+		requestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "grpc_requests_total",
+				Help:      "Total number of gRPC requests",
+			},
+			[]string{"method", "code"},
+		),
+
+		// Request duration in seconds
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "grpc_request_duration_seconds",
+				Help:      "Duration of gRPC requests in seconds",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method"},
+		),
+
+		// Number of active requests
+		activeRequests: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "grpc_requests_active",
+				Help:      "Number of active gRPC requests",
+			},
+			[]string{"method"},
+		),
+
+		// Delivery assignment operations by status
+		deliveryAssignmentsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "delivery_assignments_total",
+				Help:      "Total number of delivery assignments",
+			},
+			[]string{"status", "operation"},
+		),
+
+		// Attempt number reached by reattempts, by outcome: "reattempted" when a new attempt was
+		// created, "dead_letter" when the attempts ran out
+		deliveryAttempts: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "delivery_attempt_number",
+				Help:      "Attempt number reached when a failed delivery is reattempted",
+				Buckets:   prometheus.LinearBuckets(1, 1, 10),
+			},
+			[]string{"outcome"},
+		),
+
+		// Database queries
+		databaseQueriesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "database_queries_total",
+				Help:      "Total number of database queries",
+			},
+			[]string{"operation", "status"},
+		),
+
+		// Database query duration
+		databaseQueryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "database_query_duration_seconds",
+				Help:      "Duration of database queries in seconds",
+				Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+			},
+			[]string{"operation"},
+		),
+
+		// Requests per method by SLO outcome. The error ratio over a window is the error budget
+		// burned:
+		//   sum(rate(..._slo_requests_total{outcome="error"}[1h])) / sum(rate(..._slo_requests_total[1h]))
+		sloRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "grpc_slo_requests_total",
+				Help:      "Total number of gRPC requests by SLO outcome",
+			},
+			[]string{"method", "outcome"},
+		),
+
+		// Per-method latency quantiles for latency objectives
+		sloLatency: prometheus.NewSummaryVec(
+			prometheus.SummaryOpts{
+				Namespace:  namespace,
+				Subsystem:  subsystem,
+				Name:       "grpc_slo_latency_seconds",
+				Help:       "Latency quantiles of gRPC requests in seconds",
+				Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+				MaxAge:     10 * time.Minute,
+			},
+			[]string{"method"},
+		),
+
+		// Deliveries created by delivery-address country; untracked countries count as OTHER
+		deliveriesByCountry: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "deliveries_created_by_country_total",
+				Help:      "Total number of deliveries created by delivery-address country",
+			},
+			[]string{"country"},
+		),
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.requestsTotal,
+		m.requestDuration,
+		m.activeRequests,
+		m.deliveryAssignmentsTotal,
+		m.deliveryAttempts,
+		m.databaseQueriesTotal,
+		m.databaseQueryDuration,
+		m.sloRequestsTotal,
+		m.sloLatency,
+		m.deliveriesByCountry,
+	}
+}
+
+// UnaryInterceptor creates a gRPC interceptor recording request counts, durations and
+// in-flight requests
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	if m == nil {
+		return passThrough
+	}
 
-// MetricsUnaryInterceptor creates a gRPC interceptor for Prometheus metrics
-func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -93,8 +229,8 @@ func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
 		start := time.Now()
 
 		// Increment active requests
-		ActiveRequests.WithLabelValues(info.FullMethod).Inc()
-		defer ActiveRequests.WithLabelValues(info.FullMethod).Dec()
+		m.activeRequests.WithLabelValues(info.FullMethod).Inc()
+		defer m.activeRequests.WithLabelValues(info.FullMethod).Dec()
 
 		// Call handler
 		resp, err := handler(ctx, req)
@@ -103,25 +239,131 @@ func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
 		duration := time.Since(start).Seconds()
 		code := status.Code(err).String()
 
-		RequestsTotal.WithLabelValues(info.FullMethod, code).Inc()
-		RequestDuration.WithLabelValues(info.FullMethod).Observe(duration)
+		m.requestsTotal.WithLabelValues(info.FullMethod, code).Inc()
+		m.requestDuration.WithLabelValues(info.FullMethod).Observe(duration)
 
 		return resp, err
 	}
 }
 
+// ActiveRequestsSnapshot returns the current number of in-flight requests per method.
+// Methods without active requests are omitted.
+func (m *Metrics) ActiveRequestsSnapshot() map[string]int {
+	snapshot := make(map[string]int)
+	if m == nil {
+		return snapshot
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		m.activeRequests.Collect(ch)
+		close(ch)
+	}()
+
+	for collected := range ch {
+		var metric dto.Metric
+		if err := collected.Write(&metric); err != nil {
+			continue
+		}
+
+		value := metric.GetGauge().GetValue()
+		if value <= 0 {
+			continue
+		}
+
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "method" {
+				snapshot[label.GetValue()] = int(value)
+			}
+		}
+	}
+
+	return snapshot
+}
+
 // RecordDeliveryOperation records a delivery assignment operation
-func RecordDeliveryOperation(operation, status string) {
-	DeliveryAssignmentsTotal.WithLabelValues(status, operation).Inc()
+func (m *Metrics) RecordDeliveryOperation(operation, status string) {
+	if m == nil {
+		return
+	}
+	m.deliveryAssignmentsTotal.WithLabelValues(status, operation).Inc()
+}
+
+// TrackCountries sets the countries, as ISO 3166-1 alpha-2 codes, that deliveries created are
+// counted under by name; all others are counted as OtherCountry. At most MaxTrackedCountries may
+// be given. It must be called before any delivery is recorded.
+func (m *Metrics) TrackCountries(countries []string) error {
+	tracked := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if !countryCode.MatchString(code) {
+			return fmt.Errorf("invalid metrics country %q: must be a two-letter ISO 3166-1 code", country)
+		}
+		tracked[code] = true
+	}
+	if len(tracked) > MaxTrackedCountries {
+		return fmt.Errorf("too many metrics countries: %d (max %d)", len(tracked), MaxTrackedCountries)
+	}
+	if m == nil {
+		return nil
+	}
+
+	m.countries = tracked
+
+	// Export every series from the start, so a country without deliveries reads as zero
+	for code := range tracked {
+		m.deliveriesByCountry.WithLabelValues(code)
+	}
+	m.deliveriesByCountry.WithLabelValues(OtherCountry)
+	return nil
+}
+
+// RecordDeliveryCreated records a created delivery under its delivery-address country
+func (m *Metrics) RecordDeliveryCreated(country string) {
+	if m == nil {
+		return
+	}
+	m.deliveriesByCountry.WithLabelValues(m.countryLabel(country)).Inc()
+}
+
+// countryLabel returns country's label value: the country itself when tracked, else OtherCountry
+func (m *Metrics) countryLabel(country string) string {
+	code := strings.ToUpper(strings.TrimSpace(country))
+	if m.countries[code] {
+		return code
+	}
+	return OtherCountry
+}
+
+// RecordDeliveryAttempt records the attempt number reached by a reattempt
+func (m *Metrics) RecordDeliveryAttempt(attemptNumber int, deadLettered bool) {
+	if m == nil {
+		return
+	}
+
+	outcome := "reattempted"
+	if deadLettered {
+		outcome = "dead_letter"
+	}
+	m.deliveryAttempts.WithLabelValues(outcome).Observe(float64(attemptNumber))
 }
 
 // RecordDatabaseQuery records a database query with timing
-func RecordDatabaseQuery(operation string, duration time.Duration, err error) {
+func (m *Metrics) RecordDatabaseQuery(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
 	status := "success"
 	if err != nil {
 		status = "error"
 	}
 
-	DatabaseQueriesTotal.WithLabelValues(operation, status).Inc()
-	DatabaseQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	m.databaseQueriesTotal.WithLabelValues(operation, status).Inc()
+	m.databaseQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// passThrough is the interceptor of a nil *Metrics
+func passThrough(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(ctx, req)
 }
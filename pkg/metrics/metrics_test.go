@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+)
+
+// newTestMetrics creates metrics registered with a registry of their own
+func newTestMetrics(t *testing.T) *Metrics {
+	t.Helper()
+	m, err := New(prometheus.NewRegistry(), constants.DefaultMetricsNamespace, constants.DefaultMetricsSubsystem)
+	require.NoError(t, err)
+	return m
+}
+
+func TestNew_Namespace(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry, "fleet", "east")
+	require.NoError(t, err)
+
+	m.RecordDeliveryOperation("create", "PENDING")
+
+	names := gatheredNames(t, registry)
+	assert.Contains(t, names, "fleet_east_delivery_assignments_total")
+	assert.NotContains(t, names, "order_delivery_service_delivery_assignments_total")
+
+	// Registering the same metrics twice with one registry fails
+	_, err = New(registry, "fleet", "east")
+	assert.Error(t, err)
+
+	for _, name := range [][2]string{{"", "east"}, {"not-valid", ""}, {"fleet", "1east"}} {
+		_, err := New(prometheus.NewRegistry(), name[0], name[1])
+		assert.Error(t, err, name)
+	}
+}
+
+func TestNew_IsolatedRegistries(t *testing.T) {
+	first := newTestMetrics(t)
+	second := newTestMetrics(t)
+
+	first.RecordDeliveryOperation("create", "PENDING")
+	first.RecordDeliveryOperation("create", "PENDING")
+	second.RecordDeliveryOperation("create", "PENDING")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(first.deliveryAssignmentsTotal.WithLabelValues("PENDING", "create")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(second.deliveryAssignmentsTotal.WithLabelValues("PENDING", "create")))
+}
+
+func TestRecordDeliveryCreated_Countries(t *testing.T) {
+	m := newTestMetrics(t)
+	require.NoError(t, m.TrackCountries([]string{"US", " gb "}))
+
+	m.RecordDeliveryCreated("US")
+	m.RecordDeliveryCreated("gb")
+	m.RecordDeliveryCreated("ZZ")
+	m.RecordDeliveryCreated("")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.deliveriesByCountry.WithLabelValues("US")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.deliveriesByCountry.WithLabelValues("GB")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.deliveriesByCountry.WithLabelValues(OtherCountry)))
+
+	// Unknown countries never get a label of their own
+	assert.Equal(t, 3, testutil.CollectAndCount(m.deliveriesByCountry))
+}
+
+func TestTrackCountries_Invalid(t *testing.T) {
+	m := newTestMetrics(t)
+
+	tooMany := make([]string, 0, MaxTrackedCountries+1)
+	for i := range MaxTrackedCountries + 1 {
+		tooMany = append(tooMany, string([]byte{'A' + byte(i/26), 'A' + byte(i%26)}))
+	}
+	assert.Error(t, m.TrackCountries(tooMany))
+	assert.Error(t, m.TrackCountries([]string{"USA"}))
+	assert.Error(t, m.TrackCountries([]string{OtherCountry}))
+
+	// Duplicates count once
+	assert.NoError(t, m.TrackCountries(append(tooMany[:MaxTrackedCountries], "aa")))
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+
+	m.RecordDeliveryOperation("create", "PENDING")
+	m.RecordDeliveryAttempt(2, false)
+	m.RecordDeliveryCreated("US")
+	assert.Empty(t, m.ActiveRequestsSnapshot())
+
+	resp, err := m.UnaryInterceptor()(context.Background(), "req", &grpc.UnaryServerInfo{}, func(_ context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req", resp)
+}
+
+// gatheredNames lists the metric families in registry
+func gatheredNames(t *testing.T, registry prometheus.Gatherer) []string {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	return names
+}
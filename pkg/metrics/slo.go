@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SLO outcomes recorded by SLOUnaryInterceptor
+const (
+	SLOOutcomeSuccess = "success"
+	SLOOutcomeError   = "error"
+)
+
+// sloErrorCodes are the codes that count against the error budget: failures of the service
+// itself rather than of the request. Client mistakes such as InvalidArgument or NotFound are
+// successes as far as the SLO is concerned.
+var sloErrorCodes = map[codes.Code]bool{
+	codes.Unknown:           true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Internal:          true,
+	codes.Unavailable:       true,
+	codes.DataLoss:          true,
+}
+
+// SLOOutcome classifies a request's error for SLO tracking
+func SLOOutcome(err error) string {
+	if sloErrorCodes[status.Code(err)] {
+		return SLOOutcomeError
+	}
+	return SLOOutcomeSuccess
+}
+
+// SLOUnaryInterceptor creates a gRPC interceptor recording SLO metrics. It only writes the
+// grpc_slo_* series, so it can be chained alongside UnaryInterceptor without either
+// counting a request twice.
+func (m *Metrics) SLOUnaryInterceptor() grpc.UnaryServerInterceptor {
+	if m == nil {
+		return passThrough
+	}
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		m.sloLatency.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		m.sloRequestsTotal.WithLabelValues(info.FullMethod, SLOOutcome(err)).Inc()
+
+		return resp, err
+	}
+}
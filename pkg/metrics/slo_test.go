@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSLOUnaryInterceptor(t *testing.T) {
+	method := "/delivery.DeliveryService/TestSLOUnaryInterceptor"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	m := newTestMetrics(t)
+
+	// Chained as in the server, so both interceptors see every request
+	chain := func(err error) {
+		_, _ = m.UnaryInterceptor()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.SLOUnaryInterceptor()(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+				return nil, err
+			})
+		})
+	}
+
+	chain(nil)
+	chain(status.Error(codes.NotFound, "not found"))
+	chain(status.Error(codes.Internal, "boom"))
+	chain(status.Error(codes.Unavailable, "down"))
+
+	// Client errors do not burn the error budget
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.sloRequestsTotal.WithLabelValues(method, SLOOutcomeSuccess)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.sloRequestsTotal.WithLabelValues(method, SLOOutcomeError)))
+
+	// Each request is observed once by each interceptor
+	var latency dto.Metric
+	require.NoError(t, m.sloLatency.WithLabelValues(method).(prometheus.Metric).Write(&latency))
+	assert.Equal(t, uint64(4), latency.GetSummary().GetSampleCount())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requestsTotal.WithLabelValues(method, codes.OK.String())))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requestsTotal.WithLabelValues(method, codes.Internal.String())))
+}
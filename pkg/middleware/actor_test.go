@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+func TestActorUnaryInterceptor(t *testing.T) {
+	named, err := ParseAPIKeyActors("user-42@tenant-7=key-a")
+	require.NoError(t, err)
+	interceptor := ActorUnaryInterceptor(APIKeyActors([]string{"key-a", "key-b"}, named))
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/UpdateDeliveryStatus"}
+
+	var got domain.Actor
+	var present bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, present = domain.ActorFromContext(ctx)
+		return nil, nil
+	}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		expected *domain.Actor
+	}{
+		{
+			name:     "named key",
+			md:       metadata.Pairs("x-api-key", "key-a"),
+			expected: &domain.Actor{UserID: "user-42", TenantID: "tenant-7"},
+		},
+		{
+			name:     "unnamed key is recorded by fingerprint",
+			md:       metadata.Pairs("x-api-key", "key-b"),
+			expected: &domain.Actor{UserID: KeyFingerprint("key-b")},
+		},
+		{
+			name:     "identity metadata is ignored",
+			md:       metadata.Pairs("x-api-key", "key-b", "x-user-id", "user-42", "x-tenant-id", "tenant-7"),
+			expected: &domain.Actor{UserID: KeyFingerprint("key-b")},
+		},
+		{
+			name: "unknown key",
+			md:   metadata.Pairs("x-api-key", "key-c", "x-user-id", "user-42"),
+		},
+		{
+			name: "no key",
+			md:   metadata.MD{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, present = domain.Actor{}, false
+			_, err := interceptor(metadata.NewIncomingContext(context.Background(), tt.md), nil, info, handler)
+
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.False(t, present, "no actor without a known API key")
+				return
+			}
+			assert.True(t, present)
+			assert.Equal(t, *tt.expected, got)
+		})
+	}
+}
+
+func TestParseAPIKeyActors(t *testing.T) {
+	actors, err := ParseAPIKeyActors(" ops@acme=k1 , batch=k2==, ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.Actor{
+		"k1":   {UserID: "ops", TenantID: "acme"},
+		"k2==": {UserID: "batch"},
+	}, actors)
+
+	for _, spec := range []string{"ops", "ops=", "@acme=k1", "a=k1,b=k1"} {
+		_, err := ParseAPIKeyActors(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestKeyFingerprint(t *testing.T) {
+	fingerprint := KeyFingerprint("secret-key")
+
+	assert.Regexp(t, `^api-key:[0-9a-f]{12}$`, fingerprint)
+	assert.NotContains(t, fingerprint, "secret")
+	assert.Equal(t, fingerprint, KeyFingerprint("secret-key"))
+	assert.NotEqual(t, fingerprint, KeyFingerprint("other-key"))
+}
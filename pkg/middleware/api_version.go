@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+)
+
+type apiVersionKey struct{}
+
+// APIVersionUnaryInterceptor negotiates the API version a client declares in the x-api-version
+// metadata. supported is ordered oldest to newest; requests without the header get the newest.
+// Unsupported versions are rejected with FailedPrecondition. The negotiated version is stored in
+// the context (see GetAPIVersion) and echoed in the response header.
+func APIVersionUnaryInterceptor(supported []string) grpc.UnaryServerInterceptor {
+	latest := ""
+	if len(supported) > 0 {
+		latest = supported[len(supported)-1]
+	}
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		version := strings.TrimSpace(firstMetadataValue(ctx, constants.APIVersionHeader))
+		if version == "" {
+			version = latest
+		} else if !isSupportedAPIVersion(supported, version) {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"unsupported API version %q; supported versions: %s", version, strings.Join(supported, ", "))
+		}
+
+		ctx = context.WithValue(ctx, apiVersionKey{}, version)
+
+		// Tell the client which version answered; failing to set the header must not fail the request
+		_ = grpc.SetHeader(ctx, metadata.Pairs(constants.APIVersionHeader, version))
+
+		return handler(ctx, req)
+	}
+}
+
+// isSupportedAPIVersion reports whether version is in supported
+func isSupportedAPIVersion(supported []string, version string) bool {
+	for _, s := range supported {
+		if s == version {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAPIVersion retrieves the negotiated API version from context
+func GetAPIVersion(ctx context.Context) string {
+	if version, ok := ctx.Value(apiVersionKey{}).(string); ok {
+		return version
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIVersionUnaryInterceptor(t *testing.T) {
+	interceptor := APIVersionUnaryInterceptor([]string{"v1", "v2"})
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	tests := []struct {
+		name         string
+		header       string
+		expectedCode codes.Code
+		negotiated   string
+	}{
+		{name: "supported version", header: "v1", expectedCode: codes.OK, negotiated: "v1"},
+		{name: "absent defaults to latest", header: "", expectedCode: codes.OK, negotiated: "v2"},
+		{name: "unsupported version", header: "v9", expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-version", tt.header))
+			}
+
+			called := false
+			negotiated := ""
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				negotiated = GetAPIVersion(ctx)
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, nil, info, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called, "handler runs only for supported versions")
+			assert.Equal(t, tt.negotiated, negotiated)
+		})
+	}
+}
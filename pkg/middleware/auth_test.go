@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	var authenticated bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated = IsAuthenticated(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := APIKeyAuthMiddleware(AuthConfig{
+		APIKeys:      []string{"key-one", "key-two"},
+		ExemptRoutes: []string{"/v1/server-info", "/public/*"},
+	})(next)
+
+	tests := []struct {
+		name           string
+		path           string
+		apiKey         string
+		expectedStatus int
+		authenticated  bool
+	}{
+		{name: "exempt route without key", path: "/v1/server-info", expectedStatus: http.StatusOK},
+		{name: "exempt route with valid key", path: "/v1/server-info", apiKey: "key-one", expectedStatus: http.StatusOK, authenticated: true},
+		{name: "exempt prefix without key", path: "/public/docs", expectedStatus: http.StatusOK},
+		{name: "protected route without key", path: "/v1/deliveries", expectedStatus: http.StatusUnauthorized},
+		{name: "protected route with wrong key", path: "/v1/deliveries", apiKey: "nope", expectedStatus: http.StatusUnauthorized},
+		{name: "protected route with valid key", path: "/v1/deliveries", apiKey: "key-two", expectedStatus: http.StatusOK, authenticated: true},
+		{name: "exempt match is exact", path: "/v1/server-info/extra", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			authenticated = false
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.authenticated, authenticated)
+		})
+	}
+}
+
+func TestAPIKeyAuthMiddleware_Disabled(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	handler := APIKeyAuthMiddleware(AuthConfig{})(next)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil))
+
+	assert.True(t, called)
+}
+
+func TestAdminUnaryInterceptor(t *testing.T) {
+	const adminMethod = "/delivery.DeliveryService/BulkDeleteDeliveries"
+	methods := []string{adminMethod}
+
+	tests := []struct {
+		name         string
+		adminKeys    []string
+		method       string
+		apiKey       string
+		expectedCode codes.Code
+	}{
+		{name: "admin key", adminKeys: []string{"admin-key"}, method: adminMethod, apiKey: "admin-key", expectedCode: codes.OK},
+		{name: "no key", adminKeys: []string{"admin-key"}, method: adminMethod, expectedCode: codes.PermissionDenied},
+		{name: "non-admin key", adminKeys: []string{"admin-key"}, method: adminMethod, apiKey: "user-key", expectedCode: codes.PermissionDenied},
+		{name: "no admin keys configured", method: adminMethod, apiKey: "admin-key", expectedCode: codes.PermissionDenied},
+		{name: "other methods are not gated", method: "/delivery.DeliveryService/GetDeliveryAssignment", expectedCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.apiKey != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", tt.apiKey))
+			}
+
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+
+			_, err := AdminUnaryInterceptor(tt.adminKeys, methods)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectedCode == codes.OK, called)
+		})
+	}
+}
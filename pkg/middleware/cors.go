@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+)
+
+// CORSConfig holds cross-origin resource sharing settings for the HTTP gateway
+type CORSConfig struct {
+	AllowedOrigins []string // Use "*" to allow any origin
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration // How long browsers may cache preflight results
+}
+
+// CORSMiddleware adds CORS headers for allowed origins and answers preflight requests
+func CORSMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			// Not a cross-origin request
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+
+			if !isOriginAllowed(cfg.AllowedOrigins, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", constants.RequestIDHeader+", "+constants.TraceIDHeader)
+
+			// Answer preflight requests without hitting the gateway
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				if cfg.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isOriginAllowed checks the origin against the allowed list (case-insensitive)
+func isOriginAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	handler := CORSMiddleware(CORSConfig{
+		AllowedOrigins: []string{"https://dashboard.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	})(next)
+
+	tests := []struct {
+		name           string
+		origin         string
+		expectedStatus int
+		expectHeaders  bool
+	}{
+		{
+			name:           "allowed origin",
+			origin:         "https://dashboard.example.com",
+			expectedStatus: http.StatusNoContent,
+			expectHeaders:  true,
+		},
+		{
+			name:           "disallowed origin",
+			origin:         "https://evil.example.com",
+			expectedStatus: http.StatusForbidden,
+			expectHeaders:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(http.MethodOptions, "/v1/deliveries", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.False(t, called, "preflight must not reach the gateway")
+			if tt.expectHeaders {
+				assert.Equal(t, tt.origin, rec.Header().Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Content-Type, X-Request-ID", rec.Header().Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_SimpleRequest(t *testing.T) {
+	handler := CORSMiddleware(CORSConfig{
+		AllowedOrigins: []string{"*"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "http://localhost:3000", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID, X-Trace-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+}
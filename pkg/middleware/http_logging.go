@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/mohamadchoker/order-delivery-service/internal/constants"
@@ -33,20 +32,31 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
-// HTTPLoggingMiddleware logs HTTP requests with request ID, method, path, status, and duration
+// HTTPLoggingMiddleware logs HTTP requests with request ID, trace ID, method, path, status, and duration.
+// The trace ID is the caller's X-Trace-ID if it is a valid request ID, or else the request ID; it is
+// echoed in the response and left on the request for the gateway to forward to gRPC.
 func HTTPLoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return HTTPLoggingMiddlewareWithConfig(logger, DefaultRequestIDConfig())
+}
+
+// HTTPLoggingMiddlewareWithConfig is HTTPLoggingMiddleware keeping the caller's X-Request-ID only if requestIDs accepts it
+func HTTPLoggingMiddlewareWithConfig(logger *zap.Logger, requestIDs RequestIDConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
-			// Get or generate request ID
-			requestID := r.Header.Get(constants.RequestIDHeader)
-			if requestID == "" {
-				requestID = uuid.New().String()
+			// Keep a valid request ID from the caller, otherwise generate one
+			requestID := requestIDs.resolve(r.Header.Get(constants.RequestIDHeader))
+
+			traceID := r.Header.Get(constants.TraceIDHeader)
+			if !requestIDs.accepts(traceID) {
+				traceID = requestID
+				r.Header.Set(constants.TraceIDHeader, traceID)
 			}
 
-			// Add request ID to response header
+			// Add request and trace IDs to response header
 			w.Header().Set(constants.RequestIDHeader, requestID)
+			w.Header().Set(constants.TraceIDHeader, traceID)
 
 			// Wrap response writer to capture status code
 			rw := &responseWriter{
@@ -67,6 +77,7 @@ func HTTPLoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int("status", rw.statusCode),
 				zap.Duration("duration", duration),
 				zap.String("request_id", requestID),
+				zap.String("trace_id", traceID),
 				zap.String("user_agent", r.UserAgent()),
 			}
 
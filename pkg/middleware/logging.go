@@ -2,16 +2,61 @@ package middleware
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
 )
 
+// latencyBuckets are the coarse upper bounds used to label request latency in logs
+var latencyBuckets = []struct {
+	limit time.Duration
+	label string
+}{
+	{10 * time.Millisecond, "<10ms"},
+	{100 * time.Millisecond, "<100ms"},
+	{time.Second, "<1s"},
+}
+
+// latencyBucket returns a coarse label for d so slow requests can be grepped without Prometheus
+func latencyBucket(d time.Duration) string {
+	for _, b := range latencyBuckets {
+		if d < b.limit {
+			return b.label
+		}
+	}
+	return ">1s"
+}
+
+// LoggingConfig controls which successful requests are logged and at what level.
+// Failed requests are always logged regardless of these settings.
+type LoggingConfig struct {
+	SkipMethods         []string // Full method names (e.g. "/grpc.health.v1.Health/Check") whose successful calls are not logged
+	DebugMethodPrefixes []string // Method name prefixes (e.g. "Get", "List") whose successful calls are logged at debug
+
+	LogPayloads bool      // Log request and response payloads at debug level
+	Redactor    *Redactor // Fields replaced in logged payloads; nil logs payloads as-is
+}
+
 // LoggingUnaryInterceptor creates a gRPC unary interceptor that logs requests with request ID and status code
 func LoggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return LoggingUnaryInterceptorWithConfig(logger, LoggingConfig{})
+}
+
+// LoggingUnaryInterceptorWithConfig creates a logging interceptor that quiets successful calls per cfg
+func LoggingUnaryInterceptorWithConfig(logger *zap.Logger, cfg LoggingConfig) grpc.UnaryServerInterceptor {
+	skip := make(map[string]struct{}, len(cfg.SkipMethods))
+	for _, method := range cfg.SkipMethods {
+		skip[method] = struct{}{}
+	}
+
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -20,10 +65,15 @@ func LoggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	) (interface{}, error) {
 		start := time.Now()
 		requestID := GetRequestID(ctx)
+		traceID := domain.TraceIDFromContext(ctx)
 
 		// Call handler
 		resp, err := handler(ctx, req)
 
+		if _, ok := skip[info.FullMethod]; ok && err == nil {
+			return resp, err
+		}
+
 		// Extract gRPC status code
 		grpcStatus := codes.OK
 		if err != nil {
@@ -40,16 +90,77 @@ func LoggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 			zap.String("method", info.FullMethod),
 			zap.String("grpc_code", grpcStatus.String()),
 			zap.Duration("duration", duration),
+			zap.String("latency_bucket", latencyBucket(duration)),
 			zap.String("request_id", requestID),
+			zap.String("trace_id", traceID),
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			fields = append(fields, zap.String("peer", p.Addr.String()))
 		}
 
-		if err != nil {
+		if cfg.LogPayloads {
+			logPayloads(logger, cfg.Redactor, info.FullMethod, requestID, traceID, req, resp)
+		}
+
+		switch {
+		case err == nil && hasMethodPrefix(info.FullMethod, cfg.DebugMethodPrefixes):
+			logger.Debug("gRPC request completed", fields...)
+		case err == nil:
+			logger.Info("gRPC request completed", fields...)
+		case grpcStatus == codes.Canceled || grpcStatus == codes.DeadlineExceeded:
+			// Client went away or ran out of time; not a server fault
+			fields = append(fields, zap.Error(err))
+			logger.Warn("gRPC request aborted", fields...)
+		default:
 			fields = append(fields, zap.Error(err))
 			logger.Error("gRPC request failed", fields...)
-		} else {
-			logger.Info("gRPC request completed", fields...)
 		}
 
 		return resp, err
 	}
 }
+
+// hasMethodPrefix reports whether the method name in fullMethod ("/pkg.Service/Method") starts with any prefix
+func hasMethodPrefix(fullMethod string, prefixes []string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// logPayloads logs the request and response at debug level with configured fields redacted.
+// Payloads are only marshaled when debug logging is enabled.
+func logPayloads(logger *zap.Logger, redactor *Redactor, method, requestID, traceID string, req, resp interface{}) {
+	ce := logger.Check(zap.DebugLevel, "gRPC request payload")
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("request_id", requestID),
+		zap.String("trace_id", traceID),
+	}
+	fields = append(fields, payloadField("request", redactor, req))
+	if resp != nil {
+		fields = append(fields, payloadField("response", redactor, resp))
+	}
+	ce.Write(fields...)
+}
+
+// payloadField renders a proto payload as JSON, redacted; other values are never logged
+func payloadField(key string, redactor *Redactor, payload interface{}) zap.Field {
+	msg, ok := payload.(proto.Message)
+	if !ok {
+		return zap.Skip()
+	}
+
+	data, err := redactor.RedactMessage(msg) // A nil redactor leaves the payload as-is
+	if err != nil {
+		return zap.String(key, "unavailable: "+err.Error())
+	}
+	return zap.Reflect(key, data) // Embedded as JSON rather than a quoted string
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingUnaryInterceptor_Peer(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	interceptor := LoggingUnaryInterceptor(zap.New(core))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 52814},
+	})
+
+	_, err := interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "10.0.0.7:52814", fields["peer"])
+	assert.Equal(t, "<10ms", fields["latency_bucket"])
+}
+
+func TestLoggingUnaryInterceptor_NoPeer(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	interceptor := LoggingUnaryInterceptor(zap.New(core))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logs.Len())
+	assert.NotContains(t, logs.All()[0].ContextMap(), "peer")
+}
+
+func TestLoggingUnaryInterceptor_SkipMethods(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	interceptor := LoggingUnaryInterceptorWithConfig(zap.New(core), LoggingConfig{
+		SkipMethods: []string{"/grpc.health.v1.Health/Check"},
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	_, err := interceptor(context.Background(), nil, info, ok)
+	require.NoError(t, err)
+	assert.Equal(t, 0, logs.Len())
+
+	// Failures are still logged for skipped methods
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	}
+	_, err = interceptor(context.Background(), nil, info, failing)
+	require.Error(t, err)
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, zapcore.ErrorLevel, logs.All()[0].Level)
+}
+
+func TestLoggingUnaryInterceptor_DebugMethodPrefixes(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	interceptor := LoggingUnaryInterceptorWithConfig(zap.New(core), LoggingConfig{
+		DebugMethodPrefixes: []string{"Get", "List"},
+	})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		method   string
+		expected zapcore.Level
+	}{
+		{method: "/delivery.DeliveryService/GetDeliveryAssignment", expected: zapcore.DebugLevel},
+		{method: "/delivery.DeliveryService/ListDeliveryAssignments", expected: zapcore.DebugLevel},
+		{method: "/delivery.DeliveryService/CreateDeliveryAssignment", expected: zapcore.InfoLevel},
+		{method: "/delivery.DeliveryService/AssignDriver", expected: zapcore.InfoLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			require.NoError(t, err)
+
+			entries := logs.TakeAll()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].Level)
+		})
+	}
+}
+
+func TestLatencyBucket(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 3 * time.Millisecond, expected: "<10ms"},
+		{duration: 10 * time.Millisecond, expected: "<100ms"},
+		{duration: 250 * time.Millisecond, expected: "<1s"},
+		{duration: time.Second, expected: ">1s"},
+		{duration: 5 * time.Second, expected: ">1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, latencyBucket(tt.duration))
+		})
+	}
+}
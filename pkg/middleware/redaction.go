@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RedactedValue replaces the value of every redacted field; the key itself is kept
+const RedactedValue = "[REDACTED]"
+
+// Redactor replaces sensitive fields in JSON payloads.
+// Field paths use proto field names separated by dots (e.g. "pickup_address.street") and match
+// at any depth, so "notes" also covers the notes of every delivery in a list response.
+// The lowerCamelCase JSON name of each segment is matched as well.
+type Redactor struct {
+	paths [][]string
+}
+
+// NewRedactor creates a redactor for the given field paths; blank paths are ignored
+func NewRedactor(fieldPaths []string) *Redactor {
+	r := &Redactor{}
+	for _, path := range fieldPaths {
+		if path = strings.TrimSpace(path); path != "" {
+			r.paths = append(r.paths, strings.Split(path, "."))
+		}
+	}
+	return r
+}
+
+// Enabled reports whether any field is configured for redaction
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.paths) > 0
+}
+
+// RedactJSON returns data with the value of every configured field replaced by RedactedValue.
+// Data that is not a JSON object or array is returned unchanged.
+func (r *Redactor) RedactJSON(data []byte) ([]byte, error) {
+	if !r.Enabled() {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep int64 and float values exactly as encoded
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	switch doc.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return data, nil
+	}
+
+	r.redactValue(doc)
+	return json.Marshal(doc)
+}
+
+// RedactMessage marshals msg with proto field names and redacts it, for use in log fields
+func (r *Redactor) RedactMessage(msg proto.Message) (json.RawMessage, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return r.RedactJSON(data)
+}
+
+// redactValue walks v and redacts every configured path that starts at any object within it
+func (r *Redactor) redactValue(v interface{}) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for _, path := range r.paths {
+			redactPath(node, path)
+		}
+		for _, child := range node {
+			r.redactValue(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			r.redactValue(child)
+		}
+	}
+}
+
+// redactPath replaces the value at path below obj, descending into arrays along the way
+func redactPath(obj map[string]interface{}, path []string) {
+	key, ok := lookupKey(obj, path[0])
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		obj[key] = RedactedValue
+		return
+	}
+
+	switch child := obj[key].(type) {
+	case map[string]interface{}:
+		redactPath(child, path[1:])
+	case []interface{}:
+		for _, item := range child {
+			if m, ok := item.(map[string]interface{}); ok {
+				redactPath(m, path[1:])
+			}
+		}
+	}
+}
+
+// lookupKey finds field in obj by its proto name or its lowerCamelCase JSON name
+func lookupKey(obj map[string]interface{}, field string) (string, bool) {
+	if _, ok := obj[field]; ok {
+		return field, true
+	}
+	if camel := lowerCamelCase(field); camel != field {
+		if _, ok := obj[camel]; ok {
+			return camel, true
+		}
+	}
+	return "", false
+}
+
+// lowerCamelCase converts a proto field name to its default JSON name ("pickup_address" -> "pickupAddress")
+func lowerCamelCase(field string) string {
+	var b strings.Builder
+	upper := false
+	for _, c := range field {
+		switch {
+		case c == '_':
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(c)))
+			upper = false
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// RedactingMarshaler wraps a gateway marshaler and redacts every payload it marshals
+type RedactingMarshaler struct {
+	runtime.Marshaler
+	Redactor *Redactor
+}
+
+// Marshal marshals v with the wrapped marshaler and redacts the result
+func (m *RedactingMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.Marshaler.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return m.Redactor.RedactJSON(data)
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	pb "github.com/mohamadchoker/order-delivery-service/proto"
+)
+
+func TestRedactor_RedactJSON(t *testing.T) {
+	redactor := NewRedactor([]string{"notes", "pickup_address.street", "timeline_notes.note"})
+
+	input := `{
+		"deliveries": [
+			{"id": "1", "notes": "gate code 1234", "pickupAddress": {"street": "1 Main St", "city": "Springfield"}},
+			{"id": "2", "notes": "", "timeline_notes": [{"note": "left at door", "created_at": "2026-03-01T09:00:00Z"}]}
+		],
+		"total_count": "2"
+	}`
+
+	out, err := redactor.RedactJSON([]byte(input))
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &got))
+	deliveries := got["deliveries"].([]interface{})
+
+	first := deliveries[0].(map[string]interface{})
+	assert.Equal(t, RedactedValue, first["notes"])
+	assert.Equal(t, RedactedValue, first["pickupAddress"].(map[string]interface{})["street"], "JSON names match proto paths")
+	assert.Equal(t, "Springfield", first["pickupAddress"].(map[string]interface{})["city"])
+
+	second := deliveries[1].(map[string]interface{})
+	assert.Equal(t, RedactedValue, second["notes"], "keys are kept even when empty")
+	note := second["timeline_notes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, RedactedValue, note["note"])
+	assert.Equal(t, "2026-03-01T09:00:00Z", note["created_at"])
+
+	assert.Equal(t, "2", got["total_count"])
+}
+
+func TestRedactor_Disabled(t *testing.T) {
+	input := []byte(`{"notes":"gate code 1234"}`)
+
+	out, err := NewRedactor(nil).RedactJSON(input)
+	require.NoError(t, err)
+	assert.Equal(t, input, out)
+	assert.False(t, (*Redactor)(nil).Enabled())
+}
+
+func TestLoggingUnaryInterceptor_RedactsPayloads(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	interceptor := LoggingUnaryInterceptorWithConfig(zap.New(core), LoggingConfig{
+		LogPayloads: true,
+		Redactor:    NewRedactor([]string{"notes", "delivery_address.street"}),
+	})
+
+	req := &pb.CreateDeliveryAssignmentRequest{
+		OrderId:         "ORDER-123",
+		Notes:           "call 555-0100 on arrival",
+		DeliveryAddress: &pb.Address{Street: "221B Baker St", City: "London"},
+	}
+
+	// The handler stands in for the use case: it must see, and store, the original values
+	var stored *pb.DeliveryAssignment
+	handler := func(ctx context.Context, r interface{}) (interface{}, error) {
+		in := r.(*pb.CreateDeliveryAssignmentRequest)
+		stored = &pb.DeliveryAssignment{OrderId: in.OrderId, Notes: in.Notes, DeliveryAddress: in.DeliveryAddress}
+		return stored, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/CreateDeliveryAssignment"}
+
+	_, err := interceptor(context.Background(), req, info, handler)
+	require.NoError(t, err)
+
+	payloads := logs.FilterMessage("gRPC request payload").All()
+	require.Len(t, payloads, 1)
+	fields := payloads[0].ContextMap()
+
+	for _, key := range []string{"request", "response"} {
+		logged := string(fields[key].(json.RawMessage))
+		assert.Contains(t, logged, `"notes":"[REDACTED]"`, key)
+		assert.Contains(t, logged, `"street":"[REDACTED]"`, key)
+		assert.Contains(t, logged, `"city":"London"`, key)
+		assert.NotContains(t, logged, "555-0100", key)
+		assert.NotContains(t, logged, "Baker", key)
+	}
+
+	assert.Equal(t, "call 555-0100 on arrival", stored.Notes)
+	assert.Equal(t, "221B Baker St", stored.DeliveryAddress.Street)
+	assert.Equal(t, "call 555-0100 on arrival", req.Notes)
+}
+
+func TestLoggingUnaryInterceptor_PayloadsOnlyAtDebug(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	interceptor := LoggingUnaryInterceptorWithConfig(zap.New(core), LoggingConfig{LogPayloads: true})
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.DeliveryAssignment{}, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	_, err := interceptor(context.Background(), &pb.GetDeliveryAssignmentRequest{Id: "x"}, info, handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, logs.FilterMessage("gRPC request payload").Len())
+}
+
+func TestRedactingMarshaler(t *testing.T) {
+	marshaler := &RedactingMarshaler{
+		Marshaler: &runtime.JSONPb{},
+		Redactor:  NewRedactor([]string{"pickup_address.street"}),
+	}
+
+	out, err := marshaler.Marshal(&pb.DeliveryAssignment{
+		Id:            "1",
+		PickupAddress: &pb.Address{Street: "1 Main St", City: "Springfield"},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"id":"1","pickupAddress":{"street":"[REDACTED]","city":"Springfield"}}`, string(out))
+}
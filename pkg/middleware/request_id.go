@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
@@ -12,18 +13,66 @@ import (
 
 type requestIDKey struct{}
 
-// RequestIDUnaryInterceptor adds a request ID to the context
+// RequestIDConfig controls which request IDs sent by callers are kept. An ID that is longer than
+// MaxLength or does not match Pattern is replaced with a generated one, so arbitrary header values
+// never reach the logs.
+type RequestIDConfig struct {
+	Pattern   *regexp.Regexp // Accepted IDs, matched against the whole ID; nil accepts any
+	MaxLength int            // Longest accepted ID in bytes (0 = no limit)
+	Generate  func() string  // Generates missing and rejected IDs; nil generates random UUIDs
+}
+
+// DefaultRequestIDConfig accepts IDs of up to constants.DefaultRequestIDMaxLength characters
+// matching constants.DefaultRequestIDPattern
+func DefaultRequestIDConfig() RequestIDConfig {
+	return RequestIDConfig{
+		Pattern:   regexp.MustCompile(anchorPattern(constants.DefaultRequestIDPattern)),
+		MaxLength: constants.DefaultRequestIDMaxLength,
+	}
+}
+
+// ParseRequestIDPattern compiles pattern so that it must match a whole request ID
+func ParseRequestIDPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(anchorPattern(pattern))
+}
+
+func anchorPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// accepts reports whether id may be kept as sent by the caller. Trace IDs are held to the same rules.
+func (c RequestIDConfig) accepts(id string) bool {
+	return id != "" &&
+		(c.MaxLength <= 0 || len(id) <= c.MaxLength) &&
+		(c.Pattern == nil || c.Pattern.MatchString(id))
+}
+
+// resolve returns incoming if it is an acceptable request ID and a generated one otherwise
+func (c RequestIDConfig) resolve(incoming string) string {
+	if c.accepts(incoming) {
+		return incoming
+	}
+	if c.Generate != nil {
+		return c.Generate()
+	}
+	return uuid.New().String()
+}
+
+// RequestIDUnaryInterceptor adds a request ID to the context, keeping the caller's if it is valid
+// under DefaultRequestIDConfig
 func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return RequestIDUnaryInterceptorWithConfig(DefaultRequestIDConfig())
+}
+
+// RequestIDUnaryInterceptorWithConfig adds a request ID to the context, keeping the caller's if cfg accepts it
+func RequestIDUnaryInterceptorWithConfig(cfg RequestIDConfig) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		requestID := extractRequestID(ctx)
-		if requestID == "" {
-			requestID = uuid.New().String()
-		}
+		requestID := cfg.resolve(extractRequestID(ctx))
 
 		// Add request ID to context
 		ctx = context.WithValue(ctx, requestIDKey{}, requestID)
@@ -38,6 +87,36 @@ func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// RequestIDStreamInterceptorWithConfig is RequestIDUnaryInterceptorWithConfig for streaming calls
+func RequestIDStreamInterceptorWithConfig(cfg RequestIDConfig) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		requestID := cfg.resolve(extractRequestID(ss.Context()))
+
+		// Only fails once the headers are sent, which the handler has not done yet
+		_ = ss.SetHeader(metadata.Pairs(constants.RequestIDHeader, requestID))
+
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          context.WithValue(ss.Context(), requestIDKey{}, requestID),
+		})
+	}
+}
+
+// contextStream is a server stream carrying a context derived by an interceptor
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
 // extractRequestID extracts request ID from incoming metadata
 func extractRequestID(ctx context.Context) string {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	call := func(interceptor grpc.UnaryServerInterceptor, incoming string) string {
+		ctx := context.Background()
+		if incoming != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", incoming))
+		}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &trailerRecorder{})
+
+		var requestID string
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			requestID = GetRequestID(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return requestID
+	}
+
+	t.Run("valid incoming ID is preserved", func(t *testing.T) {
+		assert.Equal(t, "req-1.a:b_C", call(RequestIDUnaryInterceptor(), "req-1.a:b_C"))
+	})
+
+	t.Run("malformed incoming ID is replaced", func(t *testing.T) {
+		for _, incoming := range []string{
+			"req-1\nlevel=error msg=forged",
+			"req 1",
+			strings.Repeat("a", 129),
+		} {
+			requestID := call(RequestIDUnaryInterceptor(), incoming)
+			assert.NotEqual(t, incoming, requestID)
+			_, err := uuid.Parse(requestID)
+			assert.NoError(t, err, "replacement should be a generated UUID")
+		}
+	})
+
+	t.Run("missing ID is generated", func(t *testing.T) {
+		assert.NotEmpty(t, call(RequestIDUnaryInterceptor(), ""))
+	})
+
+	t.Run("custom pattern and generator", func(t *testing.T) {
+		pattern, err := ParseRequestIDPattern(`corr-[0-9]{6}`)
+		require.NoError(t, err)
+		interceptor := RequestIDUnaryInterceptorWithConfig(RequestIDConfig{
+			Pattern:   pattern,
+			MaxLength: 16,
+			Generate:  func() string { return "generated" },
+		})
+
+		assert.Equal(t, "corr-123456", call(interceptor, "corr-123456"))
+		// The pattern must match the whole ID, not just part of it
+		assert.Equal(t, "generated", call(interceptor, "x-corr-123456"))
+		assert.Equal(t, "generated", call(interceptor, "corr-1234567"))
+	})
+}
+
+func TestHTTPLoggingMiddleware_RequestID(t *testing.T) {
+	handler := HTTPLoggingMiddleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(incoming string) string {
+		req := httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil)
+		req.Header.Set("X-Request-ID", incoming)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("X-Request-ID")
+	}
+
+	// A valid ID from the caller is echoed
+	assert.Equal(t, "req-1", serve("req-1"))
+
+	// A malformed or overlong one is replaced with a generated UUID
+	requestID := serve("req-1\"} forged")
+	_, err := uuid.Parse(requestID)
+	assert.NoError(t, err)
+
+	requestID = serve(strings.Repeat("a", 200))
+	_, err = uuid.Parse(requestID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+)
+
+// RetryAfterUnaryInterceptor copies the google.rpc.RetryInfo delay of ResourceExhausted and
+// Unavailable errors into the retry-after header, in whole seconds, so clients can back off
+// without decoding status details. The HTTP gateway returns it as the Retry-After header.
+func RetryAfterUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		if delay, ok := RetryDelay(err); ok {
+			seconds := strconv.FormatInt(int64(math.Ceil(delay.Seconds())), 10)
+			// Only fails outside a server call or once headers were sent; the status details still carry it
+			_ = grpc.SetHeader(ctx, metadata.Pairs(constants.RetryAfterHeader, seconds))
+		}
+
+		return resp, err
+	}
+}
+
+// RetryDelay returns the retry delay carried by a ResourceExhausted or Unavailable status error
+func RetryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || (st.Code() != codes.ResourceExhausted && st.Code() != codes.Unavailable) {
+		return 0, false
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
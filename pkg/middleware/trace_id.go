@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/constants"
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// TraceIDUnaryInterceptor adds a trace ID to the context and returns it to the client in the
+// x-trace-id trailer, keeping the caller's if it is valid under DefaultRequestIDConfig
+func TraceIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return TraceIDUnaryInterceptorWithConfig(DefaultRequestIDConfig())
+}
+
+// TraceIDUnaryInterceptorWithConfig adds a trace ID to the context and returns it to the client in
+// the x-trace-id trailer. A trace ID sent by the caller is kept if cfg accepts it, so that one trace
+// can span several services; otherwise the request ID is reused. Chain it after
+// RequestIDUnaryInterceptorWithConfig.
+func TraceIDUnaryInterceptorWithConfig(cfg RequestIDConfig) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		traceID := cfg.traceID(ctx)
+		ctx = domain.ContextWithTraceID(ctx, traceID)
+
+		// A trailer is sent with errors too, so failed calls can be traced as well
+		if err := grpc.SetTrailer(ctx, metadata.Pairs(constants.TraceIDHeader, traceID)); err != nil {
+			// Only fails outside a server call; don't fail the request
+			_ = err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// TraceIDStreamInterceptorWithConfig is TraceIDUnaryInterceptorWithConfig for streaming calls.
+// Chain it after RequestIDStreamInterceptorWithConfig.
+func TraceIDStreamInterceptorWithConfig(cfg RequestIDConfig) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		traceID := cfg.traceID(ss.Context())
+		ss.SetTrailer(metadata.Pairs(constants.TraceIDHeader, traceID))
+
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          domain.ContextWithTraceID(ss.Context(), traceID),
+		})
+	}
+}
+
+// traceID returns the caller's trace ID if c accepts it, and otherwise the request ID
+func (c RequestIDConfig) traceID(ctx context.Context) string {
+	if traceID := firstMetadataValue(ctx, constants.TraceIDHeader); c.accepts(traceID) {
+		return traceID
+	}
+	if requestID := GetRequestID(ctx); requestID != "" {
+		return requestID
+	}
+	return c.resolve("")
+}
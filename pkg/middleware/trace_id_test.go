@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+)
+
+// trailerRecorder is a server transport stream that records the trailer set by interceptors
+type trailerRecorder struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerRecorder) SetHeader(metadata.MD) error { return nil }
+
+func (s *trailerRecorder) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestTraceIDUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/delivery.DeliveryService/GetDeliveryAssignment"}
+
+	var traceID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		traceID = domain.TraceIDFromContext(ctx)
+		return nil, nil
+	}
+	call := func(ctx context.Context) metadata.MD {
+		stream := &trailerRecorder{}
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+		_, err := RequestIDUnaryInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return TraceIDUnaryInterceptor()(ctx, req, info, handler)
+		})
+		require.NoError(t, err)
+		return stream.trailer
+	}
+
+	// Without one from the caller, the request ID is reused
+	trailer := call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1")))
+	assert.Equal(t, "req-1", traceID)
+	assert.Equal(t, []string{"req-1"}, trailer.Get("x-trace-id"))
+
+	// The caller's trace ID is propagated
+	trailer = call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-2", "x-trace-id", "trace-9")))
+	assert.Equal(t, "trace-9", traceID)
+	assert.Equal(t, []string{"trace-9"}, trailer.Get("x-trace-id"))
+
+	// A trace ID the request ID rules reject is replaced with the request ID
+	for _, invalid := range []string{strings.Repeat("a", 200), "trace\nforged"} {
+		trailer = call(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-3", "x-trace-id", invalid)))
+		assert.Equal(t, "req-3", traceID)
+		assert.Equal(t, []string{"req-3"}, trailer.Get("x-trace-id"))
+	}
+}
+
+// recordingServerStream is a server stream that records the header and trailer set on it
+type recordingServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *recordingServerStream) Context() context.Context { return s.ctx }
+
+func (s *recordingServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *recordingServerStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+func TestTraceIDStreamInterceptor(t *testing.T) {
+	cfg := DefaultRequestIDConfig()
+	info := &grpc.StreamServerInfo{FullMethod: "/delivery.DeliveryService/ExportDeliveries", IsServerStream: true}
+
+	var requestID, traceID string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		requestID = GetRequestID(ss.Context())
+		traceID = domain.TraceIDFromContext(ss.Context())
+		return nil
+	}
+	call := func(md metadata.MD) *recordingServerStream {
+		stream := &recordingServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+		err := RequestIDStreamInterceptorWithConfig(cfg)(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return TraceIDStreamInterceptorWithConfig(cfg)(srv, ss, info, handler)
+		})
+		require.NoError(t, err)
+		return stream
+	}
+
+	stream := call(metadata.Pairs("x-request-id", "req-1"))
+	assert.Equal(t, "req-1", requestID)
+	assert.Equal(t, "req-1", traceID)
+	assert.Equal(t, []string{"req-1"}, stream.header.Get("x-request-id"))
+	assert.Equal(t, []string{"req-1"}, stream.trailer.Get("x-trace-id"))
+
+	stream = call(metadata.Pairs("x-request-id", "req-2", "x-trace-id", "trace-9"))
+	assert.Equal(t, "trace-9", traceID)
+	assert.Equal(t, []string{"trace-9"}, stream.trailer.Get("x-trace-id"))
+
+	// Invalid IDs are replaced: the request ID with a generated one, the trace ID with the request ID
+	stream = call(metadata.Pairs("x-request-id", "bad id", "x-trace-id", "bad trace"))
+	assert.NotEqual(t, "bad id", requestID)
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, traceID)
+	assert.Equal(t, []string{requestID}, stream.trailer.Get("x-trace-id"))
+}
+
+func TestHTTPLoggingMiddleware_TraceID(t *testing.T) {
+	var forwarded string
+	handler := HTTPLoggingMiddleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get("X-Trace-ID")
+	}))
+
+	// Without one from the caller, the request ID is reused and left on the request for the gateway
+	req := httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "req-1", rec.Header().Get("X-Trace-ID"))
+	assert.Equal(t, "req-1", forwarded)
+
+	// The caller's trace ID is echoed
+	req = httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil)
+	req.Header.Set("X-Trace-ID", "trace-9")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "trace-9", rec.Header().Get("X-Trace-ID"))
+	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+
+	// An invalid trace ID is neither echoed nor forwarded
+	req = httptest.NewRequest(http.MethodGet, "/v1/deliveries", nil)
+	req.Header.Set("X-Request-ID", "req-2")
+	req.Header.Set("X-Trace-ID", "<script>")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, "req-2", rec.Header().Get("X-Trace-ID"))
+	assert.Equal(t, "req-2", forwarded)
+}
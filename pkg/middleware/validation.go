@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequiredFields maps a full gRPC method name (e.g. "/delivery.DeliveryService/AssignDriver")
+// to the request fields that must be set
+type RequiredFields map[string][]string
+
+// ValidationUnaryInterceptor rejects requests missing any required field before they reach the handler.
+// A field counts as missing when it is unset in protobuf terms: an empty string, zero number,
+// UNSPECIFIED enum or nil message. All missing fields are reported in a single InvalidArgument error.
+func ValidationUnaryInterceptor(required RequiredFields) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		fields, ok := required[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		if missing := MissingFields(msg, fields); len(missing) > 0 {
+			return nil, requiredFieldsError(missing)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// MissingFields returns the names of required fields that are not set on msg, for handlers
+// that must check messages the interceptor does not see, such as streamed ones
+func MissingFields(msg proto.Message, fields []string) []string {
+	m := msg.ProtoReflect()
+	descriptors := m.Descriptor().Fields()
+
+	var missing []string
+	for _, name := range fields {
+		fd := descriptors.ByName(protoreflect.Name(name))
+		if fd == nil || !m.Has(fd) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// requiredFieldsError builds an InvalidArgument status listing every missing field as a BadRequest violation
+func requiredFieldsError(missing []string) error {
+	messages := make([]string, len(missing))
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(missing))
+	for i, field := range missing {
+		messages[i] = fmt.Sprintf("%s is required", field)
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: "is required",
+		}
+	}
+
+	st := status.New(codes.InvalidArgument, strings.Join(messages, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
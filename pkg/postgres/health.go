@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+const (
+	selectServerVersionSQL = `SELECT version()`
+	gormModulePath         = "gorm.io/gorm"
+)
+
+// Health reports the database details served by the health detail endpoint. The server version
+// is cached for versionTTL so that frequent scrapes do not query the database every time.
+type Health struct {
+	db           *sql.DB
+	versionTTL   time.Duration
+	queryTimeout time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	version   string
+	fetchedAt time.Time
+}
+
+// NewHealth creates a Health for db caching the server version for versionTTL (0 disables caching)
+// and giving up on the version query after queryTimeout (0 = no limit beyond the caller's)
+func NewHealth(db *sql.DB, versionTTL, queryTimeout time.Duration) *Health {
+	return &Health{
+		db:           db,
+		versionTTL:   versionTTL,
+		queryTimeout: queryTimeout,
+		now:          time.Now,
+	}
+}
+
+// ServerVersion returns the PostgreSQL server version string, e.g. "PostgreSQL 16.2 on x86_64...".
+// Failures are not cached, so the next call queries again. The lock only guards the cached copy,
+// so a slow database never blocks callers behind the one querying it.
+func (h *Health) ServerVersion(ctx context.Context) (string, error) {
+	h.mu.Lock()
+	cached, fetchedAt := h.version, h.fetchedAt
+	h.mu.Unlock()
+
+	if cached != "" && h.now().Sub(fetchedAt) < h.versionTTL {
+		return cached, nil
+	}
+
+	if h.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.queryTimeout)
+		defer cancel()
+	}
+
+	var version string
+	if err := h.db.QueryRowContext(ctx, selectServerVersionSQL).Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to query server version: %w", err)
+	}
+
+	h.mu.Lock()
+	h.version = version
+	h.fetchedAt = h.now()
+	h.mu.Unlock()
+	return version, nil
+}
+
+// ORMVersion returns the version of GORM built into the binary, or "unknown" without build info
+func (h *Health) ORMVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != gormModulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return "unknown"
+}
+
+// Stats returns the connection pool statistics
+func (h *Health) Stats() sql.DBStats {
+	return h.db.Stats()
+}
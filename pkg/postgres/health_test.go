@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_ServerVersionCached(t *testing.T) {
+	var queries []string
+	sqlDB := sql.OpenDB(versionConnector{version: "PostgreSQL 16.2", queries: &queries})
+	defer sqlDB.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	health := NewHealth(sqlDB, time.Minute, time.Second)
+	health.now = func() time.Time { return now }
+
+	version, err := health.ServerVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "PostgreSQL 16.2", version)
+
+	// Within the TTL the cached version is served
+	now = now.Add(59 * time.Second)
+	version, err = health.ServerVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "PostgreSQL 16.2", version)
+	assert.Equal(t, []string{selectServerVersionSQL}, queries)
+
+	// Once it expires the database is asked again
+	now = now.Add(time.Second)
+	_, err = health.ServerVersion(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, queries, 2)
+}
+
+func TestHealth_ServerVersionTimesOut(t *testing.T) {
+	var queries []string
+	sqlDB := sql.OpenDB(versionConnector{hang: true, queries: &queries})
+	defer sqlDB.Close()
+
+	health := NewHealth(sqlDB, time.Minute, 20*time.Millisecond)
+
+	_, err := health.ServerVersion(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// versionConnector hands out connections answering every query with a single version row, or
+// with hang set, never answering before the query's context ends
+type versionConnector struct {
+	version string
+	hang    bool
+	queries *[]string
+}
+
+func (c versionConnector) Connect(context.Context) (driver.Conn, error) { return versionConn(c), nil }
+
+func (versionConnector) Driver() driver.Driver { return stubDriver{} }
+
+type versionConn versionConnector
+
+func (versionConn) Prepare(string) (driver.Stmt, error) { return stubConn{}.Prepare("") }
+
+func (versionConn) Close() error { return nil }
+
+func (versionConn) Begin() (driver.Tx, error) { return stubConn{}.Begin() }
+
+func (c versionConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	*c.queries = append(*c.queries, query)
+	if c.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &versionRows{version: c.version}, nil
+}
+
+type versionRows struct {
+	version string
+	done    bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version"} }
+
+func (r *versionRows) Close() error { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.version
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/domain"
+	"github.com/mohamadchoker/order-delivery-service/internal/repository/postgres/model"
+)
+
+// models lists every GORM model whose table is managed by Migrate; register new tables here
+func models() []interface{} {
+	return []interface{}{
+		&model.DeliveryAssignment{},
+		&model.DeliveryMetricsDaily{},
+		&model.DriverLocation{},
+		&model.AuditLogEntry{},
+	}
+}
+
+// indexStatements create the indexes GORM tags cannot express. Each must be idempotent and
+// match the SQL migrations, so both ways of building the schema stay interchangeable.
+var indexStatements = []string{
+	// JSONB containment lookups on addresses (see migration 000008)
+	`CREATE INDEX IF NOT EXISTS idx_delivery_assignments_pickup_address_gin
+		ON delivery_assignments USING GIN (pickup_address)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_assignments_delivery_address_gin
+		ON delivery_assignments USING GIN (delivery_address)`,
+	// One active delivery per order (see migrations 000007 and 000015)
+	`CREATE UNIQUE INDEX IF NOT EXISTS uq_delivery_assignments_active_order
+		ON delivery_assignments(order_id)
+		WHERE deleted_at IS NULL
+		  AND NOT allow_concurrent
+		  AND status NOT IN (` + terminalStatusList() + `)`,
+	// Long-waiting PENDING deliveries per priority, for escalation (see migration 000020)
+	`CREATE INDEX IF NOT EXISTS idx_delivery_assignments_pending_priority
+		ON delivery_assignments(priority, created_at)
+		WHERE status = 'PENDING'`,
+}
+
+// terminalStatusList renders domain.TerminalStatuses as a SQL list, e.g. 'DELIVERED', 'FAILED'
+func terminalStatusList() string {
+	quoted := make([]string, 0, len(domain.TerminalStatuses()))
+	for _, status := range domain.TerminalStatuses() {
+		quoted = append(quoted, "'"+string(status)+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Migrate creates or updates the schema from the GORM models and then creates the indexes the
+// models cannot describe. It is meant for development and tests; production schemas are managed
+// with the SQL files in migrations/.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(models()...); err != nil {
+		return fmt.Errorf("failed to auto-migrate models: %w", err)
+	}
+
+	for _, stmt := range indexStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
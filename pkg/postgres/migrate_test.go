@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestMigrate runs against a disposable PostgreSQL database named by TEST_DATABASE_DSN,
+// e.g. "host=localhost user=postgres password=postgres dbname=order_delivery_test sslmode=disable"
+func TestMigrate(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(pgdriver.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = Close(db) })
+
+	require.NoError(t, Migrate(db))
+	// Running again on an up-to-date schema is a no-op
+	require.NoError(t, Migrate(db))
+
+	assert.True(t, db.Migrator().HasTable("delivery_assignments"))
+	assert.True(t, db.Migrator().HasTable("delivery_metrics_daily"))
+	assert.True(t, db.Migrator().HasTable("driver_locations"))
+	for _, index := range []string{
+		"idx_delivery_assignments_pickup_address_gin",
+		"idx_delivery_assignments_delivery_address_gin",
+		"uq_delivery_assignments_active_order",
+	} {
+		assert.True(t, db.Migrator().HasIndex("delivery_assignments", index), index)
+	}
+}
+
+// The active-order index must match migration 000015, which a new terminal status would have to
+// replace with a migration of its own
+func TestTerminalStatusList(t *testing.T) {
+	assert.Equal(t, "'DELIVERED', 'FAILED', 'CANCELED', 'DEAD_LETTER'", terminalStatusList())
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The version table matches golang-migrate's, so migrations applied by the migrate CLI
+// (make migrate-up) and by MigrateUp can be mixed freely
+const (
+	createVersionTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+	selectVersionSQL      = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+)
+
+// migrationLockKey names the advisory lock MigrateUp holds while migrating, so that instances
+// started together (e.g. one init container per replica) apply each migration only once
+const migrationLockKey int64 = 4817230954
+
+// migrationDB is the part of *sql.DB and *sql.Conn the migration helpers use
+type migrationDB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// MigrationFile is a versioned up migration
+type MigrationFile struct {
+	Version uint64
+	Name    string
+}
+
+// MigrationStatus describes the schema version of a database relative to the known migrations
+type MigrationStatus struct {
+	Version uint64 // Last applied migration (0 = none)
+	Dirty   bool   // A migration failed part-way and must be fixed by hand
+	Pending []MigrationFile
+}
+
+// GetMigrationStatus reports the current schema version and the migrations in fsys not yet applied
+func GetMigrationStatus(ctx context.Context, db *sql.DB, fsys fs.FS) (*MigrationStatus, error) {
+	return migrationStatus(ctx, db, fsys)
+}
+
+// migrationStatus implements GetMigrationStatus on a pooled or a single connection
+func migrationStatus(ctx context.Context, db migrationDB, fsys fs.FS) (*MigrationStatus, error) {
+	files, err := listMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := currentVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Version: version, Dirty: dirty}
+	for _, file := range files {
+		if file.Version > version {
+			status.Pending = append(status.Pending, file)
+		}
+	}
+	return status, nil
+}
+
+// MigrateUp applies every pending up migration in fsys in version order and returns those applied.
+// A migration that fails leaves the database dirty at its version, as golang-migrate does.
+// Concurrent calls against the same database wait for each other on a Postgres advisory lock, and
+// each sees the version the previous one left behind.
+func MigrateUp(ctx context.Context, db *sql.DB, fsys fs.FS) (_ []MigrationFile, err error) {
+	// Advisory locks belong to the session, so the lock is taken, used and released on one connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a connection for migrating: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return nil, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	defer func() {
+		_, unlockErr := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+		if unlockErr != nil {
+			// Discard the connection rather than return it to the pool still holding the lock
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			if err == nil {
+				err = fmt.Errorf("failed to release the migration lock: %w", unlockErr)
+			}
+		}
+	}()
+
+	return migrateUp(ctx, conn, fsys)
+}
+
+// migrateUp applies the pending migrations once MigrateUp holds the migration lock
+func migrateUp(ctx context.Context, db migrationDB, fsys fs.FS) ([]MigrationFile, error) {
+	status, err := migrationStatus(ctx, db, fsys)
+	if err != nil {
+		return nil, err
+	}
+	if status.Dirty {
+		return nil, fmt.Errorf("database is dirty at version %d; fix the schema and force the version before migrating", status.Version)
+	}
+
+	var applied []MigrationFile
+	for _, file := range status.Pending {
+		body, err := fs.ReadFile(fsys, file.Name)
+		if err != nil {
+			return applied, fmt.Errorf("failed to read migration %s: %w", file.Name, err)
+		}
+
+		if err := setVersion(ctx, db, file.Version, true); err != nil {
+			return applied, err
+		}
+		if _, err := db.ExecContext(ctx, string(body)); err != nil {
+			return applied, fmt.Errorf("migration %s failed: %w", file.Name, err)
+		}
+		if err := setVersion(ctx, db, file.Version, false); err != nil {
+			return applied, err
+		}
+		applied = append(applied, file)
+	}
+
+	return applied, nil
+}
+
+// listMigrations returns the up migrations in fsys sorted by version
+func listMigrations(fsys fs.FS) ([]MigrationFile, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]MigrationFile, 0, len(names))
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", name, err)
+		}
+		files = append(files, MigrationFile{Version: version, Name: name})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+	return files, nil
+}
+
+// currentVersion reads the applied version, creating the version table on first use
+func currentVersion(ctx context.Context, db migrationDB) (uint64, bool, error) {
+	if _, err := db.ExecContext(ctx, createVersionTableSQL); err != nil {
+		return 0, false, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, selectVersionSQL).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint64(version), dirty, nil
+}
+
+// setVersion replaces the single row of the version table
+func setVersion(ctx context.Context, db migrationDB, version uint64, dirty bool) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE schema_migrations`); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, int64(version), dirty); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return tx.Commit()
+}
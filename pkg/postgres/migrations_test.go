@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateUp_HoldsAdvisoryLock(t *testing.T) {
+	recorder := &recordingConnector{}
+	sqlDB := sql.OpenDB(recorder)
+	defer sqlDB.Close()
+
+	fsys := fstest.MapFS{
+		"000001_init.up.sql":   {Data: []byte("CREATE TABLE one (id int)")},
+		"000001_init.down.sql": {Data: []byte("DROP TABLE one")},
+		"000002_more.up.sql":   {Data: []byte("CREATE TABLE two (id int)")},
+	}
+
+	applied, err := MigrateUp(context.Background(), sqlDB, fsys)
+
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationFile{{Version: 1, Name: "000001_init.up.sql"}, {Version: 2, Name: "000002_more.up.sql"}}, applied)
+
+	statements := recorder.statements()
+	require.NotEmpty(t, statements)
+	assert.Equal(t, "SELECT pg_advisory_lock($1)", statements[0].query)
+	assert.Equal(t, "SELECT pg_advisory_unlock($1)", statements[len(statements)-1].query)
+	assert.Contains(t, statements, recordedStatement{conn: statements[0].conn, query: "CREATE TABLE two (id int)"})
+	for _, statement := range statements {
+		assert.Equal(t, statements[0].conn, statement.conn, "%q runs on the connection holding the lock", statement.query)
+	}
+}
+
+// recordingConnector hands out connections that record their statements and find no schema version
+type recordingConnector struct {
+	mu       sync.Mutex
+	conns    int
+	executed []recordedStatement
+}
+
+type recordedStatement struct {
+	conn  int
+	query string
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conns++
+	return &recordingConn{connector: c, id: c.conns}, nil
+}
+
+func (*recordingConnector) Driver() driver.Driver { return stubDriver{} }
+
+func (c *recordingConnector) record(conn int, query string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.executed = append(c.executed, recordedStatement{conn: conn, query: query})
+}
+
+func (c *recordingConnector) statements() []recordedStatement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]recordedStatement(nil), c.executed...)
+}
+
+type recordingConn struct {
+	connector *recordingConnector
+	id        int
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return stubConn{}.Prepare("") }
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.connector.record(c.id, strings.TrimSpace(query))
+	return driver.RowsAffected(0), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.connector.record(c.id, strings.TrimSpace(query))
+	return emptyRows{}, nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error { return nil }
+
+func (recordingTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string { return []string{"version", "dirty"} }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
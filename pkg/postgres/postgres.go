@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
@@ -52,9 +53,7 @@ func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	}
 
 	// Configure connection pool
-	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
-	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
-	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	configurePool(sqlDB, cfg)
 
 	// Test connection
 	if err := sqlDB.Ping(); err != nil {
@@ -64,6 +63,15 @@ func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// configurePool applies the connection pool settings to sqlDB
+func configurePool(sqlDB *sql.DB, cfg config.DatabaseConfig) {
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	// Drop idle connections before a pooler such as PgBouncer kills them server-side
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
 // Close closes the database connection
 func Close(db *gorm.DB) error {
 	sqlDB, err := db.DB()
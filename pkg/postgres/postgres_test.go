@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mohamadchoker/order-delivery-service/internal/config"
+)
+
+func TestConfigurePool(t *testing.T) {
+	sqlDB := sql.OpenDB(stubConnector{})
+	defer sqlDB.Close()
+
+	configurePool(sqlDB, config.DatabaseConfig{
+		MaxOpenConns:    7,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: 50 * time.Millisecond,
+	})
+
+	assert.Equal(t, 7, sqlDB.Stats().MaxOpenConnections)
+
+	// Check a connection out and back in so it sits idle in the pool
+	conn, err := sqlDB.Conn(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	assert.Equal(t, 1, sqlDB.Stats().Idle)
+
+	// The pool cleaner runs at most once per second
+	assert.Eventually(t, func() bool {
+		return sqlDB.Stats().MaxIdleTimeClosed == 1
+	}, 3*time.Second, 50*time.Millisecond, "idle connection should be closed after ConnMaxIdleTime")
+	assert.Zero(t, sqlDB.Stats().Idle)
+}
+
+// stubConnector hands out connections that never talk to a database
+type stubConnector struct{}
+
+func (stubConnector) Connect(context.Context) (driver.Conn, error) { return stubConn{}, nil }
+
+func (stubConnector) Driver() driver.Driver { return stubDriver{} }
+
+type stubDriver struct{}
+
+func (stubDriver) Open(string) (driver.Conn, error) { return stubConn{}, nil }
+
+type stubConn struct{}
+
+func (stubConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+
+func (stubConn) Close() error { return nil }
+
+func (stubConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
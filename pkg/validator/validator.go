@@ -2,9 +2,12 @@ package validator
 
 import (
 	"fmt"
+	"math"
+	"net/mail"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 var (
@@ -41,6 +44,8 @@ func (e ValidationErrors) HasErrors() bool {
 // Validator provides validation methods
 type Validator struct {
 	errors ValidationErrors
+
+	postalCodeOptional map[string]bool // Upper-cased countries that do not use postal codes
 }
 
 // New creates a new Validator
@@ -50,6 +55,17 @@ func New() *Validator {
 	}
 }
 
+// WithPostalCodeOptional makes the postal code optional in ValidateAddress for addresses in
+// the given countries (compared case-insensitively against Address.Country).
+// A postal code that is given is still format-checked.
+func (v *Validator) WithPostalCodeOptional(countries []string) *Validator {
+	v.postalCodeOptional = make(map[string]bool, len(countries))
+	for _, country := range countries {
+		v.postalCodeOptional[strings.ToUpper(strings.TrimSpace(country))] = true
+	}
+	return v
+}
+
 // AddError adds a validation error
 func (v *Validator) AddError(field, message string) {
 	v.errors = append(v.errors, ValidationError{
@@ -78,9 +94,10 @@ func (v *Validator) ValidateRequired(field, value string) {
 	}
 }
 
-// ValidateStringLength validates string length
+// ValidateStringLength validates string length in characters (runes), so multibyte text is not
+// rejected early
 func (v *Validator) ValidateStringLength(field, value string, min, max int) {
-	length := len(strings.TrimSpace(value))
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
 	if length < min {
 		v.AddError(field, fmt.Sprintf("must be at least %d characters", min))
 	}
@@ -112,11 +129,15 @@ func (v *Validator) ValidateTimeFuture(field string, t time.Time) {
 
 // ValidateTimeRange validates time is within a reasonable range
 func (v *Validator) ValidateTimeRange(field string, t time.Time, minDuration, maxDuration time.Duration) {
+	v.ValidateTimeRangeFrom(field, t, time.Now(), minDuration, maxDuration)
+}
+
+// ValidateTimeRangeFrom validates time is within a reasonable range of the given reference time
+func (v *Validator) ValidateTimeRangeFrom(field string, t, now time.Time, minDuration, maxDuration time.Duration) {
 	if t.IsZero() {
 		return
 	}
 
-	now := time.Now()
 	diff := t.Sub(now)
 
 	if diff < minDuration {
@@ -139,7 +160,9 @@ func (v *Validator) ValidateAddress(fieldPrefix string, street, city, state, pos
 		v.AddError(fieldPrefix+".state", "is required")
 	}
 	if strings.TrimSpace(postalCode) == "" {
-		v.AddError(fieldPrefix+".postal_code", "is required")
+		if !v.postalCodeOptional[strings.ToUpper(strings.TrimSpace(country))] {
+			v.AddError(fieldPrefix+".postal_code", "is required")
+		}
 	} else if !postalCodeRegex.MatchString(strings.ToUpper(postalCode)) {
 		v.AddError(fieldPrefix+".postal_code", "is invalid")
 	}
@@ -147,14 +170,29 @@ func (v *Validator) ValidateAddress(fieldPrefix string, street, city, state, pos
 		v.AddError(fieldPrefix+".country", "is required")
 	}
 
-	// Validate coordinates if provided
-	if latitude != 0 || longitude != 0 {
-		if latitude < -90 || latitude > 90 {
-			v.AddError(fieldPrefix+".latitude", "must be between -90 and 90")
-		}
-		if longitude < -180 || longitude > 180 {
-			v.AddError(fieldPrefix+".longitude", "must be between -180 and 180")
-		}
+	v.ValidateAddressCoordinates(fieldPrefix, latitude, longitude)
+}
+
+// ValidateAddressCoordinates validates an address's coordinates, if provided (both zero means
+// none). A latitude beyond ±90 paired with a longitude within ±90 is reported as swapped.
+func (v *Validator) ValidateAddressCoordinates(fieldPrefix string, latitude, longitude float64) {
+	if latitude == 0 && longitude == 0 {
+		return
+	}
+	if math.Abs(latitude) > 90 && math.Abs(latitude) <= 180 && math.Abs(longitude) <= 90 {
+		v.AddError(fieldPrefix+".latitude", "must be between -90 and 90 (latitude and longitude look swapped)")
+		return
+	}
+	v.ValidateCoordinates(fieldPrefix+".latitude", fieldPrefix+".longitude", latitude, longitude)
+}
+
+// ValidateCoordinates validates a latitude/longitude pair (NaN is rejected)
+func (v *Validator) ValidateCoordinates(latitudeField, longitudeField string, latitude, longitude float64) {
+	if !(latitude >= -90 && latitude <= 90) {
+		v.AddError(latitudeField, "must be between -90 and 90")
+	}
+	if !(longitude >= -180 && longitude <= 180) {
+		v.AddError(longitudeField, "must be between -180 and 180")
 	}
 }
 
@@ -172,6 +210,30 @@ func (v *Validator) ValidateUUID(field, value string) {
 	}
 }
 
+// ValidateTimeZone validates an IANA time zone name (e.g. "America/New_York"); empty means UTC
+func (v *Validator) ValidateTimeZone(field, name string) {
+	if name == "" {
+		return
+	}
+	// "Local" depends on the server's configuration and is never a merchant's zone
+	if name == "Local" {
+		v.AddError(field, "is not a valid IANA time zone")
+		return
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		v.AddError(field, "is not a valid IANA time zone")
+	}
+}
+
+// ValidateEmail validates a bare email address such as "jane@example.com". Display names
+// ("Jane <jane@example.com>") are rejected, as is anything longer than RFC 5321 allows.
+func (v *Validator) ValidateEmail(field, email string) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" || len(email) > 254 {
+		v.AddError(field, "is not a valid email address")
+	}
+}
+
 // ValidateEnum validates if value is in allowed list
 func (v *Validator) ValidateEnum(field string, value interface{}, allowed []interface{}) {
 	for _, a := range allowed {
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddress_PostalCodeOptionalCountries(t *testing.T) {
+	tests := []struct {
+		name       string
+		postalCode string
+		country    string
+		wantError  string
+	}{
+		{name: "optional country without code", postalCode: "", country: "BS"},
+		{name: "optional country matched case-insensitively", postalCode: "", country: " bs "},
+		{name: "optional country with invalid code", postalCode: "!!", country: "BS", wantError: "is invalid"},
+		{name: "other country without code", postalCode: "", country: "US", wantError: "is required"},
+		{name: "other country with code", postalCode: "10001", country: "US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New().WithPostalCodeOptional([]string{"BS", "AG"})
+
+			v.ValidateAddress("pickup_address", "1 Bay St", "Nassau", "New Providence", tt.postalCode, tt.country, 0, 0)
+
+			if tt.wantError == "" {
+				assert.NoError(t, v.Errors())
+				return
+			}
+			assert.Equal(t, ValidationErrors{{Field: "pickup_address.postal_code", Message: tt.wantError}}, v.Errors())
+		})
+	}
+}
+
+func TestValidateAddress_PostalCodeRequiredByDefault(t *testing.T) {
+	v := New()
+
+	v.ValidateAddress("delivery_address", "1 Bay St", "Nassau", "New Providence", "", "BS", 0, 0)
+
+	assert.Equal(t, ValidationErrors{{Field: "delivery_address.postal_code", Message: "is required"}}, v.Errors())
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		valid bool
+	}{
+		{email: "jane@example.com", valid: true},
+		{email: "jane.doe+orders@mail.example.co.uk", valid: true},
+		{email: "", valid: false},
+		{email: "jane", valid: false},
+		{email: "jane@", valid: false},
+		{email: "@example.com", valid: false},
+		{email: "Jane <jane@example.com>", valid: false},
+		{email: " jane@example.com", valid: false},
+		{email: strings.Repeat("a", 250) + "@example.com", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			v := New()
+
+			v.ValidateEmail("recipient_email", tt.email)
+
+			if tt.valid {
+				assert.NoError(t, v.Errors())
+				return
+			}
+			assert.Equal(t, ValidationErrors{{Field: "recipient_email", Message: "is not a valid email address"}}, v.Errors())
+		})
+	}
+}
+
+func TestValidateAddressCoordinates(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		want      ValidationErrors
+	}{
+		{name: "not provided", latitude: 0, longitude: 0},
+		{name: "valid", latitude: 40.7128, longitude: -74.006},
+		{name: "latitude out of range", latitude: -190, longitude: 10, want: ValidationErrors{{Field: "pickup_address.latitude", Message: "must be between -90 and 90"}}},
+		{name: "longitude out of range", latitude: 10, longitude: 181, want: ValidationErrors{{Field: "pickup_address.longitude", Message: "must be between -180 and 180"}}},
+		{
+			name:      "swapped",
+			latitude:  -74.006 * 2, // A longitude-sized value where the latitude belongs
+			longitude: 40.7128,
+			want:      ValidationErrors{{Field: "pickup_address.latitude", Message: "must be between -90 and 90 (latitude and longitude look swapped)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+
+			v.ValidateAddressCoordinates("pickup_address", tt.latitude, tt.longitude)
+
+			if tt.want == nil {
+				assert.NoError(t, v.Errors())
+				return
+			}
+			assert.Equal(t, tt.want, v.Errors())
+		})
+	}
+}
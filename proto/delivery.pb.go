@@ -10,7 +10,9 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -44,6 +46,8 @@ const (
 	DeliveryStatus_FAILED DeliveryStatus = 6
 	// Cancelled - delivery cancelled
 	DeliveryStatus_CANCELLED DeliveryStatus = 7
+	// Dead letter - permanently failed, excluded from retry
+	DeliveryStatus_DEAD_LETTER DeliveryStatus = 8
 )
 
 // Enum value maps for DeliveryStatus.
@@ -57,6 +61,7 @@ var (
 		5: "DELIVERED",
 		6: "FAILED",
 		7: "CANCELLED",
+		8: "DEAD_LETTER",
 	}
 	DeliveryStatus_value = map[string]int32{
 		"UNSPECIFIED": 0,
@@ -67,6 +72,7 @@ var (
 		"DELIVERED":   5,
 		"FAILED":      6,
 		"CANCELLED":   7,
+		"DEAD_LETTER": 8,
 	}
 )
 
@@ -97,6 +103,117 @@ func (DeliveryStatus) EnumDescriptor() ([]byte, []int) {
 	return file_proto_delivery_proto_rawDescGZIP(), []int{0}
 }
 
+// ActivityFilter narrows listings to in-progress or finished deliveries
+type ActivityFilter int32
+
+const (
+	// Any - no activity filtering (default)
+	ActivityFilter_ACTIVITY_ANY ActivityFilter = 0
+	// Active - PENDING, ASSIGNED, PICKED_UP or IN_TRANSIT
+	ActivityFilter_ACTIVITY_ACTIVE ActivityFilter = 1
+	// Terminal - DELIVERED, FAILED, CANCELLED or DEAD_LETTER
+	ActivityFilter_ACTIVITY_TERMINAL ActivityFilter = 2
+	// Dead letter - only permanently-failed deliveries
+	ActivityFilter_ACTIVITY_DEAD_LETTER ActivityFilter = 3
+)
+
+// Enum value maps for ActivityFilter.
+var (
+	ActivityFilter_name = map[int32]string{
+		0: "ACTIVITY_ANY",
+		1: "ACTIVITY_ACTIVE",
+		2: "ACTIVITY_TERMINAL",
+		3: "ACTIVITY_DEAD_LETTER",
+	}
+	ActivityFilter_value = map[string]int32{
+		"ACTIVITY_ANY":         0,
+		"ACTIVITY_ACTIVE":      1,
+		"ACTIVITY_TERMINAL":    2,
+		"ACTIVITY_DEAD_LETTER": 3,
+	}
+)
+
+func (x ActivityFilter) Enum() *ActivityFilter {
+	p := new(ActivityFilter)
+	*p = x
+	return p
+}
+
+func (x ActivityFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActivityFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_delivery_proto_enumTypes[1].Descriptor()
+}
+
+func (ActivityFilter) Type() protoreflect.EnumType {
+	return &file_proto_delivery_proto_enumTypes[1]
+}
+
+func (x ActivityFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActivityFilter.Descriptor instead.
+func (ActivityFilter) EnumDescriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{1}
+}
+
+// DeliveryPriority ranks how urgently a delivery should be dispatched
+type DeliveryPriority int32
+
+const (
+	// Unspecified - NORMAL on create
+	DeliveryPriority_PRIORITY_UNSPECIFIED DeliveryPriority = 0
+	DeliveryPriority_PRIORITY_LOW         DeliveryPriority = 1
+	DeliveryPriority_PRIORITY_NORMAL      DeliveryPriority = 2
+	DeliveryPriority_PRIORITY_HIGH        DeliveryPriority = 3
+)
+
+// Enum value maps for DeliveryPriority.
+var (
+	DeliveryPriority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_NORMAL",
+		3: "PRIORITY_HIGH",
+	}
+	DeliveryPriority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_LOW":         1,
+		"PRIORITY_NORMAL":      2,
+		"PRIORITY_HIGH":        3,
+	}
+)
+
+func (x DeliveryPriority) Enum() *DeliveryPriority {
+	p := new(DeliveryPriority)
+	*p = x
+	return p
+}
+
+func (x DeliveryPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeliveryPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_delivery_proto_enumTypes[2].Descriptor()
+}
+
+func (DeliveryPriority) Type() protoreflect.EnumType {
+	return &file_proto_delivery_proto_enumTypes[2]
+}
+
+func (x DeliveryPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeliveryPriority.Descriptor instead.
+func (DeliveryPriority) EnumDescriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{2}
+}
+
 // Address represents a physical address
 type Address struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -206,8 +323,52 @@ type DeliveryAssignment struct {
 	Notes                 string                 `protobuf:"bytes,11,opt,name=notes,proto3" json:"notes,omitempty"`
 	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt             *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	Rating                int32                  `protobuf:"varint,14,opt,name=rating,proto3" json:"rating,omitempty"`
+	Feedback              string                 `protobuf:"bytes,15,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	// Append-only notes in the order they were added
+	TimelineNotes []*TimelineNote `protobuf:"bytes,16,rep,name=timeline_notes,json=timelineNotes,proto3" json:"timeline_notes,omitempty"`
+	// Set when the delivery was moved to DEAD_LETTER
+	DeadLetterReason string `protobuf:"bytes,17,opt,name=dead_letter_reason,json=deadLetterReason,proto3" json:"dead_letter_reason,omitempty"`
+	// Merchant's IANA time zone; empty means UTC
+	TimeZone string `protobuf:"bytes,18,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Scheduled and estimated times in time_zone, as RFC 3339 with offset (display only)
+	ScheduledPickupTimeLocal   string `protobuf:"bytes,19,opt,name=scheduled_pickup_time_local,json=scheduledPickupTimeLocal,proto3" json:"scheduled_pickup_time_local,omitempty"`
+	EstimatedDeliveryTimeLocal string `protobuf:"bytes,20,opt,name=estimated_delivery_time_local,json=estimatedDeliveryTimeLocal,proto3" json:"estimated_delivery_time_local,omitempty"`
+	// Customer-facing range around estimated_delivery_time; unset when there is no window
+	EstimatedDeliveryWindowStart *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=estimated_delivery_window_start,json=estimatedDeliveryWindowStart,proto3" json:"estimated_delivery_window_start,omitempty"`
+	EstimatedDeliveryWindowEnd   *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=estimated_delivery_window_end,json=estimatedDeliveryWindowEnd,proto3" json:"estimated_delivery_window_end,omitempty"`
+	// Whether this delivery may be active alongside another delivery for the same order
+	AllowConcurrent bool `protobuf:"varint,23,opt,name=allow_concurrent,json=allowConcurrent,proto3" json:"allow_concurrent,omitempty"`
+	// Pickup confirmation code for the sender; only returned by create and clone
+	PickupCode string `protobuf:"bytes,24,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	// Vehicle type the driver must have (e.g. REFRIGERATED); empty means any
+	RequiredVehicleType string `protobuf:"bytes,25,opt,name=required_vehicle_type,json=requiredVehicleType,proto3" json:"required_vehicle_type,omitempty"`
+	// Set when the delivery was deleted; only returned by ListModifiedDeliveries
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,26,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Lower-case ops labels (e.g. "vip"), in the order they were added
+	Tags []string `protobuf:"bytes,27,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Packages carried, when known; set on deliveries created by SplitDelivery
+	Packages []*Package `protobuf:"bytes,28,rep,name=packages,proto3" json:"packages,omitempty"`
+	// Delivery this one was split or reattempted from; empty otherwise
+	ParentDeliveryId string `protobuf:"bytes,29,opt,name=parent_delivery_id,json=parentDeliveryId,proto3" json:"parent_delivery_id,omitempty"`
+	// Why the delivery failed (e.g. WRONG_ADDRESS); empty unless it failed
+	FailureReasonCode string `protobuf:"bytes,30,opt,name=failure_reason_code,json=failureReasonCode,proto3" json:"failure_reason_code,omitempty"`
+	// Email address the recipient is notified at; empty when they are not emailed
+	RecipientEmail string `protobuf:"bytes,31,opt,name=recipient_email,json=recipientEmail,proto3" json:"recipient_email,omitempty"`
+	// Statuses the recipient is notified of; empty means every status
+	NotifyPrefs []DeliveryStatus `protobuf:"varint,32,rep,packed,name=notify_prefs,json=notifyPrefs,proto3,enum=delivery.DeliveryStatus" json:"notify_prefs,omitempty"`
+	// 1 for the first attempt; ReattemptDelivery increments it on the new delivery
+	AttemptNumber int32 `protobuf:"varint,33,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`
+	// Driver's latest arrival estimate from UpdateDriverETA; unset until they send one
+	DriverEta *timestamppb.Timestamp `protobuf:"bytes,34,opt,name=driver_eta,json=driverEta,proto3" json:"driver_eta,omitempty"`
+	// Dispatch priority; raised over time while the delivery waits in PENDING
+	Priority DeliveryPriority `protobuf:"varint,35,opt,name=priority,proto3,enum=delivery.DeliveryPriority" json:"priority,omitempty"`
+	// Driver who takes over if the primary driver is unassigned; empty when there is none
+	BackupDriverId string `protobuf:"bytes,36,opt,name=backup_driver_id,json=backupDriverId,proto3" json:"backup_driver_id,omitempty"`
+	// Tenant-defined details, such as a gate code; unset when there are none
+	Metadata      *structpb.Struct `protobuf:"bytes,37,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeliveryAssignment) Reset() {
@@ -331,223 +492,198 @@ func (x *DeliveryAssignment) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// CreateDeliveryAssignmentRequest creates a new delivery assignment
-type CreateDeliveryAssignmentRequest struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	OrderId               string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	PickupAddress         *Address               `protobuf:"bytes,2,opt,name=pickup_address,json=pickupAddress,proto3" json:"pickup_address,omitempty"`
-	DeliveryAddress       *Address               `protobuf:"bytes,3,opt,name=delivery_address,json=deliveryAddress,proto3" json:"delivery_address,omitempty"`
-	ScheduledPickupTime   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_pickup_time,json=scheduledPickupTime,proto3" json:"scheduled_pickup_time,omitempty"`
-	EstimatedDeliveryTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=estimated_delivery_time,json=estimatedDeliveryTime,proto3" json:"estimated_delivery_time,omitempty"`
-	Notes                 string                 `protobuf:"bytes,6,opt,name=notes,proto3" json:"notes,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+func (x *DeliveryAssignment) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
 }
 
-func (x *CreateDeliveryAssignmentRequest) Reset() {
-	*x = CreateDeliveryAssignmentRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DeliveryAssignment) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
 }
 
-func (x *CreateDeliveryAssignmentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DeliveryAssignment) GetTimelineNotes() []*TimelineNote {
+	if x != nil {
+		return x.TimelineNotes
+	}
+	return nil
 }
 
-func (*CreateDeliveryAssignmentRequest) ProtoMessage() {}
-
-func (x *CreateDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[2]
+func (x *DeliveryAssignment) GetDeadLetterReason() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.DeadLetterReason
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use CreateDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
-func (*CreateDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{2}
+func (x *DeliveryAssignment) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetOrderId() string {
+func (x *DeliveryAssignment) GetScheduledPickupTimeLocal() string {
 	if x != nil {
-		return x.OrderId
+		return x.ScheduledPickupTimeLocal
 	}
 	return ""
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetPickupAddress() *Address {
+func (x *DeliveryAssignment) GetEstimatedDeliveryTimeLocal() string {
 	if x != nil {
-		return x.PickupAddress
+		return x.EstimatedDeliveryTimeLocal
 	}
-	return nil
+	return ""
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetDeliveryAddress() *Address {
+func (x *DeliveryAssignment) GetEstimatedDeliveryWindowStart() *timestamppb.Timestamp {
 	if x != nil {
-		return x.DeliveryAddress
+		return x.EstimatedDeliveryWindowStart
 	}
 	return nil
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetScheduledPickupTime() *timestamppb.Timestamp {
+func (x *DeliveryAssignment) GetEstimatedDeliveryWindowEnd() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ScheduledPickupTime
+		return x.EstimatedDeliveryWindowEnd
 	}
 	return nil
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetEstimatedDeliveryTime() *timestamppb.Timestamp {
+func (x *DeliveryAssignment) GetAllowConcurrent() bool {
 	if x != nil {
-		return x.EstimatedDeliveryTime
+		return x.AllowConcurrent
 	}
-	return nil
+	return false
 }
 
-func (x *CreateDeliveryAssignmentRequest) GetNotes() string {
+func (x *DeliveryAssignment) GetPickupCode() string {
 	if x != nil {
-		return x.Notes
+		return x.PickupCode
 	}
 	return ""
 }
 
-// GetDeliveryAssignmentRequest retrieves a delivery assignment
-type GetDeliveryAssignmentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetDeliveryAssignmentRequest) Reset() {
-	*x = GetDeliveryAssignmentRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DeliveryAssignment) GetRequiredVehicleType() string {
+	if x != nil {
+		return x.RequiredVehicleType
+	}
+	return ""
 }
 
-func (x *GetDeliveryAssignmentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DeliveryAssignment) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
 }
 
-func (*GetDeliveryAssignmentRequest) ProtoMessage() {}
-
-func (x *GetDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[3]
+func (x *DeliveryAssignment) GetTags() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Tags
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GetDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
-func (*GetDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{3}
+func (x *DeliveryAssignment) GetPackages() []*Package {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
 }
 
-func (x *GetDeliveryAssignmentRequest) GetId() string {
+func (x *DeliveryAssignment) GetParentDeliveryId() string {
 	if x != nil {
-		return x.Id
+		return x.ParentDeliveryId
 	}
 	return ""
 }
 
-// UpdateDeliveryStatusRequest updates delivery status
-type UpdateDeliveryStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Status        DeliveryStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
-	Notes         string                 `protobuf:"bytes,3,opt,name=notes,proto3" json:"notes,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DeliveryAssignment) GetFailureReasonCode() string {
+	if x != nil {
+		return x.FailureReasonCode
+	}
+	return ""
 }
 
-func (x *UpdateDeliveryStatusRequest) Reset() {
-	*x = UpdateDeliveryStatusRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DeliveryAssignment) GetRecipientEmail() string {
+	if x != nil {
+		return x.RecipientEmail
+	}
+	return ""
 }
 
-func (x *UpdateDeliveryStatusRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DeliveryAssignment) GetNotifyPrefs() []DeliveryStatus {
+	if x != nil {
+		return x.NotifyPrefs
+	}
+	return nil
 }
 
-func (*UpdateDeliveryStatusRequest) ProtoMessage() {}
-
-func (x *UpdateDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[4]
+func (x *DeliveryAssignment) GetAttemptNumber() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.AttemptNumber
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use UpdateDeliveryStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateDeliveryStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{4}
+func (x *DeliveryAssignment) GetDriverEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DriverEta
+	}
+	return nil
 }
 
-func (x *UpdateDeliveryStatusRequest) GetId() string {
+func (x *DeliveryAssignment) GetPriority() DeliveryPriority {
 	if x != nil {
-		return x.Id
+		return x.Priority
 	}
-	return ""
+	return DeliveryPriority_PRIORITY_UNSPECIFIED
 }
 
-func (x *UpdateDeliveryStatusRequest) GetStatus() DeliveryStatus {
+func (x *DeliveryAssignment) GetBackupDriverId() string {
 	if x != nil {
-		return x.Status
+		return x.BackupDriverId
 	}
-	return DeliveryStatus_UNSPECIFIED
+	return ""
 }
 
-func (x *UpdateDeliveryStatusRequest) GetNotes() string {
+func (x *DeliveryAssignment) GetMetadata() *structpb.Struct {
 	if x != nil {
-		return x.Notes
+		return x.Metadata
 	}
-	return ""
+	return nil
 }
 
-// ListDeliveryAssignmentsRequest lists delivery assignments
-type ListDeliveryAssignmentsRequest struct {
+// Package is one item carried by a delivery
+type Package struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	Status        DeliveryStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
-	DriverId      string                 `protobuf:"bytes,4,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDeliveryAssignmentsRequest) Reset() {
-	*x = ListDeliveryAssignmentsRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[5]
+func (x *Package) Reset() {
+	*x = Package{}
+	mi := &file_proto_delivery_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDeliveryAssignmentsRequest) String() string {
+func (x *Package) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDeliveryAssignmentsRequest) ProtoMessage() {}
+func (*Package) ProtoMessage() {}
 
-func (x *ListDeliveryAssignmentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[5]
+func (x *Package) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -558,65 +694,49 @@ func (x *ListDeliveryAssignmentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDeliveryAssignmentsRequest.ProtoReflect.Descriptor instead.
-func (*ListDeliveryAssignmentsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Package.ProtoReflect.Descriptor instead.
+func (*Package) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ListDeliveryAssignmentsRequest) GetPage() int32 {
+func (x *Package) GetDescription() string {
 	if x != nil {
-		return x.Page
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-func (x *ListDeliveryAssignmentsRequest) GetPageSize() int32 {
+func (x *Package) GetQuantity() int32 {
 	if x != nil {
-		return x.PageSize
+		return x.Quantity
 	}
 	return 0
 }
 
-func (x *ListDeliveryAssignmentsRequest) GetStatus() DeliveryStatus {
-	if x != nil {
-		return x.Status
-	}
-	return DeliveryStatus_UNSPECIFIED
-}
-
-func (x *ListDeliveryAssignmentsRequest) GetDriverId() string {
-	if x != nil {
-		return x.DriverId
-	}
-	return ""
-}
-
-// ListDeliveryAssignmentsResponse returns paginated delivery assignments
-type ListDeliveryAssignmentsResponse struct {
+// TimelineNote is a timestamped note left during a delivery
+type TimelineNote struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Assignments   []*DeliveryAssignment  `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Note          string                 `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDeliveryAssignmentsResponse) Reset() {
-	*x = ListDeliveryAssignmentsResponse{}
-	mi := &file_proto_delivery_proto_msgTypes[6]
+func (x *TimelineNote) Reset() {
+	*x = TimelineNote{}
+	mi := &file_proto_delivery_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDeliveryAssignmentsResponse) String() string {
+func (x *TimelineNote) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDeliveryAssignmentsResponse) ProtoMessage() {}
+func (*TimelineNote) ProtoMessage() {}
 
-func (x *ListDeliveryAssignmentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[6]
+func (x *TimelineNote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -627,63 +747,3333 @@ func (x *ListDeliveryAssignmentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDeliveryAssignmentsResponse.ProtoReflect.Descriptor instead.
-func (*ListDeliveryAssignmentsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use TimelineNote.ProtoReflect.Descriptor instead.
+func (*TimelineNote) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TimelineNote) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *TimelineNote) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreateDeliveryAssignmentRequest creates a new delivery assignment
+type CreateDeliveryAssignmentRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	OrderId               string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PickupAddress         *Address               `protobuf:"bytes,2,opt,name=pickup_address,json=pickupAddress,proto3" json:"pickup_address,omitempty"`
+	DeliveryAddress       *Address               `protobuf:"bytes,3,opt,name=delivery_address,json=deliveryAddress,proto3" json:"delivery_address,omitempty"`
+	ScheduledPickupTime   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_pickup_time,json=scheduledPickupTime,proto3" json:"scheduled_pickup_time,omitempty"`
+	EstimatedDeliveryTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=estimated_delivery_time,json=estimatedDeliveryTime,proto3" json:"estimated_delivery_time,omitempty"`
+	Notes                 string                 `protobuf:"bytes,6,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Merchant's IANA time zone (e.g. "America/New_York") used to render local times
+	TimeZone string `protobuf:"bytes,7,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Optional delivery window; set both or neither. Derived from the estimate when omitted.
+	EstimatedDeliveryWindowStart *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=estimated_delivery_window_start,json=estimatedDeliveryWindowStart,proto3" json:"estimated_delivery_window_start,omitempty"`
+	EstimatedDeliveryWindowEnd   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=estimated_delivery_window_end,json=estimatedDeliveryWindowEnd,proto3" json:"estimated_delivery_window_end,omitempty"`
+	// Allow this delivery to be active alongside another active delivery for the order.
+	// Without it, creating a second active delivery fails with ALREADY_EXISTS.
+	AllowConcurrent bool `protobuf:"varint,10,opt,name=allow_concurrent,json=allowConcurrent,proto3" json:"allow_concurrent,omitempty"`
+	// Optional vehicle type the driver must have; one of the configured types (e.g. BIKE, CAR, VAN, REFRIGERATED)
+	RequiredVehicleType string `protobuf:"bytes,11,opt,name=required_vehicle_type,json=requiredVehicleType,proto3" json:"required_vehicle_type,omitempty"`
+	// Optional ops labels (e.g. "vip", "promo"); trimmed, lower-cased and de-duplicated
+	Tags []string `protobuf:"bytes,12,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Optional email address to notify the recipient at on status changes
+	RecipientEmail string `protobuf:"bytes,13,opt,name=recipient_email,json=recipientEmail,proto3" json:"recipient_email,omitempty"`
+	// Optional statuses the recipient wants to be notified of; empty means every status
+	NotifyPrefs []DeliveryStatus `protobuf:"varint,14,rep,packed,name=notify_prefs,json=notifyPrefs,proto3,enum=delivery.DeliveryStatus" json:"notify_prefs,omitempty"`
+	// Optional dispatch priority; unspecified means NORMAL
+	Priority DeliveryPriority `protobuf:"varint,15,opt,name=priority,proto3,enum=delivery.DeliveryPriority" json:"priority,omitempty"`
+	// Optional tenant-defined details, such as a gate code. When the caller's tenant has a metadata
+	// schema, metadata must match it or the request fails with INVALID_ARGUMENT naming the key.
+	Metadata      *structpb.Struct `protobuf:"bytes,16,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeliveryAssignmentRequest) Reset() {
+	*x = CreateDeliveryAssignmentRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeliveryAssignmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeliveryAssignmentRequest) ProtoMessage() {}
+
+func (x *CreateDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetPickupAddress() *Address {
+	if x != nil {
+		return x.PickupAddress
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetDeliveryAddress() *Address {
+	if x != nil {
+		return x.DeliveryAddress
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetScheduledPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledPickupTime
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetEstimatedDeliveryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDeliveryTime
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetEstimatedDeliveryWindowStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDeliveryWindowStart
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetEstimatedDeliveryWindowEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDeliveryWindowEnd
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetAllowConcurrent() bool {
+	if x != nil {
+		return x.AllowConcurrent
+	}
+	return false
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetRequiredVehicleType() string {
+	if x != nil {
+		return x.RequiredVehicleType
+	}
+	return ""
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetRecipientEmail() string {
+	if x != nil {
+		return x.RecipientEmail
+	}
+	return ""
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetNotifyPrefs() []DeliveryStatus {
+	if x != nil {
+		return x.NotifyPrefs
+	}
+	return nil
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetPriority() DeliveryPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return DeliveryPriority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateDeliveryAssignmentRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// ImportDeliveriesRequest is a chunk of rows to import
+type ImportDeliveriesRequest struct {
+	state protoimpl.MessageState             `protogen:"open.v1"`
+	Rows  []*CreateDeliveryAssignmentRequest `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	// Accept pickups in the past, to backfill history; the server must enable
+	// DELIVERY_ALLOW_PAST_SCHEDULING
+	AllowPastScheduling bool `protobuf:"varint,2,opt,name=allow_past_scheduling,json=allowPastScheduling,proto3" json:"allow_past_scheduling,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ImportDeliveriesRequest) Reset() {
+	*x = ImportDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDeliveriesRequest) ProtoMessage() {}
+
+func (x *ImportDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ImportDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ImportDeliveriesRequest) GetRows() []*CreateDeliveryAssignmentRequest {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *ImportDeliveriesRequest) GetAllowPastScheduling() bool {
+	if x != nil {
+		return x.AllowPastScheduling
+	}
+	return false
+}
+
+// ImportRowError reports why a row was not imported
+type ImportRowError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1-based position of the row across the whole stream
+	Row           int32  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowError) Reset() {
+	*x = ImportRowError{}
+	mi := &file_proto_delivery_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowError) ProtoMessage() {}
+
+func (x *ImportRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowError.ProtoReflect.Descriptor instead.
+func (*ImportRowError) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ImportRowError) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ImportedDelivery is a row that was imported
+type ImportedDelivery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1-based position of the row across the whole stream
+	Row int32  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Id  string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Code the driver must present at pickup; only returned here and on creation
+	PickupCode    string `protobuf:"bytes,3,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportedDelivery) Reset() {
+	*x = ImportedDelivery{}
+	mi := &file_proto_delivery_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportedDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedDelivery) ProtoMessage() {}
+
+func (x *ImportedDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedDelivery.ProtoReflect.Descriptor instead.
+func (*ImportedDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImportedDelivery) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportedDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportedDelivery) GetPickupCode() string {
+	if x != nil {
+		return x.PickupCode
+	}
+	return ""
+}
+
+// ImportDeliveriesResponse summarizes an import
+type ImportDeliveriesResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Succeeded int32                  `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed    int32                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	Errors    []*ImportRowError      `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Imported rows in row order
+	Created       []*ImportedDelivery `protobuf:"bytes,4,rep,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportDeliveriesResponse) Reset() {
+	*x = ImportDeliveriesResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDeliveriesResponse) ProtoMessage() {}
+
+func (x *ImportDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ImportDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ImportDeliveriesResponse) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *ImportDeliveriesResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ImportDeliveriesResponse) GetErrors() []*ImportRowError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportDeliveriesResponse) GetCreated() []*ImportedDelivery {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+// GetDeliveryAssignmentRequest retrieves a delivery assignment
+type GetDeliveryAssignmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryAssignmentRequest) Reset() {
+	*x = GetDeliveryAssignmentRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryAssignmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryAssignmentRequest) ProtoMessage() {}
+
+func (x *GetDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetDeliveryAssignmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// UpdateDeliveryStatusRequest updates delivery status
+type UpdateDeliveryStatusRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status DeliveryStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	Notes  string                 `protobuf:"bytes,3,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Required when status is PICKED_UP: the code returned to the sender at creation
+	PickupCode string `protobuf:"bytes,4,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	// Required when status is FAILED: one of the configured failure reason codes (e.g. WRONG_ADDRESS);
+	// put the details in notes
+	FailureReasonCode string `protobuf:"bytes,5,opt,name=failure_reason_code,json=failureReasonCode,proto3" json:"failure_reason_code,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UpdateDeliveryStatusRequest) Reset() {
+	*x = UpdateDeliveryStatusRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDeliveryStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeliveryStatusRequest) ProtoMessage() {}
+
+func (x *UpdateDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateDeliveryStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateDeliveryStatusRequest) GetStatus() DeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryStatus_UNSPECIFIED
+}
+
+func (x *UpdateDeliveryStatusRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateDeliveryStatusRequest) GetPickupCode() string {
+	if x != nil {
+		return x.PickupCode
+	}
+	return ""
+}
+
+func (x *UpdateDeliveryStatusRequest) GetFailureReasonCode() string {
+	if x != nil {
+		return x.FailureReasonCode
+	}
+	return ""
+}
+
+// ListDeliveryAssignmentsRequest lists delivery assignments
+type ListDeliveryAssignmentsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Page     int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Status   DeliveryStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	DriverId string                 `protobuf:"bytes,4,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// Combined with status by intersection
+	Activity ActivityFilter `protobuf:"varint,5,opt,name=activity,proto3,enum=delivery.ActivityFilter" json:"activity,omitempty"`
+	// Only deliveries requiring this vehicle type
+	RequiredVehicleType string `protobuf:"bytes,6,opt,name=required_vehicle_type,json=requiredVehicleType,proto3" json:"required_vehicle_type,omitempty"`
+	// Only deliveries carrying this tag (case-insensitive)
+	Tag string `protobuf:"bytes,7,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Also count the matching deliveries per status, from the same snapshot as the page
+	IncludeStatusBreakdown bool `protobuf:"varint,8,opt,name=include_status_breakdown,json=includeStatusBreakdown,proto3" json:"include_status_breakdown,omitempty"`
+	// Only deliveries with (true) or without (false) a driver; unset means either
+	HasDriver     *bool `protobuf:"varint,9,opt,name=has_driver,json=hasDriver,proto3,oneof" json:"has_driver,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveryAssignmentsRequest) Reset() {
+	*x = ListDeliveryAssignmentsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveryAssignmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryAssignmentsRequest) ProtoMessage() {}
+
+func (x *ListDeliveryAssignmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryAssignmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeliveryAssignmentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetStatus() DeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryStatus_UNSPECIFIED
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetActivity() ActivityFilter {
+	if x != nil {
+		return x.Activity
+	}
+	return ActivityFilter_ACTIVITY_ANY
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetRequiredVehicleType() string {
+	if x != nil {
+		return x.RequiredVehicleType
+	}
+	return ""
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetIncludeStatusBreakdown() bool {
+	if x != nil {
+		return x.IncludeStatusBreakdown
+	}
+	return false
+}
+
+func (x *ListDeliveryAssignmentsRequest) GetHasDriver() bool {
+	if x != nil && x.HasDriver != nil {
+		return *x.HasDriver
+	}
+	return false
+}
+
+// ListDeliveryAssignmentsResponse returns paginated delivery assignments
+type ListDeliveryAssignmentsResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Assignments []*DeliveryAssignment  `protobuf:"bytes,1,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	TotalCount  int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Page        int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize    int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Matching deliveries per status, keyed by DeliveryStatus name; only with include_status_breakdown
+	StatusBreakdown map[string]int64 `protobuf:"bytes,5,rep,name=status_breakdown,json=statusBreakdown,proto3" json:"status_breakdown,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Set when counting the matches exactly took too long (DB_COUNT_TIMEOUT) and total_count is
+	// the database's estimate instead
+	TotalCountIsEstimate bool `protobuf:"varint,6,opt,name=total_count_is_estimate,json=totalCountIsEstimate,proto3" json:"total_count_is_estimate,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ListDeliveryAssignmentsResponse) Reset() {
+	*x = ListDeliveryAssignmentsResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveryAssignmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryAssignmentsResponse) ProtoMessage() {}
+
+func (x *ListDeliveryAssignmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryAssignmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeliveryAssignmentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListDeliveryAssignmentsResponse) GetAssignments() []*DeliveryAssignment {
 	if x != nil {
-		return x.Assignments
+		return x.Assignments
+	}
+	return nil
+}
+
+func (x *ListDeliveryAssignmentsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListDeliveryAssignmentsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDeliveryAssignmentsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeliveryAssignmentsResponse) GetStatusBreakdown() map[string]int64 {
+	if x != nil {
+		return x.StatusBreakdown
+	}
+	return nil
+}
+
+func (x *ListDeliveryAssignmentsResponse) GetTotalCountIsEstimate() bool {
+	if x != nil {
+		return x.TotalCountIsEstimate
+	}
+	return false
+}
+
+// AssignDriverRequest assigns a driver to delivery
+type AssignDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignDriverRequest) Reset() {
+	*x = AssignDriverRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignDriverRequest) ProtoMessage() {}
+
+func (x *AssignDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignDriverRequest.ProtoReflect.Descriptor instead.
+func (*AssignDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AssignDriverRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AssignDriverRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+// AssignBackupDriverRequest assigns a backup driver to a delivery
+type AssignBackupDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignBackupDriverRequest) Reset() {
+	*x = AssignBackupDriverRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignBackupDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignBackupDriverRequest) ProtoMessage() {}
+
+func (x *AssignBackupDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignBackupDriverRequest.ProtoReflect.Descriptor instead.
+func (*AssignBackupDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AssignBackupDriverRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AssignBackupDriverRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+// GetDeliveryMetricsRequest retrieves delivery metrics
+type GetDeliveryMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	DriverId      string                 `protobuf:"bytes,3,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryMetricsRequest) Reset() {
+	*x = GetDeliveryMetricsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryMetricsRequest) ProtoMessage() {}
+
+func (x *GetDeliveryMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetDeliveryMetricsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetDeliveryMetricsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetDeliveryMetricsRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+// DeliveryMetrics contains aggregated delivery statistics
+type DeliveryMetrics struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	TotalDeliveries            int32                  `protobuf:"varint,1,opt,name=total_deliveries,json=totalDeliveries,proto3" json:"total_deliveries,omitempty"`
+	CompletedDeliveries        int32                  `protobuf:"varint,2,opt,name=completed_deliveries,json=completedDeliveries,proto3" json:"completed_deliveries,omitempty"`
+	FailedDeliveries           int32                  `protobuf:"varint,3,opt,name=failed_deliveries,json=failedDeliveries,proto3" json:"failed_deliveries,omitempty"`
+	CancelledDeliveries        int32                  `protobuf:"varint,4,opt,name=cancelled_deliveries,json=cancelledDeliveries,proto3" json:"cancelled_deliveries,omitempty"`
+	AverageDeliveryTimeMinutes float64                `protobuf:"fixed64,5,opt,name=average_delivery_time_minutes,json=averageDeliveryTimeMinutes,proto3" json:"average_delivery_time_minutes,omitempty"`
+	OnTimeDeliveryRate         float64                `protobuf:"fixed64,6,opt,name=on_time_delivery_rate,json=onTimeDeliveryRate,proto3" json:"on_time_delivery_rate,omitempty"`
+	AverageRating              float64                `protobuf:"fixed64,7,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	DeadLetterDeliveries       int32                  `protobuf:"varint,9,opt,name=dead_letter_deliveries,json=deadLetterDeliveries,proto3" json:"dead_letter_deliveries,omitempty"`
+	// Average time from creation to delivery, for delivered assignments
+	AverageLifecycleDurationMinutes float64 `protobuf:"fixed64,10,opt,name=average_lifecycle_duration_minutes,json=averageLifecycleDurationMinutes,proto3" json:"average_lifecycle_duration_minutes,omitempty"`
+	// Sub-metrics that could not be computed; their values are reported as zero
+	Errors []string `protobuf:"bytes,8,rep,name=errors,proto3" json:"errors,omitempty"`
+	// FAILED and DEAD_LETTER deliveries per failure reason code
+	FailuresByReason map[string]int32 `protobuf:"bytes,11,rep,name=failures_by_reason,json=failuresByReason,proto3" json:"failures_by_reason,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeliveryMetrics) Reset() {
+	*x = DeliveryMetrics{}
+	mi := &file_proto_delivery_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryMetrics) ProtoMessage() {}
+
+func (x *DeliveryMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryMetrics.ProtoReflect.Descriptor instead.
+func (*DeliveryMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeliveryMetrics) GetTotalDeliveries() int32 {
+	if x != nil {
+		return x.TotalDeliveries
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetCompletedDeliveries() int32 {
+	if x != nil {
+		return x.CompletedDeliveries
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetFailedDeliveries() int32 {
+	if x != nil {
+		return x.FailedDeliveries
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetCancelledDeliveries() int32 {
+	if x != nil {
+		return x.CancelledDeliveries
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetAverageDeliveryTimeMinutes() float64 {
+	if x != nil {
+		return x.AverageDeliveryTimeMinutes
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetOnTimeDeliveryRate() float64 {
+	if x != nil {
+		return x.OnTimeDeliveryRate
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetDeadLetterDeliveries() int32 {
+	if x != nil {
+		return x.DeadLetterDeliveries
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetAverageLifecycleDurationMinutes() float64 {
+	if x != nil {
+		return x.AverageLifecycleDurationMinutes
+	}
+	return 0
+}
+
+func (x *DeliveryMetrics) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *DeliveryMetrics) GetFailuresByReason() map[string]int32 {
+	if x != nil {
+		return x.FailuresByReason
+	}
+	return nil
+}
+
+// GetDriverLeaderboardRequest retrieves the top drivers for a time range
+type GetDriverLeaderboardRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Number of drivers to return (default 10, max 100)
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverLeaderboardRequest) Reset() {
+	*x = GetDriverLeaderboardRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverLeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverLeaderboardRequest) ProtoMessage() {}
+
+func (x *GetDriverLeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverLeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDriverLeaderboardRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetDriverLeaderboardRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetDriverLeaderboardRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// DriverStats is a driver's leaderboard entry
+type DriverStats struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Rank                int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	DriverId            string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	CompletedDeliveries int32                  `protobuf:"varint,3,opt,name=completed_deliveries,json=completedDeliveries,proto3" json:"completed_deliveries,omitempty"`
+	OnTimeDeliveryRate  float64                `protobuf:"fixed64,4,opt,name=on_time_delivery_rate,json=onTimeDeliveryRate,proto3" json:"on_time_delivery_rate,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DriverStats) Reset() {
+	*x = DriverStats{}
+	mi := &file_proto_delivery_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverStats) ProtoMessage() {}
+
+func (x *DriverStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverStats.ProtoReflect.Descriptor instead.
+func (*DriverStats) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DriverStats) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *DriverStats) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *DriverStats) GetCompletedDeliveries() int32 {
+	if x != nil {
+		return x.CompletedDeliveries
+	}
+	return 0
+}
+
+func (x *DriverStats) GetOnTimeDeliveryRate() float64 {
+	if x != nil {
+		return x.OnTimeDeliveryRate
+	}
+	return 0
+}
+
+// DriverLeaderboard lists drivers from best to worst
+type DriverLeaderboard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Drivers       []*DriverStats         `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverLeaderboard) Reset() {
+	*x = DriverLeaderboard{}
+	mi := &file_proto_delivery_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverLeaderboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverLeaderboard) ProtoMessage() {}
+
+func (x *DriverLeaderboard) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverLeaderboard.ProtoReflect.Descriptor instead.
+func (*DriverLeaderboard) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DriverLeaderboard) GetDrivers() []*DriverStats {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+// ListActiveDriverIDsRequest lists drivers that currently have active work
+type ListActiveDriverIDsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveDriverIDsRequest) Reset() {
+	*x = ListActiveDriverIDsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveDriverIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveDriverIDsRequest) ProtoMessage() {}
+
+func (x *ListActiveDriverIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveDriverIDsRequest.ProtoReflect.Descriptor instead.
+func (*ListActiveDriverIDsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{20}
+}
+
+// ListActiveDriverIDsResponse contains active driver IDs in ascending order
+type ListActiveDriverIDsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverIds     []string               `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveDriverIDsResponse) Reset() {
+	*x = ListActiveDriverIDsResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveDriverIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveDriverIDsResponse) ProtoMessage() {}
+
+func (x *ListActiveDriverIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveDriverIDsResponse.ProtoReflect.Descriptor instead.
+func (*ListActiveDriverIDsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListActiveDriverIDsResponse) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+// ListServedCitiesRequest lists the cities with pickups or deliveries
+type ListServedCitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServedCitiesRequest) Reset() {
+	*x = ListServedCitiesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServedCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServedCitiesRequest) ProtoMessage() {}
+
+func (x *ListServedCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServedCitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListServedCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{22}
+}
+
+// ListServedCitiesResponse contains served cities in ascending order
+type ListServedCitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cities        []string               `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServedCitiesResponse) Reset() {
+	*x = ListServedCitiesResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServedCitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServedCitiesResponse) ProtoMessage() {}
+
+func (x *ListServedCitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServedCitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListServedCitiesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListServedCitiesResponse) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+type DeleteDeliveryAssignmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeliveryAssignmentRequest) Reset() {
+	*x = DeleteDeliveryAssignmentRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeliveryAssignmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeliveryAssignmentRequest) ProtoMessage() {}
+
+func (x *DeleteDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteDeliveryAssignmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DeleteDeliveriesByOrderRequest deletes every delivery for an order
+type DeleteDeliveriesByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeliveriesByOrderRequest) Reset() {
+	*x = DeleteDeliveriesByOrderRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeliveriesByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeliveriesByOrderRequest) ProtoMessage() {}
+
+func (x *DeleteDeliveriesByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeliveriesByOrderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeliveriesByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeleteDeliveriesByOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+// DeleteDeliveriesByOrderResponse reports how many deliveries were deleted (0 on a retry)
+type DeleteDeliveriesByOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedCount  int64                  `protobuf:"varint,1,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeliveriesByOrderResponse) Reset() {
+	*x = DeleteDeliveriesByOrderResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeliveriesByOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeliveriesByOrderResponse) ProtoMessage() {}
+
+func (x *DeleteDeliveriesByOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeliveriesByOrderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDeliveriesByOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteDeliveriesByOrderResponse) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+// BulkDeleteDeliveriesRequest selects deliveries like ListDeliveryAssignmentsRequest; at least one filter is required
+type BulkDeleteDeliveriesRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Status              DeliveryStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	DriverId            string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Activity            ActivityFilter         `protobuf:"varint,3,opt,name=activity,proto3,enum=delivery.ActivityFilter" json:"activity,omitempty"`
+	RequiredVehicleType string                 `protobuf:"bytes,4,opt,name=required_vehicle_type,json=requiredVehicleType,proto3" json:"required_vehicle_type,omitempty"`
+	Tag                 string                 `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	HasDriver           *bool                  `protobuf:"varint,6,opt,name=has_driver,json=hasDriver,proto3,oneof" json:"has_driver,omitempty"`
+	// Only deliveries created before this time
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Token from a previous call with the same filters; empty only previews the matches
+	ConfirmToken  string `protobuf:"bytes,8,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkDeleteDeliveriesRequest) Reset() {
+	*x = BulkDeleteDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkDeleteDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkDeleteDeliveriesRequest) ProtoMessage() {}
+
+func (x *BulkDeleteDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkDeleteDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*BulkDeleteDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetStatus() DeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryStatus_UNSPECIFIED
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetActivity() ActivityFilter {
+	if x != nil {
+		return x.Activity
+	}
+	return ActivityFilter_ACTIVITY_ANY
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetRequiredVehicleType() string {
+	if x != nil {
+		return x.RequiredVehicleType
+	}
+	return ""
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetHasDriver() bool {
+	if x != nil && x.HasDriver != nil {
+		return *x.HasDriver
+	}
+	return false
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *BulkDeleteDeliveriesRequest) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+// BulkDeleteDeliveriesResponse reports the matches and, once confirmed, how many were deleted
+type BulkDeleteDeliveriesResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MatchedCount int64                  `protobuf:"varint,1,opt,name=matched_count,json=matchedCount,proto3" json:"matched_count,omitempty"`
+	// Echo back to delete the matched deliveries; invalid once the filters or the count change
+	ConfirmToken  string `protobuf:"bytes,2,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+	DeletedCount  int64  `protobuf:"varint,3,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkDeleteDeliveriesResponse) Reset() {
+	*x = BulkDeleteDeliveriesResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkDeleteDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkDeleteDeliveriesResponse) ProtoMessage() {}
+
+func (x *BulkDeleteDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkDeleteDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*BulkDeleteDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *BulkDeleteDeliveriesResponse) GetMatchedCount() int64 {
+	if x != nil {
+		return x.MatchedCount
+	}
+	return 0
+}
+
+func (x *BulkDeleteDeliveriesResponse) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+func (x *BulkDeleteDeliveriesResponse) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+// CheckDeliveryFeasibilityRequest describes a prospective delivery; both addresses need coordinates
+type CheckDeliveryFeasibilityRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	PickupAddress         *Address               `protobuf:"bytes,1,opt,name=pickup_address,json=pickupAddress,proto3" json:"pickup_address,omitempty"`
+	DeliveryAddress       *Address               `protobuf:"bytes,2,opt,name=delivery_address,json=deliveryAddress,proto3" json:"delivery_address,omitempty"`
+	ScheduledPickupTime   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=scheduled_pickup_time,json=scheduledPickupTime,proto3" json:"scheduled_pickup_time,omitempty"`
+	EstimatedDeliveryTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=estimated_delivery_time,json=estimatedDeliveryTime,proto3" json:"estimated_delivery_time,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CheckDeliveryFeasibilityRequest) Reset() {
+	*x = CheckDeliveryFeasibilityRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckDeliveryFeasibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckDeliveryFeasibilityRequest) ProtoMessage() {}
+
+func (x *CheckDeliveryFeasibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckDeliveryFeasibilityRequest.ProtoReflect.Descriptor instead.
+func (*CheckDeliveryFeasibilityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CheckDeliveryFeasibilityRequest) GetPickupAddress() *Address {
+	if x != nil {
+		return x.PickupAddress
+	}
+	return nil
+}
+
+func (x *CheckDeliveryFeasibilityRequest) GetDeliveryAddress() *Address {
+	if x != nil {
+		return x.DeliveryAddress
+	}
+	return nil
+}
+
+func (x *CheckDeliveryFeasibilityRequest) GetScheduledPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledPickupTime
+	}
+	return nil
+}
+
+func (x *CheckDeliveryFeasibilityRequest) GetEstimatedDeliveryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDeliveryTime
+	}
+	return nil
+}
+
+// CheckDeliveryFeasibilityResponse reports the estimate and why the delivery is infeasible, if it is
+type CheckDeliveryFeasibilityResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Feasible bool                   `protobuf:"varint,1,opt,name=feasible,proto3" json:"feasible,omitempty"`
+	// Straight-line distance between pickup and delivery
+	DistanceKm float64 `protobuf:"fixed64,2,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// Travel time at the server's configured average speed
+	RequiredDuration *durationpb.Duration `protobuf:"bytes,3,opt,name=required_duration,json=requiredDuration,proto3" json:"required_duration,omitempty"`
+	// Time between scheduled pickup and estimated delivery
+	AvailableDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=available_duration,json=availableDuration,proto3" json:"available_duration,omitempty"`
+	// One entry per failed check; empty when feasible
+	Reasons       []string `protobuf:"bytes,5,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckDeliveryFeasibilityResponse) Reset() {
+	*x = CheckDeliveryFeasibilityResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckDeliveryFeasibilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckDeliveryFeasibilityResponse) ProtoMessage() {}
+
+func (x *CheckDeliveryFeasibilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckDeliveryFeasibilityResponse.ProtoReflect.Descriptor instead.
+func (*CheckDeliveryFeasibilityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CheckDeliveryFeasibilityResponse) GetFeasible() bool {
+	if x != nil {
+		return x.Feasible
+	}
+	return false
+}
+
+func (x *CheckDeliveryFeasibilityResponse) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *CheckDeliveryFeasibilityResponse) GetRequiredDuration() *durationpb.Duration {
+	if x != nil {
+		return x.RequiredDuration
+	}
+	return nil
+}
+
+func (x *CheckDeliveryFeasibilityResponse) GetAvailableDuration() *durationpb.Duration {
+	if x != nil {
+		return x.AvailableDuration
+	}
+	return nil
+}
+
+func (x *CheckDeliveryFeasibilityResponse) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+// ExportDeliveriesRequest streams all delivery assignments
+type ExportDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDeliveriesRequest) Reset() {
+	*x = ExportDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDeliveriesRequest) ProtoMessage() {}
+
+func (x *ExportDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ExportDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{31}
+}
+
+// SubmitFeedbackRequest records a rating (1-5) and optional feedback for a delivery
+type SubmitFeedbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rating        int32                  `protobuf:"varint,2,opt,name=rating,proto3" json:"rating,omitempty"`
+	Feedback      string                 `protobuf:"bytes,3,opt,name=feedback,proto3" json:"feedback,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitFeedbackRequest) Reset() {
+	*x = SubmitFeedbackRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitFeedbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitFeedbackRequest) ProtoMessage() {}
+
+func (x *SubmitFeedbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitFeedbackRequest.ProtoReflect.Descriptor instead.
+func (*SubmitFeedbackRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SubmitFeedbackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SubmitFeedbackRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *SubmitFeedbackRequest) GetFeedback() string {
+	if x != nil {
+		return x.Feedback
+	}
+	return ""
+}
+
+// BatchGetDeliveriesRequest lists the delivery IDs to fetch
+type BatchGetDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetDeliveriesRequest) Reset() {
+	*x = BatchGetDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetDeliveriesRequest) ProtoMessage() {}
+
+func (x *BatchGetDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchGetDeliveriesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// BatchGetDeliveriesResponse contains the deliveries that were found; unknown IDs are omitted
+type BatchGetDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*DeliveryAssignment  `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetDeliveriesResponse) Reset() {
+	*x = BatchGetDeliveriesResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetDeliveriesResponse) ProtoMessage() {}
+
+func (x *BatchGetDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BatchGetDeliveriesResponse) GetDeliveries() []*DeliveryAssignment {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// AppendNoteRequest adds a note to a delivery's timeline
+type AppendNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendNoteRequest) Reset() {
+	*x = AppendNoteRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendNoteRequest) ProtoMessage() {}
+
+func (x *AppendNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendNoteRequest.ProtoReflect.Descriptor instead.
+func (*AppendNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AppendNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppendNoteRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// UpdateDriverETARequest sets a picked-up delivery's driver ETA; it must be in the future
+type UpdateDriverETARequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverEta     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=driver_eta,json=driverEta,proto3" json:"driver_eta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDriverETARequest) Reset() {
+	*x = UpdateDriverETARequest{}
+	mi := &file_proto_delivery_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDriverETARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDriverETARequest) ProtoMessage() {}
+
+func (x *UpdateDriverETARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDriverETARequest.ProtoReflect.Descriptor instead.
+func (*UpdateDriverETARequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UpdateDriverETARequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateDriverETARequest) GetDriverEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DriverEta
+	}
+	return nil
+}
+
+// CloneDeliveryAssignmentRequest clones a delivery for a recurring run
+type CloneDeliveryAssignmentRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ScheduledPickupTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_pickup_time,json=scheduledPickupTime,proto3" json:"scheduled_pickup_time,omitempty"`
+	EstimatedDeliveryTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=estimated_delivery_time,json=estimatedDeliveryTime,proto3" json:"estimated_delivery_time,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CloneDeliveryAssignmentRequest) Reset() {
+	*x = CloneDeliveryAssignmentRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneDeliveryAssignmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneDeliveryAssignmentRequest) ProtoMessage() {}
+
+func (x *CloneDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
+func (*CloneDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CloneDeliveryAssignmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CloneDeliveryAssignmentRequest) GetScheduledPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledPickupTime
+	}
+	return nil
+}
+
+func (x *CloneDeliveryAssignmentRequest) GetEstimatedDeliveryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDeliveryTime
+	}
+	return nil
+}
+
+// GetDeliveryStatusRequest retrieves the current status of a delivery
+type GetDeliveryStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatusRequest) Reset() {
+	*x = GetDeliveryStatusRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatusRequest) ProtoMessage() {}
+
+func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetDeliveryStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DeliveryStatusInfo is the status-only view of a delivery
+type DeliveryStatusInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        DeliveryStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryStatusInfo) Reset() {
+	*x = DeliveryStatusInfo{}
+	mi := &file_proto_delivery_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryStatusInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryStatusInfo) ProtoMessage() {}
+
+func (x *DeliveryStatusInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryStatusInfo.ProtoReflect.Descriptor instead.
+func (*DeliveryStatusInfo) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeliveryStatusInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliveryStatusInfo) GetStatus() DeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryStatus_UNSPECIFIED
+}
+
+func (x *DeliveryStatusInfo) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// GetSLADeadlineRequest retrieves the SLA deadline of a delivery
+type GetSLADeadlineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSLADeadlineRequest) Reset() {
+	*x = GetSLADeadlineRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSLADeadlineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSLADeadlineRequest) ProtoMessage() {}
+
+func (x *GetSLADeadlineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSLADeadlineRequest.ProtoReflect.Descriptor instead.
+func (*GetSLADeadlineRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetSLADeadlineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DeliverySLA is a delivery's SLA deadline as of the time of the request
+type DeliverySLA struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Deadline *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Time left until the deadline; zero once it has passed
+	Remaining     *durationpb.Duration `protobuf:"bytes,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliverySLA) Reset() {
+	*x = DeliverySLA{}
+	mi := &file_proto_delivery_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverySLA) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverySLA) ProtoMessage() {}
+
+func (x *DeliverySLA) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverySLA.ProtoReflect.Descriptor instead.
+func (*DeliverySLA) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeliverySLA) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliverySLA) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *DeliverySLA) GetRemaining() *durationpb.Duration {
+	if x != nil {
+		return x.Remaining
+	}
+	return nil
+}
+
+// MarkDeadLetterRequest dead-letters a failed delivery with a reason
+type MarkDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkDeadLetterRequest) Reset() {
+	*x = MarkDeadLetterRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkDeadLetterRequest) ProtoMessage() {}
+
+func (x *MarkDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*MarkDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *MarkDeadLetterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MarkDeadLetterRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ListModifiedDeliveriesRequest asks for deliveries changed after cursor or, on the first call,
+// after since (unset means from the beginning)
+type ListModifiedDeliveriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	// Max deliveries to return; defaults to 100, at most LIMITS_MAX_SYNC_BATCH (500)
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous response; takes precedence over since
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModifiedDeliveriesRequest) Reset() {
+	*x = ListModifiedDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModifiedDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModifiedDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListModifiedDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModifiedDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListModifiedDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListModifiedDeliveriesRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListModifiedDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListModifiedDeliveriesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// ListModifiedDeliveriesResponse contains changed deliveries, oldest change first
+type ListModifiedDeliveriesResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries []*DeliveryAssignment  `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	// Change time of the last delivery returned; equals the starting position when nothing changed.
+	// Deliveries changed at this same instant may still follow, so resume with next_cursor instead.
+	Watermark *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=watermark,proto3" json:"watermark,omitempty"`
+	// Opaque position after the last delivery returned; pass as cursor on the next call
+	NextCursor    string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModifiedDeliveriesResponse) Reset() {
+	*x = ListModifiedDeliveriesResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModifiedDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModifiedDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListModifiedDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModifiedDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListModifiedDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListModifiedDeliveriesResponse) GetDeliveries() []*DeliveryAssignment {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *ListModifiedDeliveriesResponse) GetWatermark() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Watermark
+	}
+	return nil
+}
+
+func (x *ListModifiedDeliveriesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// SplitDeliveryRequest lists the packages that could not be delivered
+type SplitDeliveryRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RemainingPackages []*Package             `protobuf:"bytes,2,rep,name=remaining_packages,json=remainingPackages,proto3" json:"remaining_packages,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SplitDeliveryRequest) Reset() {
+	*x = SplitDeliveryRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitDeliveryRequest) ProtoMessage() {}
+
+func (x *SplitDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*SplitDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SplitDeliveryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SplitDeliveryRequest) GetRemainingPackages() []*Package {
+	if x != nil {
+		return x.RemainingPackages
+	}
+	return nil
+}
+
+// SplitDeliveryResponse contains the delivered parent and the child created for the remaining packages
+type SplitDeliveryResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Parent *DeliveryAssignment    `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	// Includes the child's pickup_code
+	Child         *DeliveryAssignment `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitDeliveryResponse) Reset() {
+	*x = SplitDeliveryResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitDeliveryResponse) ProtoMessage() {}
+
+func (x *SplitDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*SplitDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SplitDeliveryResponse) GetParent() *DeliveryAssignment {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+func (x *SplitDeliveryResponse) GetChild() *DeliveryAssignment {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+// ReplayEventsRequest names the delivery whose events are re-sent
+type ReplayEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayEventsRequest) Reset() {
+	*x = ReplayEventsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayEventsRequest) ProtoMessage() {}
+
+func (x *ReplayEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayEventsRequest.ProtoReflect.Descriptor instead.
+func (*ReplayEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ReplayEventsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ReplayEventsResponse reports how many events were re-sent
+type ReplayEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replayed      int32                  `protobuf:"varint,1,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayEventsResponse) Reset() {
+	*x = ReplayEventsResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayEventsResponse) ProtoMessage() {}
+
+func (x *ReplayEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayEventsResponse.ProtoReflect.Descriptor instead.
+func (*ReplayEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ReplayEventsResponse) GetReplayed() int32 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+// ReattemptDeliveryRequest names the failed delivery to try again
+type ReattemptDeliveryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReattemptDeliveryRequest) Reset() {
+	*x = ReattemptDeliveryRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReattemptDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReattemptDeliveryRequest) ProtoMessage() {}
+
+func (x *ReattemptDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReattemptDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ReattemptDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ReattemptDeliveryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// AddTagsRequest adds tags to a delivery; they are trimmed and lower-cased
+type AddTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTagsRequest) Reset() {
+	*x = AddTagsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTagsRequest) ProtoMessage() {}
+
+func (x *AddTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTagsRequest.ProtoReflect.Descriptor instead.
+func (*AddTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *AddTagsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// RemoveTagsRequest removes tags from a delivery
+type RemoveTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTagsRequest) Reset() {
+	*x = RemoveTagsRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTagsRequest) ProtoMessage() {}
+
+func (x *RemoveTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTagsRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RemoveTagsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RemoveTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// GetMetricsForDriversRequest lists the drivers to compute metrics for over a time range
+type GetMetricsForDriversRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most LIMITS_MAX_DRIVER_METRICS (50) distinct drivers
+	DriverIds     []string               `protobuf:"bytes,1,rep,name=driver_ids,json=driverIds,proto3" json:"driver_ids,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsForDriversRequest) Reset() {
+	*x = GetMetricsForDriversRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsForDriversRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsForDriversRequest) ProtoMessage() {}
+
+func (x *GetMetricsForDriversRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsForDriversRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsForDriversRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetMetricsForDriversRequest) GetDriverIds() []string {
+	if x != nil {
+		return x.DriverIds
+	}
+	return nil
+}
+
+func (x *GetMetricsForDriversRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetMetricsForDriversRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+// GetMetricsForDriversResponse contains metrics keyed by driver ID; every requested driver is present
+type GetMetricsForDriversResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Metrics       map[string]*DeliveryMetrics `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsForDriversResponse) Reset() {
+	*x = GetMetricsForDriversResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsForDriversResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsForDriversResponse) ProtoMessage() {}
+
+func (x *GetMetricsForDriversResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsForDriversResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsForDriversResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetMetricsForDriversResponse) GetMetrics() map[string]*DeliveryMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+// GetStatusTransitionGraphRequest retrieves the delivery status state machine
+type GetStatusTransitionGraphRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusTransitionGraphRequest) Reset() {
+	*x = GetStatusTransitionGraphRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusTransitionGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusTransitionGraphRequest) ProtoMessage() {}
+
+func (x *GetStatusTransitionGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusTransitionGraphRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusTransitionGraphRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{54}
+}
+
+// StatusTransitions lists the statuses a delivery may move to from status; empty for final statuses
+type StatusTransitions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        DeliveryStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	AllowedNext   []DeliveryStatus       `protobuf:"varint,2,rep,packed,name=allowed_next,json=allowedNext,proto3,enum=delivery.DeliveryStatus" json:"allowed_next,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusTransitions) Reset() {
+	*x = StatusTransitions{}
+	mi := &file_proto_delivery_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusTransitions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusTransitions) ProtoMessage() {}
+
+func (x *StatusTransitions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusTransitions.ProtoReflect.Descriptor instead.
+func (*StatusTransitions) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *StatusTransitions) GetStatus() DeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryStatus_UNSPECIFIED
+}
+
+func (x *StatusTransitions) GetAllowedNext() []DeliveryStatus {
+	if x != nil {
+		return x.AllowedNext
+	}
+	return nil
+}
+
+// StatusTransitionGraph contains one entry per status
+type StatusTransitionGraph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*StatusTransitions   `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusTransitionGraph) Reset() {
+	*x = StatusTransitionGraph{}
+	mi := &file_proto_delivery_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusTransitionGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusTransitionGraph) ProtoMessage() {}
+
+func (x *StatusTransitionGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusTransitionGraph.ProtoReflect.Descriptor instead.
+func (*StatusTransitionGraph) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *StatusTransitionGraph) GetStatuses() []*StatusTransitions {
+	if x != nil {
+		return x.Statuses
 	}
 	return nil
 }
 
-func (x *ListDeliveryAssignmentsResponse) GetTotalCount() int32 {
+// GetServerInfoRequest retrieves build information about the running server
+type GetServerInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerInfoRequest) Reset() {
+	*x = GetServerInfoRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerInfoRequest) ProtoMessage() {}
+
+func (x *GetServerInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[57]
 	if x != nil {
-		return x.TotalCount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ListDeliveryAssignmentsResponse) GetPage() int32 {
+// Deprecated: Use GetServerInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetServerInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{57}
+}
+
+// ServerInfo describes the running server build
+type ServerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	BuildDate     string                 `protobuf:"bytes,2,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"`
+	GitCommit     string                 `protobuf:"bytes,3,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Uptime        *durationpb.Duration   `protobuf:"bytes,5,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerInfo) Reset() {
+	*x = ServerInfo{}
+	mi := &file_proto_delivery_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfo) ProtoMessage() {}
+
+func (x *ServerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[58]
 	if x != nil {
-		return x.Page
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ListDeliveryAssignmentsResponse) GetPageSize() int32 {
+// Deprecated: Use ServerInfo.ProtoReflect.Descriptor instead.
+func (*ServerInfo) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ServerInfo) GetVersion() string {
 	if x != nil {
-		return x.PageSize
+		return x.Version
 	}
-	return 0
+	return ""
 }
 
-// AssignDriverRequest assigns a driver to delivery
-type AssignDriverRequest struct {
+func (x *ServerInfo) GetBuildDate() string {
+	if x != nil {
+		return x.BuildDate
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *ServerInfo) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ServerInfo) GetUptime() *durationpb.Duration {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+// DriverLocation is a location ping from a driver's device
+type DriverLocation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`                     // -90 to 90
+	Longitude     float64                `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`                   // -180 to 180
+	RecordedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"` // When the device took the fix; defaults to receipt time
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssignDriverRequest) Reset() {
-	*x = AssignDriverRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[7]
+func (x *DriverLocation) Reset() {
+	*x = DriverLocation{}
+	mi := &file_proto_delivery_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignDriverRequest) String() string {
+func (x *DriverLocation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignDriverRequest) ProtoMessage() {}
+func (*DriverLocation) ProtoMessage() {}
 
-func (x *AssignDriverRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[7]
+func (x *DriverLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -694,50 +4084,63 @@ func (x *AssignDriverRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignDriverRequest.ProtoReflect.Descriptor instead.
-func (*AssignDriverRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DriverLocation.ProtoReflect.Descriptor instead.
+func (*DriverLocation) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *AssignDriverRequest) GetId() string {
+func (x *DriverLocation) GetDriverId() string {
 	if x != nil {
-		return x.Id
+		return x.DriverId
 	}
 	return ""
 }
 
-func (x *AssignDriverRequest) GetDriverId() string {
+func (x *DriverLocation) GetLatitude() float64 {
 	if x != nil {
-		return x.DriverId
+		return x.Latitude
 	}
-	return ""
+	return 0
 }
 
-// GetDeliveryMetricsRequest retrieves delivery metrics
-type GetDeliveryMetricsRequest struct {
+func (x *DriverLocation) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *DriverLocation) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+// StreamDriverLocationResponse summarizes an ingested location stream
+type StreamDriverLocationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
-	DriverId      string                 `protobuf:"bytes,3,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Accepted      int32                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected      int32                  `protobuf:"varint,2,opt,name=rejected,proto3" json:"rejected,omitempty"` // Pings skipped for invalid driver ID, coordinates or timestamp
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDeliveryMetricsRequest) Reset() {
-	*x = GetDeliveryMetricsRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[8]
+func (x *StreamDriverLocationResponse) Reset() {
+	*x = StreamDriverLocationResponse{}
+	mi := &file_proto_delivery_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDeliveryMetricsRequest) String() string {
+func (x *StreamDriverLocationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDeliveryMetricsRequest) ProtoMessage() {}
+func (*StreamDriverLocationResponse) ProtoMessage() {}
 
-func (x *GetDeliveryMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[8]
+func (x *StreamDriverLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -748,60 +4151,102 @@ func (x *GetDeliveryMetricsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDeliveryMetricsRequest.ProtoReflect.Descriptor instead.
-func (*GetDeliveryMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use StreamDriverLocationResponse.ProtoReflect.Descriptor instead.
+func (*StreamDriverLocationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *GetDeliveryMetricsRequest) GetStartTime() *timestamppb.Timestamp {
+func (x *StreamDriverLocationResponse) GetAccepted() int32 {
 	if x != nil {
-		return x.StartTime
+		return x.Accepted
 	}
-	return nil
+	return 0
 }
 
-func (x *GetDeliveryMetricsRequest) GetEndTime() *timestamppb.Timestamp {
+func (x *StreamDriverLocationResponse) GetRejected() int32 {
 	if x != nil {
-		return x.EndTime
+		return x.Rejected
 	}
-	return nil
+	return 0
 }
 
-func (x *GetDeliveryMetricsRequest) GetDriverId() string {
+// WatchDriverDeliveriesRequest selects the driver whose deliveries are watched
+type WatchDriverDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDriverDeliveriesRequest) Reset() {
+	*x = WatchDriverDeliveriesRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDriverDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDriverDeliveriesRequest) ProtoMessage() {}
+
+func (x *WatchDriverDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDriverDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*WatchDriverDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *WatchDriverDeliveriesRequest) GetDriverId() string {
 	if x != nil {
 		return x.DriverId
 	}
 	return ""
 }
 
-// DeliveryMetrics contains aggregated delivery statistics
-type DeliveryMetrics struct {
-	state                      protoimpl.MessageState `protogen:"open.v1"`
-	TotalDeliveries            int32                  `protobuf:"varint,1,opt,name=total_deliveries,json=totalDeliveries,proto3" json:"total_deliveries,omitempty"`
-	CompletedDeliveries        int32                  `protobuf:"varint,2,opt,name=completed_deliveries,json=completedDeliveries,proto3" json:"completed_deliveries,omitempty"`
-	FailedDeliveries           int32                  `protobuf:"varint,3,opt,name=failed_deliveries,json=failedDeliveries,proto3" json:"failed_deliveries,omitempty"`
-	CancelledDeliveries        int32                  `protobuf:"varint,4,opt,name=cancelled_deliveries,json=cancelledDeliveries,proto3" json:"cancelled_deliveries,omitempty"`
-	AverageDeliveryTimeMinutes float64                `protobuf:"fixed64,5,opt,name=average_delivery_time_minutes,json=averageDeliveryTimeMinutes,proto3" json:"average_delivery_time_minutes,omitempty"`
-	OnTimeDeliveryRate         float64                `protobuf:"fixed64,6,opt,name=on_time_delivery_rate,json=onTimeDeliveryRate,proto3" json:"on_time_delivery_rate,omitempty"`
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+// DeliveryStatusEvent is a saved status change of a delivery
+type DeliveryStatusEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId string                 `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	OrderId    string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status     DeliveryStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=delivery.DeliveryStatus" json:"status,omitempty"`
+	// Driver now assigned; empty or another driver means the delivery was taken away from the watched one
+	DriverId  string                 `protobuf:"bytes,4,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	ChangedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	// Driver's latest arrival estimate, when they sent one
+	DriverEta *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=driver_eta,json=driverEta,proto3" json:"driver_eta,omitempty"`
+	// Set when only the driver ETA changed; status is the current, unchanged status
+	EtaUpdate     bool `protobuf:"varint,7,opt,name=eta_update,json=etaUpdate,proto3" json:"eta_update,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliveryMetrics) Reset() {
-	*x = DeliveryMetrics{}
-	mi := &file_proto_delivery_proto_msgTypes[9]
+func (x *DeliveryStatusEvent) Reset() {
+	*x = DeliveryStatusEvent{}
+	mi := &file_proto_delivery_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliveryMetrics) String() string {
+func (x *DeliveryStatusEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeliveryMetrics) ProtoMessage() {}
+func (*DeliveryStatusEvent) ProtoMessage() {}
 
-func (x *DeliveryMetrics) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[9]
+func (x *DeliveryStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -812,75 +4257,83 @@ func (x *DeliveryMetrics) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeliveryMetrics.ProtoReflect.Descriptor instead.
-func (*DeliveryMetrics) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use DeliveryStatusEvent.ProtoReflect.Descriptor instead.
+func (*DeliveryStatusEvent) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *DeliveryMetrics) GetTotalDeliveries() int32 {
+func (x *DeliveryStatusEvent) GetDeliveryId() string {
 	if x != nil {
-		return x.TotalDeliveries
+		return x.DeliveryId
 	}
-	return 0
+	return ""
 }
 
-func (x *DeliveryMetrics) GetCompletedDeliveries() int32 {
+func (x *DeliveryStatusEvent) GetOrderId() string {
 	if x != nil {
-		return x.CompletedDeliveries
+		return x.OrderId
 	}
-	return 0
+	return ""
 }
 
-func (x *DeliveryMetrics) GetFailedDeliveries() int32 {
+func (x *DeliveryStatusEvent) GetStatus() DeliveryStatus {
 	if x != nil {
-		return x.FailedDeliveries
+		return x.Status
 	}
-	return 0
+	return DeliveryStatus_UNSPECIFIED
 }
 
-func (x *DeliveryMetrics) GetCancelledDeliveries() int32 {
+func (x *DeliveryStatusEvent) GetDriverId() string {
 	if x != nil {
-		return x.CancelledDeliveries
+		return x.DriverId
 	}
-	return 0
+	return ""
 }
 
-func (x *DeliveryMetrics) GetAverageDeliveryTimeMinutes() float64 {
+func (x *DeliveryStatusEvent) GetChangedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.AverageDeliveryTimeMinutes
+		return x.ChangedAt
 	}
-	return 0
+	return nil
 }
 
-func (x *DeliveryMetrics) GetOnTimeDeliveryRate() float64 {
+func (x *DeliveryStatusEvent) GetDriverEta() *timestamppb.Timestamp {
 	if x != nil {
-		return x.OnTimeDeliveryRate
+		return x.DriverEta
 	}
-	return 0
+	return nil
 }
 
-type DeleteDeliveryAssignmentRequest struct {
+func (x *DeliveryStatusEvent) GetEtaUpdate() bool {
+	if x != nil {
+		return x.EtaUpdate
+	}
+	return false
+}
+
+// GetDriverLocationRequest retrieves a driver's latest location
+type GetDriverLocationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteDeliveryAssignmentRequest) Reset() {
-	*x = DeleteDeliveryAssignmentRequest{}
-	mi := &file_proto_delivery_proto_msgTypes[10]
+func (x *GetDriverLocationRequest) Reset() {
+	*x = GetDriverLocationRequest{}
+	mi := &file_proto_delivery_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteDeliveryAssignmentRequest) String() string {
+func (x *GetDriverLocationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteDeliveryAssignmentRequest) ProtoMessage() {}
+func (*GetDriverLocationRequest) ProtoMessage() {}
 
-func (x *DeleteDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_delivery_proto_msgTypes[10]
+func (x *GetDriverLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_delivery_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -891,14 +4344,14 @@ func (x *DeleteDeliveryAssignmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteDeliveryAssignmentRequest.ProtoReflect.Descriptor instead.
-func (*DeleteDeliveryAssignmentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_delivery_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetDriverLocationRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverLocationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_delivery_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *DeleteDeliveryAssignmentRequest) GetId() string {
+func (x *GetDriverLocationRequest) GetDriverId() string {
 	if x != nil {
-		return x.Id
+		return x.DriverId
 	}
 	return ""
 }
@@ -907,7 +4360,7 @@ var File_proto_delivery_proto protoreflect.FileDescriptor
 
 const file_proto_delivery_proto_rawDesc = "" +
 	"\n" +
-	"\x14proto/delivery.proto\x12\bdelivery\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xc0\x01\n" +
+	"\x14proto/delivery.proto\x12\bdelivery\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc0\x01\n" +
 	"\aAddress\x12\x16\n" +
 	"\x06street\x18\x01 \x01(\tR\x06street\x12\x12\n" +
 	"\x04city\x18\x02 \x01(\tR\x04city\x12\x14\n" +
@@ -916,7 +4369,7 @@ const file_proto_delivery_proto_rawDesc = "" +
 	"postalCode\x12\x18\n" +
 	"\acountry\x18\x05 \x01(\tR\acountry\x12\x1a\n" +
 	"\blatitude\x18\x06 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\a \x01(\x01R\tlongitude\"\xce\x05\n" +
+	"\tlongitude\x18\a \x01(\x01R\tlongitude\"\x8b\x0f\n" +
 	"\x12DeliveryAssignment\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1b\n" +
@@ -933,48 +4386,306 @@ const file_proto_delivery_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xee\x02\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06rating\x18\x0e \x01(\x05R\x06rating\x12\x1a\n" +
+	"\bfeedback\x18\x0f \x01(\tR\bfeedback\x12=\n" +
+	"\x0etimeline_notes\x18\x10 \x03(\v2\x16.delivery.TimelineNoteR\rtimelineNotes\x12,\n" +
+	"\x12dead_letter_reason\x18\x11 \x01(\tR\x10deadLetterReason\x12\x1b\n" +
+	"\ttime_zone\x18\x12 \x01(\tR\btimeZone\x12=\n" +
+	"\x1bscheduled_pickup_time_local\x18\x13 \x01(\tR\x18scheduledPickupTimeLocal\x12A\n" +
+	"\x1destimated_delivery_time_local\x18\x14 \x01(\tR\x1aestimatedDeliveryTimeLocal\x12a\n" +
+	"\x1festimated_delivery_window_start\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\x1cestimatedDeliveryWindowStart\x12]\n" +
+	"\x1destimated_delivery_window_end\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\x1aestimatedDeliveryWindowEnd\x12)\n" +
+	"\x10allow_concurrent\x18\x17 \x01(\bR\x0fallowConcurrent\x12\x1f\n" +
+	"\vpickup_code\x18\x18 \x01(\tR\n" +
+	"pickupCode\x122\n" +
+	"\x15required_vehicle_type\x18\x19 \x01(\tR\x13requiredVehicleType\x129\n" +
+	"\n" +
+	"deleted_at\x18\x1a \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x12\n" +
+	"\x04tags\x18\x1b \x03(\tR\x04tags\x12-\n" +
+	"\bpackages\x18\x1c \x03(\v2\x11.delivery.PackageR\bpackages\x12,\n" +
+	"\x12parent_delivery_id\x18\x1d \x01(\tR\x10parentDeliveryId\x12.\n" +
+	"\x13failure_reason_code\x18\x1e \x01(\tR\x11failureReasonCode\x12'\n" +
+	"\x0frecipient_email\x18\x1f \x01(\tR\x0erecipientEmail\x12;\n" +
+	"\fnotify_prefs\x18  \x03(\x0e2\x18.delivery.DeliveryStatusR\vnotifyPrefs\x12%\n" +
+	"\x0eattempt_number\x18! \x01(\x05R\rattemptNumber\x129\n" +
+	"\n" +
+	"driver_eta\x18\" \x01(\v2\x1a.google.protobuf.TimestampR\tdriverEta\x126\n" +
+	"\bpriority\x18# \x01(\x0e2\x1a.delivery.DeliveryPriorityR\bpriority\x12(\n" +
+	"\x10backup_driver_id\x18$ \x01(\tR\x0ebackupDriverId\x123\n" +
+	"\bmetadata\x18% \x01(\v2\x17.google.protobuf.StructR\bmetadata\"G\n" +
+	"\aPackage\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"]\n" +
+	"\fTimelineNote\x12\x12\n" +
+	"\x04note\x18\x01 \x01(\tR\x04note\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x93\a\n" +
 	"\x1fCreateDeliveryAssignmentRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x128\n" +
 	"\x0epickup_address\x18\x02 \x01(\v2\x11.delivery.AddressR\rpickupAddress\x12<\n" +
 	"\x10delivery_address\x18\x03 \x01(\v2\x11.delivery.AddressR\x0fdeliveryAddress\x12N\n" +
 	"\x15scheduled_pickup_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x13scheduledPickupTime\x12R\n" +
 	"\x17estimated_delivery_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x15estimatedDeliveryTime\x12\x14\n" +
-	"\x05notes\x18\x06 \x01(\tR\x05notes\".\n" +
+	"\x05notes\x18\x06 \x01(\tR\x05notes\x12\x1b\n" +
+	"\ttime_zone\x18\a \x01(\tR\btimeZone\x12a\n" +
+	"\x1festimated_delivery_window_start\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x1cestimatedDeliveryWindowStart\x12]\n" +
+	"\x1destimated_delivery_window_end\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x1aestimatedDeliveryWindowEnd\x12)\n" +
+	"\x10allow_concurrent\x18\n" +
+	" \x01(\bR\x0fallowConcurrent\x122\n" +
+	"\x15required_vehicle_type\x18\v \x01(\tR\x13requiredVehicleType\x12\x12\n" +
+	"\x04tags\x18\f \x03(\tR\x04tags\x12'\n" +
+	"\x0frecipient_email\x18\r \x01(\tR\x0erecipientEmail\x12;\n" +
+	"\fnotify_prefs\x18\x0e \x03(\x0e2\x18.delivery.DeliveryStatusR\vnotifyPrefs\x126\n" +
+	"\bpriority\x18\x0f \x01(\x0e2\x1a.delivery.DeliveryPriorityR\bpriority\x123\n" +
+	"\bmetadata\x18\x10 \x01(\v2\x17.google.protobuf.StructR\bmetadata\"\x8c\x01\n" +
+	"\x17ImportDeliveriesRequest\x12=\n" +
+	"\x04rows\x18\x01 \x03(\v2).delivery.CreateDeliveryAssignmentRequestR\x04rows\x122\n" +
+	"\x15allow_past_scheduling\x18\x02 \x01(\bR\x13allowPastScheduling\"8\n" +
+	"\x0eImportRowError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"U\n" +
+	"\x10ImportedDelivery\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1f\n" +
+	"\vpickup_code\x18\x03 \x01(\tR\n" +
+	"pickupCode\"\xb8\x01\n" +
+	"\x18ImportDeliveriesResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x05R\x06failed\x120\n" +
+	"\x06errors\x18\x03 \x03(\v2\x18.delivery.ImportRowErrorR\x06errors\x124\n" +
+	"\acreated\x18\x04 \x03(\v2\x1a.delivery.ImportedDeliveryR\acreated\".\n" +
 	"\x1cGetDeliveryAssignmentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"u\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc6\x01\n" +
 	"\x1bUpdateDeliveryStatusRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x12\x14\n" +
-	"\x05notes\x18\x03 \x01(\tR\x05notes\"\xa0\x01\n" +
+	"\x05notes\x18\x03 \x01(\tR\x05notes\x12\x1f\n" +
+	"\vpickup_code\x18\x04 \x01(\tR\n" +
+	"pickupCode\x12.\n" +
+	"\x13failure_reason_code\x18\x05 \x01(\tR\x11failureReasonCode\"\x89\x03\n" +
 	"\x1eListDeliveryAssignmentsRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
 	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x120\n" +
 	"\x06status\x18\x03 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x12\x1b\n" +
-	"\tdriver_id\x18\x04 \x01(\tR\bdriverId\"\xb3\x01\n" +
+	"\tdriver_id\x18\x04 \x01(\tR\bdriverId\x124\n" +
+	"\bactivity\x18\x05 \x01(\x0e2\x18.delivery.ActivityFilterR\bactivity\x122\n" +
+	"\x15required_vehicle_type\x18\x06 \x01(\tR\x13requiredVehicleType\x12\x10\n" +
+	"\x03tag\x18\a \x01(\tR\x03tag\x128\n" +
+	"\x18include_status_breakdown\x18\b \x01(\bR\x16includeStatusBreakdown\x12\"\n" +
+	"\n" +
+	"has_driver\x18\t \x01(\bH\x00R\thasDriver\x88\x01\x01B\r\n" +
+	"\v_has_driver\"\x99\x03\n" +
 	"\x1fListDeliveryAssignmentsResponse\x12>\n" +
 	"\vassignments\x18\x01 \x03(\v2\x1c.delivery.DeliveryAssignmentR\vassignments\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
 	"totalCount\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"B\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12i\n" +
+	"\x10status_breakdown\x18\x05 \x03(\v2>.delivery.ListDeliveryAssignmentsResponse.StatusBreakdownEntryR\x0fstatusBreakdown\x125\n" +
+	"\x17total_count_is_estimate\x18\x06 \x01(\bR\x14totalCountIsEstimate\x1aB\n" +
+	"\x14StatusBreakdownEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"B\n" +
 	"\x13AssignDriverRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\"H\n" +
+	"\x19AssignBackupDriverRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\"\xaa\x01\n" +
 	"\x19GetDeliveryMetricsRequest\x129\n" +
 	"\n" +
 	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
 	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
-	"\tdriver_id\x18\x03 \x01(\tR\bdriverId\"\xc5\x02\n" +
+	"\tdriver_id\x18\x03 \x01(\tR\bdriverId\"\xab\x05\n" +
 	"\x0fDeliveryMetrics\x12)\n" +
 	"\x10total_deliveries\x18\x01 \x01(\x05R\x0ftotalDeliveries\x121\n" +
 	"\x14completed_deliveries\x18\x02 \x01(\x05R\x13completedDeliveries\x12+\n" +
 	"\x11failed_deliveries\x18\x03 \x01(\x05R\x10failedDeliveries\x121\n" +
 	"\x14cancelled_deliveries\x18\x04 \x01(\x05R\x13cancelledDeliveries\x12A\n" +
 	"\x1daverage_delivery_time_minutes\x18\x05 \x01(\x01R\x1aaverageDeliveryTimeMinutes\x121\n" +
-	"\x15on_time_delivery_rate\x18\x06 \x01(\x01R\x12onTimeDeliveryRate\"1\n" +
+	"\x15on_time_delivery_rate\x18\x06 \x01(\x01R\x12onTimeDeliveryRate\x12%\n" +
+	"\x0eaverage_rating\x18\a \x01(\x01R\raverageRating\x124\n" +
+	"\x16dead_letter_deliveries\x18\t \x01(\x05R\x14deadLetterDeliveries\x12K\n" +
+	"\"average_lifecycle_duration_minutes\x18\n" +
+	" \x01(\x01R\x1faverageLifecycleDurationMinutes\x12\x16\n" +
+	"\x06errors\x18\b \x03(\tR\x06errors\x12]\n" +
+	"\x12failures_by_reason\x18\v \x03(\v2/.delivery.DeliveryMetrics.FailuresByReasonEntryR\x10failuresByReason\x1aC\n" +
+	"\x15FailuresByReasonEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xa5\x01\n" +
+	"\x1bGetDriverLeaderboardRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xa4\x01\n" +
+	"\vDriverStats\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x121\n" +
+	"\x14completed_deliveries\x18\x03 \x01(\x05R\x13completedDeliveries\x121\n" +
+	"\x15on_time_delivery_rate\x18\x04 \x01(\x01R\x12onTimeDeliveryRate\"D\n" +
+	"\x11DriverLeaderboard\x12/\n" +
+	"\adrivers\x18\x01 \x03(\v2\x15.delivery.DriverStatsR\adrivers\"\x1c\n" +
+	"\x1aListActiveDriverIDsRequest\"<\n" +
+	"\x1bListActiveDriverIDsResponse\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds\"\x19\n" +
+	"\x17ListServedCitiesRequest\"2\n" +
+	"\x18ListServedCitiesResponse\x12\x16\n" +
+	"\x06cities\x18\x01 \x03(\tR\x06cities\"1\n" +
 	"\x1fDeleteDeliveryAssignmentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id*\x85\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x1eDeleteDeliveriesByOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"F\n" +
+	"\x1fDeleteDeliveriesByOrderResponse\x12#\n" +
+	"\rdeleted_count\x18\x01 \x01(\x03R\fdeletedCount\"\x83\x03\n" +
+	"\x1bBulkDeleteDeliveriesRequest\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x124\n" +
+	"\bactivity\x18\x03 \x01(\x0e2\x18.delivery.ActivityFilterR\bactivity\x122\n" +
+	"\x15required_vehicle_type\x18\x04 \x01(\tR\x13requiredVehicleType\x12\x10\n" +
+	"\x03tag\x18\x05 \x01(\tR\x03tag\x12\"\n" +
+	"\n" +
+	"has_driver\x18\x06 \x01(\bH\x00R\thasDriver\x88\x01\x01\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12#\n" +
+	"\rconfirm_token\x18\b \x01(\tR\fconfirmTokenB\r\n" +
+	"\v_has_driver\"\x8d\x01\n" +
+	"\x1cBulkDeleteDeliveriesResponse\x12#\n" +
+	"\rmatched_count\x18\x01 \x01(\x03R\fmatchedCount\x12#\n" +
+	"\rconfirm_token\x18\x02 \x01(\tR\fconfirmToken\x12#\n" +
+	"\rdeleted_count\x18\x03 \x01(\x03R\fdeletedCount\"\xbd\x02\n" +
+	"\x1fCheckDeliveryFeasibilityRequest\x128\n" +
+	"\x0epickup_address\x18\x01 \x01(\v2\x11.delivery.AddressR\rpickupAddress\x12<\n" +
+	"\x10delivery_address\x18\x02 \x01(\v2\x11.delivery.AddressR\x0fdeliveryAddress\x12N\n" +
+	"\x15scheduled_pickup_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x13scheduledPickupTime\x12R\n" +
+	"\x17estimated_delivery_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x15estimatedDeliveryTime\"\x8b\x02\n" +
+	" CheckDeliveryFeasibilityResponse\x12\x1a\n" +
+	"\bfeasible\x18\x01 \x01(\bR\bfeasible\x12\x1f\n" +
+	"\vdistance_km\x18\x02 \x01(\x01R\n" +
+	"distanceKm\x12F\n" +
+	"\x11required_duration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x10requiredDuration\x12H\n" +
+	"\x12available_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x11availableDuration\x12\x18\n" +
+	"\areasons\x18\x05 \x03(\tR\areasons\"\x19\n" +
+	"\x17ExportDeliveriesRequest\"[\n" +
+	"\x15SubmitFeedbackRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06rating\x18\x02 \x01(\x05R\x06rating\x12\x1a\n" +
+	"\bfeedback\x18\x03 \x01(\tR\bfeedback\"-\n" +
+	"\x19BatchGetDeliveriesRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"Z\n" +
+	"\x1aBatchGetDeliveriesResponse\x12<\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1c.delivery.DeliveryAssignmentR\n" +
+	"deliveries\"7\n" +
+	"\x11AppendNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04note\x18\x02 \x01(\tR\x04note\"c\n" +
+	"\x16UpdateDriverETARequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"driver_eta\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdriverEta\"\xd4\x01\n" +
+	"\x1eCloneDeliveryAssignmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12N\n" +
+	"\x15scheduled_pickup_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x13scheduledPickupTime\x12R\n" +
+	"\x17estimated_delivery_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x15estimatedDeliveryTime\"*\n" +
+	"\x18GetDeliveryStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x91\x01\n" +
+	"\x12DeliveryStatusInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"'\n" +
+	"\x15GetSLADeadlineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8e\x01\n" +
+	"\vDeliverySLA\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
+	"\bdeadline\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x127\n" +
+	"\tremaining\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\tremaining\"?\n" +
+	"\x15MarkDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x7f\n" +
+	"\x1dListModifiedDeliveriesRequest\x120\n" +
+	"\x05since\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\xb9\x01\n" +
+	"\x1eListModifiedDeliveriesResponse\x12<\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1c.delivery.DeliveryAssignmentR\n" +
+	"deliveries\x128\n" +
+	"\twatermark\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\twatermark\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"h\n" +
+	"\x14SplitDeliveryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12@\n" +
+	"\x12remaining_packages\x18\x02 \x03(\v2\x11.delivery.PackageR\x11remainingPackages\"\x81\x01\n" +
+	"\x15SplitDeliveryResponse\x124\n" +
+	"\x06parent\x18\x01 \x01(\v2\x1c.delivery.DeliveryAssignmentR\x06parent\x122\n" +
+	"\x05child\x18\x02 \x01(\v2\x1c.delivery.DeliveryAssignmentR\x05child\"%\n" +
+	"\x13ReplayEventsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"2\n" +
+	"\x14ReplayEventsResponse\x12\x1a\n" +
+	"\breplayed\x18\x01 \x01(\x05R\breplayed\"*\n" +
+	"\x18ReattemptDeliveryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x0eAddTagsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"7\n" +
+	"\x11RemoveTagsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"\xae\x01\n" +
+	"\x1bGetMetricsForDriversRequest\x12\x1d\n" +
+	"\n" +
+	"driver_ids\x18\x01 \x03(\tR\tdriverIds\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"\xc4\x01\n" +
+	"\x1cGetMetricsForDriversResponse\x12M\n" +
+	"\ametrics\x18\x01 \x03(\v23.delivery.GetMetricsForDriversResponse.MetricsEntryR\ametrics\x1aU\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
+	"\x05value\x18\x02 \x01(\v2\x19.delivery.DeliveryMetricsR\x05value:\x028\x01\"!\n" +
+	"\x1fGetStatusTransitionGraphRequest\"\x82\x01\n" +
+	"\x11StatusTransitions\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x12;\n" +
+	"\fallowed_next\x18\x02 \x03(\x0e2\x18.delivery.DeliveryStatusR\vallowedNext\"P\n" +
+	"\x15StatusTransitionGraph\x127\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x1b.delivery.StatusTransitionsR\bstatuses\"\x16\n" +
+	"\x14GetServerInfoRequest\"\xd2\x01\n" +
+	"\n" +
+	"ServerInfo\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1d\n" +
+	"\n" +
+	"build_date\x18\x02 \x01(\tR\tbuildDate\x12\x1d\n" +
+	"\n" +
+	"git_commit\x18\x03 \x01(\tR\tgitCommit\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x121\n" +
+	"\x06uptime\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x06uptime\"\xa4\x01\n" +
+	"\x0eDriverLocation\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\x12;\n" +
+	"\vrecorded_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\"V\n" +
+	"\x1cStreamDriverLocationResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x02 \x01(\x05R\brejected\";\n" +
+	"\x1cWatchDriverDeliveriesRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\"\xb5\x02\n" +
+	"\x13DeliveryStatusEvent\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x120\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x18.delivery.DeliveryStatusR\x06status\x12\x1b\n" +
+	"\tdriver_id\x18\x04 \x01(\tR\bdriverId\x129\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\x129\n" +
+	"\n" +
+	"driver_eta\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tdriverEta\x12\x1d\n" +
+	"\n" +
+	"eta_update\x18\a \x01(\bR\tetaUpdate\"7\n" +
+	"\x18GetDriverLocationRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId*\x96\x01\n" +
 	"\x0eDeliveryStatus\x12\x0f\n" +
 	"\vUNSPECIFIED\x10\x00\x12\v\n" +
 	"\aPENDING\x10\x01\x12\f\n" +
@@ -985,15 +4696,58 @@ const file_proto_delivery_proto_rawDesc = "" +
 	"\tDELIVERED\x10\x05\x12\n" +
 	"\n" +
 	"\x06FAILED\x10\x06\x12\r\n" +
-	"\tCANCELLED\x10\a2\x88\a\n" +
+	"\tCANCELLED\x10\a\x12\x0f\n" +
+	"\vDEAD_LETTER\x10\b*h\n" +
+	"\x0eActivityFilter\x12\x10\n" +
+	"\fACTIVITY_ANY\x10\x00\x12\x13\n" +
+	"\x0fACTIVITY_ACTIVE\x10\x01\x12\x15\n" +
+	"\x11ACTIVITY_TERMINAL\x10\x02\x12\x18\n" +
+	"\x14ACTIVITY_DEAD_LETTER\x10\x03*f\n" +
+	"\x10DeliveryPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_NORMAL\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x032\x8b#\n" +
 	"\x0fDeliveryService\x12~\n" +
-	"\x18CreateDeliveryAssignment\x12).delivery.CreateDeliveryAssignmentRequest\x1a\x1c.delivery.DeliveryAssignment\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/deliveries\x12z\n" +
+	"\x18CreateDeliveryAssignment\x12).delivery.CreateDeliveryAssignmentRequest\x1a\x1c.delivery.DeliveryAssignment\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/deliveries\x12\x98\x01\n" +
+	"\x18CheckDeliveryFeasibility\x12).delivery.CheckDeliveryFeasibilityRequest\x1a*.delivery.CheckDeliveryFeasibilityResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/deliveries/feasibility\x12[\n" +
+	"\x10ImportDeliveries\x12!.delivery.ImportDeliveriesRequest\x1a\".delivery.ImportDeliveriesResponse(\x01\x12z\n" +
 	"\x15GetDeliveryAssignment\x12&.delivery.GetDeliveryAssignmentRequest\x1a\x1c.delivery.DeliveryAssignment\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/deliveries/{id}\x12\x82\x01\n" +
 	"\x14UpdateDeliveryStatus\x12%.delivery.UpdateDeliveryStatusRequest\x1a\x1c.delivery.DeliveryAssignment\"%\x82\xd3\xe4\x93\x02\x1f:\x01*2\x1a/v1/deliveries/{id}/status\x12\x86\x01\n" +
 	"\x17ListDeliveryAssignments\x12(.delivery.ListDeliveryAssignmentsRequest\x1a).delivery.ListDeliveryAssignmentsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/deliveries\x12y\n" +
-	"\fAssignDriver\x12\x1d.delivery.AssignDriverRequest\x1a\x1c.delivery.DeliveryAssignment\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/deliveries/{id}/assign-driver\x12t\n" +
-	"\x12GetDeliveryMetrics\x12#.delivery.GetDeliveryMetricsRequest\x1a\x19.delivery.DeliveryMetrics\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/deliveries/metrics\x12z\n" +
-	"\x18DeleteDeliveryAssignment\x12).delivery.DeleteDeliveryAssignmentRequest\x1a\x16.google.protobuf.Empty\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/deliveries/{id}B7Z5github.com/mohamadchoker/order-delivery-service/protob\x06proto3"
+	"\fAssignDriver\x12\x1d.delivery.AssignDriverRequest\x1a\x1c.delivery.DeliveryAssignment\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/deliveries/{id}/assign-driver\x12\x8c\x01\n" +
+	"\x12AssignBackupDriver\x12#.delivery.AssignBackupDriverRequest\x1a\x1c.delivery.DeliveryAssignment\"3\x82\xd3\xe4\x93\x02-:\x01*\"(/v1/deliveries/{id}/assign-backup-driver\x12t\n" +
+	"\x12GetDeliveryMetrics\x12#.delivery.GetDeliveryMetricsRequest\x1a\x19.delivery.DeliveryMetrics\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/deliveries/metrics\x12{\n" +
+	"\x14GetDriverLeaderboard\x12%.delivery.GetDriverLeaderboardRequest\x1a\x1b.delivery.DriverLeaderboard\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/drivers/leaderboard\x12~\n" +
+	"\x13ListActiveDriverIDs\x12$.delivery.ListActiveDriverIDsRequest\x1a%.delivery.ListActiveDriverIDsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/drivers/active\x12m\n" +
+	"\x10ListServedCities\x12!.delivery.ListServedCitiesRequest\x1a\".delivery.ListServedCitiesResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/cities\x12z\n" +
+	"\x18DeleteDeliveryAssignment\x12).delivery.DeleteDeliveryAssignmentRequest\x1a\x16.google.protobuf.Empty\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/deliveries/{id}\x12\x98\x01\n" +
+	"\x17DeleteDeliveriesByOrder\x12(.delivery.DeleteDeliveriesByOrderRequest\x1a).delivery.DeleteDeliveriesByOrderResponse\"(\x82\xd3\xe4\x93\x02\"* /v1/orders/{order_id}/deliveries\x12\x92\x01\n" +
+	"\x14BulkDeleteDeliveries\x12%.delivery.BulkDeleteDeliveriesRequest\x1a&.delivery.BulkDeleteDeliveriesResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /v1/admin/deliveries/bulk-delete\x12x\n" +
+	"\x0eSubmitFeedback\x12\x1f.delivery.SubmitFeedbackRequest\x1a\x1c.delivery.DeliveryAssignment\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/deliveries/{id}/feedback\x12t\n" +
+	"\x10ExportDeliveries\x12!.delivery.ExportDeliveriesRequest\x1a\x1c.delivery.DeliveryAssignment\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/deliveries/export0\x01\x12\x87\x01\n" +
+	"\x17CloneDeliveryAssignment\x12(.delivery.CloneDeliveryAssignmentRequest\x1a\x1c.delivery.DeliveryAssignment\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/deliveries/{id}/clone\x12m\n" +
+	"\n" +
+	"AppendNote\x12\x1b.delivery.AppendNoteRequest\x1a\x1c.delivery.DeliveryAssignment\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/deliveries/{id}/notes\x12u\n" +
+	"\x0fUpdateDriverETA\x12 .delivery.UpdateDriverETARequest\x1a\x1c.delivery.DeliveryAssignment\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/deliveries/{id}/eta\x12y\n" +
+	"\x11GetDeliveryStatus\x12\".delivery.GetDeliveryStatusRequest\x1a\x1c.delivery.DeliveryStatusInfo\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/deliveries/{id}/status\x12i\n" +
+	"\x0eGetSLADeadline\x12\x1f.delivery.GetSLADeadlineRequest\x1a\x15.delivery.DeliverySLA\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/deliveries/{id}/sla\x12\x84\x01\n" +
+	"\x12BatchGetDeliveries\x12#.delivery.BatchGetDeliveriesRequest\x1a$.delivery.BatchGetDeliveriesResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/deliveries/batch-get\x12{\n" +
+	"\x0eMarkDeadLetter\x12\x1f.delivery.MarkDeadLetterRequest\x1a\x1c.delivery.DeliveryAssignment\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/deliveries/{id}/dead-letter\x12\x88\x01\n" +
+	"\x16ListModifiedDeliveries\x12'.delivery.ListModifiedDeliveriesRequest\x1a(.delivery.ListModifiedDeliveriesResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/sync/deliveries\x12v\n" +
+	"\rSplitDelivery\x12\x1e.delivery.SplitDeliveryRequest\x1a\x1f.delivery.SplitDeliveryResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/deliveries/{id}/split\x12{\n" +
+	"\fReplayEvents\x12\x1d.delivery.ReplayEventsRequest\x1a\x1e.delivery.ReplayEventsResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/deliveries/{id}/events/replay\x12\x7f\n" +
+	"\x11ReattemptDelivery\x12\".delivery.ReattemptDeliveryRequest\x1a\x1c.delivery.DeliveryAssignment\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/deliveries/{id}/reattempt\x12f\n" +
+	"\aAddTags\x12\x18.delivery.AddTagsRequest\x1a\x1c.delivery.DeliveryAssignment\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/deliveries/{id}/tags\x12s\n" +
+	"\n" +
+	"RemoveTags\x12\x1b.delivery.RemoveTagsRequest\x1a\x1c.delivery.DeliveryAssignment\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/deliveries/{id}/tags/remove\x12\x8f\x01\n" +
+	"\x14GetMetricsForDrivers\x12%.delivery.GetMetricsForDriversRequest\x1a&.delivery.GetMetricsForDriversResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/drivers/metrics/batch-get\x12\x88\x01\n" +
+	"\x18GetStatusTransitionGraph\x12).delivery.GetStatusTransitionGraphRequest\x1a\x1f.delivery.StatusTransitionGraph\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/v1/statuses/transitions\x12Z\n" +
+	"\x14StreamDriverLocation\x12\x18.delivery.DriverLocation\x1a&.delivery.StreamDriverLocationResponse(\x01\x12`\n" +
+	"\x15WatchDriverDeliveries\x12&.delivery.WatchDriverDeliveriesRequest\x1a\x1d.delivery.DeliveryStatusEvent0\x01\x12{\n" +
+	"\x11GetDriverLocation\x12\".delivery.GetDriverLocationRequest\x1a\x18.delivery.DriverLocation\"(\x82\xd3\xe4\x93\x02\"\x12 /v1/drivers/{driver_id}/location\x12^\n" +
+	"\rGetServerInfo\x12\x1e.delivery.GetServerInfoRequest\x1a\x14.delivery.ServerInfo\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/server-infoB7Z5github.com/mohamadchoker/order-delivery-service/protob\x06proto3"
 
 var (
 	file_proto_delivery_proto_rawDescOnce sync.Once
@@ -1007,62 +4761,240 @@ func file_proto_delivery_proto_rawDescGZIP() []byte {
 	return file_proto_delivery_proto_rawDescData
 }
 
-var file_proto_delivery_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_delivery_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_delivery_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_delivery_proto_msgTypes = make([]protoimpl.MessageInfo, 67)
 var file_proto_delivery_proto_goTypes = []any{
-	(DeliveryStatus)(0),                     // 0: delivery.DeliveryStatus
-	(*Address)(nil),                         // 1: delivery.Address
-	(*DeliveryAssignment)(nil),              // 2: delivery.DeliveryAssignment
-	(*CreateDeliveryAssignmentRequest)(nil), // 3: delivery.CreateDeliveryAssignmentRequest
-	(*GetDeliveryAssignmentRequest)(nil),    // 4: delivery.GetDeliveryAssignmentRequest
-	(*UpdateDeliveryStatusRequest)(nil),     // 5: delivery.UpdateDeliveryStatusRequest
-	(*ListDeliveryAssignmentsRequest)(nil),  // 6: delivery.ListDeliveryAssignmentsRequest
-	(*ListDeliveryAssignmentsResponse)(nil), // 7: delivery.ListDeliveryAssignmentsResponse
-	(*AssignDriverRequest)(nil),             // 8: delivery.AssignDriverRequest
-	(*GetDeliveryMetricsRequest)(nil),       // 9: delivery.GetDeliveryMetricsRequest
-	(*DeliveryMetrics)(nil),                 // 10: delivery.DeliveryMetrics
-	(*DeleteDeliveryAssignmentRequest)(nil), // 11: delivery.DeleteDeliveryAssignmentRequest
-	(*timestamppb.Timestamp)(nil),           // 12: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),                   // 13: google.protobuf.Empty
+	(DeliveryStatus)(0),                      // 0: delivery.DeliveryStatus
+	(ActivityFilter)(0),                      // 1: delivery.ActivityFilter
+	(DeliveryPriority)(0),                    // 2: delivery.DeliveryPriority
+	(*Address)(nil),                          // 3: delivery.Address
+	(*DeliveryAssignment)(nil),               // 4: delivery.DeliveryAssignment
+	(*Package)(nil),                          // 5: delivery.Package
+	(*TimelineNote)(nil),                     // 6: delivery.TimelineNote
+	(*CreateDeliveryAssignmentRequest)(nil),  // 7: delivery.CreateDeliveryAssignmentRequest
+	(*ImportDeliveriesRequest)(nil),          // 8: delivery.ImportDeliveriesRequest
+	(*ImportRowError)(nil),                   // 9: delivery.ImportRowError
+	(*ImportedDelivery)(nil),                 // 10: delivery.ImportedDelivery
+	(*ImportDeliveriesResponse)(nil),         // 11: delivery.ImportDeliveriesResponse
+	(*GetDeliveryAssignmentRequest)(nil),     // 12: delivery.GetDeliveryAssignmentRequest
+	(*UpdateDeliveryStatusRequest)(nil),      // 13: delivery.UpdateDeliveryStatusRequest
+	(*ListDeliveryAssignmentsRequest)(nil),   // 14: delivery.ListDeliveryAssignmentsRequest
+	(*ListDeliveryAssignmentsResponse)(nil),  // 15: delivery.ListDeliveryAssignmentsResponse
+	(*AssignDriverRequest)(nil),              // 16: delivery.AssignDriverRequest
+	(*AssignBackupDriverRequest)(nil),        // 17: delivery.AssignBackupDriverRequest
+	(*GetDeliveryMetricsRequest)(nil),        // 18: delivery.GetDeliveryMetricsRequest
+	(*DeliveryMetrics)(nil),                  // 19: delivery.DeliveryMetrics
+	(*GetDriverLeaderboardRequest)(nil),      // 20: delivery.GetDriverLeaderboardRequest
+	(*DriverStats)(nil),                      // 21: delivery.DriverStats
+	(*DriverLeaderboard)(nil),                // 22: delivery.DriverLeaderboard
+	(*ListActiveDriverIDsRequest)(nil),       // 23: delivery.ListActiveDriverIDsRequest
+	(*ListActiveDriverIDsResponse)(nil),      // 24: delivery.ListActiveDriverIDsResponse
+	(*ListServedCitiesRequest)(nil),          // 25: delivery.ListServedCitiesRequest
+	(*ListServedCitiesResponse)(nil),         // 26: delivery.ListServedCitiesResponse
+	(*DeleteDeliveryAssignmentRequest)(nil),  // 27: delivery.DeleteDeliveryAssignmentRequest
+	(*DeleteDeliveriesByOrderRequest)(nil),   // 28: delivery.DeleteDeliveriesByOrderRequest
+	(*DeleteDeliveriesByOrderResponse)(nil),  // 29: delivery.DeleteDeliveriesByOrderResponse
+	(*BulkDeleteDeliveriesRequest)(nil),      // 30: delivery.BulkDeleteDeliveriesRequest
+	(*BulkDeleteDeliveriesResponse)(nil),     // 31: delivery.BulkDeleteDeliveriesResponse
+	(*CheckDeliveryFeasibilityRequest)(nil),  // 32: delivery.CheckDeliveryFeasibilityRequest
+	(*CheckDeliveryFeasibilityResponse)(nil), // 33: delivery.CheckDeliveryFeasibilityResponse
+	(*ExportDeliveriesRequest)(nil),          // 34: delivery.ExportDeliveriesRequest
+	(*SubmitFeedbackRequest)(nil),            // 35: delivery.SubmitFeedbackRequest
+	(*BatchGetDeliveriesRequest)(nil),        // 36: delivery.BatchGetDeliveriesRequest
+	(*BatchGetDeliveriesResponse)(nil),       // 37: delivery.BatchGetDeliveriesResponse
+	(*AppendNoteRequest)(nil),                // 38: delivery.AppendNoteRequest
+	(*UpdateDriverETARequest)(nil),           // 39: delivery.UpdateDriverETARequest
+	(*CloneDeliveryAssignmentRequest)(nil),   // 40: delivery.CloneDeliveryAssignmentRequest
+	(*GetDeliveryStatusRequest)(nil),         // 41: delivery.GetDeliveryStatusRequest
+	(*DeliveryStatusInfo)(nil),               // 42: delivery.DeliveryStatusInfo
+	(*GetSLADeadlineRequest)(nil),            // 43: delivery.GetSLADeadlineRequest
+	(*DeliverySLA)(nil),                      // 44: delivery.DeliverySLA
+	(*MarkDeadLetterRequest)(nil),            // 45: delivery.MarkDeadLetterRequest
+	(*ListModifiedDeliveriesRequest)(nil),    // 46: delivery.ListModifiedDeliveriesRequest
+	(*ListModifiedDeliveriesResponse)(nil),   // 47: delivery.ListModifiedDeliveriesResponse
+	(*SplitDeliveryRequest)(nil),             // 48: delivery.SplitDeliveryRequest
+	(*SplitDeliveryResponse)(nil),            // 49: delivery.SplitDeliveryResponse
+	(*ReplayEventsRequest)(nil),              // 50: delivery.ReplayEventsRequest
+	(*ReplayEventsResponse)(nil),             // 51: delivery.ReplayEventsResponse
+	(*ReattemptDeliveryRequest)(nil),         // 52: delivery.ReattemptDeliveryRequest
+	(*AddTagsRequest)(nil),                   // 53: delivery.AddTagsRequest
+	(*RemoveTagsRequest)(nil),                // 54: delivery.RemoveTagsRequest
+	(*GetMetricsForDriversRequest)(nil),      // 55: delivery.GetMetricsForDriversRequest
+	(*GetMetricsForDriversResponse)(nil),     // 56: delivery.GetMetricsForDriversResponse
+	(*GetStatusTransitionGraphRequest)(nil),  // 57: delivery.GetStatusTransitionGraphRequest
+	(*StatusTransitions)(nil),                // 58: delivery.StatusTransitions
+	(*StatusTransitionGraph)(nil),            // 59: delivery.StatusTransitionGraph
+	(*GetServerInfoRequest)(nil),             // 60: delivery.GetServerInfoRequest
+	(*ServerInfo)(nil),                       // 61: delivery.ServerInfo
+	(*DriverLocation)(nil),                   // 62: delivery.DriverLocation
+	(*StreamDriverLocationResponse)(nil),     // 63: delivery.StreamDriverLocationResponse
+	(*WatchDriverDeliveriesRequest)(nil),     // 64: delivery.WatchDriverDeliveriesRequest
+	(*DeliveryStatusEvent)(nil),              // 65: delivery.DeliveryStatusEvent
+	(*GetDriverLocationRequest)(nil),         // 66: delivery.GetDriverLocationRequest
+	nil,                                      // 67: delivery.ListDeliveryAssignmentsResponse.StatusBreakdownEntry
+	nil,                                      // 68: delivery.DeliveryMetrics.FailuresByReasonEntry
+	nil,                                      // 69: delivery.GetMetricsForDriversResponse.MetricsEntry
+	(*timestamppb.Timestamp)(nil),            // 70: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                  // 71: google.protobuf.Struct
+	(*durationpb.Duration)(nil),              // 72: google.protobuf.Duration
+	(*emptypb.Empty)(nil),                    // 73: google.protobuf.Empty
 }
 var file_proto_delivery_proto_depIdxs = []int32{
-	0,  // 0: delivery.DeliveryAssignment.status:type_name -> delivery.DeliveryStatus
-	1,  // 1: delivery.DeliveryAssignment.pickup_address:type_name -> delivery.Address
-	1,  // 2: delivery.DeliveryAssignment.delivery_address:type_name -> delivery.Address
-	12, // 3: delivery.DeliveryAssignment.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
-	12, // 4: delivery.DeliveryAssignment.estimated_delivery_time:type_name -> google.protobuf.Timestamp
-	12, // 5: delivery.DeliveryAssignment.actual_pickup_time:type_name -> google.protobuf.Timestamp
-	12, // 6: delivery.DeliveryAssignment.actual_delivery_time:type_name -> google.protobuf.Timestamp
-	12, // 7: delivery.DeliveryAssignment.created_at:type_name -> google.protobuf.Timestamp
-	12, // 8: delivery.DeliveryAssignment.updated_at:type_name -> google.protobuf.Timestamp
-	1,  // 9: delivery.CreateDeliveryAssignmentRequest.pickup_address:type_name -> delivery.Address
-	1,  // 10: delivery.CreateDeliveryAssignmentRequest.delivery_address:type_name -> delivery.Address
-	12, // 11: delivery.CreateDeliveryAssignmentRequest.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
-	12, // 12: delivery.CreateDeliveryAssignmentRequest.estimated_delivery_time:type_name -> google.protobuf.Timestamp
-	0,  // 13: delivery.UpdateDeliveryStatusRequest.status:type_name -> delivery.DeliveryStatus
-	0,  // 14: delivery.ListDeliveryAssignmentsRequest.status:type_name -> delivery.DeliveryStatus
-	2,  // 15: delivery.ListDeliveryAssignmentsResponse.assignments:type_name -> delivery.DeliveryAssignment
-	12, // 16: delivery.GetDeliveryMetricsRequest.start_time:type_name -> google.protobuf.Timestamp
-	12, // 17: delivery.GetDeliveryMetricsRequest.end_time:type_name -> google.protobuf.Timestamp
-	3,  // 18: delivery.DeliveryService.CreateDeliveryAssignment:input_type -> delivery.CreateDeliveryAssignmentRequest
-	4,  // 19: delivery.DeliveryService.GetDeliveryAssignment:input_type -> delivery.GetDeliveryAssignmentRequest
-	5,  // 20: delivery.DeliveryService.UpdateDeliveryStatus:input_type -> delivery.UpdateDeliveryStatusRequest
-	6,  // 21: delivery.DeliveryService.ListDeliveryAssignments:input_type -> delivery.ListDeliveryAssignmentsRequest
-	8,  // 22: delivery.DeliveryService.AssignDriver:input_type -> delivery.AssignDriverRequest
-	9,  // 23: delivery.DeliveryService.GetDeliveryMetrics:input_type -> delivery.GetDeliveryMetricsRequest
-	11, // 24: delivery.DeliveryService.DeleteDeliveryAssignment:input_type -> delivery.DeleteDeliveryAssignmentRequest
-	2,  // 25: delivery.DeliveryService.CreateDeliveryAssignment:output_type -> delivery.DeliveryAssignment
-	2,  // 26: delivery.DeliveryService.GetDeliveryAssignment:output_type -> delivery.DeliveryAssignment
-	2,  // 27: delivery.DeliveryService.UpdateDeliveryStatus:output_type -> delivery.DeliveryAssignment
-	7,  // 28: delivery.DeliveryService.ListDeliveryAssignments:output_type -> delivery.ListDeliveryAssignmentsResponse
-	2,  // 29: delivery.DeliveryService.AssignDriver:output_type -> delivery.DeliveryAssignment
-	10, // 30: delivery.DeliveryService.GetDeliveryMetrics:output_type -> delivery.DeliveryMetrics
-	13, // 31: delivery.DeliveryService.DeleteDeliveryAssignment:output_type -> google.protobuf.Empty
-	25, // [25:32] is the sub-list for method output_type
-	18, // [18:25] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	0,   // 0: delivery.DeliveryAssignment.status:type_name -> delivery.DeliveryStatus
+	3,   // 1: delivery.DeliveryAssignment.pickup_address:type_name -> delivery.Address
+	3,   // 2: delivery.DeliveryAssignment.delivery_address:type_name -> delivery.Address
+	70,  // 3: delivery.DeliveryAssignment.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
+	70,  // 4: delivery.DeliveryAssignment.estimated_delivery_time:type_name -> google.protobuf.Timestamp
+	70,  // 5: delivery.DeliveryAssignment.actual_pickup_time:type_name -> google.protobuf.Timestamp
+	70,  // 6: delivery.DeliveryAssignment.actual_delivery_time:type_name -> google.protobuf.Timestamp
+	70,  // 7: delivery.DeliveryAssignment.created_at:type_name -> google.protobuf.Timestamp
+	70,  // 8: delivery.DeliveryAssignment.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 9: delivery.DeliveryAssignment.timeline_notes:type_name -> delivery.TimelineNote
+	70,  // 10: delivery.DeliveryAssignment.estimated_delivery_window_start:type_name -> google.protobuf.Timestamp
+	70,  // 11: delivery.DeliveryAssignment.estimated_delivery_window_end:type_name -> google.protobuf.Timestamp
+	70,  // 12: delivery.DeliveryAssignment.deleted_at:type_name -> google.protobuf.Timestamp
+	5,   // 13: delivery.DeliveryAssignment.packages:type_name -> delivery.Package
+	0,   // 14: delivery.DeliveryAssignment.notify_prefs:type_name -> delivery.DeliveryStatus
+	70,  // 15: delivery.DeliveryAssignment.driver_eta:type_name -> google.protobuf.Timestamp
+	2,   // 16: delivery.DeliveryAssignment.priority:type_name -> delivery.DeliveryPriority
+	71,  // 17: delivery.DeliveryAssignment.metadata:type_name -> google.protobuf.Struct
+	70,  // 18: delivery.TimelineNote.created_at:type_name -> google.protobuf.Timestamp
+	3,   // 19: delivery.CreateDeliveryAssignmentRequest.pickup_address:type_name -> delivery.Address
+	3,   // 20: delivery.CreateDeliveryAssignmentRequest.delivery_address:type_name -> delivery.Address
+	70,  // 21: delivery.CreateDeliveryAssignmentRequest.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
+	70,  // 22: delivery.CreateDeliveryAssignmentRequest.estimated_delivery_time:type_name -> google.protobuf.Timestamp
+	70,  // 23: delivery.CreateDeliveryAssignmentRequest.estimated_delivery_window_start:type_name -> google.protobuf.Timestamp
+	70,  // 24: delivery.CreateDeliveryAssignmentRequest.estimated_delivery_window_end:type_name -> google.protobuf.Timestamp
+	0,   // 25: delivery.CreateDeliveryAssignmentRequest.notify_prefs:type_name -> delivery.DeliveryStatus
+	2,   // 26: delivery.CreateDeliveryAssignmentRequest.priority:type_name -> delivery.DeliveryPriority
+	71,  // 27: delivery.CreateDeliveryAssignmentRequest.metadata:type_name -> google.protobuf.Struct
+	7,   // 28: delivery.ImportDeliveriesRequest.rows:type_name -> delivery.CreateDeliveryAssignmentRequest
+	9,   // 29: delivery.ImportDeliveriesResponse.errors:type_name -> delivery.ImportRowError
+	10,  // 30: delivery.ImportDeliveriesResponse.created:type_name -> delivery.ImportedDelivery
+	0,   // 31: delivery.UpdateDeliveryStatusRequest.status:type_name -> delivery.DeliveryStatus
+	0,   // 32: delivery.ListDeliveryAssignmentsRequest.status:type_name -> delivery.DeliveryStatus
+	1,   // 33: delivery.ListDeliveryAssignmentsRequest.activity:type_name -> delivery.ActivityFilter
+	4,   // 34: delivery.ListDeliveryAssignmentsResponse.assignments:type_name -> delivery.DeliveryAssignment
+	67,  // 35: delivery.ListDeliveryAssignmentsResponse.status_breakdown:type_name -> delivery.ListDeliveryAssignmentsResponse.StatusBreakdownEntry
+	70,  // 36: delivery.GetDeliveryMetricsRequest.start_time:type_name -> google.protobuf.Timestamp
+	70,  // 37: delivery.GetDeliveryMetricsRequest.end_time:type_name -> google.protobuf.Timestamp
+	68,  // 38: delivery.DeliveryMetrics.failures_by_reason:type_name -> delivery.DeliveryMetrics.FailuresByReasonEntry
+	70,  // 39: delivery.GetDriverLeaderboardRequest.start_time:type_name -> google.protobuf.Timestamp
+	70,  // 40: delivery.GetDriverLeaderboardRequest.end_time:type_name -> google.protobuf.Timestamp
+	21,  // 41: delivery.DriverLeaderboard.drivers:type_name -> delivery.DriverStats
+	0,   // 42: delivery.BulkDeleteDeliveriesRequest.status:type_name -> delivery.DeliveryStatus
+	1,   // 43: delivery.BulkDeleteDeliveriesRequest.activity:type_name -> delivery.ActivityFilter
+	70,  // 44: delivery.BulkDeleteDeliveriesRequest.created_before:type_name -> google.protobuf.Timestamp
+	3,   // 45: delivery.CheckDeliveryFeasibilityRequest.pickup_address:type_name -> delivery.Address
+	3,   // 46: delivery.CheckDeliveryFeasibilityRequest.delivery_address:type_name -> delivery.Address
+	70,  // 47: delivery.CheckDeliveryFeasibilityRequest.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
+	70,  // 48: delivery.CheckDeliveryFeasibilityRequest.estimated_delivery_time:type_name -> google.protobuf.Timestamp
+	72,  // 49: delivery.CheckDeliveryFeasibilityResponse.required_duration:type_name -> google.protobuf.Duration
+	72,  // 50: delivery.CheckDeliveryFeasibilityResponse.available_duration:type_name -> google.protobuf.Duration
+	4,   // 51: delivery.BatchGetDeliveriesResponse.deliveries:type_name -> delivery.DeliveryAssignment
+	70,  // 52: delivery.UpdateDriverETARequest.driver_eta:type_name -> google.protobuf.Timestamp
+	70,  // 53: delivery.CloneDeliveryAssignmentRequest.scheduled_pickup_time:type_name -> google.protobuf.Timestamp
+	70,  // 54: delivery.CloneDeliveryAssignmentRequest.estimated_delivery_time:type_name -> google.protobuf.Timestamp
+	0,   // 55: delivery.DeliveryStatusInfo.status:type_name -> delivery.DeliveryStatus
+	70,  // 56: delivery.DeliveryStatusInfo.updated_at:type_name -> google.protobuf.Timestamp
+	70,  // 57: delivery.DeliverySLA.deadline:type_name -> google.protobuf.Timestamp
+	72,  // 58: delivery.DeliverySLA.remaining:type_name -> google.protobuf.Duration
+	70,  // 59: delivery.ListModifiedDeliveriesRequest.since:type_name -> google.protobuf.Timestamp
+	4,   // 60: delivery.ListModifiedDeliveriesResponse.deliveries:type_name -> delivery.DeliveryAssignment
+	70,  // 61: delivery.ListModifiedDeliveriesResponse.watermark:type_name -> google.protobuf.Timestamp
+	5,   // 62: delivery.SplitDeliveryRequest.remaining_packages:type_name -> delivery.Package
+	4,   // 63: delivery.SplitDeliveryResponse.parent:type_name -> delivery.DeliveryAssignment
+	4,   // 64: delivery.SplitDeliveryResponse.child:type_name -> delivery.DeliveryAssignment
+	70,  // 65: delivery.GetMetricsForDriversRequest.start_time:type_name -> google.protobuf.Timestamp
+	70,  // 66: delivery.GetMetricsForDriversRequest.end_time:type_name -> google.protobuf.Timestamp
+	69,  // 67: delivery.GetMetricsForDriversResponse.metrics:type_name -> delivery.GetMetricsForDriversResponse.MetricsEntry
+	0,   // 68: delivery.StatusTransitions.status:type_name -> delivery.DeliveryStatus
+	0,   // 69: delivery.StatusTransitions.allowed_next:type_name -> delivery.DeliveryStatus
+	58,  // 70: delivery.StatusTransitionGraph.statuses:type_name -> delivery.StatusTransitions
+	70,  // 71: delivery.ServerInfo.started_at:type_name -> google.protobuf.Timestamp
+	72,  // 72: delivery.ServerInfo.uptime:type_name -> google.protobuf.Duration
+	70,  // 73: delivery.DriverLocation.recorded_at:type_name -> google.protobuf.Timestamp
+	0,   // 74: delivery.DeliveryStatusEvent.status:type_name -> delivery.DeliveryStatus
+	70,  // 75: delivery.DeliveryStatusEvent.changed_at:type_name -> google.protobuf.Timestamp
+	70,  // 76: delivery.DeliveryStatusEvent.driver_eta:type_name -> google.protobuf.Timestamp
+	19,  // 77: delivery.GetMetricsForDriversResponse.MetricsEntry.value:type_name -> delivery.DeliveryMetrics
+	7,   // 78: delivery.DeliveryService.CreateDeliveryAssignment:input_type -> delivery.CreateDeliveryAssignmentRequest
+	32,  // 79: delivery.DeliveryService.CheckDeliveryFeasibility:input_type -> delivery.CheckDeliveryFeasibilityRequest
+	8,   // 80: delivery.DeliveryService.ImportDeliveries:input_type -> delivery.ImportDeliveriesRequest
+	12,  // 81: delivery.DeliveryService.GetDeliveryAssignment:input_type -> delivery.GetDeliveryAssignmentRequest
+	13,  // 82: delivery.DeliveryService.UpdateDeliveryStatus:input_type -> delivery.UpdateDeliveryStatusRequest
+	14,  // 83: delivery.DeliveryService.ListDeliveryAssignments:input_type -> delivery.ListDeliveryAssignmentsRequest
+	16,  // 84: delivery.DeliveryService.AssignDriver:input_type -> delivery.AssignDriverRequest
+	17,  // 85: delivery.DeliveryService.AssignBackupDriver:input_type -> delivery.AssignBackupDriverRequest
+	18,  // 86: delivery.DeliveryService.GetDeliveryMetrics:input_type -> delivery.GetDeliveryMetricsRequest
+	20,  // 87: delivery.DeliveryService.GetDriverLeaderboard:input_type -> delivery.GetDriverLeaderboardRequest
+	23,  // 88: delivery.DeliveryService.ListActiveDriverIDs:input_type -> delivery.ListActiveDriverIDsRequest
+	25,  // 89: delivery.DeliveryService.ListServedCities:input_type -> delivery.ListServedCitiesRequest
+	27,  // 90: delivery.DeliveryService.DeleteDeliveryAssignment:input_type -> delivery.DeleteDeliveryAssignmentRequest
+	28,  // 91: delivery.DeliveryService.DeleteDeliveriesByOrder:input_type -> delivery.DeleteDeliveriesByOrderRequest
+	30,  // 92: delivery.DeliveryService.BulkDeleteDeliveries:input_type -> delivery.BulkDeleteDeliveriesRequest
+	35,  // 93: delivery.DeliveryService.SubmitFeedback:input_type -> delivery.SubmitFeedbackRequest
+	34,  // 94: delivery.DeliveryService.ExportDeliveries:input_type -> delivery.ExportDeliveriesRequest
+	40,  // 95: delivery.DeliveryService.CloneDeliveryAssignment:input_type -> delivery.CloneDeliveryAssignmentRequest
+	38,  // 96: delivery.DeliveryService.AppendNote:input_type -> delivery.AppendNoteRequest
+	39,  // 97: delivery.DeliveryService.UpdateDriverETA:input_type -> delivery.UpdateDriverETARequest
+	41,  // 98: delivery.DeliveryService.GetDeliveryStatus:input_type -> delivery.GetDeliveryStatusRequest
+	43,  // 99: delivery.DeliveryService.GetSLADeadline:input_type -> delivery.GetSLADeadlineRequest
+	36,  // 100: delivery.DeliveryService.BatchGetDeliveries:input_type -> delivery.BatchGetDeliveriesRequest
+	45,  // 101: delivery.DeliveryService.MarkDeadLetter:input_type -> delivery.MarkDeadLetterRequest
+	46,  // 102: delivery.DeliveryService.ListModifiedDeliveries:input_type -> delivery.ListModifiedDeliveriesRequest
+	48,  // 103: delivery.DeliveryService.SplitDelivery:input_type -> delivery.SplitDeliveryRequest
+	50,  // 104: delivery.DeliveryService.ReplayEvents:input_type -> delivery.ReplayEventsRequest
+	52,  // 105: delivery.DeliveryService.ReattemptDelivery:input_type -> delivery.ReattemptDeliveryRequest
+	53,  // 106: delivery.DeliveryService.AddTags:input_type -> delivery.AddTagsRequest
+	54,  // 107: delivery.DeliveryService.RemoveTags:input_type -> delivery.RemoveTagsRequest
+	55,  // 108: delivery.DeliveryService.GetMetricsForDrivers:input_type -> delivery.GetMetricsForDriversRequest
+	57,  // 109: delivery.DeliveryService.GetStatusTransitionGraph:input_type -> delivery.GetStatusTransitionGraphRequest
+	62,  // 110: delivery.DeliveryService.StreamDriverLocation:input_type -> delivery.DriverLocation
+	64,  // 111: delivery.DeliveryService.WatchDriverDeliveries:input_type -> delivery.WatchDriverDeliveriesRequest
+	66,  // 112: delivery.DeliveryService.GetDriverLocation:input_type -> delivery.GetDriverLocationRequest
+	60,  // 113: delivery.DeliveryService.GetServerInfo:input_type -> delivery.GetServerInfoRequest
+	4,   // 114: delivery.DeliveryService.CreateDeliveryAssignment:output_type -> delivery.DeliveryAssignment
+	33,  // 115: delivery.DeliveryService.CheckDeliveryFeasibility:output_type -> delivery.CheckDeliveryFeasibilityResponse
+	11,  // 116: delivery.DeliveryService.ImportDeliveries:output_type -> delivery.ImportDeliveriesResponse
+	4,   // 117: delivery.DeliveryService.GetDeliveryAssignment:output_type -> delivery.DeliveryAssignment
+	4,   // 118: delivery.DeliveryService.UpdateDeliveryStatus:output_type -> delivery.DeliveryAssignment
+	15,  // 119: delivery.DeliveryService.ListDeliveryAssignments:output_type -> delivery.ListDeliveryAssignmentsResponse
+	4,   // 120: delivery.DeliveryService.AssignDriver:output_type -> delivery.DeliveryAssignment
+	4,   // 121: delivery.DeliveryService.AssignBackupDriver:output_type -> delivery.DeliveryAssignment
+	19,  // 122: delivery.DeliveryService.GetDeliveryMetrics:output_type -> delivery.DeliveryMetrics
+	22,  // 123: delivery.DeliveryService.GetDriverLeaderboard:output_type -> delivery.DriverLeaderboard
+	24,  // 124: delivery.DeliveryService.ListActiveDriverIDs:output_type -> delivery.ListActiveDriverIDsResponse
+	26,  // 125: delivery.DeliveryService.ListServedCities:output_type -> delivery.ListServedCitiesResponse
+	73,  // 126: delivery.DeliveryService.DeleteDeliveryAssignment:output_type -> google.protobuf.Empty
+	29,  // 127: delivery.DeliveryService.DeleteDeliveriesByOrder:output_type -> delivery.DeleteDeliveriesByOrderResponse
+	31,  // 128: delivery.DeliveryService.BulkDeleteDeliveries:output_type -> delivery.BulkDeleteDeliveriesResponse
+	4,   // 129: delivery.DeliveryService.SubmitFeedback:output_type -> delivery.DeliveryAssignment
+	4,   // 130: delivery.DeliveryService.ExportDeliveries:output_type -> delivery.DeliveryAssignment
+	4,   // 131: delivery.DeliveryService.CloneDeliveryAssignment:output_type -> delivery.DeliveryAssignment
+	4,   // 132: delivery.DeliveryService.AppendNote:output_type -> delivery.DeliveryAssignment
+	4,   // 133: delivery.DeliveryService.UpdateDriverETA:output_type -> delivery.DeliveryAssignment
+	42,  // 134: delivery.DeliveryService.GetDeliveryStatus:output_type -> delivery.DeliveryStatusInfo
+	44,  // 135: delivery.DeliveryService.GetSLADeadline:output_type -> delivery.DeliverySLA
+	37,  // 136: delivery.DeliveryService.BatchGetDeliveries:output_type -> delivery.BatchGetDeliveriesResponse
+	4,   // 137: delivery.DeliveryService.MarkDeadLetter:output_type -> delivery.DeliveryAssignment
+	47,  // 138: delivery.DeliveryService.ListModifiedDeliveries:output_type -> delivery.ListModifiedDeliveriesResponse
+	49,  // 139: delivery.DeliveryService.SplitDelivery:output_type -> delivery.SplitDeliveryResponse
+	51,  // 140: delivery.DeliveryService.ReplayEvents:output_type -> delivery.ReplayEventsResponse
+	4,   // 141: delivery.DeliveryService.ReattemptDelivery:output_type -> delivery.DeliveryAssignment
+	4,   // 142: delivery.DeliveryService.AddTags:output_type -> delivery.DeliveryAssignment
+	4,   // 143: delivery.DeliveryService.RemoveTags:output_type -> delivery.DeliveryAssignment
+	56,  // 144: delivery.DeliveryService.GetMetricsForDrivers:output_type -> delivery.GetMetricsForDriversResponse
+	59,  // 145: delivery.DeliveryService.GetStatusTransitionGraph:output_type -> delivery.StatusTransitionGraph
+	63,  // 146: delivery.DeliveryService.StreamDriverLocation:output_type -> delivery.StreamDriverLocationResponse
+	65,  // 147: delivery.DeliveryService.WatchDriverDeliveries:output_type -> delivery.DeliveryStatusEvent
+	62,  // 148: delivery.DeliveryService.GetDriverLocation:output_type -> delivery.DriverLocation
+	61,  // 149: delivery.DeliveryService.GetServerInfo:output_type -> delivery.ServerInfo
+	114, // [114:150] is the sub-list for method output_type
+	78,  // [78:114] is the sub-list for method input_type
+	78,  // [78:78] is the sub-list for extension type_name
+	78,  // [78:78] is the sub-list for extension extendee
+	0,   // [0:78] is the sub-list for field type_name
 }
 
 func init() { file_proto_delivery_proto_init() }
@@ -1070,13 +5002,15 @@ func file_proto_delivery_proto_init() {
 	if File_proto_delivery_proto != nil {
 		return
 	}
+	file_proto_delivery_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_delivery_proto_msgTypes[27].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_delivery_proto_rawDesc), len(file_proto_delivery_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      3,
+			NumMessages:   67,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,12 +21,41 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	DeliveryService_CreateDeliveryAssignment_FullMethodName = "/delivery.DeliveryService/CreateDeliveryAssignment"
+	DeliveryService_CheckDeliveryFeasibility_FullMethodName = "/delivery.DeliveryService/CheckDeliveryFeasibility"
+	DeliveryService_ImportDeliveries_FullMethodName         = "/delivery.DeliveryService/ImportDeliveries"
 	DeliveryService_GetDeliveryAssignment_FullMethodName    = "/delivery.DeliveryService/GetDeliveryAssignment"
 	DeliveryService_UpdateDeliveryStatus_FullMethodName     = "/delivery.DeliveryService/UpdateDeliveryStatus"
 	DeliveryService_ListDeliveryAssignments_FullMethodName  = "/delivery.DeliveryService/ListDeliveryAssignments"
 	DeliveryService_AssignDriver_FullMethodName             = "/delivery.DeliveryService/AssignDriver"
+	DeliveryService_AssignBackupDriver_FullMethodName       = "/delivery.DeliveryService/AssignBackupDriver"
 	DeliveryService_GetDeliveryMetrics_FullMethodName       = "/delivery.DeliveryService/GetDeliveryMetrics"
+	DeliveryService_GetDriverLeaderboard_FullMethodName     = "/delivery.DeliveryService/GetDriverLeaderboard"
+	DeliveryService_ListActiveDriverIDs_FullMethodName      = "/delivery.DeliveryService/ListActiveDriverIDs"
+	DeliveryService_ListServedCities_FullMethodName         = "/delivery.DeliveryService/ListServedCities"
 	DeliveryService_DeleteDeliveryAssignment_FullMethodName = "/delivery.DeliveryService/DeleteDeliveryAssignment"
+	DeliveryService_DeleteDeliveriesByOrder_FullMethodName  = "/delivery.DeliveryService/DeleteDeliveriesByOrder"
+	DeliveryService_BulkDeleteDeliveries_FullMethodName     = "/delivery.DeliveryService/BulkDeleteDeliveries"
+	DeliveryService_SubmitFeedback_FullMethodName           = "/delivery.DeliveryService/SubmitFeedback"
+	DeliveryService_ExportDeliveries_FullMethodName         = "/delivery.DeliveryService/ExportDeliveries"
+	DeliveryService_CloneDeliveryAssignment_FullMethodName  = "/delivery.DeliveryService/CloneDeliveryAssignment"
+	DeliveryService_AppendNote_FullMethodName               = "/delivery.DeliveryService/AppendNote"
+	DeliveryService_UpdateDriverETA_FullMethodName          = "/delivery.DeliveryService/UpdateDriverETA"
+	DeliveryService_GetDeliveryStatus_FullMethodName        = "/delivery.DeliveryService/GetDeliveryStatus"
+	DeliveryService_GetSLADeadline_FullMethodName           = "/delivery.DeliveryService/GetSLADeadline"
+	DeliveryService_BatchGetDeliveries_FullMethodName       = "/delivery.DeliveryService/BatchGetDeliveries"
+	DeliveryService_MarkDeadLetter_FullMethodName           = "/delivery.DeliveryService/MarkDeadLetter"
+	DeliveryService_ListModifiedDeliveries_FullMethodName   = "/delivery.DeliveryService/ListModifiedDeliveries"
+	DeliveryService_SplitDelivery_FullMethodName            = "/delivery.DeliveryService/SplitDelivery"
+	DeliveryService_ReplayEvents_FullMethodName             = "/delivery.DeliveryService/ReplayEvents"
+	DeliveryService_ReattemptDelivery_FullMethodName        = "/delivery.DeliveryService/ReattemptDelivery"
+	DeliveryService_AddTags_FullMethodName                  = "/delivery.DeliveryService/AddTags"
+	DeliveryService_RemoveTags_FullMethodName               = "/delivery.DeliveryService/RemoveTags"
+	DeliveryService_GetMetricsForDrivers_FullMethodName     = "/delivery.DeliveryService/GetMetricsForDrivers"
+	DeliveryService_GetStatusTransitionGraph_FullMethodName = "/delivery.DeliveryService/GetStatusTransitionGraph"
+	DeliveryService_StreamDriverLocation_FullMethodName     = "/delivery.DeliveryService/StreamDriverLocation"
+	DeliveryService_WatchDriverDeliveries_FullMethodName    = "/delivery.DeliveryService/WatchDriverDeliveries"
+	DeliveryService_GetDriverLocation_FullMethodName        = "/delivery.DeliveryService/GetDriverLocation"
+	DeliveryService_GetServerInfo_FullMethodName            = "/delivery.DeliveryService/GetServerInfo"
 )
 
 // DeliveryServiceClient is the client API for DeliveryService service.
@@ -37,6 +66,15 @@ const (
 type DeliveryServiceClient interface {
 	// CreateDeliveryAssignment creates a new delivery assignment
 	CreateDeliveryAssignment(ctx context.Context, in *CreateDeliveryAssignmentRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// CheckDeliveryFeasibility checks, before a delivery is accepted, that its pickup and delivery
+	// are within range of each other and that the time between them allows the trip. Distances are
+	// straight-line, so a feasible result is an estimate rather than a route.
+	CheckDeliveryFeasibility(ctx context.Context, in *CheckDeliveryFeasibilityRequest, opts ...grpc.CallOption) (*CheckDeliveryFeasibilityResponse, error)
+	// ImportDeliveries creates deliveries from a client stream of row chunks, e.g. converted from a
+	// CSV file, and reports which rows failed. Valid rows are saved even when others fail.
+	// A stream of more than LIMITS_MAX_IMPORT_ROWS rows is rejected with InvalidArgument once the
+	// chunk crossing the limit arrives; rows of earlier chunks stay imported.
+	ImportDeliveries(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportDeliveriesRequest, ImportDeliveriesResponse], error)
 	// GetDeliveryAssignment retrieves a delivery assignment by ID
 	GetDeliveryAssignment(ctx context.Context, in *GetDeliveryAssignmentRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
 	// UpdateDeliveryStatus updates the status of a delivery
@@ -45,9 +83,77 @@ type DeliveryServiceClient interface {
 	ListDeliveryAssignments(ctx context.Context, in *ListDeliveryAssignmentsRequest, opts ...grpc.CallOption) (*ListDeliveryAssignmentsResponse, error)
 	// AssignDriver assigns a driver to a delivery
 	AssignDriver(ctx context.Context, in *AssignDriverRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// AssignBackupDriver sets the driver who takes over if the primary driver is unassigned.
+	// The delivery must be ASSIGNED to a primary driver.
+	AssignBackupDriver(ctx context.Context, in *AssignBackupDriverRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
 	// GetDeliveryMetrics retrieves delivery metrics
 	GetDeliveryMetrics(ctx context.Context, in *GetDeliveryMetricsRequest, opts ...grpc.CallOption) (*DeliveryMetrics, error)
+	// GetDriverLeaderboard ranks drivers by completed deliveries, then on-time rate
+	GetDriverLeaderboard(ctx context.Context, in *GetDriverLeaderboardRequest, opts ...grpc.CallOption) (*DriverLeaderboard, error)
+	// ListActiveDriverIDs lists drivers with at least one non-terminal delivery, sorted by ID
+	ListActiveDriverIDs(ctx context.Context, in *ListActiveDriverIDsRequest, opts ...grpc.CallOption) (*ListActiveDriverIDsResponse, error)
+	// ListServedCities lists the cities deliveries are picked up from or delivered to, sorted
+	ListServedCities(ctx context.Context, in *ListServedCitiesRequest, opts ...grpc.CallOption) (*ListServedCitiesResponse, error)
 	DeleteDeliveryAssignment(ctx context.Context, in *DeleteDeliveryAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteDeliveriesByOrder deletes all deliveries for a cancelled order unless any was already picked up
+	DeleteDeliveriesByOrder(ctx context.Context, in *DeleteDeliveriesByOrderRequest, opts ...grpc.CallOption) (*DeleteDeliveriesByOrderResponse, error)
+	// BulkDeleteDeliveries is an admin operation soft-deleting every delivery matching the filters.
+	// Called without confirm_token it deletes nothing and returns the token for the current matches.
+	// At least one filter is required, and deliveries a driver has picked up are never deleted.
+	// Requires an admin API key (AUTH_ADMIN_API_KEYS) in x-api-key.
+	BulkDeleteDeliveries(ctx context.Context, in *BulkDeleteDeliveriesRequest, opts ...grpc.CallOption) (*BulkDeleteDeliveriesResponse, error)
+	// SubmitFeedback records the recipient's rating and feedback for a delivered delivery
+	SubmitFeedback(ctx context.Context, in *SubmitFeedbackRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// ExportDeliveries streams all delivery assignments for bulk export
+	ExportDeliveries(ctx context.Context, in *ExportDeliveriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeliveryAssignment], error)
+	// CloneDeliveryAssignment copies an existing delivery into a new PENDING delivery with fresh times
+	CloneDeliveryAssignment(ctx context.Context, in *CloneDeliveryAssignmentRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// AppendNote adds a timestamped timeline note without changing status
+	AppendNote(ctx context.Context, in *AppendNoteRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// UpdateDriverETA records the driver's latest arrival estimate while the package is on its way,
+	// without changing status or the committed estimated_delivery_time
+	UpdateDriverETA(ctx context.Context, in *UpdateDriverETARequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// GetDeliveryStatus retrieves only the current status of a delivery, for lightweight polling
+	GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*DeliveryStatusInfo, error)
+	// GetSLADeadline retrieves the deadline a delivery is committed to and the time left until it
+	GetSLADeadline(ctx context.Context, in *GetSLADeadlineRequest, opts ...grpc.CallOption) (*DeliverySLA, error)
+	// BatchGetDeliveries retrieves multiple delivery assignments by ID in a single call
+	BatchGetDeliveries(ctx context.Context, in *BatchGetDeliveriesRequest, opts ...grpc.CallOption) (*BatchGetDeliveriesResponse, error)
+	// MarkDeadLetter permanently parks a FAILED delivery so it is never retried
+	MarkDeadLetter(ctx context.Context, in *MarkDeadLetterRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// ListModifiedDeliveries returns deliveries changed since a sync watermark, including deleted ones
+	ListModifiedDeliveries(ctx context.Context, in *ListModifiedDeliveriesRequest, opts ...grpc.CallOption) (*ListModifiedDeliveriesResponse, error)
+	// SplitDelivery marks a delivery DELIVERED and creates a PENDING child for the packages left over.
+	// When the delivery lists its packages, the left-over ones are moved off it onto the child, and at
+	// least one package must stay with it.
+	SplitDelivery(ctx context.Context, in *SplitDeliveryRequest, opts ...grpc.CallOption) (*SplitDeliveryResponse, error)
+	// ReplayEvents re-sends a delivery's status change events to downstream consumers, marked as
+	// replays. The events are derived from the delivery's stored lifecycle timestamps. Requires an
+	// admin API key.
+	ReplayEvents(ctx context.Context, in *ReplayEventsRequest, opts ...grpc.CallOption) (*ReplayEventsResponse, error)
+	// ReattemptDelivery creates a new PENDING delivery retrying a FAILED one, which is kept for audit.
+	// The response includes the new delivery's pickup_code. When the failed delivery was already the
+	// last attempt allowed (DELIVERY_MAX_ATTEMPTS), it is moved to DEAD_LETTER and returned instead.
+	// A delivery is reattempted once; the reattempt must fit business hours and the order's active limit.
+	ReattemptDelivery(ctx context.Context, in *ReattemptDeliveryRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// AddTags tags a delivery; tags it already has are ignored
+	AddTags(ctx context.Context, in *AddTagsRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// RemoveTags removes tags from a delivery; tags it does not have are ignored
+	RemoveTags(ctx context.Context, in *RemoveTagsRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error)
+	// GetMetricsForDrivers retrieves delivery metrics for several drivers in one call
+	GetMetricsForDrivers(ctx context.Context, in *GetMetricsForDriversRequest, opts ...grpc.CallOption) (*GetMetricsForDriversResponse, error)
+	// GetStatusTransitionGraph lists, for every status, the statuses a delivery may move to next
+	GetStatusTransitionGraph(ctx context.Context, in *GetStatusTransitionGraphRequest, opts ...grpc.CallOption) (*StatusTransitionGraph, error)
+	// StreamDriverLocation ingests a driver app's location pings. Pings with invalid coordinates are
+	// counted and skipped so one bad GPS fix does not end the stream.
+	StreamDriverLocation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[DriverLocation, StreamDriverLocationResponse], error)
+	// WatchDriverDeliveries streams status changes of a driver's deliveries, including deliveries
+	// being assigned to or taken away from the driver. gRPC only.
+	WatchDriverDeliveries(ctx context.Context, in *WatchDriverDeliveriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeliveryStatusEvent], error)
+	// GetDriverLocation retrieves the most recent location ping of a driver
+	GetDriverLocation(ctx context.Context, in *GetDriverLocationRequest, opts ...grpc.CallOption) (*DriverLocation, error)
+	// GetServerInfo reports the build and uptime of the running instance
+	GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*ServerInfo, error)
 }
 
 type deliveryServiceClient struct {
@@ -68,6 +174,29 @@ func (c *deliveryServiceClient) CreateDeliveryAssignment(ctx context.Context, in
 	return out, nil
 }
 
+func (c *deliveryServiceClient) CheckDeliveryFeasibility(ctx context.Context, in *CheckDeliveryFeasibilityRequest, opts ...grpc.CallOption) (*CheckDeliveryFeasibilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckDeliveryFeasibilityResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_CheckDeliveryFeasibility_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ImportDeliveries(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportDeliveriesRequest, ImportDeliveriesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeliveryService_ServiceDesc.Streams[0], DeliveryService_ImportDeliveries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportDeliveriesRequest, ImportDeliveriesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_ImportDeliveriesClient = grpc.ClientStreamingClient[ImportDeliveriesRequest, ImportDeliveriesResponse]
+
 func (c *deliveryServiceClient) GetDeliveryAssignment(ctx context.Context, in *GetDeliveryAssignmentRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeliveryAssignment)
@@ -108,6 +237,16 @@ func (c *deliveryServiceClient) AssignDriver(ctx context.Context, in *AssignDriv
 	return out, nil
 }
 
+func (c *deliveryServiceClient) AssignBackupDriver(ctx context.Context, in *AssignBackupDriverRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_AssignBackupDriver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *deliveryServiceClient) GetDeliveryMetrics(ctx context.Context, in *GetDeliveryMetricsRequest, opts ...grpc.CallOption) (*DeliveryMetrics, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeliveryMetrics)
@@ -118,6 +257,36 @@ func (c *deliveryServiceClient) GetDeliveryMetrics(ctx context.Context, in *GetD
 	return out, nil
 }
 
+func (c *deliveryServiceClient) GetDriverLeaderboard(ctx context.Context, in *GetDriverLeaderboardRequest, opts ...grpc.CallOption) (*DriverLeaderboard, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverLeaderboard)
+	err := c.cc.Invoke(ctx, DeliveryService_GetDriverLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ListActiveDriverIDs(ctx context.Context, in *ListActiveDriverIDsRequest, opts ...grpc.CallOption) (*ListActiveDriverIDsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActiveDriverIDsResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_ListActiveDriverIDs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ListServedCities(ctx context.Context, in *ListServedCitiesRequest, opts ...grpc.CallOption) (*ListServedCitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServedCitiesResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_ListServedCities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *deliveryServiceClient) DeleteDeliveryAssignment(ctx context.Context, in *DeleteDeliveryAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -128,6 +297,257 @@ func (c *deliveryServiceClient) DeleteDeliveryAssignment(ctx context.Context, in
 	return out, nil
 }
 
+func (c *deliveryServiceClient) DeleteDeliveriesByOrder(ctx context.Context, in *DeleteDeliveriesByOrderRequest, opts ...grpc.CallOption) (*DeleteDeliveriesByOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDeliveriesByOrderResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_DeleteDeliveriesByOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) BulkDeleteDeliveries(ctx context.Context, in *BulkDeleteDeliveriesRequest, opts ...grpc.CallOption) (*BulkDeleteDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkDeleteDeliveriesResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_BulkDeleteDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) SubmitFeedback(ctx context.Context, in *SubmitFeedbackRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_SubmitFeedback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ExportDeliveries(ctx context.Context, in *ExportDeliveriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeliveryAssignment], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeliveryService_ServiceDesc.Streams[1], DeliveryService_ExportDeliveries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportDeliveriesRequest, DeliveryAssignment]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_ExportDeliveriesClient = grpc.ServerStreamingClient[DeliveryAssignment]
+
+func (c *deliveryServiceClient) CloneDeliveryAssignment(ctx context.Context, in *CloneDeliveryAssignmentRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_CloneDeliveryAssignment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) AppendNote(ctx context.Context, in *AppendNoteRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_AppendNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) UpdateDriverETA(ctx context.Context, in *UpdateDriverETARequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_UpdateDriverETA_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*DeliveryStatusInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryStatusInfo)
+	err := c.cc.Invoke(ctx, DeliveryService_GetDeliveryStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetSLADeadline(ctx context.Context, in *GetSLADeadlineRequest, opts ...grpc.CallOption) (*DeliverySLA, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliverySLA)
+	err := c.cc.Invoke(ctx, DeliveryService_GetSLADeadline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) BatchGetDeliveries(ctx context.Context, in *BatchGetDeliveriesRequest, opts ...grpc.CallOption) (*BatchGetDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetDeliveriesResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_BatchGetDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) MarkDeadLetter(ctx context.Context, in *MarkDeadLetterRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_MarkDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ListModifiedDeliveries(ctx context.Context, in *ListModifiedDeliveriesRequest, opts ...grpc.CallOption) (*ListModifiedDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModifiedDeliveriesResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_ListModifiedDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) SplitDelivery(ctx context.Context, in *SplitDeliveryRequest, opts ...grpc.CallOption) (*SplitDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SplitDeliveryResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_SplitDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ReplayEvents(ctx context.Context, in *ReplayEventsRequest, opts ...grpc.CallOption) (*ReplayEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayEventsResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_ReplayEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) ReattemptDelivery(ctx context.Context, in *ReattemptDeliveryRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_ReattemptDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) AddTags(ctx context.Context, in *AddTagsRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_AddTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) RemoveTags(ctx context.Context, in *RemoveTagsRequest, opts ...grpc.CallOption) (*DeliveryAssignment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveryAssignment)
+	err := c.cc.Invoke(ctx, DeliveryService_RemoveTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetMetricsForDrivers(ctx context.Context, in *GetMetricsForDriversRequest, opts ...grpc.CallOption) (*GetMetricsForDriversResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetricsForDriversResponse)
+	err := c.cc.Invoke(ctx, DeliveryService_GetMetricsForDrivers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetStatusTransitionGraph(ctx context.Context, in *GetStatusTransitionGraphRequest, opts ...grpc.CallOption) (*StatusTransitionGraph, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusTransitionGraph)
+	err := c.cc.Invoke(ctx, DeliveryService_GetStatusTransitionGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) StreamDriverLocation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[DriverLocation, StreamDriverLocationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeliveryService_ServiceDesc.Streams[2], DeliveryService_StreamDriverLocation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DriverLocation, StreamDriverLocationResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_StreamDriverLocationClient = grpc.ClientStreamingClient[DriverLocation, StreamDriverLocationResponse]
+
+func (c *deliveryServiceClient) WatchDriverDeliveries(ctx context.Context, in *WatchDriverDeliveriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeliveryStatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeliveryService_ServiceDesc.Streams[3], DeliveryService_WatchDriverDeliveries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDriverDeliveriesRequest, DeliveryStatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_WatchDriverDeliveriesClient = grpc.ServerStreamingClient[DeliveryStatusEvent]
+
+func (c *deliveryServiceClient) GetDriverLocation(ctx context.Context, in *GetDriverLocationRequest, opts ...grpc.CallOption) (*DriverLocation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverLocation)
+	err := c.cc.Invoke(ctx, DeliveryService_GetDriverLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deliveryServiceClient) GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*ServerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfo)
+	err := c.cc.Invoke(ctx, DeliveryService_GetServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeliveryServiceServer is the server API for DeliveryService service.
 // All implementations must embed UnimplementedDeliveryServiceServer
 // for forward compatibility.
@@ -136,6 +556,15 @@ func (c *deliveryServiceClient) DeleteDeliveryAssignment(ctx context.Context, in
 type DeliveryServiceServer interface {
 	// CreateDeliveryAssignment creates a new delivery assignment
 	CreateDeliveryAssignment(context.Context, *CreateDeliveryAssignmentRequest) (*DeliveryAssignment, error)
+	// CheckDeliveryFeasibility checks, before a delivery is accepted, that its pickup and delivery
+	// are within range of each other and that the time between them allows the trip. Distances are
+	// straight-line, so a feasible result is an estimate rather than a route.
+	CheckDeliveryFeasibility(context.Context, *CheckDeliveryFeasibilityRequest) (*CheckDeliveryFeasibilityResponse, error)
+	// ImportDeliveries creates deliveries from a client stream of row chunks, e.g. converted from a
+	// CSV file, and reports which rows failed. Valid rows are saved even when others fail.
+	// A stream of more than LIMITS_MAX_IMPORT_ROWS rows is rejected with InvalidArgument once the
+	// chunk crossing the limit arrives; rows of earlier chunks stay imported.
+	ImportDeliveries(grpc.ClientStreamingServer[ImportDeliveriesRequest, ImportDeliveriesResponse]) error
 	// GetDeliveryAssignment retrieves a delivery assignment by ID
 	GetDeliveryAssignment(context.Context, *GetDeliveryAssignmentRequest) (*DeliveryAssignment, error)
 	// UpdateDeliveryStatus updates the status of a delivery
@@ -144,9 +573,77 @@ type DeliveryServiceServer interface {
 	ListDeliveryAssignments(context.Context, *ListDeliveryAssignmentsRequest) (*ListDeliveryAssignmentsResponse, error)
 	// AssignDriver assigns a driver to a delivery
 	AssignDriver(context.Context, *AssignDriverRequest) (*DeliveryAssignment, error)
+	// AssignBackupDriver sets the driver who takes over if the primary driver is unassigned.
+	// The delivery must be ASSIGNED to a primary driver.
+	AssignBackupDriver(context.Context, *AssignBackupDriverRequest) (*DeliveryAssignment, error)
 	// GetDeliveryMetrics retrieves delivery metrics
 	GetDeliveryMetrics(context.Context, *GetDeliveryMetricsRequest) (*DeliveryMetrics, error)
+	// GetDriverLeaderboard ranks drivers by completed deliveries, then on-time rate
+	GetDriverLeaderboard(context.Context, *GetDriverLeaderboardRequest) (*DriverLeaderboard, error)
+	// ListActiveDriverIDs lists drivers with at least one non-terminal delivery, sorted by ID
+	ListActiveDriverIDs(context.Context, *ListActiveDriverIDsRequest) (*ListActiveDriverIDsResponse, error)
+	// ListServedCities lists the cities deliveries are picked up from or delivered to, sorted
+	ListServedCities(context.Context, *ListServedCitiesRequest) (*ListServedCitiesResponse, error)
 	DeleteDeliveryAssignment(context.Context, *DeleteDeliveryAssignmentRequest) (*emptypb.Empty, error)
+	// DeleteDeliveriesByOrder deletes all deliveries for a cancelled order unless any was already picked up
+	DeleteDeliveriesByOrder(context.Context, *DeleteDeliveriesByOrderRequest) (*DeleteDeliveriesByOrderResponse, error)
+	// BulkDeleteDeliveries is an admin operation soft-deleting every delivery matching the filters.
+	// Called without confirm_token it deletes nothing and returns the token for the current matches.
+	// At least one filter is required, and deliveries a driver has picked up are never deleted.
+	// Requires an admin API key (AUTH_ADMIN_API_KEYS) in x-api-key.
+	BulkDeleteDeliveries(context.Context, *BulkDeleteDeliveriesRequest) (*BulkDeleteDeliveriesResponse, error)
+	// SubmitFeedback records the recipient's rating and feedback for a delivered delivery
+	SubmitFeedback(context.Context, *SubmitFeedbackRequest) (*DeliveryAssignment, error)
+	// ExportDeliveries streams all delivery assignments for bulk export
+	ExportDeliveries(*ExportDeliveriesRequest, grpc.ServerStreamingServer[DeliveryAssignment]) error
+	// CloneDeliveryAssignment copies an existing delivery into a new PENDING delivery with fresh times
+	CloneDeliveryAssignment(context.Context, *CloneDeliveryAssignmentRequest) (*DeliveryAssignment, error)
+	// AppendNote adds a timestamped timeline note without changing status
+	AppendNote(context.Context, *AppendNoteRequest) (*DeliveryAssignment, error)
+	// UpdateDriverETA records the driver's latest arrival estimate while the package is on its way,
+	// without changing status or the committed estimated_delivery_time
+	UpdateDriverETA(context.Context, *UpdateDriverETARequest) (*DeliveryAssignment, error)
+	// GetDeliveryStatus retrieves only the current status of a delivery, for lightweight polling
+	GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*DeliveryStatusInfo, error)
+	// GetSLADeadline retrieves the deadline a delivery is committed to and the time left until it
+	GetSLADeadline(context.Context, *GetSLADeadlineRequest) (*DeliverySLA, error)
+	// BatchGetDeliveries retrieves multiple delivery assignments by ID in a single call
+	BatchGetDeliveries(context.Context, *BatchGetDeliveriesRequest) (*BatchGetDeliveriesResponse, error)
+	// MarkDeadLetter permanently parks a FAILED delivery so it is never retried
+	MarkDeadLetter(context.Context, *MarkDeadLetterRequest) (*DeliveryAssignment, error)
+	// ListModifiedDeliveries returns deliveries changed since a sync watermark, including deleted ones
+	ListModifiedDeliveries(context.Context, *ListModifiedDeliveriesRequest) (*ListModifiedDeliveriesResponse, error)
+	// SplitDelivery marks a delivery DELIVERED and creates a PENDING child for the packages left over.
+	// When the delivery lists its packages, the left-over ones are moved off it onto the child, and at
+	// least one package must stay with it.
+	SplitDelivery(context.Context, *SplitDeliveryRequest) (*SplitDeliveryResponse, error)
+	// ReplayEvents re-sends a delivery's status change events to downstream consumers, marked as
+	// replays. The events are derived from the delivery's stored lifecycle timestamps. Requires an
+	// admin API key.
+	ReplayEvents(context.Context, *ReplayEventsRequest) (*ReplayEventsResponse, error)
+	// ReattemptDelivery creates a new PENDING delivery retrying a FAILED one, which is kept for audit.
+	// The response includes the new delivery's pickup_code. When the failed delivery was already the
+	// last attempt allowed (DELIVERY_MAX_ATTEMPTS), it is moved to DEAD_LETTER and returned instead.
+	// A delivery is reattempted once; the reattempt must fit business hours and the order's active limit.
+	ReattemptDelivery(context.Context, *ReattemptDeliveryRequest) (*DeliveryAssignment, error)
+	// AddTags tags a delivery; tags it already has are ignored
+	AddTags(context.Context, *AddTagsRequest) (*DeliveryAssignment, error)
+	// RemoveTags removes tags from a delivery; tags it does not have are ignored
+	RemoveTags(context.Context, *RemoveTagsRequest) (*DeliveryAssignment, error)
+	// GetMetricsForDrivers retrieves delivery metrics for several drivers in one call
+	GetMetricsForDrivers(context.Context, *GetMetricsForDriversRequest) (*GetMetricsForDriversResponse, error)
+	// GetStatusTransitionGraph lists, for every status, the statuses a delivery may move to next
+	GetStatusTransitionGraph(context.Context, *GetStatusTransitionGraphRequest) (*StatusTransitionGraph, error)
+	// StreamDriverLocation ingests a driver app's location pings. Pings with invalid coordinates are
+	// counted and skipped so one bad GPS fix does not end the stream.
+	StreamDriverLocation(grpc.ClientStreamingServer[DriverLocation, StreamDriverLocationResponse]) error
+	// WatchDriverDeliveries streams status changes of a driver's deliveries, including deliveries
+	// being assigned to or taken away from the driver. gRPC only.
+	WatchDriverDeliveries(*WatchDriverDeliveriesRequest, grpc.ServerStreamingServer[DeliveryStatusEvent]) error
+	// GetDriverLocation retrieves the most recent location ping of a driver
+	GetDriverLocation(context.Context, *GetDriverLocationRequest) (*DriverLocation, error)
+	// GetServerInfo reports the build and uptime of the running instance
+	GetServerInfo(context.Context, *GetServerInfoRequest) (*ServerInfo, error)
 	mustEmbedUnimplementedDeliveryServiceServer()
 }
 
@@ -160,6 +657,12 @@ type UnimplementedDeliveryServiceServer struct{}
 func (UnimplementedDeliveryServiceServer) CreateDeliveryAssignment(context.Context, *CreateDeliveryAssignmentRequest) (*DeliveryAssignment, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateDeliveryAssignment not implemented")
 }
+func (UnimplementedDeliveryServiceServer) CheckDeliveryFeasibility(context.Context, *CheckDeliveryFeasibilityRequest) (*CheckDeliveryFeasibilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDeliveryFeasibility not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ImportDeliveries(grpc.ClientStreamingServer[ImportDeliveriesRequest, ImportDeliveriesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportDeliveries not implemented")
+}
 func (UnimplementedDeliveryServiceServer) GetDeliveryAssignment(context.Context, *GetDeliveryAssignmentRequest) (*DeliveryAssignment, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryAssignment not implemented")
 }
@@ -172,12 +675,93 @@ func (UnimplementedDeliveryServiceServer) ListDeliveryAssignments(context.Contex
 func (UnimplementedDeliveryServiceServer) AssignDriver(context.Context, *AssignDriverRequest) (*DeliveryAssignment, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AssignDriver not implemented")
 }
+func (UnimplementedDeliveryServiceServer) AssignBackupDriver(context.Context, *AssignBackupDriverRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignBackupDriver not implemented")
+}
 func (UnimplementedDeliveryServiceServer) GetDeliveryMetrics(context.Context, *GetDeliveryMetricsRequest) (*DeliveryMetrics, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryMetrics not implemented")
 }
+func (UnimplementedDeliveryServiceServer) GetDriverLeaderboard(context.Context, *GetDriverLeaderboardRequest) (*DriverLeaderboard, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverLeaderboard not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ListActiveDriverIDs(context.Context, *ListActiveDriverIDsRequest) (*ListActiveDriverIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActiveDriverIDs not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ListServedCities(context.Context, *ListServedCitiesRequest) (*ListServedCitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServedCities not implemented")
+}
 func (UnimplementedDeliveryServiceServer) DeleteDeliveryAssignment(context.Context, *DeleteDeliveryAssignmentRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeliveryAssignment not implemented")
 }
+func (UnimplementedDeliveryServiceServer) DeleteDeliveriesByOrder(context.Context, *DeleteDeliveriesByOrderRequest) (*DeleteDeliveriesByOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeliveriesByOrder not implemented")
+}
+func (UnimplementedDeliveryServiceServer) BulkDeleteDeliveries(context.Context, *BulkDeleteDeliveriesRequest) (*BulkDeleteDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkDeleteDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) SubmitFeedback(context.Context, *SubmitFeedbackRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitFeedback not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ExportDeliveries(*ExportDeliveriesRequest, grpc.ServerStreamingServer[DeliveryAssignment]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) CloneDeliveryAssignment(context.Context, *CloneDeliveryAssignmentRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneDeliveryAssignment not implemented")
+}
+func (UnimplementedDeliveryServiceServer) AppendNote(context.Context, *AppendNoteRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendNote not implemented")
+}
+func (UnimplementedDeliveryServiceServer) UpdateDriverETA(context.Context, *UpdateDriverETARequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDriverETA not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*DeliveryStatusInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStatus not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetSLADeadline(context.Context, *GetSLADeadlineRequest) (*DeliverySLA, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLADeadline not implemented")
+}
+func (UnimplementedDeliveryServiceServer) BatchGetDeliveries(context.Context, *BatchGetDeliveriesRequest) (*BatchGetDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) MarkDeadLetter(context.Context, *MarkDeadLetterRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkDeadLetter not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ListModifiedDeliveries(context.Context, *ListModifiedDeliveriesRequest) (*ListModifiedDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModifiedDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) SplitDelivery(context.Context, *SplitDeliveryRequest) (*SplitDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SplitDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ReplayEvents(context.Context, *ReplayEventsRequest) (*ReplayEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayEvents not implemented")
+}
+func (UnimplementedDeliveryServiceServer) ReattemptDelivery(context.Context, *ReattemptDeliveryRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReattemptDelivery not implemented")
+}
+func (UnimplementedDeliveryServiceServer) AddTags(context.Context, *AddTagsRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTags not implemented")
+}
+func (UnimplementedDeliveryServiceServer) RemoveTags(context.Context, *RemoveTagsRequest) (*DeliveryAssignment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTags not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetMetricsForDrivers(context.Context, *GetMetricsForDriversRequest) (*GetMetricsForDriversResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetricsForDrivers not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetStatusTransitionGraph(context.Context, *GetStatusTransitionGraphRequest) (*StatusTransitionGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatusTransitionGraph not implemented")
+}
+func (UnimplementedDeliveryServiceServer) StreamDriverLocation(grpc.ClientStreamingServer[DriverLocation, StreamDriverLocationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDriverLocation not implemented")
+}
+func (UnimplementedDeliveryServiceServer) WatchDriverDeliveries(*WatchDriverDeliveriesRequest, grpc.ServerStreamingServer[DeliveryStatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDriverDeliveries not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetDriverLocation(context.Context, *GetDriverLocationRequest) (*DriverLocation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverLocation not implemented")
+}
+func (UnimplementedDeliveryServiceServer) GetServerInfo(context.Context, *GetServerInfoRequest) (*ServerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerInfo not implemented")
+}
 func (UnimplementedDeliveryServiceServer) mustEmbedUnimplementedDeliveryServiceServer() {}
 func (UnimplementedDeliveryServiceServer) testEmbeddedByValue()                         {}
 
@@ -217,26 +801,51 @@ func _DeliveryService_CreateDeliveryAssignment_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DeliveryService_GetDeliveryAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetDeliveryAssignmentRequest)
+func _DeliveryService_CheckDeliveryFeasibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckDeliveryFeasibilityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DeliveryServiceServer).GetDeliveryAssignment(ctx, in)
+		return srv.(DeliveryServiceServer).CheckDeliveryFeasibility(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DeliveryService_GetDeliveryAssignment_FullMethodName,
+		FullMethod: DeliveryService_CheckDeliveryFeasibility_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DeliveryServiceServer).GetDeliveryAssignment(ctx, req.(*GetDeliveryAssignmentRequest))
+		return srv.(DeliveryServiceServer).CheckDeliveryFeasibility(ctx, req.(*CheckDeliveryFeasibilityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DeliveryService_UpdateDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateDeliveryStatusRequest)
+func _DeliveryService_ImportDeliveries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliveryServiceServer).ImportDeliveries(&grpc.GenericServerStream[ImportDeliveriesRequest, ImportDeliveriesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_ImportDeliveriesServer = grpc.ClientStreamingServer[ImportDeliveriesRequest, ImportDeliveriesResponse]
+
+func _DeliveryService_GetDeliveryAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryAssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetDeliveryAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetDeliveryAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetDeliveryAssignment(ctx, req.(*GetDeliveryAssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_UpdateDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDeliveryStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -289,6 +898,24 @@ func _DeliveryService_AssignDriver_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeliveryService_AssignBackupDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignBackupDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).AssignBackupDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_AssignBackupDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).AssignBackupDriver(ctx, req.(*AssignBackupDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DeliveryService_GetDeliveryMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetDeliveryMetricsRequest)
 	if err := dec(in); err != nil {
@@ -307,6 +934,60 @@ func _DeliveryService_GetDeliveryMetrics_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeliveryService_GetDriverLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetDriverLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetDriverLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetDriverLeaderboard(ctx, req.(*GetDriverLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ListActiveDriverIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveDriverIDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ListActiveDriverIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ListActiveDriverIDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ListActiveDriverIDs(ctx, req.(*ListActiveDriverIDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ListServedCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServedCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ListServedCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ListServedCities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ListServedCities(ctx, req.(*ListServedCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DeliveryService_DeleteDeliveryAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteDeliveryAssignmentRequest)
 	if err := dec(in); err != nil {
@@ -325,6 +1006,395 @@ func _DeliveryService_DeleteDeliveryAssignment_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeliveryService_DeleteDeliveriesByOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeliveriesByOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).DeleteDeliveriesByOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_DeleteDeliveriesByOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).DeleteDeliveriesByOrder(ctx, req.(*DeleteDeliveriesByOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_BulkDeleteDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkDeleteDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).BulkDeleteDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_BulkDeleteDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).BulkDeleteDeliveries(ctx, req.(*BulkDeleteDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_SubmitFeedback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitFeedbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).SubmitFeedback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_SubmitFeedback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).SubmitFeedback(ctx, req.(*SubmitFeedbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ExportDeliveries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportDeliveriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeliveryServiceServer).ExportDeliveries(m, &grpc.GenericServerStream[ExportDeliveriesRequest, DeliveryAssignment]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_ExportDeliveriesServer = grpc.ServerStreamingServer[DeliveryAssignment]
+
+func _DeliveryService_CloneDeliveryAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneDeliveryAssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).CloneDeliveryAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_CloneDeliveryAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).CloneDeliveryAssignment(ctx, req.(*CloneDeliveryAssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_AppendNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).AppendNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_AppendNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).AppendNote(ctx, req.(*AppendNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_UpdateDriverETA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDriverETARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).UpdateDriverETA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_UpdateDriverETA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).UpdateDriverETA(ctx, req.(*UpdateDriverETARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetDeliveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetDeliveryStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetDeliveryStatus(ctx, req.(*GetDeliveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetSLADeadline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSLADeadlineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetSLADeadline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetSLADeadline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetSLADeadline(ctx, req.(*GetSLADeadlineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_BatchGetDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).BatchGetDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_BatchGetDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).BatchGetDeliveries(ctx, req.(*BatchGetDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_MarkDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).MarkDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_MarkDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).MarkDeadLetter(ctx, req.(*MarkDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ListModifiedDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModifiedDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ListModifiedDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ListModifiedDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ListModifiedDeliveries(ctx, req.(*ListModifiedDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_SplitDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).SplitDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_SplitDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).SplitDelivery(ctx, req.(*SplitDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ReplayEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ReplayEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ReplayEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ReplayEvents(ctx, req.(*ReplayEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_ReattemptDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReattemptDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).ReattemptDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_ReattemptDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).ReattemptDelivery(ctx, req.(*ReattemptDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_AddTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).AddTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_AddTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).AddTags(ctx, req.(*AddTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_RemoveTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).RemoveTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_RemoveTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).RemoveTags(ctx, req.(*RemoveTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetMetricsForDrivers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetricsForDriversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetMetricsForDrivers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetMetricsForDrivers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetMetricsForDrivers(ctx, req.(*GetMetricsForDriversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetStatusTransitionGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusTransitionGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetStatusTransitionGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetStatusTransitionGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetStatusTransitionGraph(ctx, req.(*GetStatusTransitionGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_StreamDriverLocation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliveryServiceServer).StreamDriverLocation(&grpc.GenericServerStream[DriverLocation, StreamDriverLocationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_StreamDriverLocationServer = grpc.ClientStreamingServer[DriverLocation, StreamDriverLocationResponse]
+
+func _DeliveryService_WatchDriverDeliveries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDriverDeliveriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeliveryServiceServer).WatchDriverDeliveries(m, &grpc.GenericServerStream[WatchDriverDeliveriesRequest, DeliveryStatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeliveryService_WatchDriverDeliveriesServer = grpc.ServerStreamingServer[DeliveryStatusEvent]
+
+func _DeliveryService_GetDriverLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetDriverLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetDriverLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetDriverLocation(ctx, req.(*GetDriverLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeliveryService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).GetServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliveryService_GetServerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).GetServerInfo(ctx, req.(*GetServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DeliveryService_ServiceDesc is the grpc.ServiceDesc for DeliveryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -336,6 +1406,10 @@ var DeliveryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateDeliveryAssignment",
 			Handler:    _DeliveryService_CreateDeliveryAssignment_Handler,
 		},
+		{
+			MethodName: "CheckDeliveryFeasibility",
+			Handler:    _DeliveryService_CheckDeliveryFeasibility_Handler,
+		},
 		{
 			MethodName: "GetDeliveryAssignment",
 			Handler:    _DeliveryService_GetDeliveryAssignment_Handler,
@@ -352,15 +1426,132 @@ var DeliveryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AssignDriver",
 			Handler:    _DeliveryService_AssignDriver_Handler,
 		},
+		{
+			MethodName: "AssignBackupDriver",
+			Handler:    _DeliveryService_AssignBackupDriver_Handler,
+		},
 		{
 			MethodName: "GetDeliveryMetrics",
 			Handler:    _DeliveryService_GetDeliveryMetrics_Handler,
 		},
+		{
+			MethodName: "GetDriverLeaderboard",
+			Handler:    _DeliveryService_GetDriverLeaderboard_Handler,
+		},
+		{
+			MethodName: "ListActiveDriverIDs",
+			Handler:    _DeliveryService_ListActiveDriverIDs_Handler,
+		},
+		{
+			MethodName: "ListServedCities",
+			Handler:    _DeliveryService_ListServedCities_Handler,
+		},
 		{
 			MethodName: "DeleteDeliveryAssignment",
 			Handler:    _DeliveryService_DeleteDeliveryAssignment_Handler,
 		},
+		{
+			MethodName: "DeleteDeliveriesByOrder",
+			Handler:    _DeliveryService_DeleteDeliveriesByOrder_Handler,
+		},
+		{
+			MethodName: "BulkDeleteDeliveries",
+			Handler:    _DeliveryService_BulkDeleteDeliveries_Handler,
+		},
+		{
+			MethodName: "SubmitFeedback",
+			Handler:    _DeliveryService_SubmitFeedback_Handler,
+		},
+		{
+			MethodName: "CloneDeliveryAssignment",
+			Handler:    _DeliveryService_CloneDeliveryAssignment_Handler,
+		},
+		{
+			MethodName: "AppendNote",
+			Handler:    _DeliveryService_AppendNote_Handler,
+		},
+		{
+			MethodName: "UpdateDriverETA",
+			Handler:    _DeliveryService_UpdateDriverETA_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStatus",
+			Handler:    _DeliveryService_GetDeliveryStatus_Handler,
+		},
+		{
+			MethodName: "GetSLADeadline",
+			Handler:    _DeliveryService_GetSLADeadline_Handler,
+		},
+		{
+			MethodName: "BatchGetDeliveries",
+			Handler:    _DeliveryService_BatchGetDeliveries_Handler,
+		},
+		{
+			MethodName: "MarkDeadLetter",
+			Handler:    _DeliveryService_MarkDeadLetter_Handler,
+		},
+		{
+			MethodName: "ListModifiedDeliveries",
+			Handler:    _DeliveryService_ListModifiedDeliveries_Handler,
+		},
+		{
+			MethodName: "SplitDelivery",
+			Handler:    _DeliveryService_SplitDelivery_Handler,
+		},
+		{
+			MethodName: "ReplayEvents",
+			Handler:    _DeliveryService_ReplayEvents_Handler,
+		},
+		{
+			MethodName: "ReattemptDelivery",
+			Handler:    _DeliveryService_ReattemptDelivery_Handler,
+		},
+		{
+			MethodName: "AddTags",
+			Handler:    _DeliveryService_AddTags_Handler,
+		},
+		{
+			MethodName: "RemoveTags",
+			Handler:    _DeliveryService_RemoveTags_Handler,
+		},
+		{
+			MethodName: "GetMetricsForDrivers",
+			Handler:    _DeliveryService_GetMetricsForDrivers_Handler,
+		},
+		{
+			MethodName: "GetStatusTransitionGraph",
+			Handler:    _DeliveryService_GetStatusTransitionGraph_Handler,
+		},
+		{
+			MethodName: "GetDriverLocation",
+			Handler:    _DeliveryService_GetDriverLocation_Handler,
+		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _DeliveryService_GetServerInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportDeliveries",
+			Handler:       _DeliveryService_ImportDeliveries_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportDeliveries",
+			Handler:       _DeliveryService_ExportDeliveries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamDriverLocation",
+			Handler:       _DeliveryService_StreamDriverLocation_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchDriverDeliveries",
+			Handler:       _DeliveryService_WatchDriverDeliveries_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/delivery.proto",
 }